@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"log"
@@ -11,10 +12,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
 	"github.com/jenglund/rlship-tools/internal/api/handlers"
 	"github.com/jenglund/rlship-tools/internal/api/service"
 	"github.com/jenglund/rlship-tools/internal/config"
 	"github.com/jenglund/rlship-tools/internal/middleware"
+	"github.com/jenglund/rlship-tools/internal/models"
 	"github.com/jenglund/rlship-tools/internal/repository/postgres"
 	"github.com/jenglund/rlship-tools/internal/worker"
 )
@@ -66,7 +69,75 @@ func setupApp(cfg *config.Config) (*gin.Engine, error) {
 	repos := postgres.NewRepositories(db)
 
 	// Initialize services
-	listService := service.NewListService(repos.Lists)
+	var syncProviders []service.SyncProvider
+	if cfg.Sync.ImportDir != "" {
+		syncProviders = append(syncProviders, service.NewFileSyncProvider(cfg.Sync.ImportDir))
+	}
+	if cfg.Sync.GoogleMaps.APIKey != "" {
+		syncProviders = append(syncProviders, service.NewGoogleMapsProvider(
+			cfg.Sync.GoogleMaps.BaseURL, cfg.Sync.GoogleMaps.APIKey, cfg.Sync.GoogleMaps.Timeout))
+	}
+	listService := service.NewListService(repos.Lists, syncProviders...)
+	tribeAuthorizer := service.NewTribeAuthorizer(repos.Tribes)
+	listAuthorizer := service.NewListAuthorizer(repos.Lists, tribeAuthorizer)
+	menuService := service.NewMenuService(listService, listAuthorizer, repos.MenuSessions, tribeAuthorizer)
+	votingService := service.NewVotingService(listService, listAuthorizer, repos.MenuSessions, repos.Tribes)
+
+	signingKey := []byte(cfg.Storage.SigningKey)
+	if len(signingKey) == 0 {
+		log.Printf("Warning: no storage signing key configured; photo URLs will not survive a restart")
+		signingKey = make([]byte, 32)
+		if _, err = rand.Read(signingKey); err != nil {
+			return nil, fmt.Errorf("error generating storage signing key: %w", err)
+		}
+	}
+	blobStore, err := service.NewLocalBlobStore(cfg.Storage.Dir, "/api/photos", signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing photo storage: %w", err)
+	}
+
+	// Photo variant worker resizes new photos, sweeping for missed or failed
+	// ones every five minutes and giving up after five attempts
+	variantService := service.NewPhotoVariantService(repos.ActivityPhotos, blobStore, nil, cfg.Storage.MaxUploadSize)
+	variantWorker := worker.NewPhotoVariantWorker(repos.ActivityPhotos, variantService, 5*time.Minute, 5, time.Minute)
+	photoService := service.NewPhotoService(variantWorker.QueueOnCreate(repos.ActivityPhotos), blobStore,
+		cfg.Storage.MaxUploadSize, cfg.Storage.URLTTL)
+	calendarService := service.NewCalendarService(repos.CalendarTokens, repos.Activities, repos.Tribes)
+	availabilityService := service.NewAvailabilityService(repos.Availability, repos.Tribes, nil)
+	interestService := service.NewInterestService(repos.Interests, repos.Lists, repos.Tribes, menuService)
+
+	inviteKey := []byte(cfg.Invites.SigningKey)
+	if len(inviteKey) == 0 {
+		log.Printf("Warning: no invite signing key configured; invite links will not survive a restart")
+		inviteKey = make([]byte, 32)
+		if _, err = rand.Read(inviteKey); err != nil {
+			return nil, fmt.Errorf("error generating invite signing key: %w", err)
+		}
+	}
+	inviteService := service.NewTribeInviteService(repos.Invites, tribeAuthorizer, inviteKey)
+
+	var mailer service.Mailer
+	if cfg.Mail.SMTPHost != "" {
+		mailer, err = service.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing mailer: %w", err)
+		}
+	} else {
+		log.Printf("Warning: no SMTP host configured; writing outgoing mail to %s", cfg.Mail.Dir)
+		mailer, err = service.NewFileMailer(cfg.Mail.Dir, cfg.Mail.From)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing mailer: %w", err)
+		}
+	}
+	emailInvitationService := service.NewEmailInvitationService(repos.EmailInvitations, repos.Users, repos.Tribes,
+		tribeAuthorizer, mailer, cfg.Mail.SignupURL)
+
+	guestPolicy := models.GuestExpiryPolicy(cfg.Guests.ExpiryPolicy)
+	if err = guestPolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("error in guest configuration: %w", err)
+	}
+	guestExpiryService := service.NewGuestExpiryService(repos.Tribes, repos.GuestExpiry, repos.Users, mailer,
+		guestPolicy, time.Duration(cfg.Guests.WarnDays)*24*time.Hour)
 
 	// Check for development mode
 	environment := os.Getenv("ENVIRONMENT")
@@ -100,7 +171,7 @@ func setupApp(cfg *config.Config) (*gin.Engine, error) {
 	}
 
 	// Initialize and configure Gin router
-	router := setupRouter(repos, authMiddleware, listService)
+	router := setupRouter(repos, authMiddleware, listService, menuService, votingService, photoService, calendarService, availabilityService, interestService, inviteService, emailInvitationService)
 
 	// Initialize and start background workers
 	// Share cleanup worker runs every hour
@@ -108,6 +179,27 @@ func setupApp(cfg *config.Config) (*gin.Engine, error) {
 	cleanupWorker.Start()
 	log.Println("Share cleanup worker started")
 
+	// Interest cleanup worker purges expired interest indicators
+	interestWorker := worker.NewInterestCleanupWorker(interestService, 15*time.Minute)
+	interestWorker.Start()
+	log.Println("Interest cleanup worker started")
+
+	// Guest expiry worker warns and expires guest memberships
+	guestWorker := worker.NewGuestExpiryWorker(guestExpiryService, cfg.Guests.CheckInterval)
+	guestWorker.Start()
+	log.Println("Guest expiry worker started")
+
+	// List sync worker keeps synced lists fresh; a zero interval disables it
+	if cfg.Sync.Interval > 0 {
+		syncWorker := worker.NewListSyncWorker(repos.Lists, listService,
+			cfg.Sync.Interval, cfg.Sync.Concurrency, cfg.Sync.Jitter)
+		syncWorker.Start()
+		log.Println("List sync worker started")
+	}
+
+	variantWorker.Start()
+	log.Println("Photo variant worker started")
+
 	// Start a background goroutine to monitor database health
 	go monitorDatabaseHealth(repos.DB())
 
@@ -175,7 +267,7 @@ func monitorDatabaseHealth(db *sql.DB) {
 }
 
 // setupRouter creates and configures the Gin router with all routes and middlewares
-func setupRouter(repos *postgres.Repositories, authMiddleware middleware.AuthMiddleware, listService service.ListService) *gin.Engine {
+func setupRouter(repos *postgres.Repositories, authMiddleware middleware.AuthMiddleware, listService service.ListService, menuService service.MenuService, votingService service.VotingService, photoService service.PhotoService, calendarService service.CalendarService, availabilityService service.AvailabilityService, interestService service.InterestService, inviteService service.TribeInviteService, emailInvitationService service.EmailInvitationService) *gin.Engine {
 	// Set Gin to release mode in production
 	if os.Getenv("GIN_MODE") != "debug" {
 		gin.SetMode(gin.ReleaseMode)
@@ -260,9 +352,18 @@ func setupRouter(repos *postgres.Repositories, authMiddleware middleware.AuthMid
 	{
 		// Initialize and register user handler for public routes
 		userHandler := handlers.NewUserHandler(repos)
+		userHandler.SetInvitationConverter(emailInvitationService)
 		userHandler.RegisterRoutes(publicAPI)
 	}
 
+	// Photos are served through signed URLs, which need no authentication
+	photoHandler := handlers.NewActivityPhotoHandler(repos, photoService)
+	photoHandler.RegisterPublicRoutes(publicAPI)
+
+	// Calendar feeds are authorized by feed tokens, for calendar apps
+	calendarHandler := handlers.NewCalendarHandler(repos, calendarService)
+	calendarHandler.RegisterPublicRoutes(publicAPI)
+
 	// Protected API routes
 	protectedAPI := api.Group("")
 	{
@@ -306,15 +407,46 @@ func setupRouter(repos *postgres.Repositories, authMiddleware middleware.AuthMid
 		tribeHandler := handlers.NewTribeHandler(repos)
 		tribeHandler.RegisterRoutes(protectedAPI)
 
+		// Initialize and register activity handler
+		activityHandler := handlers.NewActivityHandler(repos)
+		activityHandler.RegisterRoutes(protectedAPI)
+		photoHandler.RegisterRoutes(protectedAPI)
+		calendarHandler.RegisterRoutes(protectedAPI)
+		availabilityHandler := handlers.NewAvailabilityHandler(repos, availabilityService)
+		availabilityHandler.RegisterRoutes(protectedAPI)
+		interestHandler := handlers.NewInterestHandler(interestService)
+		interestHandler.RegisterRoutes(protectedAPI)
+		inviteHandler := handlers.NewTribeInviteHandler(inviteService)
+		inviteHandler.RegisterRoutes(protectedAPI)
+		emailInvitationHandler := handlers.NewEmailInvitationHandler(emailInvitationService)
+		emailInvitationHandler.RegisterRoutes(protectedAPI)
+
 		// Initialize and register v1 group
 		v1 := protectedAPI.Group("/v1")
 
 		// Initialize the list handler
-		listHandler := handlers.NewListHandler(listService)
+		tribeAuthorizer := service.NewTribeAuthorizer(repos.Tribes)
+		listHandler := handlers.NewListHandler(listService, tribeAuthorizer, service.NewListAuthorizer(repos.Lists, tribeAuthorizer))
+
+		// Initialize the menu and voting handlers
+		menuHandler := handlers.NewMenuHandler(menuService)
+		votingHandler := handlers.NewVotingHandler(votingService)
 
 		// Register list routes directly with Gin
 		lists := v1.Group("/lists")
 
+		// Menu generation and picks
+		lists.POST("/menu", wrapHandler(menuHandler.GenerateMenu))
+		lists.GET("/menu/sessions", wrapHandler(menuHandler.ListSessions))
+		lists.GET("/menu/sessions/:sessionID", wrapHandler(menuHandler.GetSession))
+		lists.POST("/menu/sessions/:sessionID/pick", wrapHandler(menuHandler.PickItem))
+
+		// Tribe votes on menus
+		lists.POST("/menu/vote", wrapHandler(votingHandler.StartVote))
+		lists.POST("/menu/sessions/:sessionID/votes", wrapHandler(votingHandler.CastVotes))
+		lists.GET("/menu/sessions/:sessionID/votes", wrapHandler(votingHandler.GetTally))
+		lists.POST("/menu/sessions/:sessionID/close", wrapHandler(votingHandler.CloseVote))
+
 		// Basic list operations
 		lists.POST("", wrapHandler(listHandler.CreateList))
 		lists.GET("", wrapHandler(listHandler.ListLists))
@@ -341,6 +473,11 @@ func setupRouter(repos *postgres.Repositories, authMiddleware middleware.AuthMid
 		lists.POST("/:listID/share/:tribeID", wrapHandler(listHandler.ShareListWithTribe))
 		lists.DELETE("/:listID/share/:tribeID", wrapHandler(listHandler.UnshareListWithTribe))
 
+		// Sync
+		lists.POST("/:listID/sync", wrapHandler(listHandler.SyncList))
+		lists.GET("/:listID/conflicts", wrapHandler(listHandler.GetListConflicts))
+		lists.POST("/:listID/conflicts/:conflictID/resolve", wrapHandler(listHandler.ResolveListConflict))
+
 		// Admin endpoints
 		admin := lists.Group("/admin")
 		admin.POST("/cleanup-expired-shares", wrapHandler(listHandler.CleanupExpiredShares))
@@ -375,6 +512,14 @@ func wrapHandler(h func(http.ResponseWriter, *http.Request)) gin.HandlerFunc {
 		} else {
 			log.Printf("Wrapping handler: No user ID found in Gin context")
 		}
+		// Expose path parameters to handlers that read them through chi
+		if len(c.Params) > 0 {
+			routeCtx := chi.NewRouteContext()
+			for _, param := range c.Params {
+				routeCtx.URLParams.Add(param.Key, param.Value)
+			}
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), chi.RouteCtxKey, routeCtx))
+		}
 		h(c.Writer, c.Request)
 	}
 }
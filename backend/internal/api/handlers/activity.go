@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
 	"github.com/jenglund/rlship-tools/internal/middleware"
 	"github.com/jenglund/rlship-tools/internal/models"
 	"github.com/jenglund/rlship-tools/internal/repository/postgres"
@@ -15,11 +18,15 @@ import (
 // ActivityHandler handles activity-related requests
 type ActivityHandler struct {
 	repos *postgres.Repositories
+	authz service.TribeAuthorizer
 }
 
 // NewActivityHandler creates a new activity handler
 func NewActivityHandler(repos *postgres.Repositories) *ActivityHandler {
-	return &ActivityHandler{repos: repos}
+	return &ActivityHandler{
+		repos: repos,
+		authz: service.NewTribeAuthorizer(repos.Tribes),
+	}
 }
 
 // RegisterRoutes registers the activity routes
@@ -41,6 +48,22 @@ func (h *ActivityHandler) RegisterRoutes(r *gin.RouterGroup) {
 		activities.POST("/:id/share", h.ShareActivity)
 		activities.DELETE("/:id/share/:tribeID", h.UnshareActivity)
 		activities.GET("/shared", h.ListSharedActivities)
+
+		// Activity scheduling
+		activities.PUT("/:id/schedule", h.ScheduleActivity)
+		activities.GET("/:id/schedule", h.GetSchedule)
+		activities.DELETE("/:id/schedule", h.UnscheduleActivity)
+		activities.PUT("/:id/rsvp", h.SetRSVP)
+		activities.DELETE("/:id/rsvp", h.DeleteRSVP)
+		activities.GET("/:id/rsvps", h.ListRSVPs)
+
+		// Recurrence
+		activities.PUT("/:id/recurrence", h.SetRecurrence)
+		activities.DELETE("/:id/recurrence", h.ClearRecurrence)
+		activities.GET("/:id/occurrences", h.ListOccurrences)
+		activities.GET("/:id/exceptions", h.ListOccurrenceExceptions)
+		activities.PUT("/:id/exceptions", h.SetOccurrenceException)
+		activities.DELETE("/:id/exceptions", h.DeleteOccurrenceException)
 	}
 }
 
@@ -126,12 +149,35 @@ func (h *ActivityHandler) CreateActivity(c *gin.Context) {
 	response.GinCreated(c, activity)
 }
 
-// ListActivities returns a paginated list of activities
+// maxActivityPageSize caps the limit accepted by ListActivities
+const maxActivityPageSize = 100
+
+// ListActivities returns a page of the activities the current user owns,
+// directly or through their tribes
 func (h *ActivityHandler) ListActivities(c *gin.Context) {
-	offset := 0
-	limit := 50
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		response.GinBadRequest(c, "Page number must be greater than 0")
+		return
+	}
 
-	activities, err := h.repos.Activities.List(offset, limit)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		response.GinBadRequest(c, "Limit must be greater than 0")
+		return
+	}
+	if limit > maxActivityPageSize {
+		limit = maxActivityPageSize
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	activities, err := h.repos.Activities.ListForUser(userID, (page-1)*limit, limit)
 	if err != nil {
 		response.GinInternalError(c, err)
 		return
@@ -142,17 +188,17 @@ func (h *ActivityHandler) ListActivities(c *gin.Context) {
 
 // GetActivity returns a single activity by ID
 func (h *ActivityHandler) GetActivity(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+	activity, userID, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
 		return
 	}
 
-	activity, err := h.repos.Activities.GetByID(id)
+	creatorName, err := h.repos.Activities.GetCreatorName(activity.ID, userID)
 	if err != nil {
-		response.GinNotFound(c, "Activity not found")
+		response.GinInternalError(c, err)
 		return
 	}
+	activity.CreatorName = creatorName
 
 	response.GinSuccess(c, activity)
 }
@@ -173,19 +219,8 @@ func (h *ActivityHandler) UpdateActivity(c *gin.Context) {
 		return
 	}
 
-	activityID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
-		return
-	}
-
-	activity, err := h.repos.Activities.GetByID(activityID)
-	if err != nil {
-		if err.Error() == "activity not found" {
-			response.GinNotFound(c, "Activity not found")
-			return
-		}
-		response.GinInternalError(c, err)
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
@@ -224,13 +259,12 @@ func (h *ActivityHandler) UpdateActivity(c *gin.Context) {
 
 // DeleteActivity removes an activity
 func (h *ActivityHandler) DeleteActivity(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
-	if err := h.repos.Activities.Delete(id); err != nil {
+	if err := h.repos.Activities.Delete(activity.ID); err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
@@ -246,9 +280,8 @@ type AddOwnerRequest struct {
 
 // AddOwner adds an owner to an activity
 func (h *ActivityHandler) AddOwner(c *gin.Context) {
-	activityID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+	activity, userID, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
@@ -258,7 +291,15 @@ func (h *ActivityHandler) AddOwner(c *gin.Context) {
 		return
 	}
 
-	if err := h.repos.Activities.AddOwner(activityID, req.OwnerID, req.OwnerType); err != nil {
+	// Handing an activity to a tribe needs the right to contribute to it
+	if req.OwnerType == models.OwnerTypeTribe {
+		if _, err := h.authz.Authorize(userID, req.OwnerID, models.PermissionContribute); err != nil {
+			writeTribeAuthError(c, err, "Your membership does not allow adding activities to this tribe")
+			return
+		}
+	}
+
+	if err := h.repos.Activities.AddOwner(activity.ID, req.OwnerID, req.OwnerType); err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
@@ -268,19 +309,18 @@ func (h *ActivityHandler) AddOwner(c *gin.Context) {
 
 // RemoveOwner removes an owner from an activity
 func (h *ActivityHandler) RemoveOwner(c *gin.Context) {
-	activityID, err := uuid.Parse(c.Param("id"))
+	ownerID, err := uuid.Parse(c.Param("ownerId"))
 	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+		response.GinBadRequest(c, "Invalid owner ID")
 		return
 	}
 
-	ownerID, err := uuid.Parse(c.Param("ownerId"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid owner ID")
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
-	if err := h.repos.Activities.RemoveOwner(activityID, ownerID); err != nil {
+	if err := h.repos.Activities.RemoveOwner(activity.ID, ownerID); err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
@@ -290,13 +330,12 @@ func (h *ActivityHandler) RemoveOwner(c *gin.Context) {
 
 // ListOwners returns all owners of an activity
 func (h *ActivityHandler) ListOwners(c *gin.Context) {
-	activityID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+	activity, _, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
 		return
 	}
 
-	owners, err := h.repos.Activities.GetOwners(activityID)
+	owners, err := h.repos.Activities.GetOwners(activity.ID)
 	if err != nil {
 		response.GinInternalError(c, err)
 		return
@@ -311,11 +350,10 @@ type ShareActivityRequest struct {
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
-// ShareActivity shares an activity with a tribe
+// ShareActivity shares an activity with a tribe the current user belongs to
 func (h *ActivityHandler) ShareActivity(c *gin.Context) {
-	activityID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+	activity, userID, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
@@ -325,20 +363,12 @@ func (h *ActivityHandler) ShareActivity(c *gin.Context) {
 		return
 	}
 
-	// Get the current user's ID from Firebase UID
-	firebaseUID := middleware.GetFirebaseUID(c)
-	if firebaseUID == "" {
-		response.GinInternalError(c, fmt.Errorf("firebase UID not found in context"))
+	if _, err := h.authz.Authorize(userID, req.TribeID, models.PermissionContribute); err != nil {
+		writeTribeAuthError(c, err, "Your membership does not allow sharing with this tribe")
 		return
 	}
 
-	user, err := h.repos.Users.GetByFirebaseUID(firebaseUID)
-	if err != nil {
-		response.GinInternalError(c, err)
-		return
-	}
-
-	if err := h.repos.Activities.ShareWithTribe(activityID, req.TribeID, user.ID, req.ExpiresAt); err != nil {
+	if err := h.repos.Activities.ShareWithTribe(activity.ID, req.TribeID, userID, req.ExpiresAt); err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
@@ -348,19 +378,18 @@ func (h *ActivityHandler) ShareActivity(c *gin.Context) {
 
 // UnshareActivity removes an activity share from a tribe
 func (h *ActivityHandler) UnshareActivity(c *gin.Context) {
-	activityID, err := uuid.Parse(c.Param("id"))
+	tribeID, err := uuid.Parse(c.Param("tribeID"))
 	if err != nil {
-		response.GinBadRequest(c, "Invalid activity ID")
+		response.GinBadRequest(c, "Invalid tribe ID")
 		return
 	}
 
-	tribeID, err := uuid.Parse(c.Param("tribeID"))
-	if err != nil {
-		response.GinBadRequest(c, "Invalid tribe ID")
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
 		return
 	}
 
-	if err := h.repos.Activities.UnshareWithTribe(activityID, tribeID); err != nil {
+	if err := h.repos.Activities.UnshareWithTribe(activity.ID, tribeID); err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
@@ -368,33 +397,41 @@ func (h *ActivityHandler) UnshareActivity(c *gin.Context) {
 	response.GinNoContent(c)
 }
 
-// ListSharedActivities returns all activities shared with the user's tribes
+// ListSharedActivities returns the activities shared with the tribes the
+// current user actively belongs to, optionally narrowed to one tribe with the
+// tribe_id query parameter
 func (h *ActivityHandler) ListSharedActivities(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
-		response.GinInternalError(c, fmt.Errorf("user ID not found in context"))
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinInternalError(c, err)
 		return
 	}
 
-	uid, err := uuid.Parse(userID)
+	tribes, err := h.memberTribes(userID)
 	if err != nil {
 		response.GinInternalError(c, err)
 		return
 	}
 
-	// Get user's tribes
-	tribes, err := h.repos.Tribes.GetUserTribes(uid)
-	if err != nil {
-		response.GinSuccess(c, []*models.Activity{})
-		return
+	if tribeParam := c.Query("tribe_id"); tribeParam != "" {
+		tribeID, parseErr := uuid.Parse(tribeParam)
+		if parseErr != nil {
+			response.GinBadRequest(c, "Invalid tribe ID")
+			return
+		}
+		if !tribes[tribeID] {
+			response.GinForbidden(c, "You are not a member of this tribe")
+			return
+		}
+		tribes = map[uuid.UUID]bool{tribeID: true}
 	}
 
 	// Use a map to deduplicate activities by ID
 	activityMap := make(map[uuid.UUID]*models.Activity)
 
 	// Get shared activities for each tribe
-	for _, tribe := range tribes {
-		activities, sharedActivitiesErr := h.repos.Activities.GetSharedActivities(tribe.ID)
+	for tribeID := range tribes {
+		activities, sharedActivitiesErr := h.repos.Activities.GetSharedActivities(tribeID)
 		if sharedActivitiesErr != nil {
 			continue
 		}
@@ -405,11 +442,14 @@ func (h *ActivityHandler) ListSharedActivities(c *gin.Context) {
 		}
 	}
 
-	// Convert map to slice
-	var deduplicatedActivities []*models.Activity
+	// Convert map to slice, newest first
+	deduplicatedActivities := make([]*models.Activity, 0, len(activityMap))
 	for _, activity := range activityMap {
 		deduplicatedActivities = append(deduplicatedActivities, activity)
 	}
+	sort.Slice(deduplicatedActivities, func(i, j int) bool {
+		return deduplicatedActivities[i].CreatedAt.After(deduplicatedActivities[j].CreatedAt)
+	})
 
 	response.GinSuccess(c, deduplicatedActivities)
 }
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// activityAccess is the level of access a user has to an activity
+type activityAccess int
+
+const (
+	// activityAccessNone hides the activity from the user
+	activityAccessNone activityAccess = iota
+	// activityAccessView lets the user read the activity
+	activityAccessView
+	// activityAccessEdit lets the user change, share and delete the activity
+	activityAccessEdit
+)
+
+// memberTribes returns the IDs of the tribes the user actively belongs to,
+// leaving out pending invitations and expired guest memberships
+func (h *ActivityHandler) memberTribes(userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	return h.authz.AuthorizedTribes(userID, models.PermissionViewTribe)
+}
+
+// accessFor works out a user's access to an activity. Owners may edit it
+// directly, or through a tribe whose membership lets them contribute; other
+// members of an owning tribe may view it. Anyone may view a public activity,
+// and members of a tribe it is shared with may view a shared one.
+func (h *ActivityHandler) accessFor(userID uuid.UUID, activity *models.Activity) (activityAccess, error) {
+	owners, err := h.repos.Activities.GetOwners(activity.ID)
+	if err != nil {
+		return activityAccessNone, fmt.Errorf("error getting activity owners: %w", err)
+	}
+
+	var tribeOwners []uuid.UUID
+	for _, owner := range owners {
+		switch owner.OwnerType {
+		case models.OwnerTypeUser:
+			if owner.OwnerID == userID {
+				return activityAccessEdit, nil
+			}
+		case models.OwnerTypeTribe:
+			tribeOwners = append(tribeOwners, owner.OwnerID)
+		}
+	}
+
+	var tribes map[uuid.UUID]bool
+	if len(tribeOwners) > 0 {
+		contributing, contributeErr := h.authz.AuthorizedTribes(userID, models.PermissionContribute)
+		if contributeErr != nil {
+			return activityAccessNone, contributeErr
+		}
+		if tribes, err = h.memberTribes(userID); err != nil {
+			return activityAccessNone, err
+		}
+
+		access := activityAccessNone
+		for _, tribeID := range tribeOwners {
+			if contributing[tribeID] {
+				return activityAccessEdit, nil
+			}
+			if tribes[tribeID] {
+				access = activityAccessView
+			}
+		}
+		if access != activityAccessNone {
+			return access, nil
+		}
+	}
+
+	switch activity.Visibility {
+	case models.VisibilityPublic:
+		return activityAccessView, nil
+	case models.VisibilityShared:
+		if tribes == nil {
+			if tribes, err = h.memberTribes(userID); err != nil {
+				return activityAccessNone, err
+			}
+		}
+		for tribeID := range tribes {
+			shared, sharedErr := h.repos.Activities.GetSharedActivities(tribeID)
+			if sharedErr != nil {
+				return activityAccessNone, fmt.Errorf("error getting shared activities: %w", sharedErr)
+			}
+			for _, a := range shared {
+				if a.ID == activity.ID {
+					return activityAccessView, nil
+				}
+			}
+		}
+	}
+
+	return activityAccessNone, nil
+}
+
+// authorizeActivity loads the activity named by the id path parameter and
+// checks the current user has at least the needed access, writing the error
+// response when they do not. Activities the user cannot see are reported as
+// not found.
+func (h *ActivityHandler) authorizeActivity(c *gin.Context, need activityAccess) (*models.Activity, uuid.UUID, bool) {
+	activityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid activity ID")
+		return nil, uuid.Nil, false
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return nil, uuid.Nil, false
+	}
+
+	activity, err := h.repos.Activities.GetByID(activityID)
+	if err != nil {
+		if err.Error() == "activity not found" {
+			response.GinNotFound(c, "Activity not found")
+			return nil, uuid.Nil, false
+		}
+		response.GinInternalError(c, err)
+		return nil, uuid.Nil, false
+	}
+
+	access, err := h.accessFor(userID, activity)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return nil, uuid.Nil, false
+	}
+
+	switch {
+	case access == activityAccessNone:
+		response.GinNotFound(c, "Activity not found")
+		return nil, uuid.Nil, false
+	case access < need:
+		response.GinForbidden(c, "Only activity owners can do this")
+		return nil, uuid.Nil, false
+	}
+
+	return activity, userID, true
+}
+
+// canRSVP reports whether the user belongs to a tribe the activity is
+// shared with; only those members may RSVP
+func (h *ActivityHandler) canRSVP(userID, activityID uuid.UUID) (bool, error) {
+	tribes, err := h.memberTribes(userID)
+	if err != nil {
+		return false, err
+	}
+	for tribeID := range tribes {
+		shared, sharedErr := h.repos.Activities.GetSharedActivities(tribeID)
+		if sharedErr != nil {
+			return false, fmt.Errorf("error getting shared activities: %w", sharedErr)
+		}
+		for _, a := range shared {
+			if a.ID == activityID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+)
+
+// multipartOverhead allows for the form fields and boundaries around a photo
+const multipartOverhead = 1 << 20
+
+// ActivityPhotoHandler handles photo uploads for activities
+type ActivityPhotoHandler struct {
+	activities *ActivityHandler
+	photos     service.PhotoService
+}
+
+// NewActivityPhotoHandler creates a new activity photo handler
+func NewActivityPhotoHandler(repos *postgres.Repositories, photos service.PhotoService) *ActivityPhotoHandler {
+	return &ActivityPhotoHandler{
+		activities: NewActivityHandler(repos),
+		photos:     photos,
+	}
+}
+
+// RegisterRoutes registers the authenticated activity photo routes
+func (h *ActivityPhotoHandler) RegisterRoutes(r *gin.RouterGroup) {
+	activities := r.Group("/activities")
+	{
+		activities.POST("/:id/photos", h.UploadPhoto)
+		activities.GET("/:id/photos", h.ListPhotos)
+		activities.DELETE("/:id/photos/:photoID", h.DeletePhoto)
+	}
+}
+
+// RegisterPublicRoutes registers the route serving signed photo URLs. The
+// signature authorizes the request, so it needs no authentication.
+func (h *ActivityPhotoHandler) RegisterPublicRoutes(r *gin.RouterGroup) {
+	r.GET("/photos/*key", h.ServePhoto)
+}
+
+// UploadPhoto stores a photo sent as the "photo" field of a multipart form,
+// with an optional "caption" field
+func (h *ActivityPhotoHandler) UploadPhoto(c *gin.Context) {
+	activity, _, ok := h.activities.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.photos.MaxSize()+multipartOverhead)
+	file, _, err := c.Request.FormFile("photo")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.GinError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "Photo is too large")
+			return
+		}
+		response.GinBadRequest(c, "A photo file is required")
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.photos.Upload(c.Request.Context(), activity.ID, file, c.Request.FormValue("caption"))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrFileTooLarge):
+			response.GinError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "Photo is too large")
+		case errors.Is(err, models.ErrUnsupportedMediaType):
+			response.GinError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "Photos must be JPEG or PNG images")
+		case errors.Is(err, models.ErrInvalidInput):
+			response.GinBadRequest(c, "Invalid image file")
+		default:
+			response.GinInternalError(c, err)
+		}
+		return
+	}
+
+	response.GinCreated(c, photo)
+}
+
+// ListPhotos returns the photos of an activity with signed URLs
+func (h *ActivityPhotoHandler) ListPhotos(c *gin.Context) {
+	activity, _, ok := h.activities.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	photos, err := h.photos.ListPhotos(activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, photos)
+}
+
+// DeletePhoto removes a photo from an activity
+func (h *ActivityPhotoHandler) DeletePhoto(c *gin.Context) {
+	photoID, err := uuid.Parse(c.Param("photoID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid photo ID")
+		return
+	}
+
+	activity, _, ok := h.activities.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.photos.DeletePhoto(c.Request.Context(), activity.ID, photoID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Photo not found")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// ServePhoto streams a stored photo named by a signed URL
+func (h *ActivityPhotoHandler) ServePhoto(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	blob, err := h.photos.OpenSigned(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidSignature):
+			response.GinForbidden(c, "Invalid or expired photo URL")
+		case errors.Is(err, models.ErrNotFound):
+			response.GinNotFound(c, "Photo not found")
+		default:
+			response.GinInternalError(c, err)
+		}
+		return
+	}
+	defer blob.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, -1, contentType, blob, map[string]string{
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// defaultOccurrenceWindow is how far ahead occurrences are expanded when
+	// no end is given
+	defaultOccurrenceWindow = 30 * 24 * time.Hour
+	// maxOccurrenceWindow caps how wide an expansion request may be
+	maxOccurrenceWindow = 366 * 24 * time.Hour
+)
+
+// SetRecurrenceRequest represents the set recurrence request body
+type SetRecurrenceRequest struct {
+	RRule   string     `json:"rrule" binding:"required"`
+	DTStart *time.Time `json:"dtstart"`
+}
+
+// OccurrenceExceptionRequest represents the occurrence exception request body
+type OccurrenceExceptionRequest struct {
+	OriginalStart time.Time  `json:"original_start" binding:"required"`
+	Cancelled     bool       `json:"cancelled"`
+	StartTime     *time.Time `json:"start_time"`
+	EndTime       *time.Time `json:"end_time"`
+}
+
+// SetRecurrence makes a scheduled activity repeat. DTSTART defaults to the
+// start of the schedule and is interpreted in the schedule's time zone.
+func (h *ActivityHandler) SetRecurrence(c *gin.Context) {
+	var req SetRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	schedule, ok := h.requireSchedule(c, activity)
+	if !ok {
+		return
+	}
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	dtstart := schedule.StartTime
+	if req.DTStart != nil {
+		dtstart = *req.DTStart
+	}
+	rule, err := models.ParseRecurrenceRule(req.RRule, loc)
+	if err != nil {
+		response.GinBadRequest(c, err.Error())
+		return
+	}
+
+	if err = h.repos.Activities.SetRecurrence(activity.ID, rule.String(), &dtstart); err != nil {
+		h.writeRecurrenceError(c, err)
+		return
+	}
+
+	updated, err := h.repos.Activities.GetByID(activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, updated)
+}
+
+// ClearRecurrence stops an activity from repeating and drops its exceptions
+func (h *ActivityHandler) ClearRecurrence(c *gin.Context) {
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.repos.Activities.SetRecurrence(activity.ID, "", nil); err != nil {
+		h.writeRecurrenceError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// ListOccurrences expands the occurrences of a scheduled activity that
+// overlap the window given by the from and to query parameters
+func (h *ActivityHandler) ListOccurrences(c *gin.Context) {
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.GinBadRequest(c, "from must be an RFC 3339 timestamp")
+			return
+		}
+		from = parsed
+	}
+	to := from.Add(defaultOccurrenceWindow)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.GinBadRequest(c, "to must be an RFC 3339 timestamp")
+			return
+		}
+		to = parsed
+	}
+	if !to.After(from) {
+		response.GinBadRequest(c, "to must be after from")
+		return
+	}
+	if to.Sub(from) > maxOccurrenceWindow {
+		response.GinBadRequest(c, "The window may span at most 366 days")
+		return
+	}
+
+	activity, _, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	schedule, ok := h.requireSchedule(c, activity)
+	if !ok {
+		return
+	}
+	exceptions, err := h.repos.Activities.GetOccurrenceExceptions(activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	occurrences, err := models.ExpandOccurrences(activity, schedule, exceptions, from, to)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, occurrences)
+}
+
+// ListOccurrenceExceptions returns the cancelled and moved instances of an
+// activity
+func (h *ActivityHandler) ListOccurrenceExceptions(c *gin.Context) {
+	activity, _, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	exceptions, err := h.repos.Activities.GetOccurrenceExceptions(activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, exceptions)
+}
+
+// SetOccurrenceException cancels or moves one instance of a recurring
+// activity
+func (h *ActivityHandler) SetOccurrenceException(c *gin.Context) {
+	var req OccurrenceExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+	if activity.RecurrenceRule == "" {
+		response.GinBadRequest(c, "Activity does not recur")
+		return
+	}
+
+	schedule, ok := h.requireSchedule(c, activity)
+	if !ok {
+		return
+	}
+
+	exception := &models.OccurrenceException{
+		ActivityID:    activity.ID,
+		OriginalStart: req.OriginalStart,
+		Cancelled:     req.Cancelled,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+	}
+	if err := exception.Validate(); err != nil {
+		response.GinBadRequest(c, err.Error())
+		return
+	}
+
+	isInstance, err := isOccurrence(activity, schedule, req.OriginalStart)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+	if !isInstance {
+		response.GinBadRequest(c, "original_start is not an occurrence of this activity")
+		return
+	}
+
+	if err = h.repos.Activities.SetOccurrenceException(exception); err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, exception)
+}
+
+// DeleteOccurrenceException restores the instance starting at the
+// original_start query parameter
+func (h *ActivityHandler) DeleteOccurrenceException(c *gin.Context) {
+	originalStart, err := time.Parse(time.RFC3339, c.Query("original_start"))
+	if err != nil {
+		response.GinBadRequest(c, "original_start must be an RFC 3339 timestamp")
+		return
+	}
+
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	if err = h.repos.Activities.DeleteOccurrenceException(activity.ID, originalStart); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Occurrence exception not found")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// requireSchedule loads the schedule of an activity, writing a not found
+// response when it has none
+func (h *ActivityHandler) requireSchedule(c *gin.Context, activity *models.Activity) (*models.ActivitySchedule, bool) {
+	schedule, err := h.repos.Activities.GetSchedule(activity.ID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Activity is not scheduled")
+			return nil, false
+		}
+		response.GinInternalError(c, err)
+		return nil, false
+	}
+	return schedule, true
+}
+
+func (h *ActivityHandler) writeRecurrenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.GinNotFound(c, "Activity not found")
+	case errors.Is(err, models.ErrInvalidInput):
+		response.GinBadRequest(c, err.Error())
+	default:
+		response.GinInternalError(c, err)
+	}
+}
+
+// isOccurrence reports whether the recurrence of the activity has an
+// instance starting at t
+func isOccurrence(activity *models.Activity, schedule *models.ActivitySchedule, t time.Time) (bool, error) {
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("error loading time zone: %w", err)
+	}
+	rule, err := models.ParseRecurrenceRule(activity.RecurrenceRule, loc)
+	if err != nil {
+		return false, err
+	}
+	dtstart := schedule.StartTime
+	if activity.RecurrenceStart != nil {
+		dtstart = *activity.RecurrenceStart
+	}
+	return rule.Includes(dtstart.In(loc), t.In(loc)), nil
+}
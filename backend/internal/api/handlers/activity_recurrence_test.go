@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRecurrence(t *testing.T) {
+	router, repos, testUser := setupActivityTest(t)
+
+	activity := &models.Activity{
+		Type:       models.ActivityTypeEvent,
+		Name:       "Game Night",
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+		UserID:     testUser.ID,
+	}
+	require.NoError(t, repos.Activities.Create(activity))
+	require.NoError(t, repos.Activities.AddOwner(activity.ID, testUser.ID, "user"))
+
+	base := "/api/activities/" + activity.ID.String()
+	start := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	occurrences := func(from, to time.Time) []models.Occurrence {
+		query := url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}}
+		w := do(http.MethodGet, base+"/occurrences?"+query.Encode(), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data []models.Occurrence `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	t.Run("recurrence requires a schedule", func(t *testing.T) {
+		w := do(http.MethodPut, base+"/recurrence", SetRecurrenceRequest{RRule: "FREQ=WEEKLY"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	require.NoError(t, repos.Activities.SetSchedule(&models.ActivitySchedule{
+		ActivityID: activity.ID,
+		StartTime:  start,
+		EndTime:    start.Add(3 * time.Hour),
+		TimeZone:   "UTC",
+	}))
+
+	t.Run("invalid rule", func(t *testing.T) {
+		w := do(http.MethodPut, base+"/recurrence", SetRecurrenceRequest{RRule: "FREQ=HOURLY"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("set recurrence", func(t *testing.T) {
+		w := do(http.MethodPut, base+"/recurrence", SetRecurrenceRequest{RRule: "freq=weekly;byday=fr"})
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data models.Activity `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=FR", resp.Data.RecurrenceRule)
+		require.NotNil(t, resp.Data.RecurrenceStart)
+		assert.True(t, start.Equal(*resp.Data.RecurrenceStart))
+	})
+
+	t.Run("expand occurrences", func(t *testing.T) {
+		got := occurrences(start, start.AddDate(0, 0, 28))
+		require.Len(t, got, 4)
+		assert.True(t, start.AddDate(0, 0, 7).Equal(got[1].StartTime))
+	})
+
+	t.Run("cancel an occurrence", func(t *testing.T) {
+		w := do(http.MethodPut, base+"/exceptions", OccurrenceExceptionRequest{
+			OriginalStart: start.AddDate(0, 0, 7),
+			Cancelled:     true,
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		got := occurrences(start, start.AddDate(0, 0, 28))
+		assert.Len(t, got, 3)
+	})
+
+	t.Run("move an occurrence", func(t *testing.T) {
+		moved := start.AddDate(0, 0, 15)
+		movedEnd := moved.Add(2 * time.Hour)
+		w := do(http.MethodPut, base+"/exceptions", OccurrenceExceptionRequest{
+			OriginalStart: start.AddDate(0, 0, 14),
+			StartTime:     &moved,
+			EndTime:       &movedEnd,
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		got := occurrences(start, start.AddDate(0, 0, 28))
+		require.Len(t, got, 3)
+		assert.True(t, moved.Equal(got[1].StartTime))
+		assert.True(t, got[1].Moved)
+	})
+
+	t.Run("exception must name an occurrence", func(t *testing.T) {
+		w := do(http.MethodPut, base+"/exceptions", OccurrenceExceptionRequest{
+			OriginalStart: start.Add(time.Hour),
+			Cancelled:     true,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("restore an occurrence", func(t *testing.T) {
+		query := url.Values{"original_start": {start.AddDate(0, 0, 7).Format(time.RFC3339)}}
+		w := do(http.MethodDelete, base+"/exceptions?"+query.Encode(), nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodGet, base+"/exceptions", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data []models.OccurrenceException `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("window too wide", func(t *testing.T) {
+		query := url.Values{"from": {start.Format(time.RFC3339)}, "to": {start.AddDate(2, 0, 0).Format(time.RFC3339)}}
+		w := do(http.MethodGet, base+"/occurrences?"+query.Encode(), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("clear recurrence", func(t *testing.T) {
+		w := do(http.MethodDelete, base+"/recurrence", nil)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		got := occurrences(start, start.AddDate(0, 0, 28))
+		assert.Len(t, got, 1)
+	})
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// ScheduleActivityRequest represents the schedule activity request body
+type ScheduleActivityRequest struct {
+	StartTime  time.Time           `json:"start_time" binding:"required"`
+	EndTime    time.Time           `json:"end_time" binding:"required"`
+	TimeZone   string              `json:"time_zone" binding:"required"`
+	ListItemID *uuid.UUID          `json:"list_item_id"`
+	Location   *models.LocationRef `json:"location"`
+}
+
+// RSVPRequest represents the RSVP request body
+type RSVPRequest struct {
+	Status models.RSVPStatus `json:"status" binding:"required"`
+}
+
+// ScheduleActivity sets or replaces when and where an activity happens
+func (h *ActivityHandler) ScheduleActivity(c *gin.Context) {
+	var req ScheduleActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	schedule := &models.ActivitySchedule{
+		ActivityID: activity.ID,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		TimeZone:   req.TimeZone,
+		ListItemID: req.ListItemID,
+		Location:   req.Location,
+	}
+	if err := schedule.Validate(); err != nil {
+		response.GinBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.repos.Activities.SetSchedule(schedule); err != nil {
+		if errors.Is(err, models.ErrInvalidInput) {
+			response.GinBadRequest(c, err.Error())
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, schedule)
+}
+
+// GetSchedule returns the schedule of an activity
+func (h *ActivityHandler) GetSchedule(c *gin.Context) {
+	activity, _, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.repos.Activities.GetSchedule(activity.ID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Activity is not scheduled")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, schedule)
+}
+
+// UnscheduleActivity removes the schedule of an activity and its RSVPs
+func (h *ActivityHandler) UnscheduleActivity(c *gin.Context) {
+	activity, _, ok := h.authorizeActivity(c, activityAccessEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.repos.Activities.DeleteSchedule(activity.ID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Activity is not scheduled")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// SetRSVP records the current user's answer to a scheduled activity. Only
+// members of a tribe the activity is shared with may RSVP.
+func (h *ActivityHandler) SetRSVP(c *gin.Context) {
+	var req RSVPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+	if err := req.Status.Validate(); err != nil {
+		response.GinBadRequest(c, "Status must be yes, no or maybe")
+		return
+	}
+
+	activity, userID, ok := h.authorizeRSVP(c)
+	if !ok {
+		return
+	}
+
+	rsvp := &models.ActivityRSVP{
+		ActivityID: activity.ID,
+		UserID:     userID,
+		Status:     req.Status,
+	}
+	if err := h.repos.Activities.SetRSVP(rsvp); err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, rsvp)
+}
+
+// DeleteRSVP withdraws the current user's RSVP
+func (h *ActivityHandler) DeleteRSVP(c *gin.Context) {
+	activity, userID, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	if err := h.repos.Activities.DeleteRSVP(activity.ID, userID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "RSVP not found")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// ListRSVPs returns every RSVP to an activity
+func (h *ActivityHandler) ListRSVPs(c *gin.Context) {
+	activity, _, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return
+	}
+
+	rsvps, err := h.repos.Activities.GetRSVPs(activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, rsvps)
+}
+
+// authorizeRSVP loads a scheduled activity the current user may RSVP to,
+// writing the error response when they may not
+func (h *ActivityHandler) authorizeRSVP(c *gin.Context) (*models.Activity, uuid.UUID, bool) {
+	activity, userID, ok := h.authorizeActivity(c, activityAccessView)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	if _, err := h.repos.Activities.GetSchedule(activity.ID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinBadRequest(c, "Activity is not scheduled")
+			return nil, uuid.Nil, false
+		}
+		response.GinInternalError(c, err)
+		return nil, uuid.Nil, false
+	}
+
+	allowed, err := h.canRSVP(userID, activity.ID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return nil, uuid.Nil, false
+	}
+	if !allowed {
+		response.GinForbidden(c, "Only members of tribes this activity is shared with can RSVP")
+		return nil, uuid.Nil, false
+	}
+
+	return activity, userID, true
+}
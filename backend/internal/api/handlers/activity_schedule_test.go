@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityScheduling(t *testing.T) {
+	router, repos, testUser := setupActivityTest(t)
+
+	owner := testutil.CreateTestUser(t, repos.DB())
+	tribe := testutil.CreateTestTribe(t, repos.DB(), []testutil.TestUser{owner, *testUser})
+	outsider := testutil.CreateTestUser(t, repos.DB())
+
+	// The owner's activity is shared with a tribe the test user belongs to
+	shared := &models.Activity{
+		Type:       models.ActivityTypeEvent,
+		Name:       "Game Night",
+		Visibility: models.VisibilityShared,
+		Metadata:   models.JSONMap{},
+		UserID:     owner.ID,
+	}
+	require.NoError(t, repos.Activities.Create(shared))
+	require.NoError(t, repos.Activities.AddOwner(shared.ID, owner.ID, "user"))
+	require.NoError(t, repos.Activities.ShareWithTribe(shared.ID, tribe.ID, owner.ID, nil))
+
+	// The test user's own activity is not shared anywhere
+	own := &models.Activity{
+		Type:       models.ActivityTypeEvent,
+		Name:       "Solo Hike",
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+		UserID:     testUser.ID,
+	}
+	require.NoError(t, repos.Activities.Create(own))
+	require.NoError(t, repos.Activities.AddOwner(own.ID, testUser.ID, "user"))
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	require.NoError(t, repos.Activities.SetSchedule(&models.ActivitySchedule{
+		ActivityID: shared.ID,
+		StartTime:  start,
+		EndTime:    start.Add(3 * time.Hour),
+		TimeZone:   "UTC",
+	}))
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("schedule own activity", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+own.ID.String()+"/schedule", ScheduleActivityRequest{
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+			TimeZone:  "Europe/Berlin",
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = do(http.MethodGet, "/api/activities/"+own.ID.String()+"/schedule", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data models.ActivitySchedule `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Europe/Berlin", resp.Data.TimeZone)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+own.ID.String()+"/schedule", ScheduleActivityRequest{
+			StartTime: start,
+			EndTime:   start.Add(-time.Hour),
+			TimeZone:  "UTC",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non-owner cannot schedule", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+shared.ID.String()+"/schedule", ScheduleActivityRequest{
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+			TimeZone:  "UTC",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("tribe member can RSVP", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+shared.ID.String()+"/rsvp", RSVPRequest{Status: models.RSVPMaybe})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = do(http.MethodPut, "/api/activities/"+shared.ID.String()+"/rsvp", RSVPRequest{Status: models.RSVPYes})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = do(http.MethodGet, "/api/activities/"+shared.ID.String()+"/rsvps", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data []models.ActivityRSVP `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, testUser.ID, resp.Data[0].UserID)
+		assert.Equal(t, models.RSVPYes, resp.Data[0].Status)
+	})
+
+	t.Run("invalid RSVP status", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+shared.ID.String()+"/rsvp", RSVPRequest{Status: "perhaps"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("cannot RSVP to activity not shared with a tribe", func(t *testing.T) {
+		w := do(http.MethodPut, "/api/activities/"+own.ID.String()+"/rsvp", RSVPRequest{Status: models.RSVPYes})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("withdraw RSVP", func(t *testing.T) {
+		w := do(http.MethodDelete, "/api/activities/"+shared.ID.String()+"/rsvp", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodDelete, "/api/activities/"+shared.ID.String()+"/rsvp", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unschedule", func(t *testing.T) {
+		w := do(http.MethodDelete, "/api/activities/"+own.ID.String()+"/schedule", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodGet, "/api/activities/"+own.ID.String()+"/schedule", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = do(http.MethodPut, "/api/activities/"+own.ID.String()+"/rsvp", RSVPRequest{Status: models.RSVPYes})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("outsider cannot RSVP", func(t *testing.T) {
+		outsiderRouter := gin.New()
+		outsiderRouter.Use(func(c *gin.Context) {
+			c.Set("user_id", outsider.ID.String())
+			c.Next()
+		})
+		NewActivityHandler(repos).RegisterRoutes(outsiderRouter.Group("/api"))
+
+		body, err := json.Marshal(RSVPRequest{Status: models.RSVPYes})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/api/activities/"+shared.ID.String()+"/rsvp", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		outsiderRouter.ServeHTTP(w, req)
+
+		// Shared activities are invisible outside their tribes
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -409,7 +409,7 @@ func TestDeleteActivity(t *testing.T) {
 		{
 			name:       "non-existent activity",
 			activityID: uuid.New().String(),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusNotFound,
 		},
 		{
 			name:       "invalid uuid",
@@ -497,7 +497,7 @@ func TestShareActivity(t *testing.T) {
 			request: ShareActivityRequest{
 				TribeID: tribe.ID,
 			},
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusNotFound,
 			setupAuth:  true,
 		},
 		{
@@ -1045,7 +1045,7 @@ func TestAddOwner(t *testing.T) {
 				OwnerID:   tribe.ID,
 				OwnerType: "tribe",
 			},
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusNotFound,
 		},
 		{
 			name:       "invalid owner type",
@@ -1141,7 +1141,7 @@ func TestRemoveOwner(t *testing.T) {
 			name:       "nonexistent activity",
 			activityID: uuid.New().String(),
 			ownerID:    otherUser.ID.String(),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusNotFound,
 		},
 	}
 
@@ -1214,7 +1214,7 @@ func TestListOwners(t *testing.T) {
 		{
 			name:       "nonexistent activity",
 			activityID: uuid.New().String(),
-			wantStatus: http.StatusOK, // The handler doesn't check if activity exists
+			wantStatus: http.StatusNotFound,
 		},
 	}
 
@@ -1312,7 +1312,7 @@ func TestUnshareActivity(t *testing.T) {
 			name:       "nonexistent activity",
 			activityID: uuid.New().String(),
 			tribeID:    tribe.ID.String(),
-			wantStatus: http.StatusNoContent, // The handler doesn't check if activity exists
+			wantStatus: http.StatusNotFound,
 		},
 	}
 
@@ -1327,3 +1327,82 @@ func TestUnshareActivity(t *testing.T) {
 		})
 	}
 }
+
+func TestActivityAuthorization(t *testing.T) {
+	router, repos, testUser := setupActivityTest(t)
+
+	otherUser := testutil.CreateTestUser(t, repos.DB())
+	otherTribe := testutil.CreateTestTribe(t, repos.DB(), []testutil.TestUser{otherUser})
+
+	createOwned := func(visibility models.VisibilityType, ownerID uuid.UUID) *models.Activity {
+		activity := &models.Activity{
+			Type:       models.ActivityTypeLocation,
+			Name:       "Other Activity",
+			Visibility: visibility,
+			Metadata:   models.JSONMap{},
+			UserID:     ownerID,
+		}
+		require.NoError(t, repos.Activities.Create(activity))
+		require.NoError(t, repos.Activities.AddOwner(activity.ID, ownerID, "user"))
+		return activity
+	}
+
+	private := createOwned(models.VisibilityPrivate, otherUser.ID)
+	public := createOwned(models.VisibilityPublic, otherUser.ID)
+	own := createOwned(models.VisibilityPrivate, testUser.ID)
+
+	shareBody, err := json.Marshal(ShareActivityRequest{TribeID: otherTribe.ID})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "private activity of another user is hidden",
+			method:     http.MethodGet,
+			path:       "/api/activities/" + private.ID.String(),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "public activity of another user is visible",
+			method:     http.MethodGet,
+			path:       "/api/activities/" + public.ID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "non-owner cannot delete a public activity",
+			method:     http.MethodDelete,
+			path:       "/api/activities/" + public.ID.String(),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "cannot share with a tribe the user does not belong to",
+			method:     http.MethodPost,
+			path:       "/api/activities/" + own.ID.String() + "/share",
+			body:       shareBody,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "invalid page",
+			method:     http.MethodGet,
+			path:       "/api/activities?page=0",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBuffer(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
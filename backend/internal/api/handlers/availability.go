@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+)
+
+const (
+	// defaultSlotWindow is how far ahead slots are proposed when no end is given
+	defaultSlotWindow = 7 * 24 * time.Hour
+	// defaultSlotDuration is the meeting length when none is given
+	defaultSlotDuration = time.Hour
+)
+
+// AvailabilityHandler handles imported calendars and tribe slot proposals
+type AvailabilityHandler struct {
+	activities   *ActivityHandler
+	availability service.AvailabilityService
+}
+
+// NewAvailabilityHandler creates a new availability handler
+func NewAvailabilityHandler(repos *postgres.Repositories, availability service.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		activities:   NewActivityHandler(repos),
+		availability: availability,
+	}
+}
+
+// RegisterRoutes registers the availability routes
+func (h *AvailabilityHandler) RegisterRoutes(r *gin.RouterGroup) {
+	availability := r.Group("/availability")
+	{
+		availability.POST("/calendars", h.ImportCalendar)
+		availability.GET("/calendars", h.ListCalendars)
+		availability.POST("/calendars/:calendarID/refresh", h.RefreshCalendar)
+		availability.DELETE("/calendars/:calendarID", h.DeleteCalendar)
+		availability.GET("/busy", h.ListBusy)
+	}
+
+	r.GET("/tribes/:id/availability", h.ProposeSlots)
+}
+
+// LinkCalendarRequest represents the body linking a calendar by URL
+type LinkCalendarRequest struct {
+	Name string `json:"name" binding:"required"`
+	URL  string `json:"url" binding:"required"`
+}
+
+// ImportCalendar imports a calendar for the current user. A multipart form
+// uploads the "calendar" file with a "name" field; a JSON body links a URL.
+func (h *AvailabilityHandler) ImportCalendar(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	var calendar *models.AvailabilityCalendar
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxCalendarImportSize+multipartOverhead)
+		file, header, formErr := c.Request.FormFile("calendar")
+		if formErr != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(formErr, &maxBytesErr) {
+				response.GinError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "Calendar is too large")
+				return
+			}
+			response.GinBadRequest(c, "A calendar file is required")
+			return
+		}
+		defer file.Close()
+
+		name := c.Request.FormValue("name")
+		if name == "" {
+			name = strings.TrimSuffix(header.Filename, ".ics")
+		}
+		calendar, err = h.availability.UploadCalendar(userID, name, file)
+	} else {
+		var req LinkCalendarRequest
+		if err = c.ShouldBindJSON(&req); err != nil {
+			response.GinBadRequest(c, fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+		calendar, err = h.availability.LinkCalendar(c.Request.Context(), userID, req.Name, req.URL)
+	}
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	response.GinCreated(c, calendar)
+}
+
+// ListCalendars returns the current user's imported calendars
+func (h *AvailabilityHandler) ListCalendars(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	calendars, err := h.availability.ListCalendars(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, calendars)
+}
+
+// RefreshCalendar imports one of the current user's linked calendars again
+func (h *AvailabilityHandler) RefreshCalendar(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	calendarID, err := uuid.Parse(c.Param("calendarID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid calendar ID")
+		return
+	}
+
+	calendar, err := h.availability.RefreshCalendar(c.Request.Context(), userID, calendarID)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, calendar)
+}
+
+// DeleteCalendar removes one of the current user's calendars
+func (h *AvailabilityHandler) DeleteCalendar(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	calendarID, err := uuid.Parse(c.Param("calendarID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid calendar ID")
+		return
+	}
+
+	if err = h.availability.DeleteCalendar(userID, calendarID); err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// ListBusy returns the current user's busy time in the window given by the
+// from and to query parameters
+func (h *AvailabilityHandler) ListBusy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	from, to, ok := parseSlotWindow(c)
+	if !ok {
+		return
+	}
+
+	busy, err := h.availability.BusyIntervals(userID, from, to)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, busy)
+}
+
+// ProposeSlots proposes times when a tribe's members are free. Query
+// parameters: from and to (RFC 3339, default the next seven days),
+// duration and step (Go durations such as "90m", default one hour and
+// thirty minutes), quorum (members who must be free, default all) and limit.
+func (h *AvailabilityHandler) ProposeSlots(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	from, to, ok := parseSlotWindow(c)
+	if !ok {
+		return
+	}
+	query := models.SlotQuery{From: from, To: to, Duration: defaultSlotDuration}
+	if !durationQuery(c, "duration", &query.Duration) || !durationQuery(c, "step", &query.Step) ||
+		!positiveIntQuery(c, "quorum", &query.Quorum) || !positiveIntQuery(c, "limit", &query.Limit) {
+		return
+	}
+
+	tribes, err := h.activities.memberTribes(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+	if !tribes[tribeID] {
+		response.GinForbidden(c, "Not a member of this tribe")
+		return
+	}
+
+	slots, err := h.availability.ProposeSlots(tribeID, query)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, slots)
+}
+
+// parseSlotWindow reads the from and to query parameters, defaulting to the
+// next seven days
+func parseSlotWindow(c *gin.Context) (time.Time, time.Time, bool) {
+	from := time.Now().Truncate(time.Minute)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.GinBadRequest(c, "from must be an RFC 3339 timestamp")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	to := from.Add(defaultSlotWindow)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.GinBadRequest(c, "to must be an RFC 3339 timestamp")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	return from, to, true
+}
+
+// durationQuery reads an optional duration query parameter into target
+func durationQuery(c *gin.Context, name string, target *time.Duration) bool {
+	value := c.Query(name)
+	if value == "" {
+		return true
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		response.GinBadRequest(c, fmt.Sprintf("%s must be a duration such as 90m", name))
+		return false
+	}
+	*target = parsed
+	return true
+}
+
+// positiveIntQuery reads an optional positive integer query parameter into
+// target
+func positiveIntQuery(c *gin.Context, name string, target *int) bool {
+	value := c.Query(name)
+	if value == "" {
+		return true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		response.GinBadRequest(c, fmt.Sprintf("%s must be a positive integer", name))
+		return false
+	}
+	*target = parsed
+	return true
+}
+
+// writeAvailabilityError maps availability service errors onto responses
+func writeAvailabilityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.GinNotFound(c, "Calendar not found")
+	case errors.Is(err, models.ErrInvalidInput):
+		response.GinBadRequest(c, err.Error())
+	case errors.Is(err, models.ErrExternalSourceTimeout):
+		response.GinError(c, http.StatusGatewayTimeout, "CALENDAR_TIMEOUT", "Calendar host timed out")
+	case errors.Is(err, models.ErrExternalSourceUnavailable), errors.Is(err, models.ErrExternalSourceError):
+		response.GinError(c, http.StatusBadGateway, "CALENDAR_UNAVAILABLE", "Could not fetch the calendar")
+	default:
+		response.GinInternalError(c, err)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailability(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() {
+		testutil.TeardownTestDB(t, db)
+	})
+	repos := postgres.NewRepositories(db)
+
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{testUser, otherUser})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{otherUser})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", testUser.ID.String())
+		c.Next()
+	})
+	api := router.Group("/api")
+	NewTribeHandler(repos).RegisterRoutes(api)
+	NewAvailabilityHandler(repos, service.NewAvailabilityService(repos.Availability, repos.Tribes, nil)).RegisterRoutes(api)
+
+	from := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:meeting",
+		"DTSTART:" + from.Add(time.Hour).Format("20060102T150405Z"),
+		"DTEND:" + from.Add(2*time.Hour).Format("20060102T150405Z"),
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	do := func(method, path string, body *bytes.Buffer, contentType string) *httptest.ResponseRecorder {
+		if body == nil {
+			body = &bytes.Buffer{}
+		}
+		req := httptest.NewRequest(method, path, body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	upload := func(content string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("calendar", "work.ics")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		return do(http.MethodPost, "/api/availability/calendars", body, writer.FormDataContentType())
+	}
+
+	w := upload(calendar)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data models.AvailabilityCalendar `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "work", created.Data.Name)
+
+	t.Run("invalid upload", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, upload("not a calendar").Code)
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"name":"Local","url":"ftp://example.com/cal.ics"}`)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/availability/calendars", body, "application/json").Code)
+	})
+
+	t.Run("list calendars", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/availability/calendars", nil, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var listed struct {
+			Data []models.AvailabilityCalendar `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+		require.Len(t, listed.Data, 1)
+		assert.Equal(t, created.Data.ID, listed.Data[0].ID)
+	})
+
+	t.Run("busy time", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/availability/busy?from="+from.Format(time.RFC3339), nil, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var busy struct {
+			Data []models.BusyInterval `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &busy))
+		require.Len(t, busy.Data, 1)
+		assert.True(t, from.Add(time.Hour).Equal(busy.Data[0].StartTime))
+	})
+
+	t.Run("propose slots", func(t *testing.T) {
+		path := "/api/tribes/" + tribe.ID.String() + "/availability?from=" + from.Format(time.RFC3339) +
+			"&to=" + from.Add(4*time.Hour).Format(time.RFC3339) + "&duration=1h"
+		w := do(http.MethodGet, path, nil, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var slots struct {
+			Data []models.TimeSlot `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &slots))
+		require.Len(t, slots.Data, 2)
+		assert.True(t, from.Equal(slots.Data[0].StartTime))
+		assert.True(t, from.Add(time.Hour).Equal(slots.Data[0].EndTime))
+		assert.True(t, from.Add(2*time.Hour).Equal(slots.Data[1].StartTime))
+
+		w = do(http.MethodGet, path+"&quorum=1", nil, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &slots))
+		assert.Len(t, slots.Data, 3)
+	})
+
+	t.Run("propose slot errors", func(t *testing.T) {
+		base := "/api/tribes/" + tribe.ID.String() + "/availability"
+		tests := []struct {
+			name       string
+			path       string
+			wantStatus int
+		}{
+			{name: "not a member", path: "/api/tribes/" + otherTribe.ID.String() + "/availability", wantStatus: http.StatusForbidden},
+			{name: "invalid tribe", path: "/api/tribes/nope/availability", wantStatus: http.StatusBadRequest},
+			{name: "invalid from", path: base + "?from=tomorrow", wantStatus: http.StatusBadRequest},
+			{name: "invalid duration", path: base + "?duration=an-hour", wantStatus: http.StatusBadRequest},
+			{name: "invalid quorum", path: base + "?quorum=0", wantStatus: http.StatusBadRequest},
+			{name: "quorum above members", path: base + "?quorum=3", wantStatus: http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.wantStatus, do(http.MethodGet, tt.path, nil, "").Code)
+			})
+		}
+	})
+
+	t.Run("refresh uploaded calendar", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/availability/calendars/"+created.Data.ID.String()+"/refresh", nil, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete calendar", func(t *testing.T) {
+		path := "/api/availability/calendars/" + created.Data.ID.String()
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, path, nil, "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, path, nil, "").Code)
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+)
+
+// calendarContentType is the media type of iCalendar feeds
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// CalendarHandler handles iCalendar feeds and their tokens
+type CalendarHandler struct {
+	activities *ActivityHandler
+	calendar   service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(repos *postgres.Repositories, calendar service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		activities: NewActivityHandler(repos),
+		calendar:   calendar,
+	}
+}
+
+// RegisterRoutes registers the authenticated feed token routes
+func (h *CalendarHandler) RegisterRoutes(r *gin.RouterGroup) {
+	tokens := r.Group("/calendar/tokens")
+	{
+		tokens.POST("", h.CreateFeedToken)
+		tokens.GET("", h.ListFeedTokens)
+		tokens.DELETE("", h.RevokeAllFeedTokens)
+		tokens.DELETE("/:tokenID", h.RevokeFeedToken)
+	}
+}
+
+// RegisterPublicRoutes registers the feed routes. Calendar apps cannot log
+// in, so feeds are authorized by the token query parameter instead.
+func (h *CalendarHandler) RegisterPublicRoutes(r *gin.RouterGroup) {
+	calendar := r.Group("/calendar")
+	{
+		calendar.GET("/users/:file", h.UserFeed)
+		calendar.GET("/tribes/:file", h.TribeFeed)
+	}
+}
+
+// CreateFeedToken issues a feed token for the current user. The token value
+// is only ever returned in this response.
+func (h *CalendarHandler) CreateFeedToken(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	token, err := h.calendar.CreateFeedToken(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinCreated(c, token)
+}
+
+// ListFeedTokens returns the current user's feed tokens
+func (h *CalendarHandler) ListFeedTokens(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tokens, err := h.calendar.ListFeedTokens(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, tokens)
+}
+
+// RevokeFeedToken revokes one of the current user's feed tokens
+func (h *CalendarHandler) RevokeFeedToken(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tokenID, err := uuid.Parse(c.Param("tokenID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid token ID")
+		return
+	}
+
+	if err := h.calendar.RevokeFeedToken(userID, tokenID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Feed token not found")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// RevokeAllFeedTokens revokes every feed token of the current user
+func (h *CalendarHandler) RevokeAllFeedTokens(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.calendar.RevokeAllFeedTokens(userID); err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// UserFeed serves /calendar/users/{id}.ics, the events a user owns. Only
+// the user's own tokens open it.
+func (h *CalendarHandler) UserFeed(c *gin.Context) {
+	ownerID, ok := feedID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.authenticateFeed(c)
+	if !ok {
+		return
+	}
+	if userID != ownerID {
+		response.GinForbidden(c, "Feed token does not belong to this user")
+		return
+	}
+
+	feed, err := h.calendar.UserFeed(ownerID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, calendarContentType, feed)
+}
+
+// TribeFeed serves /calendar/tribes/{id}.ics, the events shared with a
+// tribe. Tokens of active members open it.
+func (h *CalendarHandler) TribeFeed(c *gin.Context) {
+	tribeID, ok := feedID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.authenticateFeed(c)
+	if !ok {
+		return
+	}
+
+	tribes, err := h.activities.memberTribes(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+	if !tribes[tribeID] {
+		response.GinForbidden(c, "Not a member of this tribe")
+		return
+	}
+
+	feed, err := h.calendar.TribeFeed(tribeID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, calendarContentType, feed)
+}
+
+// authenticateFeed resolves the token query parameter to its user
+func (h *CalendarHandler) authenticateFeed(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := h.calendar.AuthenticateFeed(c.Query("token"))
+	if err != nil {
+		if errors.Is(err, models.ErrUnauthorized) {
+			response.GinUnauthorized(c, "Invalid or revoked feed token")
+			return uuid.Nil, false
+		}
+		response.GinInternalError(c, err)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// feedID parses the ID out of a "{id}.ics" path segment
+func feedID(c *gin.Context) (uuid.UUID, bool) {
+	file := c.Param("file")
+	if !strings.HasSuffix(file, ".ics") {
+		response.GinNotFound(c, "Feed not found")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimSuffix(file, ".ics"))
+	if err != nil {
+		response.GinNotFound(c, "Feed not found")
+		return uuid.Nil, false
+	}
+	return id, true
+}
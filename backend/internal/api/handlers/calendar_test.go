@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeeds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() {
+		testutil.TeardownTestDB(t, db)
+	})
+	repos := postgres.NewRepositories(db)
+
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{testUser})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{otherUser})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", testUser.ID.String())
+		c.Next()
+	})
+	handler := NewCalendarHandler(repos, service.NewCalendarService(repos.CalendarTokens, repos.Activities, repos.Tribes))
+	api := router.Group("/api")
+	handler.RegisterRoutes(api)
+	handler.RegisterPublicRoutes(api)
+
+	activity := &models.Activity{
+		Type:       models.ActivityTypeEvent,
+		Name:       "Game Night",
+		Visibility: models.VisibilityShared,
+		Metadata:   models.JSONMap{},
+		UserID:     testUser.ID,
+	}
+	require.NoError(t, repos.Activities.Create(activity))
+	require.NoError(t, repos.Activities.AddOwner(activity.ID, testUser.ID, "user"))
+	require.NoError(t, repos.Activities.ShareWithTribe(activity.ID, tribe.ID, testUser.ID, nil))
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	require.NoError(t, repos.Activities.SetSchedule(&models.ActivitySchedule{
+		ActivityID: activity.ID,
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Hour),
+		TimeZone:   "UTC",
+	}))
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/calendar/tokens")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		Data models.CalendarFeedToken `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	token := created.Data.Token
+	require.NotEmpty(t, token)
+
+	userFeed := "/api/calendar/users/" + testUser.ID.String() + ".ics"
+	tribeFeed := "/api/calendar/tribes/" + tribe.ID.String() + ".ics"
+
+	t.Run("user feed", func(t *testing.T) {
+		w := do(http.MethodGet, userFeed+"?token="+token)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/calendar"))
+		assert.Contains(t, w.Body.String(), "UID:"+activity.ID.String()+"@rlship-tools")
+		assert.Contains(t, w.Body.String(), "SUMMARY:Game Night")
+	})
+
+	t.Run("tribe feed", func(t *testing.T) {
+		w := do(http.MethodGet, tribeFeed+"?token="+token)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "SUMMARY:Game Night")
+	})
+
+	t.Run("feed access", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			path       string
+			wantStatus int
+		}{
+			{name: "missing token", path: userFeed, wantStatus: http.StatusUnauthorized},
+			{name: "wrong token", path: userFeed + "?token=nope", wantStatus: http.StatusUnauthorized},
+			{name: "another user's feed", path: "/api/calendar/users/" + otherUser.ID.String() + ".ics?token=" + token, wantStatus: http.StatusForbidden},
+			{name: "tribe the user is not in", path: "/api/calendar/tribes/" + otherTribe.ID.String() + ".ics?token=" + token, wantStatus: http.StatusForbidden},
+			{name: "missing extension", path: "/api/calendar/users/" + testUser.ID.String() + "?token=" + token, wantStatus: http.StatusNotFound},
+			{name: "invalid id", path: "/api/calendar/users/nope.ics?token=" + token, wantStatus: http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.wantStatus, do(http.MethodGet, tt.path).Code)
+			})
+		}
+	})
+
+	t.Run("list tokens hides values", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/calendar/tokens")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), token)
+	})
+
+	t.Run("revoked token stops working", func(t *testing.T) {
+		w := do(http.MethodDelete, "/api/calendar/tokens/"+created.Data.ID.String())
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, userFeed+"?token="+token).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/api/calendar/tokens/"+created.Data.ID.String()).Code)
+	})
+}
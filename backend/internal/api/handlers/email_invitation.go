@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// EmailInvitationHandler handles tribe invitations sent to people without
+// an account
+type EmailInvitationHandler struct {
+	service service.EmailInvitationService
+}
+
+// NewEmailInvitationHandler creates a new email invitation handler
+func NewEmailInvitationHandler(service service.EmailInvitationService) *EmailInvitationHandler {
+	return &EmailInvitationHandler{service: service}
+}
+
+// RegisterRoutes registers the email invitation routes
+func (h *EmailInvitationHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/tribes/:id/email-invitations", h.InviteByEmail)
+	r.GET("/tribes/:id/email-invitations", h.ListInvitations)
+	r.DELETE("/tribes/:id/email-invitations/:invitationID", h.CancelInvitation)
+}
+
+// EmailInvitationRequest represents the body inviting an email to a tribe
+type EmailInvitationRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// InviteByEmail invites an unregistered email address to a tribe
+func (h *EmailInvitationHandler) InviteByEmail(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req EmailInvitationRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	invitation, err := h.service.InviteByEmail(userID, tribeID, req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidInput):
+			response.GinBadRequest(c, err.Error())
+		case errors.Is(err, models.ErrConflict):
+			response.GinConflict(c, "This email already has an account; add the user as a member instead")
+		case errors.Is(err, models.ErrDuplicate):
+			response.GinConflict(c, "This email has already been invited to the tribe")
+		default:
+			writeTribeAuthError(c, err, "Your membership does not allow inviting members")
+		}
+		return
+	}
+
+	response.GinCreated(c, invitation)
+}
+
+// ListInvitations returns a tribe's open email invitations
+func (h *EmailInvitationHandler) ListInvitations(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	invitations, err := h.service.ListInvitations(userID, tribeID)
+	if err != nil {
+		writeTribeAuthError(c, err, "Your membership does not allow inviting members")
+		return
+	}
+
+	response.GinSuccess(c, invitations)
+}
+
+// CancelInvitation withdraws an open email invitation
+func (h *EmailInvitationHandler) CancelInvitation(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitationID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if err = h.service.CancelInvitation(userID, tribeID, invitationID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Invitation not found")
+			return
+		}
+		writeTribeAuthError(c, err, "Only the invitation's sender, tribe owners and admins can cancel it")
+		return
+	}
+
+	response.GinNoContent(c)
+}
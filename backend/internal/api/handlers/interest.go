@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// InterestHandler handles interest indicators
+type InterestHandler struct {
+	service service.InterestService
+}
+
+// NewInterestHandler creates a new interest handler
+func NewInterestHandler(service service.InterestService) *InterestHandler {
+	return &InterestHandler{service: service}
+}
+
+// RegisterRoutes registers the interest routes
+func (h *InterestHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/tribes/:id/interests", h.CreateInterest)
+	r.GET("/tribes/:id/interests", h.ListTribeInterests)
+	r.POST("/tribes/:id/interests/menu", h.GenerateMenu)
+
+	interests := r.Group("/interests")
+	{
+		interests.GET("", h.ListMyInterests)
+		interests.GET("/:interestID", h.GetInterest)
+		interests.PUT("/:interestID", h.UpdateInterest)
+		interests.DELETE("/:interestID", h.DeleteInterest)
+	}
+}
+
+// InterestRequest represents the body creating or updating an interest.
+// StartsAt defaults to now and ExpiresAt to two hours after the start.
+type InterestRequest struct {
+	ListID       *uuid.UUID           `json:"list_id,omitempty"`
+	ActivityType *models.ActivityType `json:"activity_type,omitempty"`
+	Mood         string               `json:"mood"`
+	StartsAt     *time.Time           `json:"starts_at,omitempty"`
+	ExpiresAt    *time.Time           `json:"expires_at,omitempty"`
+}
+
+// InterestMenuRequest represents the body generating a menu from a tribe's
+// interests. ListID and ActivityType narrow which interests are used.
+type InterestMenuRequest struct {
+	models.MenuParams
+	ListID       *uuid.UUID           `json:"list_id,omitempty"`
+	ActivityType *models.ActivityType `json:"activity_type,omitempty"`
+}
+
+// apply copies the request onto an indicator
+func (r *InterestRequest) apply(indicator *models.InterestIndicator) {
+	indicator.ListID = r.ListID
+	indicator.ActivityType = r.ActivityType
+	indicator.Mood = r.Mood
+	indicator.StartsAt = time.Time{}
+	if r.StartsAt != nil {
+		indicator.StartsAt = *r.StartsAt
+	}
+	indicator.ExpiresAt = time.Time{}
+	if r.ExpiresAt != nil {
+		indicator.ExpiresAt = *r.ExpiresAt
+	}
+}
+
+// CreateInterest records that the current user is up for something with a
+// tribe
+func (h *InterestHandler) CreateInterest(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req InterestRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	indicator := &models.InterestIndicator{UserID: userID, TribeID: tribeID}
+	req.apply(indicator)
+	if err = h.service.CreateInterest(indicator); err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinCreated(c, indicator)
+}
+
+// ListTribeInterests returns which members of a tribe are interested in
+// something now. The list_id and activity_type query parameters narrow the
+// results; interests naming neither always match.
+func (h *InterestHandler) ListTribeInterests(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	filter, ok := parseInterestFilter(c)
+	if !ok {
+		return
+	}
+
+	indicators, err := h.service.ListTribeInterests(userID, tribeID, filter)
+	if err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, indicators)
+}
+
+// GenerateMenu generates a tribe menu from the lists its members are
+// currently interested in, along with any lists given in the body
+func (h *InterestHandler) GenerateMenu(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req InterestMenuRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	filter := models.InterestFilter{ListID: req.ListID, ActivityType: req.ActivityType}
+	session, err := h.service.GenerateMenu(userID, tribeID, filter, &req.MenuParams)
+	if err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinCreated(c, session)
+}
+
+// ListMyInterests returns the current user's current and upcoming interests
+func (h *InterestHandler) ListMyInterests(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	indicators, err := h.service.ListMyInterests(userID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, indicators)
+}
+
+// GetInterest returns an interest visible to the current user
+func (h *InterestHandler) GetInterest(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	interestID, err := uuid.Parse(c.Param("interestID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid interest ID")
+		return
+	}
+
+	indicator, err := h.service.GetInterest(userID, interestID)
+	if err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, indicator)
+}
+
+// UpdateInterest replaces the target, mood and times of one of the current
+// user's interests
+func (h *InterestHandler) UpdateInterest(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	interestID, err := uuid.Parse(c.Param("interestID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid interest ID")
+		return
+	}
+
+	var req InterestRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.GinBadRequest(c, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.StartsAt == nil || req.ExpiresAt == nil {
+		response.GinBadRequest(c, "starts_at and expires_at are required")
+		return
+	}
+
+	indicator := &models.InterestIndicator{ID: interestID}
+	req.apply(indicator)
+	if err = h.service.UpdateInterest(userID, indicator); err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinSuccess(c, indicator)
+}
+
+// DeleteInterest withdraws one of the current user's interests
+func (h *InterestHandler) DeleteInterest(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	interestID, err := uuid.Parse(c.Param("interestID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid interest ID")
+		return
+	}
+
+	if err = h.service.DeleteInterest(userID, interestID); err != nil {
+		writeInterestError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// parseInterestFilter reads the list_id and activity_type query parameters
+func parseInterestFilter(c *gin.Context) (models.InterestFilter, bool) {
+	var filter models.InterestFilter
+	if value := c.Query("list_id"); value != "" {
+		listID, err := uuid.Parse(value)
+		if err != nil {
+			response.GinBadRequest(c, "Invalid list ID")
+			return filter, false
+		}
+		filter.ListID = &listID
+	}
+	if value := c.Query("activity_type"); value != "" {
+		activityType := models.ActivityType(value)
+		if err := activityType.Validate(); err != nil {
+			response.GinBadRequest(c, err.Error())
+			return filter, false
+		}
+		filter.ActivityType = &activityType
+	}
+	return filter, true
+}
+
+// writeInterestError maps interest service errors onto responses
+func writeInterestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.GinNotFound(c, "Interest not found")
+	case errors.Is(err, models.ErrForbidden):
+		response.GinForbidden(c, "Access denied")
+	case errors.Is(err, models.ErrInvalidInput):
+		response.GinBadRequest(c, err.Error())
+	default:
+		response.GinInternalError(c, err)
+	}
+}
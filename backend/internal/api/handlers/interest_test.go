@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterests(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() {
+		testutil.TeardownTestDB(t, db)
+	})
+	repos := postgres.NewRepositories(db)
+
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{testUser, otherUser})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{otherUser})
+	list := testutil.CreateTestList(t, db, tribe)
+
+	listService := service.NewListService(repos.Lists)
+	tribeAuthorizer := service.NewTribeAuthorizer(repos.Tribes)
+	menuService := service.NewMenuService(listService,
+		service.NewListAuthorizer(repos.Lists, tribeAuthorizer), repos.MenuSessions, tribeAuthorizer)
+	interestService := service.NewInterestService(repos.Interests, repos.Lists, repos.Tribes, menuService)
+
+	newRouter := func(userID uuid.UUID) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", userID.String())
+			c.Next()
+		})
+		NewInterestHandler(interestService).RegisterRoutes(router.Group("/api"))
+		return router
+	}
+	routers := map[uuid.UUID]*gin.Engine{
+		testUser.ID:  newRouter(testUser.ID),
+		otherUser.ID: newRouter(otherUser.ID),
+	}
+	do := func(userID uuid.UUID, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		routers[userID].ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, target interface{}) {
+		var wrapper struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &wrapper))
+		require.NoError(t, json.Unmarshal(wrapper.Data, target))
+	}
+
+	tribePath := "/api/tribes/" + tribe.ID.String() + "/interests"
+	w := do(testUser.ID, http.MethodPost, tribePath, InterestRequest{ListID: &list.ID, Mood: "snacks"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.InterestIndicator
+	decode(w, &created)
+	assert.Equal(t, testUser.ID, created.UserID)
+	assert.Equal(t, list.ID, *created.ListID)
+	interestPath := "/api/interests/" + created.ID.String()
+
+	t.Run("create", func(t *testing.T) {
+		location := models.ActivityTypeLocation
+		assert.Equal(t, http.StatusBadRequest, do(testUser.ID, http.MethodPost, tribePath, InterestRequest{ListID: &list.ID, ActivityType: &location}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(testUser.ID, http.MethodPost, "/api/tribes/nope/interests", InterestRequest{}).Code)
+		assert.Equal(t, http.StatusForbidden, do(testUser.ID, http.MethodPost, "/api/tribes/"+otherTribe.ID.String()+"/interests", InterestRequest{}).Code)
+	})
+
+	t.Run("tribe interests", func(t *testing.T) {
+		w := do(otherUser.ID, http.MethodGet, tribePath+"?list_id="+list.ID.String(), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var indicators []models.InterestIndicator
+		decode(w, &indicators)
+		require.Len(t, indicators, 1)
+		assert.Equal(t, created.ID, indicators[0].ID)
+
+		w = do(otherUser.ID, http.MethodGet, tribePath+"?activity_type=event", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		decode(w, &indicators)
+		assert.Empty(t, indicators)
+
+		assert.Equal(t, http.StatusBadRequest, do(otherUser.ID, http.MethodGet, tribePath+"?activity_type=nap", nil).Code)
+	})
+
+	t.Run("menu", func(t *testing.T) {
+		w := do(otherUser.ID, http.MethodPost, tribePath+"/menu", InterestMenuRequest{})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var session models.MenuSession
+		decode(w, &session)
+		assert.Equal(t, []uuid.UUID{list.ID}, session.Params.ListIDs)
+
+		otherList := uuid.New()
+		assert.Equal(t, http.StatusBadRequest, do(otherUser.ID, http.MethodPost, tribePath+"/menu", InterestMenuRequest{ListID: &otherList}).Code)
+
+		assert.Equal(t, http.StatusForbidden, do(testUser.ID, http.MethodPost, "/api/tribes/"+otherTribe.ID.String()+"/interests/menu", InterestMenuRequest{}).Code)
+	})
+
+	t.Run("get and update", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(otherUser.ID, http.MethodGet, interestPath, nil).Code)
+		assert.Equal(t, http.StatusNotFound, do(testUser.ID, http.MethodGet, "/api/interests/"+uuid.New().String(), nil).Code)
+
+		update := InterestRequest{Mood: "anything", StartsAt: &created.StartsAt, ExpiresAt: &created.ExpiresAt}
+		assert.Equal(t, http.StatusForbidden, do(otherUser.ID, http.MethodPut, interestPath, update).Code)
+		assert.Equal(t, http.StatusBadRequest, do(testUser.ID, http.MethodPut, interestPath, InterestRequest{Mood: "anything"}).Code)
+
+		w := do(testUser.ID, http.MethodPut, interestPath, update)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var updated models.InterestIndicator
+		decode(w, &updated)
+		assert.Nil(t, updated.ListID)
+		assert.Equal(t, "anything", updated.Mood)
+	})
+
+	t.Run("mine and delete", func(t *testing.T) {
+		w := do(testUser.ID, http.MethodGet, "/api/interests", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var mine []models.InterestIndicator
+		decode(w, &mine)
+		assert.Len(t, mine, 1)
+
+		assert.Equal(t, http.StatusForbidden, do(otherUser.ID, http.MethodDelete, interestPath, nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(testUser.ID, http.MethodDelete, interestPath, nil).Code)
+		assert.Equal(t, http.StatusNotFound, do(testUser.ID, http.MethodDelete, interestPath, nil).Code)
+	})
+}
@@ -22,10 +22,12 @@ import (
 // ListHandler struct for handling list-related requests
 type ListHandler struct {
 	service service.ListService
+	authz   service.TribeAuthorizer
+	access  service.ListAuthorizer
 }
 
-func NewListHandler(service service.ListService) *ListHandler {
-	return &ListHandler{service: service}
+func NewListHandler(service service.ListService, authz service.TribeAuthorizer, access service.ListAuthorizer) *ListHandler {
+	return &ListHandler{service: service, authz: authz, access: access}
 }
 
 // RegisterRoutes registers the list management routes
@@ -125,8 +127,9 @@ func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// Verify that the user has permission to create a list for this owner
 		if list.OwnerType != nil && *list.OwnerType == models.OwnerTypeTribe {
-			// TODO: Check if user is a member of the tribe
-			// For now we'll just allow it, but in the future this should be validated
+			if !h.authorizeTribe(w, userID, *list.OwnerID, models.PermissionContribute, "Your membership does not allow creating lists for this tribe") {
+				return
+			}
 			log.Printf("Creating list for tribe ID: %s by user ID: %s", *list.OwnerID, userID)
 		} else if *list.OwnerID != userID {
 			// If it's not a tribe and the owner ID is not the current user, reject it
@@ -355,6 +358,10 @@ func (h *ListHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessView) {
+		return
+	}
+
 	list, err := h.service.GetList(listID)
 	if err != nil {
 		response.Error(w, http.StatusNotFound, err.Error())
@@ -380,6 +387,10 @@ func (h *ListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
 	var list models.List
 	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
@@ -404,6 +415,10 @@ func (h *ListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
 	if err := h.service.DeleteList(listID); err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -421,14 +436,25 @@ func (h *ListHandler) AddListItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var item models.ListItem
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
+	// Items are available unless the request says otherwise
+	item := models.ListItem{Available: true}
 	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
 	item.ListID = listID
-	if err := h.service.AddListItem(&item); err != nil {
+	if err := h.service.AddListItem(&item, userID); err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -444,6 +470,10 @@ func (h *ListHandler) GetListItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessView) {
+		return
+	}
+
 	items, err := h.service.GetListItems(listID)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
@@ -476,7 +506,12 @@ func (h *ListHandler) UpdateListItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var item models.ListItem
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
+	// Items are available unless the request says otherwise
+	item := models.ListItem{Available: true}
 	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
@@ -508,6 +543,10 @@ func (h *ListHandler) RemoveListItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
 	if err := h.service.RemoveListItem(listID, itemID); err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -524,13 +563,25 @@ func (h *ListHandler) GenerateMenu(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, listID := range params.ListIDs {
+		if !h.authorizeList(w, r, listID, service.ListAccessView) {
+			return
+		}
+	}
+
 	lists, err := h.service.GenerateMenu(&params)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.JSON(w, http.StatusOK, lists)
+	// Echo the seed so the caller can reproduce this menu
+	result := response.SuccessResponse(lists)
+	if params.Seed != nil {
+		result["seed"] = *params.Seed
+	}
+
+	response.JSON(w, http.StatusOK, result)
 }
 
 // SyncList handles syncing a list with its external source
@@ -542,12 +593,18 @@ func (h *ListHandler) SyncList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
 	if err := h.service.SyncList(listID); err != nil {
 		switch {
 		case errors.Is(err, models.ErrNotFound):
 			response.Error(w, http.StatusNotFound, "List not found")
 		case errors.Is(err, models.ErrSyncDisabled):
 			response.Error(w, http.StatusBadRequest, "Sync is not enabled for this list")
+		case errors.Is(err, models.ErrInvalidSyncSource):
+			response.Error(w, http.StatusBadRequest, "This list's source cannot be synced")
 		case errors.Is(err, models.ErrExternalSourceUnavailable):
 			response.Error(w, http.StatusServiceUnavailable, "External sync source is unavailable")
 		case errors.Is(err, models.ErrExternalSourceTimeout):
@@ -572,6 +629,10 @@ func (h *ListHandler) GetListConflicts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessView) {
+		return
+	}
+
 	conflicts, err := h.service.GetListConflicts(listID)
 	if err != nil {
 		switch {
@@ -604,15 +665,22 @@ func (h *ListHandler) ResolveListConflict(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !h.authorizeList(w, r, listID, service.ListAccessEdit) {
+		return
+	}
+
+	// Item is the caller's version of the item for the custom strategy; only
+	// its name, description, latitude, longitude and address are applied
 	var resolution struct {
-		Resolution string `json:"resolution"`
+		Resolution string           `json:"resolution"`
+		Item       *models.ListItem `json:"item,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&resolution); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	if err := h.service.ResolveListConflict(listID, conflictID, resolution.Resolution); err != nil {
+	if err := h.service.ResolveListConflict(listID, conflictID, resolution.Resolution, resolution.Item); err != nil {
 		switch {
 		case errors.Is(err, models.ErrNotFound):
 			response.Error(w, http.StatusNotFound, "List not found")
@@ -652,6 +720,18 @@ func (h *ListHandler) AddListOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Handing a list to a tribe needs the right to contribute to it
+	if req.OwnerType == string(models.OwnerTypeTribe) {
+		userID, authErr := getUserIDFromRequest(r)
+		if authErr != nil {
+			response.Error(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		if !h.authorizeTribe(w, userID, req.OwnerID, models.PermissionContribute, "Your membership does not allow adding lists to this tribe") {
+			return
+		}
+	}
+
 	if err := h.service.AddListOwner(listID, req.OwnerID, req.OwnerType); err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -771,9 +851,9 @@ func (h *ListHandler) GetTribeLists(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Authenticated user ID: %s", userID)
 
-	// TODO: Check if user is a member of the tribe
-	// For now, we'll just allow the request to proceed, but in production
-	// this should be properly checked
+	if !h.authorizeTribe(w, userID, tribeID, models.PermissionViewTribe, "You cannot view this tribe's lists") {
+		return
+	}
 
 	lists, err := h.service.GetTribeLists(tribeID)
 	if err != nil {
@@ -841,6 +921,10 @@ func (h *ListHandler) ShareList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeTribe(w, userID, req.TribeID, models.PermissionContribute, "Your membership does not allow sharing with this tribe") {
+		return
+	}
+
 	err = h.service.ShareListWithTribe(listID, req.TribeID, userID, req.ExpiresAt)
 	if err != nil {
 		h.handleError(w, err)
@@ -902,9 +986,9 @@ func (h *ListHandler) GetSharedLists(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Authenticated user ID: %s", userID)
 
-	// TODO: Check if user is a member of the tribe
-	// For now, we'll just allow the request to proceed, but in production
-	// this should be properly checked
+	if !h.authorizeTribe(w, userID, tribeID, models.PermissionViewTribe, "You cannot view this tribe's lists") {
+		return
+	}
 
 	lists, err := h.service.GetSharedLists(tribeID)
 	if err != nil {
@@ -1015,6 +1099,49 @@ func (h *ListHandler) handleError(w http.ResponseWriter, err error) {
 	}
 }
 
+// authorizeTribe checks the user holds the permission in the tribe, writing
+// the error response when they do not
+func (h *ListHandler) authorizeTribe(w http.ResponseWriter, userID, tribeID uuid.UUID, permission models.TribePermission, denied string) bool {
+	_, err := h.authz.Authorize(userID, tribeID, permission)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "Tribe not found")
+	case errors.Is(err, models.ErrNotTribeMember):
+		response.Error(w, http.StatusForbidden, "You must be a member of this tribe")
+	case errors.Is(err, models.ErrTribePermission):
+		response.Error(w, http.StatusForbidden, denied)
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+	return false
+}
+
+// authorizeList checks the requesting user has the needed access to the
+// list, writing the error response when they do not. Lists the user cannot
+// see are reported as not found.
+func (h *ListHandler) authorizeList(w http.ResponseWriter, r *http.Request, listID uuid.UUID, need service.ListAccess) bool {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "Authentication required")
+		return false
+	}
+
+	_, err = h.access.Authorize(userID, listID, need)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "List not found")
+	case errors.Is(err, models.ErrForbidden):
+		response.Error(w, http.StatusForbidden, "You cannot change this list")
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+	return false
+}
+
 // ShareListWithTribe handles sharing a list with a specific tribe
 func (h *ListHandler) ShareListWithTribe(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
@@ -1045,6 +1172,10 @@ func (h *ListHandler) ShareListWithTribe(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !h.authorizeTribe(w, userID, tribeID, models.PermissionContribute, "Your membership does not allow sharing with this tribe") {
+		return
+	}
+
 	err = h.service.ShareListWithTribe(listID, tribeID, userID, req.ExpiresAt)
 	if err != nil {
 		h.handleError(w, err)
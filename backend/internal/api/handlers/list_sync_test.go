@@ -2,17 +2,24 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/middleware"
 	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+	"github.com/jenglund/rlship-tools/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -21,8 +28,9 @@ import (
 // TestSyncListHandler tests the SyncList endpoint with a variety of scenarios
 func TestSyncListHandler(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	testCases := []struct {
@@ -66,6 +74,15 @@ func TestSyncListHandler(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "Sync is not enabled for this list",
 		},
+		{
+			name:   "Source Without Provider",
+			listID: uuid.New().String(),
+			setupMock: func() {
+				mockService.On("SyncList", mock.AnythingOfType("uuid.UUID")).Return(models.ErrInvalidSyncSource).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "This list's source cannot be synced",
+		},
 		{
 			name:   "External Source Unavailable",
 			listID: uuid.New().String(),
@@ -127,8 +144,9 @@ func TestSyncListHandler(t *testing.T) {
 // TestGetListConflictsHandler tests the GetListConflicts endpoint with a variety of scenarios
 func TestGetListConflictsHandler(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	now := time.Now()
@@ -269,8 +287,9 @@ func TestGetListConflictsHandler(t *testing.T) {
 // TestResolveListConflictHandler tests the ResolveListConflict endpoint with a variety of scenarios
 func TestResolveListConflictHandler(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	validListID := uuid.New()
@@ -293,23 +312,23 @@ func TestResolveListConflictHandler(t *testing.T) {
 		checkResponse  func(*httptest.ResponseRecorder)
 	}{
 		{
-			name:        "Happy Path - Accept Local",
+			name:        "Happy Path - Keep Local",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(nil).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(nil).Once()
 			},
 			expectedStatus: http.StatusNoContent,
 			checkResponse:  func(rec *httptest.ResponseRecorder) {},
 		},
 		{
-			name:        "Happy Path - Accept Remote",
+			name:        "Happy Path - Take Remote",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_remote"),
+			requestBody: createRequestBody("take_remote"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_remote").Return(nil).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "take_remote", (*models.ListItem)(nil)).Return(nil).Once()
 			},
 			expectedStatus: http.StatusNoContent,
 			checkResponse:  func(rec *httptest.ResponseRecorder) {},
@@ -318,7 +337,7 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:           "Invalid List ID",
 			listID:         "not-a-uuid",
 			conflictID:     validConflictID.String(),
-			requestBody:    createRequestBody("accept_local"),
+			requestBody:    createRequestBody("keep_local"),
 			setupMock:      func() {},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -329,7 +348,7 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:           "Invalid Conflict ID",
 			listID:         validListID.String(),
 			conflictID:     "not-a-uuid",
-			requestBody:    createRequestBody("accept_local"),
+			requestBody:    createRequestBody("keep_local"),
 			setupMock:      func() {},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -354,7 +373,7 @@ func TestResolveListConflictHandler(t *testing.T) {
 			requestBody: []byte("{}"),
 			setupMock: func() {
 				// Need to mock empty resolution string call since the json will decode to an empty string
-				mockService.On("ResolveListConflict", validListID, validConflictID, "").Return(models.ErrInvalidResolution).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "", (*models.ListItem)(nil)).Return(models.ErrInvalidResolution).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -365,9 +384,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:        "List Not Found",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(models.ErrNotFound).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(models.ErrNotFound).Once()
 			},
 			expectedStatus: http.StatusNotFound,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -378,9 +397,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:        "Conflict Not Found",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(models.ErrConflictNotFound).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(models.ErrConflictNotFound).Once()
 			},
 			expectedStatus: http.StatusNotFound,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -391,9 +410,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:        "Conflict Already Resolved",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(models.ErrConflictAlreadyResolved).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(models.ErrConflictAlreadyResolved).Once()
 			},
 			expectedStatus: http.StatusConflict,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -406,7 +425,7 @@ func TestResolveListConflictHandler(t *testing.T) {
 			conflictID:  validConflictID.String(),
 			requestBody: createRequestBody("invalid_resolution"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "invalid_resolution").Return(models.ErrInvalidResolution).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "invalid_resolution", (*models.ListItem)(nil)).Return(models.ErrInvalidResolution).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -417,9 +436,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:        "Sync Disabled",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(models.ErrSyncDisabled).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(models.ErrSyncDisabled).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -430,9 +449,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 			name:        "Internal Server Error",
 			listID:      validListID.String(),
 			conflictID:  validConflictID.String(),
-			requestBody: createRequestBody("accept_local"),
+			requestBody: createRequestBody("keep_local"),
 			setupMock: func() {
-				mockService.On("ResolveListConflict", validListID, validConflictID, "accept_local").Return(errors.New("database error")).Once()
+				mockService.On("ResolveListConflict", validListID, validConflictID, "keep_local", (*models.ListItem)(nil)).Return(errors.New("database error")).Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
 			checkResponse: func(rec *httptest.ResponseRecorder) {
@@ -464,8 +483,9 @@ func TestResolveListConflictHandler(t *testing.T) {
 // TestEdgeCasesForSyncHandlers tests edge cases for the sync-related handlers
 func TestEdgeCasesForSyncHandlers(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	t.Run("SyncList with missing body", func(t *testing.T) {
@@ -500,7 +520,7 @@ func TestEdgeCasesForSyncHandlers(t *testing.T) {
 		conflictID := uuid.New()
 
 		// Mock the service call with an empty resolution string
-		mockService.On("ResolveListConflict", listID, conflictID, "").Return(models.ErrInvalidResolution).Once()
+		mockService.On("ResolveListConflict", listID, conflictID, "", (*models.ListItem)(nil)).Return(models.ErrInvalidResolution).Once()
 
 		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/lists/%s/conflicts/%s/resolve", listID, conflictID),
 			bytes.NewReader([]byte(`{}`)))
@@ -524,6 +544,78 @@ func TestEdgeCasesForSyncHandlers(t *testing.T) {
 	})
 }
 
+// TestEditThenSyncMergesUntouchedFields edits a synced item through the API
+// and checks that the next sync still merges the fields the edit left alone
+func TestEditThenSyncMergesUntouchedFields(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() {
+		testutil.TeardownTestDB(t, db)
+	})
+	repos := postgres.NewRepositories(db)
+
+	user := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{user})
+	list := testutil.CreateTestList(t, db, tribe)
+	_, err := db.Exec(`UPDATE lists SET sync_source = $1, sync_id = $2, sync_status = $3 WHERE id = $4`,
+		models.SyncSourceImported, "items.json", models.ListSyncStatusSynced, list.ID)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeItems := func(name, description string) {
+		data, marshalErr := json.Marshal([]map[string]string{
+			{"external_id": "cafe", "name": name, "description": description},
+		})
+		require.NoError(t, marshalErr)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "items.json"), data, 0o600))
+	}
+
+	listService := service.NewListService(repos.Lists, service.NewFileSyncProvider(dir))
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.ContextUserIDKey, user.ID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	tribeAuthorizer := service.NewTribeAuthorizer(repos.Tribes)
+	NewListHandler(listService, tribeAuthorizer, service.NewListAuthorizer(repos.Lists, tribeAuthorizer)).RegisterRoutes(router)
+
+	writeItems("Cafe", "Coffee")
+	require.NoError(t, listService.SyncList(list.ID))
+
+	items, err := repos.Lists.GetItems(list.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	// Round-trip the item the way a client does, which drops the snapshot
+	body, err := json.Marshal(items[0])
+	require.NoError(t, err)
+	var edited map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &edited))
+	edited["description"] = "Best coffee in town"
+	body, err = json.Marshal(edited)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/lists/%s/items/%s", list.ID, items[0].ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	writeItems("Corner Cafe", "Coffee")
+	require.NoError(t, listService.SyncList(list.ID))
+
+	items, err = repos.Lists.GetItems(list.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Corner Cafe", items[0].Name)
+	assert.Equal(t, "Best coffee in town", items[0].Description)
+
+	conflicts, err := repos.Lists.GetConflicts(list.ID)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
 // MockReadCloser is a helper struct for testing with request bodies
 type MockReadCloser struct {
 	*bytes.Reader
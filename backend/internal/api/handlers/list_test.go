@@ -15,6 +15,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/service"
 	"github.com/jenglund/rlship-tools/internal/middleware"
 	"github.com/jenglund/rlship-tools/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -50,8 +51,8 @@ func (m *MockListService) DeleteList(id uuid.UUID) error {
 	return args.Error(0)
 }
 
-func (m *MockListService) AddListItem(item *models.ListItem) error {
-	args := m.Called(item)
+func (m *MockListService) AddListItem(item *models.ListItem, userID uuid.UUID) error {
+	args := m.Called(item, userID)
 	return args.Error(0)
 }
 
@@ -86,6 +87,11 @@ func (m *MockListService) SyncList(listID uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockListService) CanSync(source models.SyncSource) bool {
+	args := m.Called(source)
+	return args.Bool(0)
+}
+
 func (m *MockListService) GetListConflicts(listID uuid.UUID) ([]*models.SyncConflict, error) {
 	args := m.Called(listID)
 	if args.Get(0) == nil {
@@ -94,8 +100,8 @@ func (m *MockListService) GetListConflicts(listID uuid.UUID) ([]*models.SyncConf
 	return args.Get(0).([]*models.SyncConflict), args.Error(1)
 }
 
-func (m *MockListService) ResolveListConflict(listID, conflictID uuid.UUID, resolution string) error {
-	args := m.Called(listID, conflictID, resolution)
+func (m *MockListService) ResolveListConflict(listID, conflictID uuid.UUID, resolution string, custom *models.ListItem) error {
+	args := m.Called(listID, conflictID, resolution, custom)
 	return args.Error(0)
 }
 
@@ -178,10 +184,50 @@ func (m *MockListService) CleanupExpiredShares() error {
 	return args.Error(0)
 }
 
+// stubTribeAuthorizer answers every tribe authorization with err
+type stubTribeAuthorizer struct {
+	err error
+}
+
+func (a stubTribeAuthorizer) Authorize(userID, tribeID uuid.UUID, permission models.TribePermission) (*models.TribeMember, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &models.TribeMember{TribeID: tribeID, UserID: userID, MembershipType: models.MembershipFull}, nil
+}
+
+func (a stubTribeAuthorizer) AuthorizeRemoval(actorID, tribeID, targetID uuid.UUID) error {
+	return a.err
+}
+
+func (a stubTribeAuthorizer) AuthorizedTribes(userID uuid.UUID, permission models.TribePermission) (map[uuid.UUID]bool, error) {
+	return map[uuid.UUID]bool{}, a.err
+}
+
+// stubListAuthorizer answers every list authorization with err
+type stubListAuthorizer struct {
+	err error
+}
+
+func (a stubListAuthorizer) Authorize(userID, listID uuid.UUID, need service.ListAccess) (*models.List, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &models.List{ID: listID}, nil
+}
+
+func (a stubListAuthorizer) AccessFor(userID uuid.UUID, list *models.List) (service.ListAccess, error) {
+	if a.err != nil {
+		return service.ListAccessNone, a.err
+	}
+	return service.ListAccessEdit, nil
+}
+
 func TestListHandler(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	t.Run("CreateList", func(t *testing.T) {
@@ -381,7 +427,7 @@ func TestListHandler(t *testing.T) {
 		listID := uuid.New()
 		conflictID := uuid.New()
 		resolution := "accept"
-		mockService.On("ResolveListConflict", listID, conflictID, resolution).Return(nil)
+		mockService.On("ResolveListConflict", listID, conflictID, resolution, (*models.ListItem)(nil)).Return(nil)
 
 		reqBody := struct {
 			Resolution string `json:"resolution"`
@@ -484,7 +530,7 @@ func TestListHandler(t *testing.T) {
 
 	t.Run("List_Sharing", func(t *testing.T) {
 		localMockService := new(MockListService)
-		localHandler := NewListHandler(localMockService)
+		localHandler := NewListHandler(localMockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 		localRouter := chi.NewRouter()
 		localRouter.Post("/lists/{listID}/share", localHandler.ShareList)
@@ -525,7 +571,7 @@ func TestListHandler(t *testing.T) {
 
 	t.Run("GetListShares", func(t *testing.T) {
 		localMockService := new(MockListService)
-		localHandler := NewListHandler(localMockService)
+		localHandler := NewListHandler(localMockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 		validListID := uuid.New()
 		validUserID := uuid.New()
@@ -643,7 +689,7 @@ func TestListHandler(t *testing.T) {
 
 	t.Run("UnshareListWithTribe", func(t *testing.T) {
 		localMockService := new(MockListService)
-		localHandler := NewListHandler(localMockService)
+		localHandler := NewListHandler(localMockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 		localRouter := chi.NewRouter()
 		localHandler.RegisterRoutes(localRouter)
 
@@ -845,7 +891,7 @@ func TestListHandler(t *testing.T) {
 
 	t.Run("ShareListWithTribe", func(t *testing.T) {
 		mockService := new(MockListService)
-		handler := NewListHandler(mockService)
+		handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 		tests := []struct {
 			name           string
@@ -1116,14 +1162,14 @@ func TestListHandler_UpdateList(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/", bytes.NewReader(tt.requestBody))
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockService)
@@ -1186,14 +1232,14 @@ func TestListHandler_DeleteList(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("DELETE", "/", nil)
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				listID, _ := uuid.Parse(tt.listID)
@@ -1237,7 +1283,7 @@ func TestListHandler_AddListItem(t *testing.T) {
 			requestBody:    []byte(`{"name":"New Item","description":"Description","weight":1.0,"available":true}`),
 			expectedStatus: http.StatusCreated,
 			setupMocks: func(mockService *MockListService) {
-				mockService.On("AddListItem", mock.AnythingOfType("*models.ListItem")).Return(nil)
+				mockService.On("AddListItem", mock.AnythingOfType("*models.ListItem"), mock.AnythingOfType("uuid.UUID")).Return(nil)
 			},
 		},
 		{
@@ -1260,7 +1306,7 @@ func TestListHandler_AddListItem(t *testing.T) {
 			requestBody:    []byte(`{"name":"New Item","description":"Description","weight":1.0,"available":true}`),
 			expectedStatus: http.StatusInternalServerError,
 			setupMocks: func(mockService *MockListService) {
-				mockService.On("AddListItem", mock.AnythingOfType("*models.ListItem")).Return(models.ErrNotFound)
+				mockService.On("AddListItem", mock.AnythingOfType("*models.ListItem"), mock.AnythingOfType("uuid.UUID")).Return(models.ErrNotFound)
 			},
 		},
 	}
@@ -1268,14 +1314,14 @@ func TestListHandler_AddListItem(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("POST", "/", bytes.NewReader(tt.requestBody))
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockService)
@@ -1389,14 +1435,14 @@ func TestListHandler_GetListItems(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/", nil)
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				listID, _ := uuid.Parse(tt.listID)
@@ -1513,7 +1559,7 @@ func TestListHandler_UpdateListItem(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
@@ -1521,7 +1567,7 @@ func TestListHandler_UpdateListItem(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/", bytes.NewReader(tt.requestBody))
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockService)
@@ -1595,7 +1641,7 @@ func TestListHandler_RemoveListItem(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
@@ -1603,7 +1649,7 @@ func TestListHandler_RemoveListItem(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("DELETE", "/", nil)
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = setupAuthenticatedRequest(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
 
 			if tt.setupMocks != nil {
 				listID, _ := uuid.Parse(tt.listID)
@@ -1697,7 +1743,7 @@ func TestListHandler_RemoveListOwner(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("listID", tt.listID)
@@ -1822,7 +1868,7 @@ func TestListHandler_GetSharedLists(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockListService)
-			handler := NewListHandler(mockService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 
 			// Use the helper function to create a request with parameters and authentication
 			w, r := setupTestRequestWithParams("GET", "/lists/shared/"+tt.tribeID, map[string]string{
@@ -1858,8 +1904,9 @@ func TestListHandler_GetSharedLists(t *testing.T) {
 // TestCleanupExpiredShares tests the admin endpoint for cleaning up expired shares
 func TestCleanupExpiredShares(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	// Test successful cleanup
@@ -1911,8 +1958,9 @@ func TestCleanupExpiredShares(t *testing.T) {
 // TestCreateListValidation tests the validation rules for the CreateList handler
 func TestCreateListValidation(t *testing.T) {
 	mockService := new(MockListService)
-	handler := NewListHandler(mockService)
+	handler := NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{})
 	router := chi.NewRouter()
+	router.Use(authenticateRequests)
 	handler.RegisterRoutes(router)
 
 	userID := uuid.New()
@@ -2140,3 +2188,108 @@ func TestCreateListValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestListHandler_TribeAuthorization(t *testing.T) {
+	tribeID := uuid.New()
+	denials := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "unknown tribe", err: fmt.Errorf("%w: tribe not found", models.ErrNotFound), expectedStatus: http.StatusNotFound},
+		{name: "not a member", err: models.ErrNotTribeMember, expectedStatus: http.StatusForbidden},
+		{name: "membership denies it", err: models.ErrTribePermission, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, denial := range denials {
+		t.Run(denial.name, func(t *testing.T) {
+			mockService := new(MockListService)
+			handler := NewListHandler(mockService, stubTribeAuthorizer{err: denial.err}, stubListAuthorizer{})
+			params := map[string]string{"tribeID": tribeID.String(), "listID": uuid.New().String()}
+
+			w, r := setupTestRequestWithParams("GET", "/lists/shared/"+tribeID.String(), params)
+			handler.GetSharedLists(w, r)
+			assert.Equal(t, denial.expectedStatus, w.Code)
+
+			w, r = setupTestRequestWithParams("GET", "/lists/tribe/"+tribeID.String(), params)
+			handler.GetTribeLists(w, r)
+			assert.Equal(t, denial.expectedStatus, w.Code)
+
+			w, r = setupTestRequestWithParams("POST", "/lists/"+params["listID"]+"/share/"+tribeID.String(), params)
+			handler.ShareListWithTribe(w, r)
+			assert.Equal(t, denial.expectedStatus, w.Code)
+
+			ownerType := models.OwnerTypeTribe
+			body, err := json.Marshal(models.List{Name: "Tribe list", OwnerID: &tribeID, OwnerType: &ownerType})
+			require.NoError(t, err)
+			r = httptest.NewRequest("POST", "/lists", bytes.NewReader(body))
+			r = r.WithContext(context.WithValue(r.Context(), middleware.ContextUserIDKey, uuid.New()))
+			w = httptest.NewRecorder()
+			handler.CreateList(w, r)
+			assert.Equal(t, denial.expectedStatus, w.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListHandler_ListAuthorization(t *testing.T) {
+	listID := uuid.New()
+	itemID := uuid.New()
+	conflictID := uuid.New()
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodGet, path: "/lists/" + listID.String()},
+		{method: http.MethodPut, path: "/lists/" + listID.String(), body: `{"name":"Renamed"}`},
+		{method: http.MethodDelete, path: "/lists/" + listID.String()},
+		{method: http.MethodPost, path: "/lists/" + listID.String() + "/items", body: `{"name":"Item"}`},
+		{method: http.MethodGet, path: "/lists/" + listID.String() + "/items"},
+		{method: http.MethodPut, path: "/lists/" + listID.String() + "/items/" + itemID.String(), body: `{"name":"Item"}`},
+		{method: http.MethodDelete, path: "/lists/" + listID.String() + "/items/" + itemID.String()},
+		{method: http.MethodPost, path: "/lists/menu", body: `{"list_ids":["` + listID.String() + `"]}`},
+		{method: http.MethodPost, path: "/lists/" + listID.String() + "/sync"},
+		{method: http.MethodGet, path: "/lists/" + listID.String() + "/conflicts"},
+		{method: http.MethodPost, path: "/lists/" + listID.String() + "/conflicts/" + conflictID.String() + "/resolve", body: `{"resolution":"keep_local"}`},
+	}
+
+	denials := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "hidden list", err: fmt.Errorf("%w: list not found", models.ErrNotFound), expectedStatus: http.StatusNotFound},
+		{name: "view only", err: fmt.Errorf("%w: only list owners can do this", models.ErrForbidden), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, denial := range denials {
+		t.Run(denial.name, func(t *testing.T) {
+			// No service calls are expected once the list is refused
+			mockService := new(MockListService)
+			router := chi.NewRouter()
+			router.Use(authenticateRequests)
+			NewListHandler(mockService, stubTribeAuthorizer{}, stubListAuthorizer{err: denial.err}).RegisterRoutes(router)
+
+			for _, request := range requests {
+				req := httptest.NewRequest(request.method, request.path, strings.NewReader(request.body))
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				assert.Equal(t, denial.expectedStatus, rec.Code, "%s %s", request.method, request.path)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("requests need a user", func(t *testing.T) {
+		router := chi.NewRouter()
+		NewListHandler(new(MockListService), stubTribeAuthorizer{}, stubListAuthorizer{}).RegisterRoutes(router)
+
+		req := httptest.NewRequest(http.MethodGet, "/lists/"+listID.String(), nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
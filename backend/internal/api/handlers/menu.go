@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// MenuHandler handles generating menus and recording what was picked
+type MenuHandler struct {
+	service service.MenuService
+}
+
+// NewMenuHandler creates a new menu handler
+func NewMenuHandler(service service.MenuService) *MenuHandler {
+	return &MenuHandler{service: service}
+}
+
+// RegisterRoutes registers the menu routes
+func (h *MenuHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/lists/menu", func(r chi.Router) {
+		r.Post("/", h.GenerateMenu)
+		r.Get("/sessions", h.ListSessions)
+		r.Get("/sessions/{sessionID}", h.GetSession)
+		r.Post("/sessions/{sessionID}/pick", h.PickItem)
+	})
+}
+
+// generateMenuRequest is the body accepted by GenerateMenu
+type generateMenuRequest struct {
+	models.MenuParams
+	TribeID *uuid.UUID `json:"tribe_id,omitempty"`
+}
+
+// GenerateMenu handles generating a menu and recording it as a session
+func (h *MenuHandler) GenerateMenu(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req generateMenuRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.service.CreateSession(userID, req.TribeID, &req.MenuParams)
+	if err != nil {
+		h.handleError(w, err, "Failed to generate menu")
+		return
+	}
+
+	// Echo the seed so the caller can reproduce this menu
+	result := response.SuccessResponse(session)
+	if req.Seed != nil {
+		result["seed"] = *req.Seed
+	}
+
+	response.JSON(w, http.StatusCreated, result)
+}
+
+// ListSessions handles listing menu sessions, for a tribe when tribe_id is given
+func (h *MenuHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var sessions []*models.MenuSession
+	if tribeIDStr := r.URL.Query().Get("tribe_id"); tribeIDStr != "" {
+		tribeID, parseErr := uuid.Parse(tribeIDStr)
+		if parseErr != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid tribe ID")
+			return
+		}
+		sessions, err = h.service.GetTribeSessions(userID, tribeID, offset, limit)
+	} else {
+		sessions, err = h.service.GetUserSessions(userID, offset, limit)
+	}
+	if err != nil {
+		h.handleError(w, err, "Failed to list menu sessions")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, sessions)
+}
+
+// GetSession handles retrieving a single menu session
+func (h *MenuHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID, err := extractUUIDParam(r, "sessionID")
+	if err != nil {
+		log.Printf("Error parsing sessionID from URL parameter: %v", err)
+		response.Error(w, http.StatusBadRequest, "Invalid session ID: "+err.Error())
+		return
+	}
+
+	session, err := h.service.GetSession(userID, sessionID)
+	if err != nil {
+		h.handleError(w, err, "Failed to get menu session")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+// PickItem handles recording the item picked from a menu session
+func (h *MenuHandler) PickItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID, err := extractUUIDParam(r, "sessionID")
+	if err != nil {
+		log.Printf("Error parsing sessionID from URL parameter: %v", err)
+		response.Error(w, http.StatusBadRequest, "Invalid session ID: "+err.Error())
+		return
+	}
+
+	var req struct {
+		ItemID uuid.UUID `json:"item_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemID == uuid.Nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	session, err := h.service.PickItem(userID, sessionID, req.ItemID)
+	if err != nil {
+		h.handleError(w, err, "Failed to pick menu item")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+// handleError maps service errors to HTTP responses
+func (h *MenuHandler) handleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "Not found")
+	case errors.Is(err, models.ErrInvalidInput):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrForbidden):
+		response.Error(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, models.ErrConflict):
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		response.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -17,6 +17,14 @@ func setupAuthenticatedRequest(r *http.Request) *http.Request {
 	return r.WithContext(context.WithValue(r.Context(), middleware.ContextUserIDKey, userID))
 }
 
+// authenticateRequests is router middleware that authenticates every
+// request as a new test user, as setupAuthenticatedRequest does
+func authenticateRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, setupAuthenticatedRequest(r))
+	})
+}
+
 // setupTestRequestWithParams creates a new test request with route parameters and authentication
 // It returns both the response recorder and the authenticated request
 func setupTestRequestWithParams(method, path string, params map[string]string) (*httptest.ResponseRecorder, *http.Request) {
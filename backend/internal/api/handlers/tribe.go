@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
 	"github.com/jenglund/rlship-tools/internal/middleware"
 	"github.com/jenglund/rlship-tools/internal/models"
 	"github.com/jenglund/rlship-tools/internal/repository/postgres"
@@ -17,11 +19,15 @@ import (
 // TribeHandler handles tribe-related requests
 type TribeHandler struct {
 	repos *postgres.Repositories
+	authz service.TribeAuthorizer
 }
 
 // NewTribeHandler creates a new tribe handler
 func NewTribeHandler(repos *postgres.Repositories) *TribeHandler {
-	return &TribeHandler{repos: repos}
+	return &TribeHandler{
+		repos: repos,
+		authz: service.NewTribeAuthorizer(repos.Tribes),
+	}
 }
 
 // RegisterRoutes registers the tribe routes
@@ -39,9 +45,20 @@ func (h *TribeHandler) RegisterRoutes(r *gin.RouterGroup) {
 		tribes.POST("/:id/members", h.AddMember)
 		tribes.DELETE("/:id/members/:userID", h.RemoveMember)
 		tribes.GET("/:id/members", h.ListMembers)
+		tribes.PUT("/:id/members/:userID/role", h.UpdateMemberRole)
+		tribes.PUT("/:id/members/me", h.UpdateMyMemberProfile)
+
+		// Ownership transfer
+		tribes.POST("/:id/ownership-transfer", h.TransferOwnership)
+		tribes.GET("/:id/ownership-transfer", h.GetOwnershipTransfer)
+		tribes.POST("/:id/ownership-transfer/respond", h.RespondToOwnershipTransfer)
+		tribes.DELETE("/:id/ownership-transfer", h.CancelOwnershipTransfer)
 
 		// Invitation response
 		tribes.POST("/:id/respond", h.RespondToInvitation)
+
+		// Activity feed
+		tribes.GET("/:id/feed", h.GetFeed)
 	}
 }
 
@@ -164,6 +181,12 @@ func (h *TribeHandler) CreateTribe(c *gin.Context) {
 		return
 	}
 
+	// The creator owns the tribe
+	if roleErr := h.repos.Tribes.SetMemberRole(tribe.ID, userID, models.TribeRoleOwner); roleErr != nil {
+		response.GinInternalError(c, roleErr)
+		return
+	}
+
 	// Get the updated tribe with members
 	tribe, err = h.repos.Tribes.GetByID(tribe.ID)
 	if err != nil {
@@ -190,6 +213,22 @@ func getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	return uuid.Nil, fmt.Errorf("user ID not found in context")
 }
 
+// writeTribeAuthError writes the response for an error from the tribe
+// authorizer, using denied as the message when the membership is not allowed
+// to perform the action
+func writeTribeAuthError(c *gin.Context, err error, denied string) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.GinNotFound(c, "Tribe not found")
+	case errors.Is(err, models.ErrNotTribeMember):
+		response.GinForbidden(c, "You must be a member of this tribe")
+	case errors.Is(err, models.ErrTribePermission):
+		response.GinForbidden(c, denied)
+	default:
+		response.GinInternalError(c, err)
+	}
+}
+
 // ListTribes returns a paginated list of all tribes
 func (h *TribeHandler) ListTribes(c *gin.Context) {
 	// Parse pagination parameters
@@ -530,22 +569,9 @@ func (h *TribeHandler) UpdateTribe(c *gin.Context) {
 		userID = user.ID
 	}
 
-	// Check if user is a member of the tribe
-	isMember := false
-	for _, member := range tribe.Members {
-		if member.UserID == userID {
-			isMember = true
-			// Only full members can update the tribe
-			if member.MembershipType != models.MembershipFull {
-				response.GinForbidden(c, "Only full members can update tribe details")
-				return
-			}
-			break
-		}
-	}
-
-	if !isMember {
-		response.GinForbidden(c, "You must be a member of the tribe to update it")
+	// Only owners and admins can update the tribe
+	if _, authErr := h.authz.Authorize(userID, id, models.PermissionUpdateTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "Only tribe owners and admins can update tribe details")
 		return
 	}
 
@@ -613,6 +639,17 @@ func (h *TribeHandler) DeleteTribe(c *gin.Context) {
 		return
 	}
 
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, id, models.PermissionDeleteTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "Only tribe owners can delete the tribe")
+		return
+	}
+
 	if err := h.repos.Tribes.Delete(id); err != nil {
 		response.GinInternalError(c, err)
 		return
@@ -648,6 +685,12 @@ func (h *TribeHandler) AddMember(c *gin.Context) {
 		return
 	}
 
+	// Limited members, guests and pending members cannot invite
+	if _, authErr := h.authz.Authorize(inviterID, tribeID, models.PermissionInviteMembers); authErr != nil {
+		writeTribeAuthError(c, authErr, "Your membership does not allow inviting members")
+		return
+	}
+
 	// Use inviterID directly
 	inviter := &inviterID
 
@@ -669,10 +712,20 @@ func (h *TribeHandler) AddMember(c *gin.Context) {
 	}
 
 	for _, member := range members {
-		if member.UserID == req.UserID {
+		if member.UserID != req.UserID {
+			continue
+		}
+		if !member.IsExpiredGuest() {
 			response.GinBadRequest(c, "User is already a member of this tribe")
 			return
 		}
+		// A guest whose access expired is invited again from scratch
+		if err := h.repos.Tribes.ReinviteExpiredGuest(tribeID, req.UserID, inviter); err != nil {
+			response.GinInternalError(c, err)
+			return
+		}
+		response.GinNoContent(c)
+		return
 	}
 
 	// Check if the user was previously a member but has been removed
@@ -736,6 +789,22 @@ func (h *TribeHandler) RemoveMember(c *gin.Context) {
 		return
 	}
 
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	// Members may leave; removing someone else needs a role above theirs
+	if authErr := h.authz.AuthorizeRemoval(actorID, tribeID, userID); authErr != nil {
+		if errors.Is(authErr, models.ErrNotFound) {
+			response.GinBadRequest(c, "Tribe member not found")
+			return
+		}
+		writeTribeAuthError(c, authErr, "You cannot remove this member")
+		return
+	}
+
 	err = h.repos.Tribes.RemoveMember(tribeID, userID)
 	if err != nil {
 		fmt.Printf("[DEBUG] RemoveMember: RemoveMember error: %v\n", err)
@@ -743,6 +812,10 @@ func (h *TribeHandler) RemoveMember(c *gin.Context) {
 			response.GinBadRequest(c, "Tribe member not found")
 			return
 		}
+		if errors.Is(err, models.ErrLastOwner) {
+			response.GinConflict(c, "The last owner cannot leave; transfer ownership or delete the tribe first")
+			return
+		}
 		response.GinInternalError(c, err)
 		return
 	}
@@ -759,6 +832,17 @@ func (h *TribeHandler) ListMembers(c *gin.Context) {
 		return
 	}
 
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionViewTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "You cannot view this tribe's members")
+		return
+	}
+
 	// Check if the request includes the include_users query parameter
 	includeUsers := c.Query("include_users") == "true"
 
@@ -791,6 +875,133 @@ func (h *TribeHandler) ListMembers(c *gin.Context) {
 	response.GinSuccess(c, members)
 }
 
+// UpdateMemberRoleRequest represents the request to change a member's role
+type UpdateMemberRoleRequest struct {
+	Role models.TribeRole `json:"role" binding:"required"`
+}
+
+// UpdateMemberRole promotes a member to admin or demotes an admin
+func (h *TribeHandler) UpdateMemberRole(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req UpdateMemberRoleRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+	if roleErr := req.Role.Validate(); roleErr != nil {
+		response.GinBadRequest(c, roleErr.Error())
+		return
+	}
+	if req.Role == models.TribeRoleOwner {
+		response.GinBadRequest(c, "The owner role cannot be assigned directly")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionManageRoles); authErr != nil {
+		writeTribeAuthError(c, authErr, "Only tribe owners can change member roles")
+		return
+	}
+
+	members, err := h.repos.Tribes.GetMembers(tribeID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	target := findTribeMember(members, targetID)
+	if target == nil {
+		response.GinNotFound(c, "Tribe member not found")
+		return
+	}
+	if target.Role == models.TribeRoleOwner {
+		response.GinForbidden(c, "Owners cannot change their role here")
+		return
+	}
+	if req.Role == models.TribeRoleAdmin && target.MembershipType != models.MembershipFull {
+		response.GinBadRequest(c, "Only full members can be admins")
+		return
+	}
+
+	if err = h.repos.Tribes.SetMemberRole(tribeID, targetID, req.Role); err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	target.Role = req.Role
+	response.GinSuccess(c, target)
+}
+
+// UpdateMyMemberProfile sets the current user's display name, avatar and
+// pronouns within a tribe. Empty values fall back to their account profile.
+func (h *TribeHandler) UpdateMyMemberProfile(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req models.TribeMemberProfile
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+	req.Normalize()
+	if validateErr := req.Validate(); validateErr != nil {
+		response.GinBadRequest(c, validateErr.Error())
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionViewTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "You cannot update your profile in this tribe")
+		return
+	}
+
+	if err = h.repos.Tribes.UpdateMemberProfile(tribeID, userID, &req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Tribe member not found")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	members, err := h.repos.Tribes.GetMembers(tribeID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	member := findTribeMember(members, userID)
+	if member == nil {
+		response.GinNotFound(c, "Tribe member not found")
+		return
+	}
+	response.GinSuccess(c, member)
+}
+
 // InvitationResponse represents the response to a tribe invitation
 type InvitationResponse struct {
 	Action string `json:"action" binding:"required"` // "accept" or "reject"
@@ -833,11 +1044,13 @@ func (h *TribeHandler) RespondToInvitation(c *gin.Context) {
 
 	var isMember bool
 	var isPending bool
+	var isExpiredGuest bool
 
 	for _, member := range members {
 		if member.UserID == user.ID {
 			isMember = true
 			isPending = member.MembershipType == models.MembershipPending
+			isExpiredGuest = member.IsExpiredGuest()
 			break
 		}
 	}
@@ -852,6 +1065,12 @@ func (h *TribeHandler) RespondToInvitation(c *gin.Context) {
 		return
 	}
 
+	// Guests whose access ran out wait for a member to invite them again
+	if isExpiredGuest && req.Action == "accept" {
+		response.GinForbidden(c, "Your guest access has expired; ask a tribe member to invite you again")
+		return
+	}
+
 	if req.Action == "accept" {
 		// Update membership type to full
 		err = h.repos.Tribes.UpdateMember(tribeID, user.ID, models.MembershipFull, nil)
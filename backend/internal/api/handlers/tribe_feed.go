@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// TribeFeedResponse is one page of a tribe's feed. NextCursor is empty on
+// the last page.
+type TribeFeedResponse struct {
+	Events     []*models.TribeEvent `json:"events"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// GetFeed returns a tribe's recent events, newest first. Pages are continued
+// by passing the previous page's next_cursor as the cursor query parameter.
+func (h *TribeHandler) GetFeed(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	limit := models.DefaultTribeFeedLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			response.GinBadRequest(c, "Invalid limit")
+			return
+		}
+		if limit > models.MaxTribeFeedLimit {
+			limit = models.MaxTribeFeedLimit
+		}
+	}
+
+	var cursor *models.TribeEventCursor
+	if cursorParam := c.Query("cursor"); cursorParam != "" {
+		cursor, err = models.ParseTribeEventCursor(cursorParam)
+		if err != nil {
+			response.GinBadRequest(c, "Invalid cursor")
+			return
+		}
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionViewTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "You cannot view this tribe's feed")
+		return
+	}
+
+	// Fetch one extra event to learn whether another page follows
+	events, err := h.repos.Events.ListByTribe(tribeID, cursor, limit+1)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+
+	feed := TribeFeedResponse{Events: events}
+	if len(events) > limit {
+		feed.Events = events[:limit]
+		feed.NextCursor = feed.Events[limit-1].CursorAfter().String()
+	}
+	response.GinSuccess(c, feed)
+}
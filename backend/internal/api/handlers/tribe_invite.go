@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// TribeInviteHandler handles shareable tribe invite links
+type TribeInviteHandler struct {
+	service service.TribeInviteService
+}
+
+// NewTribeInviteHandler creates a new tribe invite handler
+func NewTribeInviteHandler(service service.TribeInviteService) *TribeInviteHandler {
+	return &TribeInviteHandler{service: service}
+}
+
+// RegisterRoutes registers the tribe invite routes
+func (h *TribeInviteHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/tribes/:id/invites", h.CreateInvite)
+	r.GET("/tribes/:id/invites", h.ListInvites)
+	r.DELETE("/tribes/:id/invites/:inviteID", h.RevokeInvite)
+	r.POST("/invites/redeem", h.RedeemInvite)
+}
+
+// CreateInviteRequest represents the body creating an invite link.
+// MembershipType defaults to full and ExpiresAt to a week from now.
+type CreateInviteRequest struct {
+	MembershipType models.MembershipType `json:"membership_type"`
+	ExpiresAt      *time.Time            `json:"expires_at,omitempty"`
+	SingleUse      bool                  `json:"single_use"`
+}
+
+// RedeemInviteRequest represents the body redeeming an invite link
+type RedeemInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CreateInvite issues an invite link for a tribe
+func (h *TribeInviteHandler) CreateInvite(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req CreateInviteRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	invite := &models.TribeInvite{
+		TribeID:        tribeID,
+		MembershipType: req.MembershipType,
+		SingleUse:      req.SingleUse,
+	}
+	if invite.MembershipType == "" {
+		invite.MembershipType = models.MembershipFull
+	}
+	if req.ExpiresAt != nil {
+		invite.ExpiresAt = *req.ExpiresAt
+	}
+
+	if err = h.service.CreateInvite(userID, invite); err != nil {
+		if errors.Is(err, models.ErrInvalidInput) {
+			response.GinBadRequest(c, err.Error())
+			return
+		}
+		writeTribeAuthError(c, err, "Your membership does not allow inviting members")
+		return
+	}
+
+	response.GinCreated(c, invite)
+}
+
+// ListInvites returns a tribe's invite links
+func (h *TribeInviteHandler) ListInvites(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	invites, err := h.service.ListInvites(userID, tribeID)
+	if err != nil {
+		writeTribeAuthError(c, err, "Your membership does not allow inviting members")
+		return
+	}
+
+	response.GinSuccess(c, invites)
+}
+
+// RevokeInvite disables an invite link
+func (h *TribeInviteHandler) RevokeInvite(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	inviteID, err := uuid.Parse(c.Param("inviteID"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid invite ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if err = h.service.RevokeInvite(userID, tribeID, inviteID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "Invite not found")
+			return
+		}
+		writeTribeAuthError(c, err, "Only the invite's creator, tribe owners and admins can revoke it")
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// RedeemInvite adds the current user to the tribe an invite link is for
+func (h *TribeInviteHandler) RedeemInvite(c *gin.Context) {
+	var req RedeemInviteRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	invite, err := h.service.RedeemInvite(userID, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			response.GinNotFound(c, "Invite not found")
+		case errors.Is(err, models.ErrInviteUnavailable):
+			response.GinError(c, http.StatusGone, "INVITE_UNAVAILABLE", "This invite has expired, been revoked or already been used")
+		case errors.Is(err, models.ErrDuplicate):
+			response.GinConflict(c, "You are already a member of this tribe")
+		case errors.Is(err, models.ErrForbidden):
+			response.GinForbidden(c, "Former members must be reinvited by a tribe member")
+		default:
+			response.GinInternalError(c, err)
+		}
+		return
+	}
+
+	response.GinSuccess(c, gin.H{
+		"tribe_id":        invite.TribeID,
+		"membership_type": invite.MembershipType,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/repository/postgres"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeInvites(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() {
+		testutil.TeardownTestDB(t, db)
+	})
+	repos := postgres.NewRepositories(db)
+
+	inviter := testutil.CreateTestUser(t, db)
+	newcomer := testutil.CreateTestUser(t, db)
+	latecomer := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{inviter})
+	inviteService := service.NewTribeInviteService(repos.Invites, service.NewTribeAuthorizer(repos.Tribes), []byte("test-key"))
+
+	do := func(userID uuid.UUID, method, path string, body interface{}) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", userID.String())
+			c.Next()
+		})
+		NewTribeInviteHandler(inviteService).RegisterRoutes(router.Group("/api"))
+
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, target interface{}) {
+		var wrapper struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &wrapper))
+		require.NoError(t, json.Unmarshal(wrapper.Data, target))
+	}
+	invitesPath := "/api/tribes/" + tribe.ID.String() + "/invites"
+
+	assert.Equal(t, http.StatusBadRequest, do(inviter.ID, http.MethodPost, invitesPath, CreateInviteRequest{MembershipType: models.MembershipGuest}).Code)
+	assert.Equal(t, http.StatusForbidden, do(newcomer.ID, http.MethodPost, invitesPath, CreateInviteRequest{}).Code)
+
+	w := do(inviter.ID, http.MethodPost, invitesPath, CreateInviteRequest{SingleUse: true})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var invite models.TribeInvite
+	decode(w, &invite)
+	assert.Equal(t, models.MembershipFull, invite.MembershipType)
+	require.NotEmpty(t, invite.Token)
+
+	assert.Equal(t, http.StatusNotFound, do(newcomer.ID, http.MethodPost, "/api/invites/redeem", RedeemInviteRequest{Token: invite.Token + "x"}).Code)
+
+	w = do(newcomer.ID, http.MethodPost, "/api/invites/redeem", RedeemInviteRequest{Token: invite.Token})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusGone, do(latecomer.ID, http.MethodPost, "/api/invites/redeem", RedeemInviteRequest{Token: invite.Token}).Code)
+
+	members, err := repos.Tribes.GetMembers(tribe.ID)
+	require.NoError(t, err)
+	var joined *models.TribeMember
+	for _, member := range members {
+		if member.UserID == newcomer.ID {
+			joined = member
+		}
+	}
+	require.NotNil(t, joined)
+	assert.Equal(t, models.MembershipFull, joined.MembershipType)
+	require.NotNil(t, joined.InvitedBy)
+	assert.Equal(t, inviter.ID, *joined.InvitedBy)
+
+	w = do(inviter.ID, http.MethodPost, invitesPath, CreateInviteRequest{MembershipType: models.MembershipPending})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var reusable models.TribeInvite
+	decode(w, &reusable)
+
+	w = do(newcomer.ID, http.MethodGet, invitesPath, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed []models.TribeInvite
+	decode(w, &listed)
+	assert.Len(t, listed, 2)
+
+	assert.Equal(t, http.StatusConflict, do(newcomer.ID, http.MethodPost, "/api/invites/redeem", RedeemInviteRequest{Token: reusable.Token}).Code)
+	assert.Equal(t, http.StatusForbidden, do(newcomer.ID, http.MethodDelete, invitesPath+"/"+reusable.ID.String(), nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(inviter.ID, http.MethodDelete, invitesPath+"/"+uuid.New().String(), nil).Code)
+	assert.Equal(t, http.StatusNoContent, do(inviter.ID, http.MethodDelete, invitesPath+"/"+reusable.ID.String(), nil).Code)
+	rec := do(latecomer.ID, http.MethodPost, "/api/invites/redeem", RedeemInviteRequest{Token: reusable.Token})
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"INVITE_UNAVAILABLE"`)
+}
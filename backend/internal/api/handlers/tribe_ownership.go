@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// TransferOwnershipRequest represents the request to offer tribe ownership
+type TransferOwnershipRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+// OwnershipTransferResponse represents the recipient's answer to a transfer
+type OwnershipTransferResponse struct {
+	Action string `json:"action" binding:"required"` // "accept" or "decline"
+}
+
+// TransferOwnership offers the caller's ownership to another full member.
+// Nothing changes until the recipient accepts.
+func (h *TribeHandler) TransferOwnership(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req TransferOwnershipRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionTransferOwnership); authErr != nil {
+		writeTribeAuthError(c, authErr, "Only tribe owners can transfer ownership")
+		return
+	}
+	if req.UserID == userID {
+		response.GinBadRequest(c, "You cannot transfer ownership to yourself")
+		return
+	}
+
+	members, err := h.repos.Tribes.GetMembers(tribeID)
+	if err != nil {
+		response.GinInternalError(c, err)
+		return
+	}
+	target := findTribeMember(members, req.UserID)
+	if target == nil {
+		response.GinNotFound(c, "Tribe member not found")
+		return
+	}
+	if target.MembershipType != models.MembershipFull {
+		response.GinBadRequest(c, "Ownership can only be transferred to a full member")
+		return
+	}
+	if target.Role == models.TribeRoleOwner {
+		response.GinBadRequest(c, "That member is already an owner")
+		return
+	}
+
+	transfer := &models.TribeOwnershipTransfer{
+		TribeID:    tribeID,
+		FromUserID: userID,
+		ToUserID:   req.UserID,
+	}
+	if err = h.repos.OwnershipTransfers.Create(transfer); err != nil {
+		if errors.Is(err, models.ErrDuplicate) {
+			response.GinConflict(c, "This tribe already has a pending ownership transfer")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinCreated(c, transfer)
+}
+
+// GetOwnershipTransfer returns the tribe's pending ownership transfer
+func (h *TribeHandler) GetOwnershipTransfer(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	transfer, ok := h.pendingOwnershipTransfer(c, userID, tribeID)
+	if !ok {
+		return
+	}
+
+	response.GinSuccess(c, transfer)
+}
+
+// RespondToOwnershipTransfer lets the recipient accept or decline a pending
+// transfer. Accepting makes them an owner and the sender an admin.
+func (h *TribeHandler) RespondToOwnershipTransfer(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	var req OwnershipTransferResponse
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		response.GinBadRequest(c, "Invalid request body")
+		return
+	}
+	if req.Action != "accept" && req.Action != "decline" {
+		response.GinBadRequest(c, "Action must be either 'accept' or 'decline'")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	transfer, ok := h.pendingOwnershipTransfer(c, userID, tribeID)
+	if !ok {
+		return
+	}
+	if transfer.ToUserID != userID {
+		response.GinForbidden(c, "Only the recipient can respond to this transfer")
+		return
+	}
+
+	status, message := models.OwnershipTransferDeclined, "Ownership transfer declined"
+	if req.Action == "accept" {
+		status, message = models.OwnershipTransferAccepted, "Ownership transfer accepted"
+	}
+
+	if err = h.repos.OwnershipTransfers.Resolve(transfer.ID, status); err != nil {
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			response.GinNotFound(c, "No pending ownership transfer")
+		case errors.Is(err, models.ErrConflict):
+			response.GinConflict(c, err.Error())
+		default:
+			response.GinInternalError(c, err)
+		}
+		return
+	}
+
+	response.GinSuccess(c, gin.H{"message": message})
+}
+
+// CancelOwnershipTransfer withdraws a pending transfer. The sender or any
+// other owner may cancel it.
+func (h *TribeHandler) CancelOwnershipTransfer(c *gin.Context) {
+	tribeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.GinBadRequest(c, "Invalid tribe ID")
+		return
+	}
+
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		response.GinUnauthorized(c, "Authentication required")
+		return
+	}
+
+	transfer, ok := h.pendingOwnershipTransfer(c, userID, tribeID)
+	if !ok {
+		return
+	}
+	if transfer.FromUserID != userID {
+		if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionTransferOwnership); authErr != nil {
+			writeTribeAuthError(c, authErr, "Only tribe owners can cancel an ownership transfer")
+			return
+		}
+	}
+
+	if err = h.repos.OwnershipTransfers.Resolve(transfer.ID, models.OwnershipTransferCancelled); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "No pending ownership transfer")
+			return
+		}
+		response.GinInternalError(c, err)
+		return
+	}
+
+	response.GinNoContent(c)
+}
+
+// pendingOwnershipTransfer loads the tribe's pending transfer for a member,
+// writing the error response and returning false if there is none
+func (h *TribeHandler) pendingOwnershipTransfer(c *gin.Context, userID, tribeID uuid.UUID) (*models.TribeOwnershipTransfer, bool) {
+	if _, authErr := h.authz.Authorize(userID, tribeID, models.PermissionViewTribe); authErr != nil {
+		writeTribeAuthError(c, authErr, "You cannot view this tribe")
+		return nil, false
+	}
+
+	transfer, err := h.repos.OwnershipTransfers.GetPending(tribeID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			response.GinNotFound(c, "No pending ownership transfer")
+			return nil, false
+		}
+		response.GinInternalError(c, err)
+		return nil, false
+	}
+
+	return transfer, true
+}
+
+// findTribeMember returns the user's membership among members, or nil
+func findTribeMember(members []*models.TribeMember, userID uuid.UUID) *models.TribeMember {
+	for _, member := range members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
@@ -89,6 +89,7 @@ func TestCreateTribe(t *testing.T) {
 				require.Len(t, members, 1, "Creator should be added as a member")
 				assert.Equal(t, testUser.ID, members[0].UserID)
 				assert.Equal(t, models.MembershipFull, members[0].MembershipType)
+				assert.Equal(t, models.TribeRoleOwner, members[0].Role, "Creator should own the tribe")
 			},
 		},
 		{
@@ -926,6 +927,8 @@ func TestUpdateTribe(t *testing.T) {
 	require.NoError(t, err)
 	err = repos.Tribes.AddMember(tribe.ID, testUser.ID, models.MembershipFull, nil, nil)
 	require.NoError(t, err)
+	err = repos.Tribes.SetMemberRole(tribe.ID, testUser.ID, models.TribeRoleOwner)
+	require.NoError(t, err)
 
 	// Create a tribe that the user doesn't own for permission testing
 	otherUser := testutil.CreateTestUser(t, repos.DB())
@@ -1119,7 +1122,7 @@ func TestUpdateTribe(t *testing.T) {
 				require.NoError(t, err)
 				assert.False(t, response.Success)
 				assert.Equal(t, "FORBIDDEN", response.Error.Code)
-				assert.Contains(t, response.Error.Message, "Only full members can update")
+				assert.Contains(t, response.Error.Message, "Only tribe owners and admins can update")
 			},
 		},
 		{
@@ -1656,6 +1659,8 @@ func TestDeleteTribe(t *testing.T) {
 	require.NoError(t, err)
 	err = repos.Tribes.AddMember(tribe.ID, testUser.ID, models.MembershipFull, nil, nil)
 	require.NoError(t, err)
+	err = repos.Tribes.SetMemberRole(tribe.ID, testUser.ID, models.TribeRoleOwner)
+	require.NoError(t, err)
 
 	tests := []struct {
 		name       string
@@ -1670,7 +1675,7 @@ func TestDeleteTribe(t *testing.T) {
 		{
 			name:       "non-existent tribe",
 			tribeID:    uuid.New().String(),
-			wantStatus: http.StatusInternalServerError,
+			wantStatus: http.StatusNotFound,
 		},
 		{
 			name:       "invalid uuid",
@@ -1859,9 +1864,11 @@ func TestRemoveMember(t *testing.T) {
 	err := repos.Tribes.Create(tribe)
 	require.NoError(t, err)
 
-	// Add the test user
+	// Add the test user as the owner
 	err = repos.Tribes.AddMember(tribe.ID, testUser.ID, models.MembershipFull, nil, nil)
 	require.NoError(t, err)
+	err = repos.Tribes.SetMemberRole(tribe.ID, testUser.ID, models.TribeRoleOwner)
+	require.NoError(t, err)
 
 	// Create and add another test user
 	memberToRemove := testutil.CreateTestUser(t, repos.DB())
@@ -2065,18 +2072,22 @@ func TestListMembers(t *testing.T) {
 			},
 		},
 		{
-			name:           "success - empty tribe",
+			name:           "error - not a member",
 			tribeID:        emptyTribe.ID,
 			usesMock:       false,
-			wantStatusCode: http.StatusOK,
+			wantStatusCode: http.StatusForbidden,
 			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
 				var response struct {
-					Success bool                  `json:"success"`
-					Data    []*models.TribeMember `json:"data"`
+					Success bool `json:"success"`
+					Error   struct {
+						Code    string `json:"code"`
+						Message string `json:"message"`
+					} `json:"error"`
 				}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				require.NoError(t, err)
-				assert.Len(t, response.Data, 0, "Should return empty member list")
+				assert.False(t, response.Success, "Response should indicate failure")
+				assert.Equal(t, "You must be a member of this tribe", response.Error.Message)
 			},
 		},
 		{
@@ -2505,3 +2516,338 @@ func TestGetTribe_PendingMemberMinimalInfo(t *testing.T) {
 	assert.Equal(t, "pending", resp.Data["current_user_membership_type"])
 	assert.Nil(t, resp.Data["members"])
 }
+
+func TestTribeRoles(t *testing.T) {
+	_, repos, owner := setupTribeTest(t)
+	admin := testutil.CreateTestUser(t, repos.DB())
+	member := testutil.CreateTestUser(t, repos.DB())
+	limited := testutil.CreateTestUser(t, repos.DB())
+	invitee := testutil.CreateTestUser(t, repos.DB())
+
+	tribe := &models.Tribe{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		},
+		Name:       "Role Tribe",
+		Type:       models.TribeTypeFriends,
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repos.Tribes.Create(tribe))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, owner.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.SetMemberRole(tribe.ID, owner.ID, models.TribeRoleOwner))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, admin.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, member.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, limited.ID, models.MembershipLimited, nil, nil))
+
+	do := func(user testutil.TestUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+		return doTribeRequestAs(t, repos, user, method, path, body)
+	}
+	tribePath := "/api/tribes/" + tribe.ID.String()
+	rolePath := func(user testutil.TestUser) string {
+		return tribePath + "/members/" + user.ID.String() + "/role"
+	}
+
+	t.Run("owner manages roles", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(member, http.MethodPut, rolePath(admin), UpdateMemberRoleRequest{Role: models.TribeRoleAdmin}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(*owner, http.MethodPut, rolePath(admin), UpdateMemberRoleRequest{Role: models.TribeRoleOwner}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(*owner, http.MethodPut, rolePath(limited), UpdateMemberRoleRequest{Role: models.TribeRoleAdmin}).Code)
+		assert.Equal(t, http.StatusNotFound, do(*owner, http.MethodPut, rolePath(invitee), UpdateMemberRoleRequest{Role: models.TribeRoleAdmin}).Code)
+
+		w := do(*owner, http.MethodPut, rolePath(admin), UpdateMemberRoleRequest{Role: models.TribeRoleAdmin})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		members, err := repos.Tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, m := range members {
+			if m.UserID == admin.ID {
+				assert.Equal(t, models.TribeRoleAdmin, m.Role)
+			}
+		}
+
+		assert.Equal(t, http.StatusForbidden, do(admin, http.MethodPut, rolePath(member), UpdateMemberRoleRequest{Role: models.TribeRoleAdmin}).Code)
+	})
+
+	t.Run("membership limits invitations", func(t *testing.T) {
+		body := AddMemberRequest{UserID: invitee.ID}
+		assert.Equal(t, http.StatusForbidden, do(limited, http.MethodPost, tribePath+"/members", body).Code)
+		assert.Equal(t, http.StatusNoContent, do(member, http.MethodPost, tribePath+"/members", body).Code)
+	})
+
+	t.Run("pending members have no rights", func(t *testing.T) {
+		update := UpdateTribeRequest{Name: "Pending Rename", Version: tribe.Version}
+		assert.Equal(t, http.StatusForbidden, do(invitee, http.MethodPut, tribePath, update).Code)
+	})
+
+	t.Run("admins update but cannot delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(member, http.MethodPut, tribePath, UpdateTribeRequest{Name: "Member Rename", Version: tribe.Version}).Code)
+		assert.Equal(t, http.StatusOK, do(admin, http.MethodPut, tribePath, UpdateTribeRequest{Name: "Admin Rename", Version: tribe.Version}).Code)
+		assert.Equal(t, http.StatusForbidden, do(admin, http.MethodDelete, tribePath, nil).Code)
+	})
+
+	t.Run("removal follows rank", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(member, http.MethodDelete, tribePath+"/members/"+limited.ID.String(), nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(admin, http.MethodDelete, tribePath+"/members/"+owner.ID.String(), nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(admin, http.MethodDelete, tribePath+"/members/"+limited.ID.String(), nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(member, http.MethodDelete, tribePath+"/members/"+member.ID.String(), nil).Code)
+	})
+
+	t.Run("owner deletes", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, do(*owner, http.MethodDelete, tribePath, nil).Code)
+	})
+}
+
+func TestUpdateMyMemberProfile(t *testing.T) {
+	_, repos, owner := setupTribeTest(t)
+	invitee := testutil.CreateTestUser(t, repos.DB())
+	outsider := testutil.CreateTestUser(t, repos.DB())
+
+	tribe := &models.Tribe{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		},
+		Name:       "Profile Tribe",
+		Type:       models.TribeTypeFriends,
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repos.Tribes.Create(tribe))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, owner.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, invitee.ID, models.MembershipPending, nil, &owner.ID))
+
+	path := "/api/tribes/" + tribe.ID.String() + "/members/me"
+	do := func(user testutil.TestUser, body interface{}) *httptest.ResponseRecorder {
+		return doTribeRequestAs(t, repos, user, http.MethodPut, path, body)
+	}
+
+	t.Run("sets the member's profile", func(t *testing.T) {
+		w := do(*owner, map[string]string{"display_name": "  Captain ", "pronouns": "she/her"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Data models.TribeMember `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Captain", resp.Data.DisplayName)
+		assert.Equal(t, "she/her", resp.Data.Pronouns)
+		assert.Equal(t, owner.ID, resp.Data.UserID)
+	})
+
+	t.Run("clearing falls back to the account name", func(t *testing.T) {
+		w := do(*owner, map[string]string{"display_name": ""})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		members, err := repos.Tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, m := range members {
+			if m.UserID == owner.ID {
+				assert.Equal(t, owner.Name, m.DisplayName)
+				assert.Equal(t, "she/her", m.Pronouns)
+			}
+		}
+	})
+
+	t.Run("invalid profiles are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(*owner, map[string]string{}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(*owner, map[string]string{"avatar_url": "ftp://example.com/me.png"}).Code)
+	})
+
+	t.Run("only active members", func(t *testing.T) {
+		body := map[string]string{"display_name": "Lurker"}
+		assert.Equal(t, http.StatusForbidden, do(invitee, body).Code)
+		assert.Equal(t, http.StatusForbidden, do(outsider, body).Code)
+	})
+}
+
+func TestTribeFeed(t *testing.T) {
+	_, repos, owner := setupTribeTest(t)
+	outsider := testutil.CreateTestUser(t, repos.DB())
+
+	tribe := &models.Tribe{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		},
+		Name:       "Feed Tribe",
+		Type:       models.TribeTypeFriends,
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repos.Tribes.Create(tribe))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, owner.ID, models.MembershipFull, nil, nil))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, repos.Events.Create(&models.TribeEvent{
+			TribeID:   tribe.ID,
+			Type:      models.EventListItemAdded,
+			ActorID:   &owner.ID,
+			SubjectID: uuid.New(),
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	feedPath := "/api/tribes/" + tribe.ID.String() + "/feed"
+	getFeed := func(user testutil.TestUser, query string) (*httptest.ResponseRecorder, TribeFeedResponse) {
+		w := doTribeRequestAs(t, repos, user, http.MethodGet, feedPath+query, nil)
+		var resp struct {
+			Data TribeFeedResponse `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp.Data
+	}
+
+	t.Run("pages newest first", func(t *testing.T) {
+		w, first := getFeed(*owner, "?limit=3")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, first.Events, 3)
+		require.NotEmpty(t, first.NextCursor)
+		assert.Equal(t, models.EventListItemAdded, first.Events[0].Type)
+		assert.True(t, first.Events[0].CreatedAt.After(first.Events[1].CreatedAt))
+		assert.Equal(t, owner.Name, first.Events[0].ActorName)
+
+		w, second := getFeed(*owner, "?limit=3&cursor="+first.NextCursor)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Len(t, second.Events, 2, "the owner's join and the oldest item remain")
+		assert.Empty(t, second.NextCursor)
+		assert.Equal(t, models.EventMemberJoined, second.Events[1].Type)
+	})
+
+	t.Run("rejects bad parameters", func(t *testing.T) {
+		w, _ := getFeed(*owner, "?cursor=nope")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w, _ = getFeed(*owner, "?limit=0")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("members only", func(t *testing.T) {
+		w, _ := getFeed(outsider, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestTribeOwnershipTransfer(t *testing.T) {
+	_, repos, owner := setupTribeTest(t)
+	heir := testutil.CreateTestUser(t, repos.DB())
+	limited := testutil.CreateTestUser(t, repos.DB())
+	outsider := testutil.CreateTestUser(t, repos.DB())
+
+	tribe := &models.Tribe{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		},
+		Name:       "Ownership Tribe",
+		Type:       models.TribeTypeFriends,
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repos.Tribes.Create(tribe))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, owner.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.SetMemberRole(tribe.ID, owner.ID, models.TribeRoleOwner))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, heir.ID, models.MembershipFull, nil, nil))
+	require.NoError(t, repos.Tribes.AddMember(tribe.ID, limited.ID, models.MembershipLimited, nil, nil))
+
+	do := func(user testutil.TestUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+		return doTribeRequestAs(t, repos, user, method, path, body)
+	}
+	tribePath := "/api/tribes/" + tribe.ID.String()
+	transferPath := tribePath + "/ownership-transfer"
+	roleOf := func(userID uuid.UUID) models.TribeRole {
+		members, err := repos.Tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, m := range members {
+			if m.UserID == userID {
+				return m.Role
+			}
+		}
+		return ""
+	}
+
+	t.Run("last owner cannot leave", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, do(*owner, http.MethodDelete, tribePath+"/members/"+owner.ID.String(), nil).Code)
+		assert.Equal(t, models.TribeRoleOwner, roleOf(owner.ID))
+	})
+
+	t.Run("offer validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(heir, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: limited.ID}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: owner.ID}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: limited.ID}).Code)
+		assert.Equal(t, http.StatusNotFound, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: outsider.ID}).Code)
+		assert.Equal(t, http.StatusNotFound, do(heir, http.MethodGet, transferPath, nil).Code)
+	})
+
+	t.Run("decline keeps roles", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: heir.ID}).Code)
+		assert.Equal(t, http.StatusConflict, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: heir.ID}).Code)
+
+		w := do(heir, http.MethodGet, transferPath, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data models.TribeOwnershipTransfer `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, heir.ID, resp.Data.ToUserID)
+		assert.Equal(t, models.OwnershipTransferPending, resp.Data.Status)
+
+		assert.Equal(t, http.StatusForbidden, do(*owner, http.MethodPost, transferPath+"/respond", OwnershipTransferResponse{Action: "accept"}).Code)
+		assert.Equal(t, http.StatusBadRequest, do(heir, http.MethodPost, transferPath+"/respond", OwnershipTransferResponse{Action: "maybe"}).Code)
+		require.Equal(t, http.StatusOK, do(heir, http.MethodPost, transferPath+"/respond", OwnershipTransferResponse{Action: "decline"}).Code)
+
+		assert.Equal(t, http.StatusNotFound, do(heir, http.MethodGet, transferPath, nil).Code)
+		assert.Equal(t, models.TribeRoleOwner, roleOf(owner.ID))
+		assert.Equal(t, models.TribeRoleMember, roleOf(heir.ID))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: heir.ID}).Code)
+		assert.Equal(t, http.StatusForbidden, do(heir, http.MethodDelete, transferPath, nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(*owner, http.MethodDelete, transferPath, nil).Code)
+		assert.Equal(t, http.StatusNotFound, do(heir, http.MethodPost, transferPath+"/respond", OwnershipTransferResponse{Action: "accept"}).Code)
+	})
+
+	t.Run("accept swaps roles", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, do(*owner, http.MethodPost, transferPath, TransferOwnershipRequest{UserID: heir.ID}).Code)
+		require.Equal(t, http.StatusOK, do(heir, http.MethodPost, transferPath+"/respond", OwnershipTransferResponse{Action: "accept"}).Code)
+
+		assert.Equal(t, models.TribeRoleOwner, roleOf(heir.ID))
+		assert.Equal(t, models.TribeRoleAdmin, roleOf(owner.ID))
+
+		assert.Equal(t, http.StatusConflict, do(heir, http.MethodDelete, tribePath+"/members/"+heir.ID.String(), nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(*owner, http.MethodDelete, tribePath+"/members/"+owner.ID.String(), nil).Code)
+	})
+}
+
+// doTribeRequestAs sends a request through a tribe router authenticated as
+// the given user
+func doTribeRequestAs(t *testing.T, repos *postgres.Repositories, user testutil.TestUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(string(middleware.ContextFirebaseUIDKey), user.FirebaseUID)
+		c.Set(string(middleware.ContextUserIDKey), user.ID)
+		c.Next()
+	})
+	NewTribeHandler(repos).RegisterRoutes(router.Group("/api"))
+
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
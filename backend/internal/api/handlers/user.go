@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,9 +16,16 @@ type RepositoryProvider interface {
 	GetUserRepository() models.UserRepository
 }
 
+// PendingInvitationConverter turns email invitations into tribe
+// memberships once the invited user exists
+type PendingInvitationConverter interface {
+	ConvertPendingInvitations(user *models.User) error
+}
+
 // UserHandler handles user-related requests
 type UserHandler struct {
-	repos RepositoryProvider
+	repos       RepositoryProvider
+	invitations PendingInvitationConverter
 }
 
 // NewUserHandler creates a new user handler
@@ -27,6 +35,22 @@ func NewUserHandler(repos RepositoryProvider) *UserHandler {
 	}
 }
 
+// SetInvitationConverter sets the converter run for newly created users
+func (h *UserHandler) SetInvitationConverter(converter PendingInvitationConverter) {
+	h.invitations = converter
+}
+
+// convertPendingInvitations makes a new user a member of the tribes their
+// email was invited to. Failures are logged so sign-up still succeeds.
+func (h *UserHandler) convertPendingInvitations(user *models.User) {
+	if h.invitations == nil {
+		return
+	}
+	if err := h.invitations.ConvertPendingInvitations(user); err != nil {
+		log.Printf("Error converting pending invitations for user %s: %v", user.ID, err)
+	}
+}
+
 // RegisterRoutes registers the user routes
 func (h *UserHandler) RegisterRoutes(r *gin.RouterGroup) {
 	users := r.Group("/users")
@@ -82,6 +106,7 @@ func (h *UserHandler) AuthenticateUser(c *gin.Context) {
 			response.GinInternalError(c, err)
 			return
 		}
+		h.convertPendingInvitations(user)
 		response.GinCreated(c, user)
 		return
 	}
@@ -170,6 +195,7 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 		response.GinInternalError(c, err)
 		return
 	}
+	h.convertPendingInvitations(user)
 
 	response.GinCreated(c, user)
 }
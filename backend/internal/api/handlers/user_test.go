@@ -139,6 +139,76 @@ func TestAuthenticateUser(t *testing.T) {
 	}
 }
 
+// recordingConverter records the users pending invitations are converted for
+type recordingConverter struct {
+	users []*models.User
+	err   error
+}
+
+func (r *recordingConverter) ConvertPendingInvitations(user *models.User) error {
+	r.users = append(r.users, user)
+	return r.err
+}
+
+func TestNewUsersConvertPendingInvitations(t *testing.T) {
+	setup := testutil.SetupTest(t)
+	setup.Repositories.Users.GetByFirebaseUIDFunc = func(firebaseUID string) (*models.User, error) {
+		if firebaseUID == setup.TestUser.FirebaseUID {
+			return setup.TestUser, nil
+		}
+		return nil, errors.New("user not found")
+	}
+	setup.Repositories.Users.GetByEmailFunc = func(email string) (*models.User, error) {
+		return nil, errors.New("user not found")
+	}
+
+	converter := &recordingConverter{}
+	handler := NewUserHandler(setup.Repositories)
+	handler.SetInvitationConverter(converter)
+
+	post := func(path string, body map[string]interface{}, h gin.HandlerFunc) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBody, err := json.Marshal(body)
+		require.NoError(t, err)
+		c.Request = httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h(c)
+		return w
+	}
+
+	w := post("/api/users/auth", map[string]interface{}{
+		"firebase_uid": "invited-uid",
+		"provider":     "google",
+		"email":        "invited@example.com",
+		"name":         "Invited",
+	}, handler.AuthenticateUser)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, converter.users, 1)
+	assert.Equal(t, "invited@example.com", converter.users[0].Email)
+
+	w = post("/api/users/auth", map[string]interface{}{
+		"firebase_uid": setup.TestUser.FirebaseUID,
+		"provider":     string(setup.TestUser.Provider),
+		"email":        setup.TestUser.Email,
+		"name":         setup.TestUser.Name,
+	}, handler.AuthenticateUser)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, converter.users, 1, "existing users are not converted again")
+
+	// A failed conversion does not fail the sign-up
+	converter.err = errors.New("conversion failed")
+	w = post("/api/users/register", map[string]interface{}{
+		"email":    "registered@example.com",
+		"name":     "Registered",
+		"provider": "email",
+	}, handler.RegisterUser)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Len(t, converter.users, 2)
+	assert.Equal(t, "registered@example.com", converter.users[1].Email)
+}
+
 func TestGetCurrentUser(t *testing.T) {
 	setup := testutil.SetupTest(t)
 	handler := NewUserHandler(setup.Repositories)
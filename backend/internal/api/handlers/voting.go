@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/api/response"
+	"github.com/jenglund/rlship-tools/internal/api/service"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// VotingHandler handles tribe votes on generated menus
+type VotingHandler struct {
+	service service.VotingService
+}
+
+// NewVotingHandler creates a new voting handler
+func NewVotingHandler(service service.VotingService) *VotingHandler {
+	return &VotingHandler{service: service}
+}
+
+// RegisterRoutes registers the voting routes
+func (h *VotingHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/lists/menu", func(r chi.Router) {
+		r.Post("/vote", h.StartVote)
+		r.Post("/sessions/{sessionID}/votes", h.CastVotes)
+		r.Get("/sessions/{sessionID}/votes", h.GetTally)
+		r.Post("/sessions/{sessionID}/close", h.CloseVote)
+	})
+}
+
+// startVoteRequest is the body accepted by StartVote
+type startVoteRequest struct {
+	models.MenuParams
+	TribeID uuid.UUID         `json:"tribe_id"`
+	Rule    models.VotingRule `json:"rule"`
+}
+
+// StartVote handles generating a tribe menu and opening it for voting
+func (h *VotingHandler) StartVote(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req startVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TribeID == uuid.Nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Rule == "" {
+		req.Rule = models.VotingRuleMajority
+	}
+
+	session, err := h.service.StartVote(userID, req.TribeID, req.Rule, &req.MenuParams)
+	if err != nil {
+		h.handleError(w, err, "Failed to start vote")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, session)
+}
+
+// CastVotes handles recording the current user's votes
+func (h *VotingHandler) CastVotes(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID, err := extractUUIDParam(r, "sessionID")
+	if err != nil {
+		log.Printf("Error parsing sessionID from URL parameter: %v", err)
+		response.Error(w, http.StatusBadRequest, "Invalid session ID: "+err.Error())
+		return
+	}
+
+	var req struct {
+		Votes []*models.MenuVote `json:"votes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.CastVotes(userID, sessionID, req.Votes); err != nil {
+		h.handleError(w, err, "Failed to cast votes")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetTally handles retrieving the current vote counts
+func (h *VotingHandler) GetTally(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID, err := extractUUIDParam(r, "sessionID")
+	if err != nil {
+		log.Printf("Error parsing sessionID from URL parameter: %v", err)
+		response.Error(w, http.StatusBadRequest, "Invalid session ID: "+err.Error())
+		return
+	}
+
+	tally, err := h.service.GetTally(userID, sessionID)
+	if err != nil {
+		h.handleError(w, err, "Failed to get votes")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tally)
+}
+
+// CloseVote handles settling the vote and recording the winner
+func (h *VotingHandler) CloseVote(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	sessionID, err := extractUUIDParam(r, "sessionID")
+	if err != nil {
+		log.Printf("Error parsing sessionID from URL parameter: %v", err)
+		response.Error(w, http.StatusBadRequest, "Invalid session ID: "+err.Error())
+		return
+	}
+
+	session, tally, err := h.service.CloseVote(userID, sessionID)
+	if errors.Is(err, models.ErrNoVoteWinner) {
+		response.JSON(w, http.StatusConflict, map[string]interface{}{
+			"success": false,
+			"error": map[string]interface{}{
+				"message": err.Error(),
+			},
+			"tally": tally,
+		})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, "Failed to close vote")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"session": session,
+		"tally":   tally,
+	})
+}
+
+// handleError maps service errors to HTTP responses
+func (h *VotingHandler) handleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "Not found")
+	case errors.Is(err, models.ErrInvalidInput), errors.Is(err, models.ErrInvalidVotingRule):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrForbidden):
+		response.Error(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, models.ErrConflict):
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		response.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
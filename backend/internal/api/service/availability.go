@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// MaxCalendarImportSize is the largest iCalendar file accepted, in bytes
+	MaxCalendarImportSize = 2 << 20
+	// availabilityLookback and availabilityHorizon bound the busy time kept
+	// from an import, relative to when it happens
+	availabilityLookback = 24 * time.Hour
+	availabilityHorizon  = 180 * 24 * time.Hour
+)
+
+// AvailabilityService defines the interface for importing members' busy
+// time and proposing times when a tribe is free
+type AvailabilityService interface {
+	// UploadCalendar imports an uploaded iCalendar file
+	UploadCalendar(userID uuid.UUID, name string, data io.Reader) (*models.AvailabilityCalendar, error)
+	// LinkCalendar imports an iCalendar file from a URL that can later be
+	// refreshed
+	LinkCalendar(ctx context.Context, userID uuid.UUID, name, calendarURL string) (*models.AvailabilityCalendar, error)
+	// RefreshCalendar imports a linked calendar again
+	RefreshCalendar(ctx context.Context, userID, calendarID uuid.UUID) (*models.AvailabilityCalendar, error)
+	// ListCalendars returns a user's imported calendars
+	ListCalendars(userID uuid.UUID) ([]*models.AvailabilityCalendar, error)
+	// DeleteCalendar removes an imported calendar and its busy time
+	DeleteCalendar(userID, calendarID uuid.UUID) error
+	// BusyIntervals returns a user's busy time overlapping [from, to)
+	BusyIntervals(userID uuid.UUID, from, to time.Time) ([]*models.BusyInterval, error)
+	// ProposeSlots finds the times when enough of a tribe's active members
+	// are free
+	ProposeSlots(tribeID uuid.UUID, query models.SlotQuery) ([]models.TimeSlot, error)
+}
+
+// availabilityService implements the AvailabilityService interface
+type availabilityService struct {
+	repo   models.AvailabilityRepository
+	tribes models.TribeRepository
+	client *http.Client
+}
+
+// NewAvailabilityService creates a new availability service. A nil client
+// uses one that times out after ten seconds and refuses to connect to
+// loopback, private and link-local addresses, since linked URLs come from
+// users.
+func NewAvailabilityService(repo models.AvailabilityRepository, tribes models.TribeRepository, client *http.Client) AvailabilityService {
+	if client == nil {
+		client = newPublicHTTPClient(10 * time.Second)
+	}
+	return &availabilityService{
+		repo:   repo,
+		tribes: tribes,
+		client: client,
+	}
+}
+
+// UploadCalendar implements AvailabilityService
+func (s *availabilityService) UploadCalendar(userID uuid.UUID, name string, data io.Reader) (*models.AvailabilityCalendar, error) {
+	content, err := readCalendar(data)
+	if err != nil {
+		return nil, err
+	}
+	calendar := &models.AvailabilityCalendar{UserID: userID, Name: strings.TrimSpace(name)}
+	return s.create(calendar, content)
+}
+
+// LinkCalendar implements AvailabilityService
+func (s *availabilityService) LinkCalendar(ctx context.Context, userID uuid.UUID, name, calendarURL string) (*models.AvailabilityCalendar, error) {
+	calendar := &models.AvailabilityCalendar{
+		UserID: userID,
+		Name:   strings.TrimSpace(name),
+		URL:    strings.TrimSpace(calendarURL),
+	}
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	content, err := s.fetch(ctx, calendar.URL)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(calendar, content)
+}
+
+// create stores a calendar and the busy time read from its content. The
+// content is parsed first so that unreadable files leave nothing behind.
+func (s *availabilityService) create(calendar *models.AvailabilityCalendar, content []byte) (*models.AvailabilityCalendar, error) {
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	intervals, err := parseImportWindow(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.CreateCalendar(calendar); err != nil {
+		return nil, err
+	}
+	if err = s.repo.ReplaceBusyIntervals(calendar.ID, intervals); err != nil {
+		if deleteErr := s.repo.DeleteCalendar(calendar.UserID, calendar.ID); deleteErr != nil {
+			return nil, errors.Join(err, deleteErr)
+		}
+		return nil, err
+	}
+	return s.repo.GetCalendar(calendar.ID)
+}
+
+// RefreshCalendar implements AvailabilityService. Uploaded calendars have
+// nothing to refresh from and are rejected.
+func (s *availabilityService) RefreshCalendar(ctx context.Context, userID, calendarID uuid.UUID) (*models.AvailabilityCalendar, error) {
+	calendar, err := s.ownCalendar(userID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if !calendar.IsLinked() {
+		return nil, fmt.Errorf("%w: uploaded calendars are refreshed by uploading them again", models.ErrInvalidInput)
+	}
+
+	content, err := s.fetch(ctx, calendar.URL)
+	if err != nil {
+		return nil, err
+	}
+	intervals, err := parseImportWindow(content)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.ReplaceBusyIntervals(calendar.ID, intervals); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCalendar(calendar.ID)
+}
+
+// ListCalendars implements AvailabilityService
+func (s *availabilityService) ListCalendars(userID uuid.UUID) ([]*models.AvailabilityCalendar, error) {
+	return s.repo.ListCalendars(userID)
+}
+
+// DeleteCalendar implements AvailabilityService
+func (s *availabilityService) DeleteCalendar(userID, calendarID uuid.UUID) error {
+	return s.repo.DeleteCalendar(userID, calendarID)
+}
+
+// BusyIntervals implements AvailabilityService
+func (s *availabilityService) BusyIntervals(userID uuid.UUID, from, to time.Time) ([]*models.BusyInterval, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", models.ErrInvalidInput)
+	}
+	return s.repo.GetBusyIntervals([]uuid.UUID{userID}, from, to)
+}
+
+// ProposeSlots implements AvailabilityService. Members without imported
+// calendars count as free, as does time past the import horizon.
+func (s *availabilityService) ProposeSlots(tribeID uuid.UUID, query models.SlotQuery) ([]models.TimeSlot, error) {
+	members, err := s.tribes.GetMembers(tribeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	memberIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if member.IsActive(now) {
+			memberIDs = append(memberIDs, member.UserID)
+		}
+	}
+	if err = query.Validate(len(memberIDs)); err != nil {
+		return nil, err
+	}
+
+	busy, err := s.repo.GetBusyIntervals(memberIDs, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	return models.ProposeSlots(memberIDs, busy, query)
+}
+
+// ownCalendar returns one of a user's calendars; other users' calendars are
+// reported as not found
+func (s *availabilityService) ownCalendar(userID, calendarID uuid.UUID) (*models.AvailabilityCalendar, error) {
+	calendar, err := s.repo.GetCalendar(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar.UserID != userID {
+		return nil, models.ErrNotFound
+	}
+	return calendar, nil
+}
+
+// fetch downloads a linked calendar, mapping failures onto the external
+// source errors. webcal URLs are fetched over HTTPS.
+func (s *availabilityService) fetch(ctx context.Context, calendarURL string) ([]byte, error) {
+	u, err := url.Parse(calendarURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid calendar URL", models.ErrInvalidInput)
+	}
+	if strings.EqualFold(u.Scheme, "webcal") {
+		u.Scheme = "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrExternalSourceError, err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, fmt.Errorf("%w: %v", models.ErrExternalSourceTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: %v", models.ErrExternalSourceUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusGatewayTimeout:
+		return nil, fmt.Errorf("%w: calendar host returned %d", models.ErrExternalSourceTimeout, resp.StatusCode)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: calendar host returned %d", models.ErrExternalSourceUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: calendar host returned %d", models.ErrExternalSourceError, resp.StatusCode)
+	}
+
+	return readCalendar(resp.Body)
+}
+
+// readCalendar reads an iCalendar file, refusing ones over the size limit
+func readCalendar(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, MaxCalendarImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar: %w", err)
+	}
+	if len(content) > MaxCalendarImportSize {
+		return nil, fmt.Errorf("%w: calendar is larger than %d bytes", models.ErrInvalidInput, MaxCalendarImportSize)
+	}
+	return content, nil
+}
+
+// parseImportWindow reads the busy time of a calendar from shortly before
+// now up to the import horizon
+func parseImportWindow(content []byte) ([]models.BusyInterval, error) {
+	now := time.Now()
+	return parseBusyIntervals(content, now.Add(-availabilityLookback), now.Add(availabilityHorizon))
+}
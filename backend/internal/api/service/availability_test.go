@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAvailabilityRepository keeps calendars and busy intervals in memory
+type fakeAvailabilityRepository struct {
+	calendars map[uuid.UUID]*models.AvailabilityCalendar
+	busy      map[uuid.UUID][]models.BusyInterval
+}
+
+func newFakeAvailabilityRepository() *fakeAvailabilityRepository {
+	return &fakeAvailabilityRepository{
+		calendars: make(map[uuid.UUID]*models.AvailabilityCalendar),
+		busy:      make(map[uuid.UUID][]models.BusyInterval),
+	}
+}
+
+func (r *fakeAvailabilityRepository) CreateCalendar(calendar *models.AvailabilityCalendar) error {
+	calendar.ID = uuid.New()
+	stored := *calendar
+	r.calendars[calendar.ID] = &stored
+	return nil
+}
+
+func (r *fakeAvailabilityRepository) GetCalendar(id uuid.UUID) (*models.AvailabilityCalendar, error) {
+	calendar, ok := r.calendars[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *calendar
+	return &copied, nil
+}
+
+func (r *fakeAvailabilityRepository) ListCalendars(userID uuid.UUID) ([]*models.AvailabilityCalendar, error) {
+	var calendars []*models.AvailabilityCalendar
+	for _, calendar := range r.calendars {
+		if calendar.UserID == userID {
+			calendars = append(calendars, calendar)
+		}
+	}
+	return calendars, nil
+}
+
+func (r *fakeAvailabilityRepository) DeleteCalendar(userID, id uuid.UUID) error {
+	calendar, ok := r.calendars[id]
+	if !ok || calendar.UserID != userID {
+		return models.ErrNotFound
+	}
+	delete(r.calendars, id)
+	delete(r.busy, id)
+	return nil
+}
+
+func (r *fakeAvailabilityRepository) ReplaceBusyIntervals(calendarID uuid.UUID, intervals []models.BusyInterval) error {
+	calendar, ok := r.calendars[calendarID]
+	if !ok {
+		return models.ErrNotFound
+	}
+	now := time.Now()
+	calendar.LastImportedAt = &now
+	stored := make([]models.BusyInterval, len(intervals))
+	for i, interval := range intervals {
+		interval.CalendarID = calendarID
+		interval.UserID = calendar.UserID
+		stored[i] = interval
+	}
+	r.busy[calendarID] = stored
+	return nil
+}
+
+func (r *fakeAvailabilityRepository) GetBusyIntervals(userIDs []uuid.UUID, from, to time.Time) ([]*models.BusyInterval, error) {
+	wanted := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	var intervals []*models.BusyInterval
+	for _, calendarIntervals := range r.busy {
+		for i := range calendarIntervals {
+			interval := calendarIntervals[i]
+			if wanted[interval.UserID] && interval.Overlaps(from, to) {
+				intervals = append(intervals, &interval)
+			}
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].StartTime.Before(intervals[j].StartTime)
+	})
+	return intervals, nil
+}
+
+// icsDocument wraps components in a calendar, joined with CRLF
+func icsDocument(lines ...string) string {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Test//EN"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return strings.Join(all, "\r\n") + "\r\n"
+}
+
+func TestParseBusyIntervals(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	utc := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("events", func(t *testing.T) {
+		doc := icsDocument(
+			"BEGIN:VEVENT",
+			"UID:standup",
+			"DTSTART;TZID=America/Chicago:20240304T090000",
+			"DURATION:PT30M",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+			"EXDATE;TZID=America/Chicago:20240311T090000",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:standup",
+			"RECURRENCE-ID;TZID=America/Chicago:20240318T090000",
+			"DTSTART;TZID=America/Chicago:20240318T140000",
+			"DTEND;TZID=America/Chicago:20240318T143000",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:lunch",
+			"DTSTART:20240305T170000Z",
+			"DTEND:20240305T180000Z",
+			"SUMMARY:A long summary that is folded onto a second line by the",
+			"  exporting calendar",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:reminder",
+			"DTSTART:20240305T173000Z",
+			"DTEND:20240305T183000Z",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:holiday",
+			"DTSTART;VALUE=DATE:20240320",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:free",
+			"DTSTART:20240306T100000Z",
+			"DTEND:20240306T110000Z",
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:cancelled",
+			"DTSTART:20240307T100000Z",
+			"DTEND:20240307T110000Z",
+			"STATUS:CANCELLED",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:before-window",
+			"DTSTART:20240201T100000Z",
+			"DTEND:20240201T110000Z",
+			"END:VEVENT",
+		)
+
+		intervals, err := parseBusyIntervals([]byte(doc), from, to)
+		require.NoError(t, err)
+
+		// Chicago is UTC-6 until March 10 and UTC-5 after. The second standup
+		// is excluded and the third was moved to the afternoon. Overlapping
+		// lunch and reminder merge into one interval.
+		assert.Equal(t, []models.BusyInterval{
+			{StartTime: utc(4, 15, 0), EndTime: utc(4, 15, 30)},
+			{StartTime: utc(5, 17, 0), EndTime: utc(5, 18, 30)},
+			{StartTime: utc(18, 19, 0), EndTime: utc(18, 19, 30)},
+			{StartTime: utc(20, 0, 0), EndTime: utc(21, 0, 0)},
+		}, intervals)
+	})
+
+	t.Run("free busy", func(t *testing.T) {
+		doc := icsDocument(
+			"BEGIN:VFREEBUSY",
+			"FREEBUSY:20240304T150000Z/20240304T160000Z,20240305T150000Z/PT2H",
+			"FREEBUSY;FBTYPE=FREE:20240306T150000Z/20240306T160000Z",
+			"FREEBUSY;FBTYPE=BUSY-TENTATIVE:20240307T150000Z/20240307T153000Z",
+			"END:VFREEBUSY",
+		)
+
+		intervals, err := parseBusyIntervals([]byte(doc), from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []models.BusyInterval{
+			{StartTime: utc(4, 15, 0), EndTime: utc(4, 16, 0)},
+			{StartTime: utc(5, 15, 0), EndTime: utc(5, 17, 0)},
+			{StartTime: utc(7, 15, 0), EndTime: utc(7, 15, 30)},
+		}, intervals)
+	})
+
+	t.Run("clipped to window", func(t *testing.T) {
+		doc := icsDocument(
+			"BEGIN:VEVENT",
+			"UID:daily",
+			"DTSTART:20240229T230000Z",
+			"DTEND:20240301T010000Z",
+			"RRULE:FREQ=DAILY",
+			"END:VEVENT",
+		)
+
+		intervals, err := parseBusyIntervals([]byte(doc), from, from.Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []models.BusyInterval{
+			{StartTime: utc(1, 0, 0), EndTime: utc(1, 1, 0)},
+			{StartTime: utc(1, 23, 0), EndTime: utc(2, 1, 0)},
+			{StartTime: utc(2, 23, 0), EndTime: utc(3, 0, 0)},
+		}, intervals)
+	})
+
+	t.Run("rules and zones from other calendar apps", func(t *testing.T) {
+		doc := icsDocument(
+			"BEGIN:VEVENT",
+			"UID:outlook",
+			"DTSTART;TZID=Eastern Standard Time:20240305T090000",
+			"DURATION:PT1H",
+			"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;WKST=SU",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:old-daily",
+			"DTSTART;TZID=America/Chicago:20000101T060000",
+			"DURATION:PT30M",
+			"RRULE:FREQ=DAILY;INTERVAL=3",
+			"END:VEVENT",
+		)
+
+		intervals, err := parseBusyIntervals([]byte(doc), from, from.AddDate(0, 0, 14))
+		require.NoError(t, err)
+
+		// New York is UTC-5 until March 10 and UTC-4 after. The daily rule
+		// started on January 1, 2000, 8826 days or 2942 periods before
+		// March 1, 2024.
+		assert.Equal(t, []models.BusyInterval{
+			{StartTime: utc(1, 12, 0), EndTime: utc(1, 12, 30)},
+			{StartTime: utc(4, 12, 0), EndTime: utc(4, 12, 30)},
+			{StartTime: utc(5, 14, 0), EndTime: utc(5, 15, 0)},
+			{StartTime: utc(7, 12, 0), EndTime: utc(7, 12, 30)},
+			{StartTime: utc(10, 11, 0), EndTime: utc(10, 11, 30)},
+			{StartTime: utc(13, 11, 0), EndTime: utc(13, 11, 30)},
+		}, intervals)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"not a calendar": "hello",
+			"bad start":      icsDocument("BEGIN:VEVENT", "DTSTART:tomorrow", "END:VEVENT"),
+			"bad duration":   icsDocument("BEGIN:VEVENT", "DTSTART:20240304T150000Z", "DURATION:1 hour", "END:VEVENT"),
+			"bad period":     icsDocument("BEGIN:VFREEBUSY", "FREEBUSY:20240304T150000Z", "END:VFREEBUSY"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := parseBusyIntervals([]byte(doc), from, to)
+				assert.ErrorIs(t, err, models.ErrInvalidInput)
+			})
+		}
+	})
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M":  90 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P2W":      14 * 24 * time.Hour,
+		"P1DT2H":   26 * time.Hour,
+		"+PT15S":   15 * time.Second,
+		"-PT15M":   -15 * time.Minute,
+		"PT0S":     0,
+		"P1DT1H1S": 25*time.Hour + time.Second,
+	}
+	for value, want := range tests {
+		got, err := parseICSDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"", "P", "1H", "PT1D", "P1H", "PT1", "PTXM"} {
+		_, err := parseICSDuration(value)
+		assert.ErrorIs(t, err, models.ErrInvalidInput, value)
+	}
+}
+
+func TestAvailabilityService(t *testing.T) {
+	userID := uuid.New()
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+	event := icsDocument(
+		"BEGIN:VEVENT",
+		"UID:meeting",
+		"DTSTART:"+start.Format(icsUTCFormat),
+		"DTEND:"+start.Add(time.Hour).Format(icsUTCFormat),
+		"END:VEVENT",
+	)
+
+	served := event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(served))
+	}))
+	defer server.Close()
+
+	repo := newFakeAvailabilityRepository()
+	svc := NewAvailabilityService(repo, testutil.NewMockTribeRepository(), server.Client())
+	ctx := context.Background()
+
+	t.Run("upload", func(t *testing.T) {
+		calendar, err := svc.UploadCalendar(userID, "Work", strings.NewReader(event))
+		require.NoError(t, err)
+		assert.Equal(t, "Work", calendar.Name)
+		assert.False(t, calendar.IsLinked())
+		assert.NotNil(t, calendar.LastImportedAt)
+
+		busy, err := svc.BusyIntervals(userID, start.Add(-time.Hour), start.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, busy, 1)
+		assert.True(t, start.Equal(busy[0].StartTime))
+
+		_, err = svc.RefreshCalendar(ctx, userID, calendar.ID)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+		require.NoError(t, svc.DeleteCalendar(userID, calendar.ID))
+	})
+
+	t.Run("upload rejects invalid files", func(t *testing.T) {
+		_, err := svc.UploadCalendar(userID, "Work", strings.NewReader("not a calendar"))
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+		_, err = svc.UploadCalendar(userID, "", strings.NewReader(event))
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+		_, err = svc.UploadCalendar(userID, "Huge", strings.NewReader(strings.Repeat("x", MaxCalendarImportSize+1)))
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		calendars, err := svc.ListCalendars(userID)
+		require.NoError(t, err)
+		assert.Empty(t, calendars, "failed imports leave nothing behind")
+	})
+
+	t.Run("link and refresh", func(t *testing.T) {
+		calendar, err := svc.LinkCalendar(ctx, userID, "Shared", server.URL+"/work.ics")
+		require.NoError(t, err)
+		assert.True(t, calendar.IsLinked())
+		assert.Len(t, repo.busy[calendar.ID], 1)
+
+		served = icsDocument()
+		defer func() { served = event }()
+		_, err = svc.RefreshCalendar(ctx, userID, calendar.ID)
+		require.NoError(t, err)
+		assert.Empty(t, repo.busy[calendar.ID])
+
+		_, err = svc.RefreshCalendar(ctx, uuid.New(), calendar.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("link failures", func(t *testing.T) {
+		_, err := svc.LinkCalendar(ctx, userID, "Missing", server.URL+"/missing.ics")
+		assert.ErrorIs(t, err, models.ErrExternalSourceError)
+		_, err = svc.LinkCalendar(ctx, userID, "Local", "ftp://example.com/cal.ics")
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("default client refuses private addresses", func(t *testing.T) {
+		public := NewAvailabilityService(repo, testutil.NewMockTribeRepository(), nil)
+		_, err := public.LinkCalendar(ctx, userID, "Local", server.URL+"/work.ics")
+		assert.ErrorIs(t, err, models.ErrExternalSourceUnavailable)
+	})
+}
+
+func TestAvailabilityProposeSlots(t *testing.T) {
+	tribeID := uuid.New()
+	alice, bob, pending := uuid.New(), uuid.New(), uuid.New()
+	from := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetMembersFunc = func(id uuid.UUID) ([]*models.TribeMember, error) {
+		assert.Equal(t, tribeID, id)
+		return []*models.TribeMember{
+			{TribeID: tribeID, UserID: alice, MembershipType: models.MembershipFull},
+			{TribeID: tribeID, UserID: bob, MembershipType: models.MembershipFull},
+			{TribeID: tribeID, UserID: pending, MembershipType: models.MembershipPending},
+		}, nil
+	}
+
+	repo := newFakeAvailabilityRepository()
+	svc := NewAvailabilityService(repo, tribes, nil)
+	doc := icsDocument(
+		"BEGIN:VEVENT",
+		"DTSTART:"+from.Format(icsUTCFormat),
+		"DTEND:"+from.Add(2*time.Hour).Format(icsUTCFormat),
+		"END:VEVENT",
+	)
+	_, err := svc.UploadCalendar(alice, "Work", strings.NewReader(doc))
+	require.NoError(t, err)
+	// Pending members are not counted, however busy they are
+	_, err = svc.UploadCalendar(pending, "Work", strings.NewReader(icsDocument(
+		"BEGIN:VEVENT",
+		"DTSTART:"+from.Format(icsUTCFormat),
+		"DTEND:"+from.Add(8*time.Hour).Format(icsUTCFormat),
+		"END:VEVENT",
+	)))
+	require.NoError(t, err)
+
+	slots, err := svc.ProposeSlots(tribeID, models.SlotQuery{From: from, To: from.Add(4 * time.Hour), Duration: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.True(t, from.Add(2*time.Hour).Equal(slots[0].StartTime))
+	assert.Equal(t, []uuid.UUID{alice, bob}, slots[0].Available)
+
+	slots, err = svc.ProposeSlots(tribeID, models.SlotQuery{From: from, To: from.Add(4 * time.Hour), Duration: time.Hour, Quorum: 1})
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.Equal(t, []uuid.UUID{bob}, slots[0].Available)
+
+	_, err = svc.ProposeSlots(tribeID, models.SlotQuery{From: from, To: from.Add(4 * time.Hour), Duration: time.Hour, Quorum: 3})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// BlobStore stores uploaded files by key. Keys are slash-separated relative
+// paths such as "activities/<id>/<photo>.jpg".
+type BlobStore interface {
+	// Put stores the data under the key, replacing anything already there
+	Put(ctx context.Context, key string, data io.Reader, contentType string) error
+	// Get opens the data stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that serves the key until the TTL runs out
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// SignatureVerifier is implemented by blob stores whose signed URLs are
+// served by this API rather than by the store itself
+type SignatureVerifier interface {
+	// Verify checks the expires and signature query values of a signed URL
+	Verify(key, expires, signature string) error
+}
+
+// LocalBlobStore keeps blobs on the local filesystem and signs URLs with an
+// HMAC so they can be served by the API without authentication
+type LocalBlobStore struct {
+	dir     string
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewLocalBlobStore creates a store rooted at dir. Signed URLs are built on
+// baseURL, the path the API serves blobs from.
+func NewLocalBlobStore(dir, baseURL string, secret []byte) (*LocalBlobStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: blob store directory is required", models.ErrInvalidInput)
+	}
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: blob store signing key is required", models.ErrInvalidInput)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating blob store directory: %w", err)
+	}
+	return &LocalBlobStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+		now:     time.Now,
+	}, nil
+}
+
+// Put implements BlobStore
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data io.Reader, contentType string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return fmt.Errorf("error creating blob directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("error storing blob: %w", err)
+	}
+	return nil
+}
+
+// Get implements BlobStore
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob: %w", err)
+	}
+	return f, nil
+}
+
+// Delete implements BlobStore
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting blob: %w", err)
+	}
+	return nil
+}
+
+// SignedURL implements BlobStore
+func (s *LocalBlobStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return s.baseURL + "/" + strings.Join(segments, "/") + "?" + query.Encode(), nil
+}
+
+// Verify implements SignatureVerifier
+func (s *LocalBlobStore) Verify(key, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return models.ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return models.ErrInvalidSignature
+	}
+	if s.now().Unix() > unix {
+		return models.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to a file under the store directory, rejecting keys that
+// would escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return "", fmt.Errorf("%w: invalid blob key %q", models.ErrInvalidInput, key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
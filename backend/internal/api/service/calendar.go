@@ -0,0 +1,173 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// feedTokenBytes is how much randomness goes into a feed token
+const feedTokenBytes = 32
+
+// CalendarService defines the interface for iCalendar feeds and the tokens
+// that protect them
+type CalendarService interface {
+	// CreateFeedToken issues a new feed token; its value is only returned here
+	CreateFeedToken(userID uuid.UUID) (*models.CalendarFeedToken, error)
+	// ListFeedTokens returns a user's feed tokens without their values
+	ListFeedTokens(userID uuid.UUID) ([]*models.CalendarFeedToken, error)
+	// RevokeFeedToken revokes one of a user's feed tokens
+	RevokeFeedToken(userID, tokenID uuid.UUID) error
+	// RevokeAllFeedTokens revokes every feed token of a user
+	RevokeAllFeedTokens(userID uuid.UUID) error
+	// AuthenticateFeed returns the user a feed token belongs to
+	AuthenticateFeed(token string) (uuid.UUID, error)
+	// UserFeed renders the events a user owns
+	UserFeed(userID uuid.UUID) ([]byte, error)
+	// TribeFeed renders the events shared with a tribe
+	TribeFeed(tribeID uuid.UUID) ([]byte, error)
+}
+
+// calendarService implements the CalendarService interface
+type calendarService struct {
+	tokens     models.CalendarFeedTokenRepository
+	activities models.ActivityRepository
+	tribes     models.TribeRepository
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(tokens models.CalendarFeedTokenRepository, activities models.ActivityRepository, tribes models.TribeRepository) CalendarService {
+	return &calendarService{
+		tokens:     tokens,
+		activities: activities,
+		tribes:     tribes,
+	}
+}
+
+// hashFeedToken returns the stored form of a feed token
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateFeedToken implements CalendarService
+func (s *calendarService) CreateFeedToken(userID uuid.UUID) (*models.CalendarFeedToken, error) {
+	raw := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("error generating feed token: %w", err)
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+
+	token := &models.CalendarFeedToken{
+		UserID:    userID,
+		TokenHash: hashFeedToken(value),
+	}
+	if err := s.tokens.Create(token); err != nil {
+		return nil, err
+	}
+	token.Token = value
+	return token, nil
+}
+
+// ListFeedTokens implements CalendarService
+func (s *calendarService) ListFeedTokens(userID uuid.UUID) ([]*models.CalendarFeedToken, error) {
+	return s.tokens.ListByUser(userID)
+}
+
+// RevokeFeedToken implements CalendarService
+func (s *calendarService) RevokeFeedToken(userID, tokenID uuid.UUID) error {
+	return s.tokens.Revoke(userID, tokenID)
+}
+
+// RevokeAllFeedTokens implements CalendarService
+func (s *calendarService) RevokeAllFeedTokens(userID uuid.UUID) error {
+	return s.tokens.RevokeAll(userID)
+}
+
+// AuthenticateFeed implements CalendarService. Unknown and revoked tokens
+// are both reported as unauthorized.
+func (s *calendarService) AuthenticateFeed(token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, models.ErrUnauthorized
+	}
+	stored, err := s.tokens.GetByHash(hashFeedToken(token))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return uuid.Nil, models.ErrUnauthorized
+		}
+		return uuid.Nil, err
+	}
+	if stored.IsRevoked() {
+		return uuid.Nil, models.ErrUnauthorized
+	}
+	return stored.UserID, nil
+}
+
+// UserFeed implements CalendarService
+func (s *calendarService) UserFeed(userID uuid.UUID) ([]byte, error) {
+	activities, err := s.activities.GetUserActivities(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user activities: %w", err)
+	}
+	events, err := s.scheduledEvents(activities)
+	if err != nil {
+		return nil, err
+	}
+	return renderICS("My Events", events), nil
+}
+
+// TribeFeed implements CalendarService
+func (s *calendarService) TribeFeed(tribeID uuid.UUID) ([]byte, error) {
+	tribe, err := s.tribes.GetByID(tribeID)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.activities.GetSharedActivities(tribeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting shared activities: %w", err)
+	}
+	events, err := s.scheduledEvents(activities)
+	if err != nil {
+		return nil, err
+	}
+	return renderICS(tribe.Name, events), nil
+}
+
+// scheduledEvents loads the schedule and exceptions of every scheduled
+// event among the activities, ordered by start
+func (s *calendarService) scheduledEvents(activities []*models.Activity) ([]calendarEvent, error) {
+	events := make([]calendarEvent, 0, len(activities))
+	for _, activity := range activities {
+		if activity.Type != models.ActivityTypeEvent {
+			continue
+		}
+		schedule, err := s.activities.GetSchedule(activity.ID)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting activity schedule: %w", err)
+		}
+
+		event := calendarEvent{activity: activity, schedule: schedule}
+		if activity.RecurrenceRule != "" {
+			event.exceptions, err = s.activities.GetOccurrenceExceptions(activity.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error getting occurrence exceptions: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].schedule.StartTime.Before(events[j].schedule.StartTime)
+	})
+	return events, nil
+}
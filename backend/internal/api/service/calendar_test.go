@@ -0,0 +1,261 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeedTokenRepository keeps calendar feed tokens in memory
+type fakeFeedTokenRepository struct {
+	tokens []*models.CalendarFeedToken
+}
+
+func (r *fakeFeedTokenRepository) Create(token *models.CalendarFeedToken) error {
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	stored := *token
+	r.tokens = append(r.tokens, &stored)
+	return nil
+}
+
+func (r *fakeFeedTokenRepository) GetByHash(tokenHash string) (*models.CalendarFeedToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (r *fakeFeedTokenRepository) ListByUser(userID uuid.UUID) ([]*models.CalendarFeedToken, error) {
+	var tokens []*models.CalendarFeedToken
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeFeedTokenRepository) Revoke(userID, tokenID uuid.UUID) error {
+	for _, token := range r.tokens {
+		if token.ID == tokenID && token.UserID == userID && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func (r *fakeFeedTokenRepository) RevokeAll(userID uuid.UUID) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func TestCalendarFeedTokens(t *testing.T) {
+	tokens := &fakeFeedTokenRepository{}
+	svc := NewCalendarService(tokens, testutil.NewMockActivityRepository(), testutil.NewMockTribeRepository())
+	userID := uuid.New()
+
+	first, err := svc.CreateFeedToken(userID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.Token)
+	assert.NotEqual(t, first.Token, tokens.tokens[0].TokenHash, "only the hash is stored")
+	assert.Empty(t, tokens.tokens[0].Token)
+
+	second, err := svc.CreateFeedToken(userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Token, second.Token)
+
+	got, err := svc.AuthenticateFeed(first.Token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, got)
+
+	_, err = svc.AuthenticateFeed("not-a-token")
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+	_, err = svc.AuthenticateFeed("")
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+
+	require.NoError(t, svc.RevokeFeedToken(userID, first.ID))
+	_, err = svc.AuthenticateFeed(first.Token)
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+	assert.ErrorIs(t, svc.RevokeFeedToken(uuid.New(), second.ID), models.ErrNotFound)
+
+	require.NoError(t, svc.RevokeAllFeedTokens(userID))
+	_, err = svc.AuthenticateFeed(second.Token)
+	assert.ErrorIs(t, err, models.ErrUnauthorized)
+}
+
+func TestCalendarFeeds(t *testing.T) {
+	userID := uuid.New()
+	tribeID := uuid.New()
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+
+	weekly := &models.Activity{
+		ID:              uuid.New(),
+		Type:            models.ActivityTypeEvent,
+		Name:            "Date Night; dinner, then a movie",
+		Description:     "Pick a place\nthen book it",
+		RecurrenceRule:  "FREQ=WEEKLY;BYDAY=FR",
+		RecurrenceStart: &start,
+		UpdatedAt:       updated,
+	}
+	single := &models.Activity{
+		ID:        uuid.New(),
+		Type:      models.ActivityTypeEvent,
+		Name:      "Concert",
+		UpdatedAt: updated,
+	}
+	unscheduled := &models.Activity{ID: uuid.New(), Type: models.ActivityTypeEvent, Name: "Someday"}
+	place := &models.Activity{ID: uuid.New(), Type: models.ActivityTypeLocation, Name: "Cafe"}
+
+	moved := start.AddDate(0, 0, 15)
+	movedEnd := moved.Add(2 * time.Hour)
+	schedules := map[uuid.UUID]*models.ActivitySchedule{
+		weekly.ID: {
+			ActivityID: weekly.ID,
+			StartTime:  start,
+			EndTime:    start.Add(3 * time.Hour),
+			TimeZone:   "America/Chicago",
+			Sequence:   2,
+			UpdatedAt:  updated,
+		},
+		single.ID: {
+			ActivityID: single.ID,
+			StartTime:  start.AddDate(0, 0, -1),
+			EndTime:    start.AddDate(0, 0, -1).Add(2 * time.Hour),
+			TimeZone:   "UTC",
+			Location:   &models.LocationRef{Latitude: 41.88, Longitude: -87.63, Address: "Chicago"},
+			UpdatedAt:  updated,
+		},
+		place.ID: {ActivityID: place.ID, StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"},
+	}
+
+	activities := testutil.NewMockActivityRepository()
+	activities.GetUserActivitiesFunc = func(id uuid.UUID) ([]*models.Activity, error) {
+		assert.Equal(t, userID, id)
+		return []*models.Activity{weekly, single, unscheduled, place}, nil
+	}
+	activities.GetSharedActivitiesFunc = func(id uuid.UUID) ([]*models.Activity, error) {
+		assert.Equal(t, tribeID, id)
+		return []*models.Activity{single}, nil
+	}
+	activities.GetScheduleFunc = func(id uuid.UUID) (*models.ActivitySchedule, error) {
+		if schedule, ok := schedules[id]; ok {
+			return schedule, nil
+		}
+		return nil, models.ErrNotFound
+	}
+	activities.GetOccurrenceExceptionsFunc = func(id uuid.UUID) ([]*models.OccurrenceException, error) {
+		return []*models.OccurrenceException{
+			{ActivityID: id, OriginalStart: start.AddDate(0, 0, 7), Cancelled: true},
+			{ActivityID: id, OriginalStart: start.AddDate(0, 0, 14), StartTime: &moved, EndTime: &movedEnd},
+		}, nil
+	}
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+		return &models.Tribe{BaseModel: models.BaseModel{ID: id}, Name: "Couple"}, nil
+	}
+
+	svc := NewCalendarService(&fakeFeedTokenRepository{}, activities, tribes)
+
+	t.Run("user feed", func(t *testing.T) {
+		feed, err := svc.UserFeed(userID)
+		require.NoError(t, err)
+		ics := string(feed)
+
+		assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+		for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75, "line %q is not folded", line)
+		}
+
+		// Unscheduled events and other activity types are left out
+		assert.Equal(t, 3, strings.Count(ics, "BEGIN:VEVENT"))
+		assert.NotContains(t, ics, "Someday")
+		assert.NotContains(t, ics, "Cafe")
+
+		// Events are ordered by start, and UIDs derive from the activity
+		unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+		assert.Less(t, strings.Index(unfolded, "SUMMARY:Concert"), strings.Index(unfolded, "SUMMARY:Date Night"))
+		assert.Contains(t, unfolded, "UID:"+weekly.ID.String()+"@rlship-tools\r\n")
+
+		assert.Contains(t, unfolded, `SUMMARY:Date Night\; dinner\, then a movie`)
+		assert.Contains(t, unfolded, `DESCRIPTION:Pick a place\nthen book it`)
+		assert.Contains(t, unfolded, "DTSTART;TZID=America/Chicago:20240105T130000\r\n")
+		assert.Contains(t, unfolded, "DTEND;TZID=America/Chicago:20240105T160000\r\n")
+		assert.Contains(t, unfolded, "RRULE:FREQ=WEEKLY;BYDAY=FR\r\n")
+		assert.Contains(t, unfolded, "SEQUENCE:2\r\n")
+		assert.Contains(t, unfolded, "EXDATE;TZID=America/Chicago:20240112T130000\r\n")
+		assert.Contains(t, unfolded, "RECURRENCE-ID;TZID=America/Chicago:20240119T130000\r\n")
+		assert.Contains(t, unfolded, "DTSTART;TZID=America/Chicago:20240120T130000\r\n")
+
+		// Zones used by local times are defined once, ahead of the events
+		assert.Equal(t, 1, strings.Count(unfolded, "BEGIN:VTIMEZONE"))
+		assert.Less(t, strings.Index(unfolded, "END:VTIMEZONE"), strings.Index(unfolded, "BEGIN:VEVENT"))
+		assert.Contains(t, unfolded, "BEGIN:VTIMEZONE\r\nTZID:America/Chicago\r\n")
+		assert.Contains(t, unfolded, "BEGIN:STANDARD\r\nDTSTART:20231105T010000\r\n"+
+			"TZOFFSETFROM:-0600\r\nTZOFFSETTO:-0600\r\nTZNAME:CST\r\nEND:STANDARD\r\n")
+		assert.Contains(t, unfolded, "BEGIN:DAYLIGHT\r\nDTSTART:20240310T020000\r\n"+
+			"TZOFFSETFROM:-0600\r\nTZOFFSETTO:-0500\r\nTZNAME:CDT\r\nEND:DAYLIGHT\r\n")
+		assert.Contains(t, unfolded, "BEGIN:STANDARD\r\nDTSTART:20241103T020000\r\n"+
+			"TZOFFSETFROM:-0500\r\nTZOFFSETTO:-0600\r\nTZNAME:CST\r\nEND:STANDARD\r\n")
+		assert.NotContains(t, unfolded, "TZID:UTC")
+
+		assert.Contains(t, unfolded, "DTSTART:20240104T190000Z\r\n")
+		assert.Contains(t, unfolded, "LOCATION:Chicago\r\n")
+		assert.Contains(t, unfolded, "GEO:41.88;-87.63\r\n")
+	})
+
+	t.Run("tribe feed", func(t *testing.T) {
+		feed, err := svc.TribeFeed(tribeID)
+		require.NoError(t, err)
+		ics := string(feed)
+
+		assert.Contains(t, ics, "X-WR-CALNAME:Couple\r\n")
+		assert.Equal(t, 1, strings.Count(ics, "BEGIN:VEVENT"))
+		assert.Contains(t, ics, "SUMMARY:Concert")
+		assert.NotContains(t, ics, "VTIMEZONE")
+	})
+}
+
+func TestICSOffset(t *testing.T) {
+	assert.Equal(t, "+0000", icsOffset(0))
+	assert.Equal(t, "-0600", icsOffset(-6*3600))
+	assert.Equal(t, "+0530", icsOffset(5*3600+30*60))
+	assert.Equal(t, "-003456", icsOffset(-(34*60 + 56)))
+}
+
+func TestICSLineFolding(t *testing.T) {
+	w := &icsWriter{}
+	w.line("DESCRIPTION", strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	var unfolded strings.Builder
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+			line = line[1:]
+		}
+		unfolded.WriteString(line)
+	}
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("é", 100), unfolded.String())
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// EmailInvitationService defines the interface for inviting people to a
+// tribe by email before they have an account
+type EmailInvitationService interface {
+	// InviteByEmail records an invitation for an unregistered email and
+	// mails it. It returns ErrConflict if the email already has an account;
+	// those users are invited as members directly.
+	InviteByEmail(inviterID, tribeID uuid.UUID, email string) (*models.PendingInvitation, error)
+	// ListInvitations returns the tribe's open email invitations
+	ListInvitations(userID, tribeID uuid.UUID) ([]*models.PendingInvitation, error)
+	// CancelInvitation withdraws an open invitation. Its inviter, tribe
+	// owners and admins may cancel it.
+	CancelInvitation(userID, tribeID, invitationID uuid.UUID) error
+	// ConvertPendingInvitations makes a newly created user a pending member
+	// of every tribe their email was invited to
+	ConvertPendingInvitations(user *models.User) error
+}
+
+// emailInvitationService implements the EmailInvitationService interface
+type emailInvitationService struct {
+	invitations models.PendingInvitationRepository
+	users       models.UserRepository
+	tribes      models.TribeRepository
+	authz       TribeAuthorizer
+	mailer      Mailer
+	signupURL   string
+	now         func() time.Time
+}
+
+// NewEmailInvitationService creates a new email invitation service. The
+// invitation email links to signupURL with the email as a query parameter.
+func NewEmailInvitationService(invitations models.PendingInvitationRepository, users models.UserRepository, tribes models.TribeRepository, authz TribeAuthorizer, mailer Mailer, signupURL string) EmailInvitationService {
+	return &emailInvitationService{
+		invitations: invitations,
+		users:       users,
+		tribes:      tribes,
+		authz:       authz,
+		mailer:      mailer,
+		signupURL:   signupURL,
+		now:         time.Now,
+	}
+}
+
+// InviteByEmail implements EmailInvitationService. If the email cannot be
+// sent the invitation is withdrawn again.
+func (s *emailInvitationService) InviteByEmail(inviterID, tribeID uuid.UUID, email string) (*models.PendingInvitation, error) {
+	if _, err := s.authz.Authorize(inviterID, tribeID, models.PermissionInviteMembers); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.PendingInvitation{
+		Email:     models.NormalizeEmail(email),
+		TribeID:   tribeID,
+		InvitedBy: inviterID,
+		ExpiresAt: s.now().Add(models.DefaultEmailInvitationDuration),
+	}
+	if err := invitation.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.users.GetByEmail(invitation.Email)
+	if err == nil && existing != nil {
+		return nil, fmt.Errorf("%w: this email already has an account", models.ErrConflict)
+	}
+	if err != nil && err.Error() != "user not found" {
+		return nil, fmt.Errorf("error looking up email: %w", err)
+	}
+
+	if err = s.invitations.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.invitationMail(invitation)
+	if err == nil {
+		err = s.mailer.Send(msg)
+	}
+	if err != nil {
+		if deleteErr := s.invitations.Delete(tribeID, invitation.ID); deleteErr != nil {
+			log.Printf("Error withdrawing unsent invitation %s: %v", invitation.ID, deleteErr)
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// ListInvitations implements EmailInvitationService
+func (s *emailInvitationService) ListInvitations(userID, tribeID uuid.UUID) ([]*models.PendingInvitation, error) {
+	if _, err := s.authz.Authorize(userID, tribeID, models.PermissionInviteMembers); err != nil {
+		return nil, err
+	}
+	return s.invitations.ListByTribe(tribeID)
+}
+
+// CancelInvitation implements EmailInvitationService
+func (s *emailInvitationService) CancelInvitation(userID, tribeID, invitationID uuid.UUID) error {
+	invitation, err := s.invitations.GetByID(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.TribeID != tribeID || invitation.ConvertedAt != nil {
+		return models.ErrNotFound
+	}
+
+	permission := models.PermissionRemoveMembers
+	if invitation.InvitedBy == userID {
+		permission = models.PermissionViewTribe
+	}
+	if _, err = s.authz.Authorize(userID, tribeID, permission); err != nil {
+		return err
+	}
+
+	return s.invitations.Delete(tribeID, invitationID)
+}
+
+// ConvertPendingInvitations implements EmailInvitationService
+func (s *emailInvitationService) ConvertPendingInvitations(user *models.User) error {
+	if user.Email == "" {
+		return nil
+	}
+	converted, err := s.invitations.ConvertForUser(user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("error converting pending invitations: %w", err)
+	}
+	if len(converted) > 0 {
+		log.Printf("Converted %d pending invitations for user %s", len(converted), user.ID)
+	}
+	return nil
+}
+
+// invitationMail builds the email sent for an invitation
+func (s *emailInvitationService) invitationMail(invitation *models.PendingInvitation) (MailMessage, error) {
+	tribe, err := s.tribes.GetByID(invitation.TribeID)
+	if err != nil {
+		return MailMessage{}, fmt.Errorf("error getting tribe: %w", err)
+	}
+	inviter, err := s.users.GetByID(invitation.InvitedBy)
+	if err != nil {
+		return MailMessage{}, fmt.Errorf("error getting inviter: %w", err)
+	}
+
+	link := s.signupURL + "?" + url.Values{"email": {invitation.Email}}.Encode()
+	return MailMessage{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s invited you to %s on Tribe", inviter.Name, tribe.Name),
+		Body: fmt.Sprintf("%s invited you to join the tribe %q on Tribe.\n\n"+
+			"Sign up with this email address to see the invitation:\n%s\n\n"+
+			"The invitation expires on %s.\n",
+			inviter.Name, tribe.Name, link, invitation.ExpiresAt.Format("January 2, 2006")),
+	}, nil
+}
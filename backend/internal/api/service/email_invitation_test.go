@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePendingInvitationRepository keeps email invitations in memory
+type fakePendingInvitationRepository struct {
+	invitations map[uuid.UUID]*models.PendingInvitation
+	converted   map[uuid.UUID][]uuid.UUID
+}
+
+func newFakePendingInvitationRepository() *fakePendingInvitationRepository {
+	return &fakePendingInvitationRepository{
+		invitations: make(map[uuid.UUID]*models.PendingInvitation),
+		converted:   make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func (r *fakePendingInvitationRepository) Create(invitation *models.PendingInvitation) error {
+	if err := invitation.Validate(); err != nil {
+		return err
+	}
+	for _, existing := range r.invitations {
+		if existing.TribeID == invitation.TribeID && existing.Email == invitation.Email && existing.ConvertedAt == nil {
+			return models.ErrDuplicate
+		}
+	}
+	invitation.ID = uuid.New()
+	invitation.CreatedAt = time.Now()
+	stored := *invitation
+	r.invitations[invitation.ID] = &stored
+	return nil
+}
+
+func (r *fakePendingInvitationRepository) GetByID(id uuid.UUID) (*models.PendingInvitation, error) {
+	invitation, ok := r.invitations[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *invitation
+	return &copied, nil
+}
+
+func (r *fakePendingInvitationRepository) ListByTribe(tribeID uuid.UUID) ([]*models.PendingInvitation, error) {
+	var invitations []*models.PendingInvitation
+	for _, invitation := range r.invitations {
+		if invitation.TribeID == tribeID && invitation.ConvertedAt == nil {
+			copied := *invitation
+			invitations = append(invitations, &copied)
+		}
+	}
+	return invitations, nil
+}
+
+func (r *fakePendingInvitationRepository) Delete(tribeID, invitationID uuid.UUID) error {
+	invitation, ok := r.invitations[invitationID]
+	if !ok || invitation.TribeID != tribeID || invitation.ConvertedAt != nil {
+		return models.ErrNotFound
+	}
+	delete(r.invitations, invitationID)
+	return nil
+}
+
+func (r *fakePendingInvitationRepository) ConvertForUser(userID uuid.UUID, email string) ([]*models.PendingInvitation, error) {
+	var converted []*models.PendingInvitation
+	now := time.Now()
+	for _, invitation := range r.invitations {
+		if invitation.Email == models.NormalizeEmail(email) && invitation.ConvertedAt == nil && invitation.ExpiresAt.After(now) {
+			invitation.ConvertedAt = &now
+			r.converted[userID] = append(r.converted[userID], invitation.TribeID)
+			copied := *invitation
+			converted = append(converted, &copied)
+		}
+	}
+	return converted, nil
+}
+
+// failingMailer rejects every message
+type failingMailer struct{}
+
+func (failingMailer) Send(MailMessage) error {
+	return errors.New("mail server unavailable")
+}
+
+func TestEmailInvitationService(t *testing.T) {
+	tribeID := uuid.New()
+	owner, member, limited := uuid.New(), uuid.New(), uuid.New()
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+		return &models.Tribe{BaseModel: models.BaseModel{ID: tribeID}, Name: "Book Club", Members: []*models.TribeMember{
+			{TribeID: tribeID, UserID: owner, MembershipType: models.MembershipFull, Role: models.TribeRoleOwner},
+			{TribeID: tribeID, UserID: member, MembershipType: models.MembershipFull, Role: models.TribeRoleMember},
+			{TribeID: tribeID, UserID: limited, MembershipType: models.MembershipLimited, Role: models.TribeRoleMember},
+		}}, nil
+	}
+	users := testutil.NewMockUserRepository()
+	users.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+		return &models.User{ID: id, Name: "Alice"}, nil
+	}
+	users.GetByEmailFunc = func(email string) (*models.User, error) {
+		if email == "taken@example.com" {
+			return &models.User{ID: uuid.New(), Email: email}, nil
+		}
+		return nil, errors.New("user not found")
+	}
+
+	repo := newFakePendingInvitationRepository()
+	mailer := NewMemoryMailer()
+	svc := NewEmailInvitationService(repo, users, tribes, NewTribeAuthorizer(tribes), mailer, "https://tribe.example/signup")
+
+	t.Run("invite sends mail", func(t *testing.T) {
+		invitation, err := svc.InviteByEmail(member, tribeID, "  Bob@Example.com ")
+		require.NoError(t, err)
+		assert.Equal(t, "bob@example.com", invitation.Email)
+		assert.Equal(t, member, invitation.InvitedBy)
+		assert.WithinDuration(t, time.Now().Add(models.DefaultEmailInvitationDuration), invitation.ExpiresAt, time.Minute)
+
+		messages := mailer.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "bob@example.com", messages[0].To)
+		assert.Contains(t, messages[0].Subject, "Alice")
+		assert.Contains(t, messages[0].Subject, "Book Club")
+		assert.Contains(t, messages[0].Body, "https://tribe.example/signup?email=bob%40example.com")
+
+		_, err = svc.InviteByEmail(owner, tribeID, "bob@example.com")
+		assert.ErrorIs(t, err, models.ErrDuplicate)
+
+		invitations, err := svc.ListInvitations(owner, tribeID)
+		require.NoError(t, err)
+		assert.Len(t, invitations, 1)
+	})
+
+	t.Run("invite validation", func(t *testing.T) {
+		_, err := svc.InviteByEmail(limited, tribeID, "carol@example.com")
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+
+		_, err = svc.InviteByEmail(owner, tribeID, "not an email")
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		_, err = svc.InviteByEmail(owner, tribeID, "Taken@example.com")
+		assert.ErrorIs(t, err, models.ErrConflict)
+
+		_, err = svc.ListInvitations(limited, tribeID)
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+	})
+
+	t.Run("unsent invitation is withdrawn", func(t *testing.T) {
+		failing := NewEmailInvitationService(repo, users, tribes, NewTribeAuthorizer(tribes), failingMailer{}, "https://tribe.example/signup")
+		_, err := failing.InviteByEmail(owner, tribeID, "dave@example.com")
+		require.Error(t, err)
+
+		invitations, err := repo.ListByTribe(tribeID)
+		require.NoError(t, err)
+		for _, invitation := range invitations {
+			assert.NotEqual(t, "dave@example.com", invitation.Email)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		invitation, err := svc.InviteByEmail(member, tribeID, "erin@example.com")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, svc.CancelInvitation(limited, tribeID, invitation.ID), models.ErrTribePermission)
+		assert.ErrorIs(t, svc.CancelInvitation(member, uuid.New(), invitation.ID), models.ErrNotFound)
+		require.NoError(t, svc.CancelInvitation(member, tribeID, invitation.ID))
+		assert.ErrorIs(t, svc.CancelInvitation(owner, tribeID, invitation.ID), models.ErrNotFound)
+
+		invitation, err = svc.InviteByEmail(member, tribeID, "erin@example.com")
+		require.NoError(t, err)
+		require.NoError(t, svc.CancelInvitation(owner, tribeID, invitation.ID))
+	})
+
+	t.Run("convert on sign up", func(t *testing.T) {
+		_, err := svc.InviteByEmail(owner, tribeID, "frank@example.com")
+		require.NoError(t, err)
+
+		user := &models.User{ID: uuid.New(), Email: "Frank@Example.com"}
+		require.NoError(t, svc.ConvertPendingInvitations(user))
+		assert.Equal(t, []uuid.UUID{tribeID}, repo.converted[user.ID])
+
+		require.NoError(t, svc.ConvertPendingInvitations(&models.User{ID: uuid.New()}))
+	})
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// GuestExpiryService defines the interface for acting on expiring guest
+// memberships
+type GuestExpiryService interface {
+	// ProcessGuestExpiry warns guests whose access ends soon, then removes
+	// or downgrades those whose access has ended
+	ProcessGuestExpiry() error
+}
+
+// guestExpiryService implements the GuestExpiryService interface
+type guestExpiryService struct {
+	tribes     models.TribeRepository
+	guests     models.GuestExpiryRepository
+	users      models.UserRepository
+	mailer     Mailer
+	policy     models.GuestExpiryPolicy
+	warnBefore time.Duration
+	now        func() time.Time
+}
+
+// NewGuestExpiryService creates a new guest expiry service. Guests are
+// warned by email warnBefore ahead of expiry; zero disables warnings.
+func NewGuestExpiryService(tribes models.TribeRepository, guests models.GuestExpiryRepository, users models.UserRepository, mailer Mailer, policy models.GuestExpiryPolicy, warnBefore time.Duration) GuestExpiryService {
+	return &guestExpiryService{
+		tribes:     tribes,
+		guests:     guests,
+		users:      users,
+		mailer:     mailer,
+		policy:     policy,
+		warnBefore: warnBefore,
+		now:        time.Now,
+	}
+}
+
+// ProcessGuestExpiry implements GuestExpiryService. A failure for one guest
+// does not stop the others; all failures are returned together.
+func (s *guestExpiryService) ProcessGuestExpiry() error {
+	var errs []error
+	if s.warnBefore > 0 && s.mailer != nil {
+		if err := s.warnExpiringGuests(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.expireGuests(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// expireGuests removes or downgrades every expired guest
+func (s *guestExpiryService) expireGuests() error {
+	expired, err := s.tribes.GetExpiredGuestMemberships()
+	if err != nil {
+		return fmt.Errorf("error getting expired guests: %w", err)
+	}
+
+	var errs []error
+	count := 0
+	for _, member := range expired {
+		_, err = s.guests.ExpireGuest(member.TribeID, member.UserID, s.policy)
+		if errors.Is(err, models.ErrNotFound) {
+			// Extended or removed since it was listed
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error expiring guest %s in tribe %s: %w", member.UserID, member.TribeID, err))
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		log.Printf("Expired %d guest memberships (%s)", count, s.policy)
+	}
+	return errors.Join(errs...)
+}
+
+// warnExpiringGuests emails guests whose access ends within the warning
+// window and have not been warned about it yet
+func (s *guestExpiryService) warnExpiringGuests() error {
+	expiring, err := s.guests.GetUnwarnedGuests(s.now().Add(s.warnBefore))
+	if err != nil {
+		return fmt.Errorf("error getting expiring guests: %w", err)
+	}
+
+	var errs []error
+	for _, member := range expiring {
+		if err = s.warnGuest(member); err != nil {
+			errs = append(errs, fmt.Errorf("error warning guest %s in tribe %s: %w", member.UserID, member.TribeID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warnGuest records the warning and then emails one guest. Recording it
+// first means a guest is never emailed twice for the same expiry, at the
+// cost of a lost warning when the mail cannot be sent.
+func (s *guestExpiryService) warnGuest(member *models.TribeMember) error {
+	user, err := s.users.GetByID(member.UserID)
+	if err != nil {
+		return fmt.Errorf("error getting user: %w", err)
+	}
+	tribe, err := s.tribes.GetByID(member.TribeID)
+	if err != nil {
+		return fmt.Errorf("error getting tribe: %w", err)
+	}
+
+	if err = s.guests.MarkExpiryWarned(member.TribeID, member.UserID, *member.ExpiresAt); err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	return s.mailer.Send(MailMessage{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Your guest access to %s ends soon", tribe.Name),
+		Body: fmt.Sprintf("Your guest access to the tribe %q on Tribe ends on %s.\n\n"+
+			"Ask a member of the tribe to extend it or invite you to join.\n",
+			tribe.Name, member.ExpiresAt.Format("January 2, 2006 at 15:04 MST")),
+	})
+}
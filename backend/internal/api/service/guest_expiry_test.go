@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGuestExpiryRepository records guest expiry actions in memory
+type fakeGuestExpiryRepository struct {
+	expiring  []*models.TribeMember
+	expired   []uuid.UUID
+	warned    map[uuid.UUID]time.Time
+	expireErr map[uuid.UUID]error
+	policies  []models.GuestExpiryPolicy
+}
+
+func newFakeGuestExpiryRepository() *fakeGuestExpiryRepository {
+	return &fakeGuestExpiryRepository{
+		warned:    make(map[uuid.UUID]time.Time),
+		expireErr: make(map[uuid.UUID]error),
+	}
+}
+
+func (r *fakeGuestExpiryRepository) ExpireGuest(tribeID, userID uuid.UUID, policy models.GuestExpiryPolicy) (*models.TribeAuditEntry, error) {
+	if err := r.expireErr[userID]; err != nil {
+		return nil, err
+	}
+	r.expired = append(r.expired, userID)
+	r.policies = append(r.policies, policy)
+	return &models.TribeAuditEntry{TribeID: tribeID, UserID: userID, Action: models.AuditGuestExpired}, nil
+}
+
+func (r *fakeGuestExpiryRepository) GetUnwarnedGuests(before time.Time) ([]*models.TribeMember, error) {
+	var members []*models.TribeMember
+	for _, member := range r.expiring {
+		warnedFor, warned := r.warned[member.UserID]
+		if !member.ExpiresAt.After(before) && (!warned || !warnedFor.Equal(*member.ExpiresAt)) {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeGuestExpiryRepository) MarkExpiryWarned(tribeID, userID uuid.UUID, expiresAt time.Time) error {
+	r.warned[userID] = expiresAt
+	return nil
+}
+
+func TestGuestExpiryService(t *testing.T) {
+	tribeID := uuid.New()
+	now := time.Now()
+	soon, later := now.Add(24*time.Hour), now.Add(10*24*time.Hour)
+	expiredGuest, raced, failing := uuid.New(), uuid.New(), uuid.New()
+	soonGuest, laterGuest := uuid.New(), uuid.New()
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+		return &models.Tribe{BaseModel: models.BaseModel{ID: tribeID}, Name: "Book Club"}, nil
+	}
+	tribes.GetExpiredGuestMembershipsFunc = func() ([]*models.TribeMember, error) {
+		return []*models.TribeMember{
+			{TribeID: tribeID, UserID: expiredGuest},
+			{TribeID: tribeID, UserID: raced},
+			{TribeID: tribeID, UserID: failing},
+		}, nil
+	}
+	users := testutil.NewMockUserRepository()
+	users.GetByIDFunc = func(id uuid.UUID) (*models.User, error) {
+		return &models.User{ID: id, Email: id.String() + "@example.com"}, nil
+	}
+
+	newRepo := func() *fakeGuestExpiryRepository {
+		repo := newFakeGuestExpiryRepository()
+		repo.expireErr[raced] = models.ErrNotFound
+		repo.expireErr[failing] = errors.New("database unavailable")
+		repo.expiring = []*models.TribeMember{
+			{TribeID: tribeID, UserID: soonGuest, MembershipType: models.MembershipGuest, ExpiresAt: &soon},
+			{TribeID: tribeID, UserID: laterGuest, MembershipType: models.MembershipGuest, ExpiresAt: &later},
+		}
+		return repo
+	}
+
+	t.Run("expires guests and reports failures", func(t *testing.T) {
+		repo := newRepo()
+		mailer := NewMemoryMailer()
+		svc := NewGuestExpiryService(tribes, repo, users, mailer, models.GuestExpiryDowngrade, 0)
+
+		err := svc.ProcessGuestExpiry()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), failing.String())
+		assert.Equal(t, []uuid.UUID{expiredGuest}, repo.expired)
+		assert.Equal(t, []models.GuestExpiryPolicy{models.GuestExpiryDowngrade}, repo.policies)
+		assert.Empty(t, mailer.Messages(), "warnings are disabled")
+	})
+
+	t.Run("warns guests once per expiry", func(t *testing.T) {
+		repo := newRepo()
+		delete(repo.expireErr, failing)
+		mailer := NewMemoryMailer()
+		svc := NewGuestExpiryService(tribes, repo, users, mailer, models.GuestExpiryRemove, 3*24*time.Hour)
+
+		require.NoError(t, svc.ProcessGuestExpiry())
+		messages := mailer.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, soonGuest.String()+"@example.com", messages[0].To)
+		assert.Contains(t, messages[0].Subject, "Book Club")
+		assert.Equal(t, soon, repo.warned[soonGuest])
+
+		require.NoError(t, svc.ProcessGuestExpiry())
+		assert.Len(t, mailer.Messages(), 1)
+
+		extended := soon.Add(time.Hour)
+		repo.expiring[0].ExpiresAt = &extended
+		require.NoError(t, svc.ProcessGuestExpiry())
+		assert.Len(t, mailer.Messages(), 2)
+	})
+
+	t.Run("unsent warnings are not retried", func(t *testing.T) {
+		repo := newRepo()
+		svc := NewGuestExpiryService(tribes, repo, users, failingMailer{}, models.GuestExpiryRemove, 3*24*time.Hour)
+
+		require.Error(t, svc.ProcessGuestExpiry())
+		assert.Equal(t, soon, repo.warned[soonGuest], "warnings are recorded before sending")
+		assert.Equal(t, []uuid.UUID{expiredGuest}, repo.expired, "expiry still runs when warnings fail")
+
+		mailer := NewMemoryMailer()
+		svc = NewGuestExpiryService(tribes, repo, users, mailer, models.GuestExpiryRemove, 3*24*time.Hour)
+		require.Error(t, svc.ProcessGuestExpiry())
+		assert.Empty(t, mailer.Messages(), "guests are not emailed twice")
+	})
+}
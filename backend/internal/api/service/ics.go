@@ -0,0 +1,291 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// icsProductID identifies this application in generated calendars
+	icsProductID = "-//rlship-tools//Calendar Feed//EN"
+	// icsUIDDomain keeps event UIDs globally unique; UIDs are derived from
+	// the activity ID so they stay stable across feed refreshes
+	icsUIDDomain = "rlship-tools"
+	// icsLineLimit is the longest line RFC 5545 allows, in octets
+	icsLineLimit = 75
+
+	// icsZoneYears is how far past a zone's last listed time its offset
+	// changes are written, so recurring events keep their local time
+	icsZoneYears = 10
+
+	icsUTCFormat   = "20060102T150405Z"
+	icsLocalFormat = "20060102T150405"
+)
+
+// calendarEvent is a scheduled activity ready to be written as a VEVENT
+type calendarEvent struct {
+	activity   *models.Activity
+	schedule   *models.ActivitySchedule
+	exceptions []*models.OccurrenceException
+}
+
+// icsWriter builds an iCalendar document, folding long lines
+type icsWriter struct {
+	buf bytes.Buffer
+}
+
+// line writes one content line, folding it at the RFC 5545 limit without
+// splitting UTF-8 sequences
+func (w *icsWriter) line(name, value string) {
+	content := name + ":" + value
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines spend one octet on the leading space
+		limit = icsLineLimit - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+// escapeICSText escapes a TEXT property value
+func escapeICSText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// icsZone returns the location of a schedule's time zone, or nil when its
+// times are written in UTC
+func icsZone(timeZone string) *time.Location {
+	if timeZone == "" || timeZone == "UTC" {
+		return nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// icsTime formats a DATE-TIME property. Times in UTC use the UTC form;
+// others are written as local time with a TZID parameter naming the IANA
+// zone, which the calendar defines in a VTIMEZONE.
+func icsTime(name string, t time.Time, timeZone string) (string, string) {
+	if loc := icsZone(timeZone); loc != nil {
+		return name + ";TZID=" + timeZone, t.In(loc).Format(icsLocalFormat)
+	}
+	return name, t.UTC().Format(icsUTCFormat)
+}
+
+// icsOffset formats a UTC offset in seconds as a UTC-OFFSET value
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	offset := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		offset += fmt.Sprintf("%02d", seconds%60)
+	}
+	return offset
+}
+
+// icsZoneSpan is the range of times a calendar lists in one zone
+type icsZoneSpan struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// zoneSpans returns the zones the events are written in, in order of first
+// use, with the range of times each one covers
+func zoneSpans(events []calendarEvent) ([]string, map[string]*icsZoneSpan) {
+	var names []string
+	spans := make(map[string]*icsZoneSpan)
+	for _, event := range events {
+		timeZone := event.schedule.TimeZone
+		loc := icsZone(timeZone)
+		if loc == nil {
+			continue
+		}
+
+		times := []time.Time{event.schedule.StartTime, event.schedule.EndTime}
+		if start := event.activity.RecurrenceStart; start != nil {
+			times = append(times, *start)
+		}
+		for _, exception := range event.exceptions {
+			times = append(times, exception.OriginalStart)
+			if exception.StartTime != nil {
+				times = append(times, *exception.StartTime)
+			}
+			if exception.EndTime != nil {
+				times = append(times, *exception.EndTime)
+			}
+		}
+
+		span, ok := spans[timeZone]
+		if !ok {
+			span = &icsZoneSpan{loc: loc, from: times[0], to: times[0]}
+			spans[timeZone] = span
+			names = append(names, timeZone)
+		}
+		for _, t := range times {
+			if t.Before(span.from) {
+				span.from = t
+			}
+			if t.After(span.to) {
+				span.to = t
+			}
+		}
+	}
+	return names, spans
+}
+
+// writeVTimezone defines a zone by the observance in effect at the start of
+// its span and every offset change from there until icsZoneYears after the
+// span ends
+func writeVTimezone(w *icsWriter, tzid string, span *icsZoneSpan) {
+	until := span.to.AddDate(icsZoneYears, 0, 0)
+
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", tzid)
+
+	first := span.from.In(span.loc)
+	name, offset := first.Zone()
+	onset, next := first.ZoneBounds()
+	if onset.IsZero() {
+		onset = first
+	}
+	writeObservance(w, first.IsDST(), name, offset, offset, onset)
+
+	for !next.IsZero() && next.Before(until) {
+		change := next.In(span.loc)
+		name, nextOffset := change.Zone()
+		writeObservance(w, change.IsDST(), name, offset, nextOffset, change)
+		offset = nextOffset
+		_, next = change.ZoneBounds()
+	}
+
+	w.line("END", "VTIMEZONE")
+}
+
+// writeObservance writes a STANDARD or DAYLIGHT component starting at onset,
+// given as local time before the change
+func writeObservance(w *icsWriter, daylight bool, name string, offsetFrom, offsetTo int, onset time.Time) {
+	component := "STANDARD"
+	if daylight {
+		component = "DAYLIGHT"
+	}
+	w.line("BEGIN", component)
+	w.line("DTSTART", onset.In(time.FixedZone(name, offsetFrom)).Format(icsLocalFormat))
+	w.line("TZOFFSETFROM", icsOffset(offsetFrom))
+	w.line("TZOFFSETTO", icsOffset(offsetTo))
+	w.line("TZNAME", escapeICSText(name))
+	w.line("END", component)
+}
+
+// renderICS writes a published calendar with one VEVENT per event, plus an
+// overriding VEVENT for each moved occurrence of a recurring event. Each
+// zone the events use is defined once, ahead of the events.
+func renderICS(name string, events []calendarEvent) []byte {
+	w := &icsWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", icsProductID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", escapeICSText(name))
+
+	zones, spans := zoneSpans(events)
+	for _, tzid := range zones {
+		writeVTimezone(w, tzid, spans[tzid])
+	}
+
+	for _, event := range events {
+		writeVEvent(w, event)
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func writeVEvent(w *icsWriter, event calendarEvent) {
+	activity, schedule := event.activity, event.schedule
+	uid := fmt.Sprintf("%s@%s", activity.ID, icsUIDDomain)
+	modified := activity.UpdatedAt
+	if schedule.UpdatedAt.After(modified) {
+		modified = schedule.UpdatedAt
+	}
+	duration := schedule.EndTime.Sub(schedule.StartTime)
+
+	start := schedule.StartTime
+	recurring := activity.RecurrenceRule != ""
+	if recurring && activity.RecurrenceStart != nil {
+		start = *activity.RecurrenceStart
+	}
+
+	writeCommon := func(start, end time.Time) {
+		w.line("UID", uid)
+		w.line("DTSTAMP", modified.UTC().Format(icsUTCFormat))
+		w.line("LAST-MODIFIED", modified.UTC().Format(icsUTCFormat))
+		w.line("SEQUENCE", strconv.Itoa(schedule.Sequence))
+		w.line(icsTime("DTSTART", start, schedule.TimeZone))
+		w.line(icsTime("DTEND", end, schedule.TimeZone))
+		w.line("SUMMARY", escapeICSText(activity.Name))
+		if activity.Description != "" {
+			w.line("DESCRIPTION", escapeICSText(activity.Description))
+		}
+		if location := schedule.Location; location != nil {
+			if location.Address != "" {
+				w.line("LOCATION", escapeICSText(location.Address))
+			}
+			w.line("GEO", fmt.Sprintf("%s;%s",
+				strconv.FormatFloat(location.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(location.Longitude, 'f', -1, 64)))
+		}
+	}
+
+	w.line("BEGIN", "VEVENT")
+	writeCommon(start, start.Add(duration))
+	if recurring {
+		w.line("RRULE", activity.RecurrenceRule)
+		for _, exception := range event.exceptions {
+			if exception.Cancelled {
+				w.line(icsTime("EXDATE", exception.OriginalStart, schedule.TimeZone))
+			}
+		}
+	}
+	w.line("END", "VEVENT")
+
+	if !recurring {
+		return
+	}
+	for _, exception := range event.exceptions {
+		if exception.Cancelled || exception.StartTime == nil || exception.EndTime == nil {
+			continue
+		}
+		w.line("BEGIN", "VEVENT")
+		w.line(icsTime("RECURRENCE-ID", exception.OriginalStart, schedule.TimeZone))
+		writeCommon(*exception.StartTime, *exception.EndTime)
+		w.line("END", "VEVENT")
+	}
+}
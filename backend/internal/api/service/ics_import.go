@@ -0,0 +1,450 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// maxBusyIntervals caps how many intervals one import may produce
+const maxBusyIntervals = 5000
+
+// icsProperty is one unfolded content line
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// icsBusyEvent holds the VEVENT properties that decide when its owner is busy
+type icsBusyEvent struct {
+	uid          string
+	start        time.Time
+	end          *time.Time
+	duration     *time.Duration
+	allDay       bool
+	rrule        string
+	exdates      []time.Time
+	recurrenceID *time.Time
+	free         bool
+}
+
+// parseBusyIntervals reads the busy time of an iCalendar document that
+// overlaps [from, to). Opaque events, including their recurrences, and BUSY
+// periods of VFREEBUSY components count as busy; transparent and cancelled
+// events do not. Floating times and dates are read as UTC. Recurrence rules
+// this application cannot expand fall back to their first instance, and
+// their week start is ignored.
+func parseBusyIntervals(data []byte, from, to time.Time) ([]models.BusyInterval, error) {
+	properties := unfoldICS(string(data))
+	if len(properties) == 0 || properties[0].name != "BEGIN" || !strings.EqualFold(properties[0].value, "VCALENDAR") {
+		return nil, fmt.Errorf("%w: not an iCalendar file", models.ErrInvalidInput)
+	}
+
+	var events []*icsBusyEvent
+	var busy []models.BusyInterval
+	var event *icsBusyEvent
+	inFreeBusy := false
+	for _, prop := range properties {
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event = &icsBusyEvent{}
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if event != nil && !event.start.IsZero() && !event.free {
+				events = append(events, event)
+			}
+			event = nil
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VFREEBUSY"):
+			inFreeBusy = true
+		case prop.name == "END" && strings.EqualFold(prop.value, "VFREEBUSY"):
+			inFreeBusy = false
+		case event != nil:
+			if err := event.set(prop); err != nil {
+				return nil, err
+			}
+		case inFreeBusy && prop.name == "FREEBUSY":
+			periods, err := parseFreeBusy(prop)
+			if err != nil {
+				return nil, err
+			}
+			busy = append(busy, periods...)
+		}
+	}
+
+	// Instances moved or edited by an override are replaced by the override
+	overridden := make(map[string][]time.Time)
+	for _, e := range events {
+		if e.recurrenceID != nil {
+			overridden[e.uid] = append(overridden[e.uid], *e.recurrenceID)
+		}
+	}
+	for _, e := range events {
+		busy = append(busy, e.intervals(overridden[e.uid], from, to)...)
+	}
+
+	return mergeBusyIntervals(busy, from, to)
+}
+
+// set records a VEVENT property
+func (e *icsBusyEvent) set(prop icsProperty) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.uid = prop.value
+	case "DTSTART":
+		e.start, e.allDay, err = parseICSTime(prop.value, prop.params)
+	case "DTEND":
+		var end time.Time
+		end, _, err = parseICSTime(prop.value, prop.params)
+		e.end = &end
+	case "DURATION":
+		var duration time.Duration
+		duration, err = parseICSDuration(prop.value)
+		e.duration = &duration
+	case "RRULE":
+		e.rrule = prop.value
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var exdate time.Time
+			if exdate, _, err = parseICSTime(value, prop.params); err != nil {
+				break
+			}
+			e.exdates = append(e.exdates, exdate)
+		}
+	case "RECURRENCE-ID":
+		var id time.Time
+		id, _, err = parseICSTime(prop.value, prop.params)
+		e.recurrenceID = &id
+	case "TRANSP":
+		e.free = e.free || strings.EqualFold(prop.value, "TRANSPARENT")
+	case "STATUS":
+		e.free = e.free || strings.EqualFold(prop.value, "CANCELLED")
+	}
+	return err
+}
+
+// intervals expands the event into the busy intervals overlapping [from, to)
+func (e *icsBusyEvent) intervals(overridden []time.Time, from, to time.Time) []models.BusyInterval {
+	var duration time.Duration
+	switch {
+	case e.end != nil:
+		duration = e.end.Sub(e.start)
+	case e.duration != nil:
+		duration = *e.duration
+	case e.allDay:
+		duration = 24 * time.Hour
+	}
+	if duration <= 0 {
+		return nil
+	}
+
+	starts := []time.Time{e.start}
+	if e.rrule != "" && e.recurrenceID == nil {
+		if rule, err := models.ParseRecurrenceRule(withoutWeekStart(e.rrule), e.start.Location()); err == nil {
+			windowStart := from.Add(-duration + time.Nanosecond)
+			starts = rule.Between(rule.SkipTo(e.start, windowStart), windowStart, to)
+		} else {
+			log.Printf("Reading only the first instance of %s: %v", e.uid, err)
+		}
+	}
+
+	skipped := make(map[time.Time]bool, len(e.exdates)+len(overridden))
+	for _, t := range e.exdates {
+		skipped[t.UTC()] = true
+	}
+	if e.recurrenceID == nil {
+		for _, t := range overridden {
+			skipped[t.UTC()] = true
+		}
+	}
+
+	intervals := make([]models.BusyInterval, 0, len(starts))
+	for _, start := range starts {
+		if skipped[start.UTC()] {
+			continue
+		}
+		intervals = append(intervals, models.BusyInterval{StartTime: start, EndTime: start.Add(duration)})
+	}
+	return intervals
+}
+
+// withoutWeekStart drops the WKST part of a rule. Imported rules come from
+// calendars whose weeks may start on any day, and the week start only moves
+// instances of weekly rules with both an INTERVAL and a BYDAY.
+func withoutWeekStart(rrule string) string {
+	parts := strings.Split(rrule, ";")
+	kept := parts[:0]
+	for _, part := range parts {
+		if name, _, _ := strings.Cut(part, "="); !strings.EqualFold(strings.TrimSpace(name), "WKST") {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, ";")
+}
+
+// parseFreeBusy reads the BUSY periods of a FREEBUSY property; FREE periods
+// are skipped
+func parseFreeBusy(prop icsProperty) ([]models.BusyInterval, error) {
+	if fbType := prop.params["FBTYPE"]; fbType != "" && strings.EqualFold(fbType, "FREE") {
+		return nil, nil
+	}
+
+	var periods []models.BusyInterval
+	for _, period := range strings.Split(prop.value, ",") {
+		startValue, endValue, ok := strings.Cut(period, "/")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid FREEBUSY period %q", models.ErrInvalidInput, period)
+		}
+		start, _, err := parseICSTime(startValue, nil)
+		if err != nil {
+			return nil, err
+		}
+		var end time.Time
+		if strings.HasPrefix(endValue, "P") || strings.HasPrefix(endValue, "+P") {
+			duration, durationErr := parseICSDuration(endValue)
+			if durationErr != nil {
+				return nil, durationErr
+			}
+			end = start.Add(duration)
+		} else if end, _, err = parseICSTime(endValue, nil); err != nil {
+			return nil, err
+		}
+		periods = append(periods, models.BusyInterval{StartTime: start, EndTime: end})
+	}
+	return periods, nil
+}
+
+// mergeBusyIntervals clips intervals to [from, to) and merges the ones that
+// overlap or touch
+func mergeBusyIntervals(intervals []models.BusyInterval, from, to time.Time) ([]models.BusyInterval, error) {
+	clipped := make([]models.BusyInterval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.StartTime.Before(from) {
+			interval.StartTime = from
+		}
+		if interval.EndTime.After(to) {
+			interval.EndTime = to
+		}
+		if interval.EndTime.After(interval.StartTime) {
+			clipped = append(clipped, models.BusyInterval{StartTime: interval.StartTime.UTC(), EndTime: interval.EndTime.UTC()})
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool {
+		return clipped[i].StartTime.Before(clipped[j].StartTime)
+	})
+
+	merged := make([]models.BusyInterval, 0, len(clipped))
+	for _, interval := range clipped {
+		if n := len(merged); n > 0 && !interval.StartTime.After(merged[n-1].EndTime) {
+			if interval.EndTime.After(merged[n-1].EndTime) {
+				merged[n-1].EndTime = interval.EndTime
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	if len(merged) > maxBusyIntervals {
+		return nil, fmt.Errorf("%w: calendar has more than %d busy intervals", models.ErrInvalidInput, maxBusyIntervals)
+	}
+	return merged, nil
+}
+
+// unfoldICS joins folded lines and splits each into its name, parameters
+// and value. Lines that are not properties are dropped.
+func unfoldICS(data string) []icsProperty {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var properties []icsProperty
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if prop, ok := parseICSProperty(line); ok {
+			properties = append(properties, prop)
+		}
+	}
+	return properties
+}
+
+// parseICSProperty splits a content line at the first colon outside a
+// quoted parameter value
+func parseICSProperty(line string) (icsProperty, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icsProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{
+		name:   strings.ToUpper(strings.TrimSpace(parts[0])),
+		params: make(map[string]string, len(parts)-1),
+		value:  strings.TrimSpace(line[colon+1:]),
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// windowsTimeZones maps the Windows zone names Outlook and Exchange write as
+// TZIDs to their IANA zones
+var windowsTimeZones = map[string]string{
+	"Dateline Standard Time":         "Etc/GMT+12",
+	"Hawaiian Standard Time":         "Pacific/Honolulu",
+	"Alaskan Standard Time":          "America/Anchorage",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"US Mountain Standard Time":      "America/Phoenix",
+	"Mountain Standard Time":         "America/Denver",
+	"Central Standard Time":          "America/Chicago",
+	"Central America Standard Time":  "America/Guatemala",
+	"Canada Central Standard Time":   "America/Regina",
+	"Eastern Standard Time":          "America/New_York",
+	"US Eastern Standard Time":       "America/Indianapolis",
+	"Atlantic Standard Time":         "America/Halifax",
+	"Newfoundland Standard Time":     "America/St_Johns",
+	"SA Pacific Standard Time":       "America/Bogota",
+	"E. South America Standard Time": "America/Sao_Paulo",
+	"Argentina Standard Time":        "America/Buenos_Aires",
+	"UTC":                            "UTC",
+	"GMT Standard Time":              "Europe/London",
+	"Greenwich Standard Time":        "Atlantic/Reykjavik",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Romance Standard Time":          "Europe/Paris",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Central European Standard Time": "Europe/Warsaw",
+	"GTB Standard Time":              "Europe/Bucharest",
+	"FLE Standard Time":              "Europe/Kiev",
+	"E. Europe Standard Time":        "Europe/Chisinau",
+	"Israel Standard Time":           "Asia/Jerusalem",
+	"South Africa Standard Time":     "Africa/Johannesburg",
+	"Russian Standard Time":          "Europe/Moscow",
+	"Turkey Standard Time":           "Europe/Istanbul",
+	"Arabian Standard Time":          "Asia/Dubai",
+	"India Standard Time":            "Asia/Calcutta",
+	"SE Asia Standard Time":          "Asia/Bangkok",
+	"China Standard Time":            "Asia/Shanghai",
+	"Singapore Standard Time":        "Asia/Singapore",
+	"Taipei Standard Time":           "Asia/Taipei",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"Korea Standard Time":            "Asia/Seoul",
+	"AUS Central Standard Time":      "Australia/Darwin",
+	"E. Australia Standard Time":     "Australia/Brisbane",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"W. Australia Standard Time":     "Australia/Perth",
+	"New Zealand Standard Time":      "Pacific/Auckland",
+}
+
+// icsLocation resolves a TZID, which may be an IANA or a Windows zone name.
+// Unknown zones are read as UTC and logged.
+func icsLocation(tzid string) *time.Location {
+	if zone, ok := windowsTimeZones[tzid]; ok {
+		tzid = zone
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		log.Printf("Reading times in unknown time zone %q as UTC", tzid)
+		return time.UTC
+	}
+	return loc
+}
+
+// parseICSTime reads a DATE or DATE-TIME value. A TZID parameter names the
+// zone of a local time; unknown zones and floating times are read as UTC.
+func parseICSTime(value string, params map[string]string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		loc = icsLocation(tzid)
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid date %q", models.ErrInvalidInput, value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsUTCFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid date-time %q", models.ErrInvalidInput, value)
+		}
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(icsLocalFormat, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: invalid date-time %q", models.ErrInvalidInput, value)
+	}
+	return t, false, nil
+}
+
+// parseICSDuration reads a DURATION value such as "PT1H30M", "P1D" or "P2W"
+func parseICSDuration(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("%w: invalid duration %q", models.ErrInvalidInput, value)
+	rest := strings.TrimPrefix(strings.TrimSpace(value), "+")
+	sign := time.Duration(1)
+	if strings.HasPrefix(rest, "-") {
+		sign, rest = -1, rest[1:]
+	}
+	rest, ok := strings.CutPrefix(rest, "P")
+	if !ok || rest == "" {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T' && !inTime && number == "":
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, invalid
+		}
+		number = ""
+		var unit time.Duration
+		switch {
+		case !inTime && r == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && r == 'D':
+			unit = 24 * time.Hour
+		case inTime && r == 'H':
+			unit = time.Hour
+		case inTime && r == 'M':
+			unit = time.Minute
+		case inTime && r == 'S':
+			unit = time.Second
+		default:
+			return 0, invalid
+		}
+		total += time.Duration(n) * unit
+	}
+	if number != "" {
+		return 0, invalid
+	}
+	return sign * total, nil
+}
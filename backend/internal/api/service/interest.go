@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// InterestService defines the interface for interest indicators, the "I'm up
+// for something" signals tribe members share
+type InterestService interface {
+	// CreateInterest records an indicator for a member of its tribe
+	CreateInterest(indicator *models.InterestIndicator) error
+	// GetInterest returns an indicator visible to the user
+	GetInterest(userID, interestID uuid.UUID) (*models.InterestIndicator, error)
+	// UpdateInterest saves changes the owner made to an indicator
+	UpdateInterest(userID uuid.UUID, indicator *models.InterestIndicator) error
+	// DeleteInterest withdraws one of the user's indicators
+	DeleteInterest(userID, interestID uuid.UUID) error
+	// ListMyInterests returns the user's current and upcoming indicators
+	ListMyInterests(userID uuid.UUID) ([]*models.InterestIndicator, error)
+	// ListTribeInterests returns the tribe's indicators in effect now that
+	// match the filter
+	ListTribeInterests(userID, tribeID uuid.UUID, filter models.InterestFilter) ([]*models.InterestIndicator, error)
+	// GenerateMenu seeds a tribe menu with the lists matching indicators
+	// point to
+	GenerateMenu(userID, tribeID uuid.UUID, filter models.InterestFilter, params *models.MenuParams) (*models.MenuSession, error)
+	// CleanupExpiredInterests purges indicators that have expired
+	CleanupExpiredInterests() error
+}
+
+// interestService implements the InterestService interface
+type interestService struct {
+	repo   models.InterestIndicatorRepository
+	lists  models.ListRepository
+	tribes models.TribeRepository
+	menus  MenuService
+}
+
+// NewInterestService creates a new interest service
+func NewInterestService(repo models.InterestIndicatorRepository, lists models.ListRepository, tribes models.TribeRepository, menus MenuService) InterestService {
+	return &interestService{
+		repo:   repo,
+		lists:  lists,
+		tribes: tribes,
+		menus:  menus,
+	}
+}
+
+// CreateInterest implements InterestService. The start defaults to now and
+// the expiry to DefaultInterestDuration after the start.
+func (s *interestService) CreateInterest(indicator *models.InterestIndicator) error {
+	now := time.Now()
+	if indicator.StartsAt.IsZero() {
+		indicator.StartsAt = now
+	}
+	if indicator.ExpiresAt.IsZero() {
+		indicator.ExpiresAt = indicator.StartsAt.Add(models.DefaultInterestDuration)
+	}
+	indicator.Mood = strings.TrimSpace(indicator.Mood)
+
+	if err := indicator.Validate(); err != nil {
+		return err
+	}
+	if indicator.IsExpired(now) {
+		return fmt.Errorf("%w: expiry must be in the future", models.ErrInvalidInput)
+	}
+	if err := s.requireTribeMember(indicator.TribeID, indicator.UserID); err != nil {
+		return err
+	}
+	if err := s.requireTribeList(indicator); err != nil {
+		return err
+	}
+
+	return s.repo.Create(indicator)
+}
+
+// GetInterest implements InterestService. Indicators are visible to members
+// of their tribe.
+func (s *interestService) GetInterest(userID, interestID uuid.UUID) (*models.InterestIndicator, error) {
+	indicator, err := s.repo.GetByID(interestID)
+	if err != nil {
+		return nil, err
+	}
+	if indicator.UserID != userID {
+		if err := s.requireTribeMember(indicator.TribeID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return indicator, nil
+}
+
+// UpdateInterest implements InterestService. Only the owner may change an
+// indicator, and its user and tribe are kept.
+func (s *interestService) UpdateInterest(userID uuid.UUID, indicator *models.InterestIndicator) error {
+	current, err := s.repo.GetByID(indicator.ID)
+	if err != nil {
+		return err
+	}
+	if current.UserID != userID {
+		return models.ErrForbidden
+	}
+
+	indicator.UserID = current.UserID
+	indicator.TribeID = current.TribeID
+	indicator.CreatedAt = current.CreatedAt
+	indicator.Mood = strings.TrimSpace(indicator.Mood)
+	if err = indicator.Validate(); err != nil {
+		return err
+	}
+	if indicator.IsExpired(time.Now()) {
+		return fmt.Errorf("%w: expiry must be in the future", models.ErrInvalidInput)
+	}
+	if err = s.requireTribeList(indicator); err != nil {
+		return err
+	}
+
+	return s.repo.Update(indicator)
+}
+
+// DeleteInterest implements InterestService
+func (s *interestService) DeleteInterest(userID, interestID uuid.UUID) error {
+	indicator, err := s.repo.GetByID(interestID)
+	if err != nil {
+		return err
+	}
+	if indicator.UserID != userID {
+		return models.ErrForbidden
+	}
+	return s.repo.Delete(interestID)
+}
+
+// ListMyInterests implements InterestService
+func (s *interestService) ListMyInterests(userID uuid.UUID) ([]*models.InterestIndicator, error) {
+	return s.repo.ListByUser(userID, time.Now())
+}
+
+// ListTribeInterests implements InterestService
+func (s *interestService) ListTribeInterests(userID, tribeID uuid.UUID, filter models.InterestFilter) ([]*models.InterestIndicator, error) {
+	if err := s.requireTribeMember(tribeID, userID); err != nil {
+		return nil, err
+	}
+
+	indicators, err := s.repo.ListActiveByTribe(tribeID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*models.InterestIndicator, 0, len(indicators))
+	for _, indicator := range indicators {
+		if indicator.Matches(filter) {
+			matching = append(matching, indicator)
+		}
+	}
+	return matching, nil
+}
+
+// GenerateMenu implements InterestService. Indicators naming a list add that
+// list; indicators naming an activity type add the tribe's lists of the
+// same type. Lists already in params are kept.
+func (s *interestService) GenerateMenu(userID, tribeID uuid.UUID, filter models.InterestFilter, params *models.MenuParams) (*models.MenuSession, error) {
+	indicators, err := s.ListTribeInterests(userID, tribeID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var tribeLists []*models.List
+	seen := make(map[uuid.UUID]bool, len(params.ListIDs))
+	for _, listID := range params.ListIDs {
+		seen[listID] = true
+	}
+	add := func(listID uuid.UUID) {
+		if !seen[listID] {
+			seen[listID] = true
+			params.ListIDs = append(params.ListIDs, listID)
+		}
+	}
+
+	for _, indicator := range indicators {
+		switch {
+		case indicator.ListID != nil:
+			add(*indicator.ListID)
+		case indicator.ActivityType != nil:
+			if tribeLists == nil {
+				if tribeLists, err = s.lists.GetTribeLists(tribeID); err != nil {
+					return nil, fmt.Errorf("error getting tribe lists: %w", err)
+				}
+			}
+			for _, list := range tribeLists {
+				if activityType, ok := list.Type.ActivityType(); ok && activityType == *indicator.ActivityType {
+					add(list.ID)
+				}
+			}
+		}
+	}
+
+	if len(params.ListIDs) == 0 {
+		return nil, fmt.Errorf("%w: no current interests point to a list", models.ErrInvalidInput)
+	}
+	return s.menus.CreateSession(userID, &tribeID, params)
+}
+
+// CleanupExpiredInterests implements InterestService
+func (s *interestService) CleanupExpiredInterests() error {
+	deleted, err := s.repo.DeleteExpired(context.Background(), time.Now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Removed %d expired interest indicators", deleted)
+	}
+	return nil
+}
+
+// requireTribeMember returns ErrForbidden unless the user is an active
+// member of the tribe
+func (s *interestService) requireTribeMember(tribeID, userID uuid.UUID) error {
+	members, err := s.tribes.GetMembers(tribeID)
+	if err != nil {
+		return fmt.Errorf("error getting tribe members: %w", err)
+	}
+
+	now := time.Now()
+	for _, member := range members {
+		if member.UserID == userID && member.IsActive(now) {
+			return nil
+		}
+	}
+
+	return models.ErrForbidden
+}
+
+// requireTribeList checks that an indicator's list is owned by or shared
+// with its tribe, so that every member may see what it points to
+func (s *interestService) requireTribeList(indicator *models.InterestIndicator) error {
+	if indicator.ListID == nil {
+		return nil
+	}
+
+	lists, err := s.lists.GetTribeLists(indicator.TribeID)
+	if err != nil {
+		return fmt.Errorf("error getting tribe lists: %w", err)
+	}
+	for _, list := range lists {
+		if list.ID == *indicator.ListID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: list is not available to this tribe", models.ErrInvalidInput)
+}
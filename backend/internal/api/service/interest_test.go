@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInterestRepository keeps interest indicators in memory
+type fakeInterestRepository struct {
+	indicators map[uuid.UUID]*models.InterestIndicator
+}
+
+func newFakeInterestRepository() *fakeInterestRepository {
+	return &fakeInterestRepository{indicators: make(map[uuid.UUID]*models.InterestIndicator)}
+}
+
+func (r *fakeInterestRepository) Create(indicator *models.InterestIndicator) error {
+	if indicator.ID == uuid.Nil {
+		indicator.ID = uuid.New()
+	}
+	stored := *indicator
+	r.indicators[indicator.ID] = &stored
+	return nil
+}
+
+func (r *fakeInterestRepository) GetByID(id uuid.UUID) (*models.InterestIndicator, error) {
+	indicator, ok := r.indicators[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *indicator
+	return &copied, nil
+}
+
+func (r *fakeInterestRepository) Update(indicator *models.InterestIndicator) error {
+	if _, ok := r.indicators[indicator.ID]; !ok {
+		return models.ErrNotFound
+	}
+	stored := *indicator
+	r.indicators[indicator.ID] = &stored
+	return nil
+}
+
+func (r *fakeInterestRepository) Delete(id uuid.UUID) error {
+	if _, ok := r.indicators[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(r.indicators, id)
+	return nil
+}
+
+func (r *fakeInterestRepository) ListByUser(userID uuid.UUID, now time.Time) ([]*models.InterestIndicator, error) {
+	var indicators []*models.InterestIndicator
+	for _, indicator := range r.indicators {
+		if indicator.UserID == userID && !indicator.IsExpired(now) {
+			indicators = append(indicators, indicator)
+		}
+	}
+	return indicators, nil
+}
+
+func (r *fakeInterestRepository) ListActiveByTribe(tribeID uuid.UUID, now time.Time) ([]*models.InterestIndicator, error) {
+	var indicators []*models.InterestIndicator
+	for _, indicator := range r.indicators {
+		if indicator.TribeID == tribeID && indicator.IsActive(now) {
+			indicators = append(indicators, indicator)
+		}
+	}
+	return indicators, nil
+}
+
+func (r *fakeInterestRepository) DeleteExpired(_ context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for id, indicator := range r.indicators {
+		if indicator.IsExpired(now) {
+			delete(r.indicators, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// recordingMenuService records the sessions it is asked to create
+type recordingMenuService struct {
+	MenuService
+	userID  uuid.UUID
+	tribeID *uuid.UUID
+}
+
+func (s *recordingMenuService) CreateSession(userID uuid.UUID, tribeID *uuid.UUID, params *models.MenuParams) (*models.MenuSession, error) {
+	s.userID, s.tribeID = userID, tribeID
+	return &models.MenuSession{UserID: userID, TribeID: tribeID, Params: *params}, nil
+}
+
+func TestInterestService(t *testing.T) {
+	alice, bob, outsider := uuid.New(), uuid.New(), uuid.New()
+	tribeID := uuid.New()
+	pizzaList := &models.List{ID: uuid.New(), Type: models.ListTypeLocation}
+	hikeList := &models.List{ID: uuid.New(), Type: models.ListTypeActivity}
+	parkList := &models.List{ID: uuid.New(), Type: models.ListTypeLocation}
+	savedPlaces := &models.List{ID: uuid.New(), Type: models.ListTypeGoogleMap}
+	chores := &models.List{ID: uuid.New(), Type: models.ListTypeGeneral}
+	location := models.ActivityTypeLocation
+
+	newService := func() (InterestService, *fakeInterestRepository, *testutil.MockListRepository, *recordingMenuService) {
+		repo := newFakeInterestRepository()
+		listRepo := new(testutil.MockListRepository)
+		listRepo.On("GetTribeLists", tribeID).Return([]*models.List{pizzaList, hikeList, parkList, savedPlaces, chores}, nil).Maybe()
+		tribeRepo := testutil.NewMockTribeRepository()
+		tribeRepo.GetMembersFunc = func(uuid.UUID) ([]*models.TribeMember, error) {
+			return []*models.TribeMember{
+				{UserID: alice, MembershipType: models.MembershipFull},
+				{UserID: bob, MembershipType: models.MembershipFull},
+			}, nil
+		}
+		menus := &recordingMenuService{}
+		return NewInterestService(repo, listRepo, tribeRepo, menus), repo, listRepo, menus
+	}
+
+	t.Run("Create defaults the window", func(t *testing.T) {
+		service, _, _, _ := newService()
+		indicator := &models.InterestIndicator{UserID: alice, TribeID: tribeID, Mood: "  hungry  "}
+
+		before := time.Now()
+		require.NoError(t, service.CreateInterest(indicator))
+		assert.NotEqual(t, uuid.Nil, indicator.ID)
+		assert.Equal(t, "hungry", indicator.Mood)
+		assert.False(t, indicator.StartsAt.Before(before))
+		assert.Equal(t, models.DefaultInterestDuration, indicator.ExpiresAt.Sub(indicator.StartsAt))
+	})
+
+	t.Run("Create rejects bad indicators", func(t *testing.T) {
+		service, _, _, _ := newService()
+		past := time.Now().Add(-2 * time.Hour)
+		unknownList := uuid.New()
+
+		err := service.CreateInterest(&models.InterestIndicator{UserID: outsider, TribeID: tribeID})
+		assert.ErrorIs(t, err, models.ErrForbidden)
+
+		err = service.CreateInterest(&models.InterestIndicator{UserID: alice, TribeID: tribeID, StartsAt: past, ExpiresAt: past.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		err = service.CreateInterest(&models.InterestIndicator{UserID: alice, TribeID: tribeID, ListID: &unknownList})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("Only the owner changes an indicator", func(t *testing.T) {
+		service, _, _, _ := newService()
+		indicator := &models.InterestIndicator{UserID: alice, TribeID: tribeID}
+		require.NoError(t, service.CreateInterest(indicator))
+
+		seen, err := service.GetInterest(bob, indicator.ID)
+		require.NoError(t, err)
+		assert.Equal(t, alice, seen.UserID)
+		_, err = service.GetInterest(outsider, indicator.ID)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+
+		update := &models.InterestIndicator{
+			ID:        indicator.ID,
+			ListID:    &pizzaList.ID,
+			StartsAt:  indicator.StartsAt,
+			ExpiresAt: indicator.ExpiresAt.Add(time.Hour),
+		}
+		assert.ErrorIs(t, service.UpdateInterest(bob, update), models.ErrForbidden)
+		require.NoError(t, service.UpdateInterest(alice, update))
+		assert.Equal(t, alice, update.UserID)
+		assert.Equal(t, tribeID, update.TribeID)
+
+		assert.ErrorIs(t, service.DeleteInterest(bob, indicator.ID), models.ErrForbidden)
+		require.NoError(t, service.DeleteInterest(alice, indicator.ID))
+		_, err = service.GetInterest(alice, indicator.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("Tribe interests match the filter", func(t *testing.T) {
+		service, repo, _, _ := newService()
+		now := time.Now()
+		anything := &models.InterestIndicator{UserID: alice, TribeID: tribeID}
+		pizza := &models.InterestIndicator{UserID: bob, TribeID: tribeID, ListID: &pizzaList.ID}
+		later := &models.InterestIndicator{UserID: bob, TribeID: tribeID, ActivityType: &location, StartsAt: now.Add(time.Hour), ExpiresAt: now.Add(2 * time.Hour)}
+		for _, indicator := range []*models.InterestIndicator{anything, pizza, later} {
+			require.NoError(t, service.CreateInterest(indicator))
+		}
+		require.NoError(t, repo.Create(&models.InterestIndicator{UserID: alice, TribeID: tribeID, StartsAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}))
+
+		all, err := service.ListTribeInterests(alice, tribeID, models.InterestFilter{})
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+
+		forPizza, err := service.ListTribeInterests(alice, tribeID, models.InterestFilter{ListID: &pizzaList.ID})
+		require.NoError(t, err)
+		assert.Len(t, forPizza, 2)
+
+		forHike, err := service.ListTribeInterests(alice, tribeID, models.InterestFilter{ListID: &hikeList.ID})
+		require.NoError(t, err)
+		require.Len(t, forHike, 1)
+		assert.Equal(t, anything.ID, forHike[0].ID)
+
+		_, err = service.ListTribeInterests(outsider, tribeID, models.InterestFilter{})
+		assert.ErrorIs(t, err, models.ErrForbidden)
+
+		mine, err := service.ListMyInterests(bob)
+		require.NoError(t, err)
+		assert.Len(t, mine, 2)
+	})
+
+	t.Run("Menu is seeded from matching interests", func(t *testing.T) {
+		service, _, _, menus := newService()
+		require.NoError(t, service.CreateInterest(&models.InterestIndicator{UserID: alice, TribeID: tribeID, ActivityType: &location}))
+		require.NoError(t, service.CreateInterest(&models.InterestIndicator{UserID: bob, TribeID: tribeID, ListID: &pizzaList.ID}))
+		require.NoError(t, service.CreateInterest(&models.InterestIndicator{UserID: bob, TribeID: tribeID}))
+
+		extra := uuid.New()
+		session, err := service.GenerateMenu(bob, tribeID, models.InterestFilter{}, &models.MenuParams{ListIDs: []uuid.UUID{extra, pizzaList.ID}})
+		require.NoError(t, err)
+		assert.Equal(t, bob, menus.userID)
+		assert.Equal(t, &tribeID, menus.tribeID)
+		assert.ElementsMatch(t, []uuid.UUID{extra, pizzaList.ID, parkList.ID, savedPlaces.ID}, session.Params.ListIDs)
+
+		_, err = service.GenerateMenu(bob, tribeID, models.InterestFilter{ListID: &hikeList.ID}, &models.MenuParams{})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		_, err = service.GenerateMenu(outsider, tribeID, models.InterestFilter{}, &models.MenuParams{})
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("Cleanup removes expired indicators", func(t *testing.T) {
+		service, repo, _, _ := newService()
+		now := time.Now()
+		require.NoError(t, repo.Create(&models.InterestIndicator{UserID: alice, TribeID: tribeID, StartsAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}))
+		require.NoError(t, service.CreateInterest(&models.InterestIndicator{UserID: alice, TribeID: tribeID}))
+
+		require.NoError(t, service.CleanupExpiredInterests())
+		assert.Len(t, repo.indicators, 1)
+	})
+}
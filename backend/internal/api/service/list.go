@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"sort"
 	"strings"
 	"time"
@@ -22,7 +24,7 @@ type ListService interface {
 	List(offset, limit int) ([]*models.List, error)
 
 	// List items
-	AddListItem(item *models.ListItem) error
+	AddListItem(item *models.ListItem, userID uuid.UUID) error
 	GetListItems(listID uuid.UUID) ([]*models.ListItem, error)
 	UpdateListItem(item *models.ListItem) error
 	RemoveListItem(listID, itemID uuid.UUID) error
@@ -32,8 +34,11 @@ type ListService interface {
 
 	// Sync management
 	SyncList(listID uuid.UUID) error
+	// CanSync reports whether lists from the source have a provider to
+	// sync them from
+	CanSync(source models.SyncSource) bool
 	GetListConflicts(listID uuid.UUID) ([]*models.SyncConflict, error)
-	ResolveListConflict(listID, conflictID uuid.UUID, resolution string) error
+	ResolveListConflict(listID, conflictID uuid.UUID, resolution string, custom *models.ListItem) error
 	CreateListConflict(conflict *models.SyncConflict) error
 
 	// Owner management
@@ -58,11 +63,13 @@ type ListService interface {
 // listService implements the ListService interface
 type listService struct {
 	repo models.ListRepository
+	sync *SyncService
 }
 
-// NewListService creates a new list service
-func NewListService(repo models.ListRepository) ListService {
-	return &listService{repo: repo}
+// NewListService creates a new list service. Lists whose source has one of
+// the given providers are synced through it.
+func NewListService(repo models.ListRepository, providers ...SyncProvider) ListService {
+	return &listService{repo: repo, sync: NewSyncService(repo, providers...)}
 }
 
 // CreateList creates a new list
@@ -175,8 +182,8 @@ func (s *listService) List(offset, limit int) ([]*models.List, error) {
 	return lists, nil
 }
 
-// AddListItem adds an item to a list
-func (s *listService) AddListItem(item *models.ListItem) error {
+// AddListItem adds an item to a list on behalf of the user
+func (s *listService) AddListItem(item *models.ListItem, userID uuid.UUID) error {
 	// Validate item
 	if item.ListID == uuid.Nil {
 		return fmt.Errorf("%w: list ID is required", models.ErrInvalidInput)
@@ -191,7 +198,7 @@ func (s *listService) AddListItem(item *models.ListItem) error {
 	}
 
 	// Add item
-	if err := s.repo.AddItem(item); err != nil {
+	if err := s.repo.AddItem(item, &userID); err != nil {
 		return fmt.Errorf("error adding list item: %w", err)
 	}
 
@@ -249,8 +256,29 @@ func (s *listService) GetListItems(listID uuid.UUID) ([]*models.ListItem, error)
 	return items, nil
 }
 
-// GenerateMenu generates a menu from multiple lists based on weights and filters
+// maxMenuSeed keeps generated seeds within the range JSON numbers can hold exactly
+const maxMenuSeed = int64(1) << 53
+
+// GenerateMenu generates a menu from multiple lists by sampling eligible items
+// without replacement, with probability proportional to their weight
 func (s *listService) GenerateMenu(params *models.MenuParams) ([]*models.List, error) {
+	// Seed the RNG so the same params and seed reproduce the same menu
+	if params.Seed == nil {
+		seed := rand.Int63n(maxMenuSeed)
+		params.Seed = &seed
+	}
+	rng := rand.New(rand.NewSource(*params.Seed))
+
+	geo, err := models.GeoFilterFromFilters(params.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(params.ExcludeItems))
+	for _, id := range params.ExcludeItems {
+		excluded[id] = true
+	}
+
 	// Get lists
 	lists := make([]*models.List, 0, len(params.ListIDs))
 	for _, listID := range params.ListIDs {
@@ -269,7 +297,12 @@ func (s *listService) GenerateMenu(params *models.MenuParams) ([]*models.List, e
 		}
 
 		// Apply weights and cooldown
+		candidates := make([]*models.ListItem, 0, len(items))
 		for _, item := range items {
+			if excluded[item.ID] {
+				continue
+			}
+
 			weight := list.DefaultWeight
 			if item.Weight > 0 {
 				weight = item.Weight
@@ -284,27 +317,84 @@ func (s *listService) GenerateMenu(params *models.MenuParams) ([]*models.List, e
 			}
 
 			item.Weight = weight
+
+			// Items without weight can never be drawn
+			if weight > 0 {
+				candidates = append(candidates, item)
+			}
 		}
 
-		// If maxItems is specified in filters, use it, otherwise return all items
-		maxItems := len(items)
-		if max, ok := params.Filters["max_items"].(int); ok && max < maxItems {
-			maxItems = max
+		// Update the list's items
+		list.Items = weightedSample(candidates, menuSize(params, len(candidates)), rng)
+		if geo != nil {
+			sortByDistance(list.Items, geo)
 		}
+	}
 
-		// Sort items by weight and select top maxItems
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Weight > items[j].Weight
-		})
-		if len(items) > maxItems {
-			items = items[:maxItems]
+	return lists, nil
+}
+
+// menuSize returns how many items to draw from a list. The max_items filter
+// takes precedence over Count; with neither set, every candidate is drawn.
+func menuSize(params *models.MenuParams, available int) int {
+	size := available
+	switch max := params.Filters["max_items"].(type) {
+	case int:
+		size = max
+	case float64: // JSON numbers decode as float64
+		size = int(max)
+	default:
+		if params.Count > 0 {
+			size = params.Count
 		}
+	}
+	if size < 0 || size > available {
+		size = available
+	}
+	return size
+}
 
-		// Update the list's items
-		list.Items = items
+// weightedSample draws n items without replacement, each with probability
+// proportional to its weight. It uses the Efraimidis-Spirakis method: every
+// item gets the key u^(1/w) for a uniform u, and the n largest keys win.
+// The returned items are in draw order.
+func weightedSample(items []*models.ListItem, n int, rng *rand.Rand) []*models.ListItem {
+	type keyedItem struct {
+		item *models.ListItem
+		key  float64
 	}
 
-	return lists, nil
+	keyed := make([]keyedItem, len(items))
+	for i, item := range items {
+		keyed[i] = keyedItem{item: item, key: math.Pow(rng.Float64(), 1/item.Weight)}
+	}
+
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return keyed[i].key > keyed[j].key
+	})
+
+	sampled := make([]*models.ListItem, 0, n)
+	for _, k := range keyed[:n] {
+		sampled = append(sampled, k.item)
+	}
+	return sampled
+}
+
+// sortByDistance orders sampled items nearest first, filling in any distance
+// the repository did not compute. Items without coordinates go last.
+func sortByDistance(items []*models.ListItem, geo *models.GeoFilter) {
+	for _, item := range items {
+		if item.DistanceKm == nil {
+			item.DistanceKm = geo.DistanceTo(item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].DistanceKm, items[j].DistanceKm
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a < *b
+	})
 }
 
 // SyncList synchronizes a list with its external source
@@ -320,33 +410,19 @@ func (s *listService) SyncList(listID uuid.UUID) error {
 		return models.ErrInvalidInput
 	}
 
-	// Update sync status to pending
-	err = s.repo.UpdateSyncStatus(listID, models.ListSyncStatusPending)
-	if err != nil {
-		// Handle concurrent modification by retrying once
-		if err == models.ErrConcurrentModification {
-			// Retry with fresh data
-			_, err = s.repo.GetByID(listID)
-			if err != nil {
-				return err
-			}
-
-			// Try again with the updated list
-			err = s.repo.UpdateSyncStatus(listID, models.ListSyncStatusPending)
-			if err != nil {
-				return err
-			}
-		} else {
-			// For any other error, return immediately
-			return err
-		}
+	// Sources without a provider have nothing to pull from
+	if !s.CanSync(list.SyncSource) {
+		return fmt.Errorf("%w: no provider for %s", models.ErrInvalidSyncSource, list.SyncSource)
 	}
 
-	// TODO: Implement external source sync (Google Maps API integration)
-	// This will be implemented in a separate PR
+	_, err = s.sync.SyncList(context.Background(), listID)
+	return err
+}
 
-	// For now, just mark as synced
-	return s.repo.UpdateSyncStatus(listID, models.ListSyncStatusSynced)
+// CanSync reports whether lists from the source have a provider
+func (s *listService) CanSync(source models.SyncSource) bool {
+	_, ok := s.sync.Providers().Get(source)
+	return ok
 }
 
 // GetListConflicts retrieves all unresolved conflicts for a list
@@ -354,11 +430,11 @@ func (s *listService) GetListConflicts(listID uuid.UUID) ([]*models.SyncConflict
 	return s.repo.GetConflicts(listID)
 }
 
-// ResolveListConflict resolves a sync conflict
-func (s *listService) ResolveListConflict(listID, conflictID uuid.UUID, resolution string) error {
-	// The resolution parameter might need to be used to determine how to resolve the conflict
-	// For now, we're just passing conflictID to the repository method
-	return s.repo.ResolveConflict(conflictID)
+// ResolveListConflict resolves a sync conflict on the list with the given
+// strategy. The custom item is only used by the custom strategy, which takes
+// its source-owned fields.
+func (s *listService) ResolveListConflict(listID, conflictID uuid.UUID, resolution string, custom *models.ListItem) error {
+	return s.sync.ResolveListConflict(context.Background(), listID, conflictID, resolution, custom)
 }
 
 // CreateListConflict creates a new sync conflict
@@ -551,7 +627,7 @@ func (s *listService) UnshareListWithTribe(listID, tribeID, userID uuid.UUID) er
 	}
 
 	// Remove the share
-	if err := s.repo.UnshareWithTribe(listID, tribeID); err != nil {
+	if err := s.repo.UnshareWithTribe(listID, tribeID, userID); err != nil {
 		return fmt.Errorf("failed to unshare list from tribe: %w", err)
 	}
 
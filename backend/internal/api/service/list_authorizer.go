@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// ListAccess is the level of access a user has to a list
+type ListAccess int
+
+const (
+	// ListAccessNone hides the list from the user
+	ListAccessNone ListAccess = iota
+	// ListAccessView lets the user read the list and build menus from it
+	ListAccessView
+	// ListAccessEdit lets the user change the list, its items and its sync
+	ListAccessEdit
+)
+
+// ListAuthorizer decides what a user may do with a list. Users who own a
+// list may edit it directly, or through an owning tribe whose membership
+// lets them contribute; other members of an owning tribe may view it.
+// Sharing a list with a tribe makes the tribe an owner. Anyone may view a
+// public list.
+type ListAuthorizer interface {
+	// Authorize returns the list if the user has at least the needed
+	// access. It returns ErrNotFound for missing lists and lists the user
+	// cannot see, and ErrForbidden when the user can see the list but not
+	// change it.
+	Authorize(userID, listID uuid.UUID, need ListAccess) (*models.List, error)
+	// AccessFor returns the user's access to a loaded list
+	AccessFor(userID uuid.UUID, list *models.List) (ListAccess, error)
+}
+
+// listAuthorizer implements the ListAuthorizer interface
+type listAuthorizer struct {
+	lists models.ListRepository
+	authz TribeAuthorizer
+}
+
+// NewListAuthorizer creates a new list authorizer
+func NewListAuthorizer(lists models.ListRepository, authz TribeAuthorizer) ListAuthorizer {
+	return &listAuthorizer{
+		lists: lists,
+		authz: authz,
+	}
+}
+
+// Authorize implements ListAuthorizer
+func (a *listAuthorizer) Authorize(userID, listID uuid.UUID, need ListAccess) (*models.List, error) {
+	list, err := a.lists.GetByID(listID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, fmt.Errorf("%w: list not found", models.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error getting list: %w", err)
+	}
+
+	access, err := a.AccessFor(userID, list)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case access == ListAccessNone:
+		return nil, fmt.Errorf("%w: list not found", models.ErrNotFound)
+	case access < need:
+		return nil, fmt.Errorf("%w: only list owners can do this", models.ErrForbidden)
+	}
+
+	return list, nil
+}
+
+// AccessFor implements ListAuthorizer
+func (a *listAuthorizer) AccessFor(userID uuid.UUID, list *models.List) (ListAccess, error) {
+	if list.OwnerType != nil && *list.OwnerType == models.OwnerTypeUser &&
+		list.OwnerID != nil && *list.OwnerID == userID {
+		return ListAccessEdit, nil
+	}
+
+	owners, err := a.lists.GetOwners(list.ID)
+	if err != nil {
+		return ListAccessNone, fmt.Errorf("error getting list owners: %w", err)
+	}
+
+	var tribeOwners []uuid.UUID
+	for _, owner := range owners {
+		switch owner.OwnerType {
+		case models.OwnerTypeUser:
+			if owner.OwnerID == userID {
+				return ListAccessEdit, nil
+			}
+		case models.OwnerTypeTribe:
+			tribeOwners = append(tribeOwners, owner.OwnerID)
+		}
+	}
+
+	if len(tribeOwners) > 0 {
+		contributing, contributeErr := a.authz.AuthorizedTribes(userID, models.PermissionContribute)
+		if contributeErr != nil {
+			return ListAccessNone, contributeErr
+		}
+		tribes, viewErr := a.authz.AuthorizedTribes(userID, models.PermissionViewTribe)
+		if viewErr != nil {
+			return ListAccessNone, viewErr
+		}
+
+		access := ListAccessNone
+		for _, tribeID := range tribeOwners {
+			if contributing[tribeID] {
+				return ListAccessEdit, nil
+			}
+			if tribes[tribeID] {
+				access = ListAccessView
+			}
+		}
+		if access != ListAccessNone {
+			return access, nil
+		}
+	}
+
+	if list.Visibility == models.VisibilityPublic {
+		return ListAccessView, nil
+	}
+
+	return ListAccessNone, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuthorizer(t *testing.T) {
+	tribeID := uuid.New()
+	owner, member, guest, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	privateID, tribeListID, publicID := uuid.New(), uuid.New(), uuid.New()
+
+	members := []*models.TribeMember{
+		{TribeID: tribeID, UserID: member, MembershipType: models.MembershipFull, Role: models.TribeRoleMember},
+		{TribeID: tribeID, UserID: guest, MembershipType: models.MembershipGuest, Role: models.TribeRoleMember},
+	}
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetUserTribesFunc = func(userID uuid.UUID) ([]*models.Tribe, error) {
+		if findMember(members, userID) == nil {
+			return nil, nil
+		}
+		return []*models.Tribe{{BaseModel: models.BaseModel{ID: tribeID}, Members: members}}, nil
+	}
+
+	userOwner := models.OwnerTypeUser
+	tribeOwner := models.OwnerTypeTribe
+	lists := new(testutil.MockListRepository)
+	lists.On("GetByID", privateID).Return(&models.List{ID: privateID, Visibility: models.VisibilityPrivate, OwnerID: &owner, OwnerType: &userOwner}, nil)
+	lists.On("GetByID", tribeListID).Return(&models.List{ID: tribeListID, Visibility: models.VisibilityPrivate, OwnerID: &tribeID, OwnerType: &tribeOwner}, nil)
+	lists.On("GetByID", publicID).Return(&models.List{ID: publicID, Visibility: models.VisibilityPublic, OwnerID: &owner, OwnerType: &userOwner}, nil)
+	lists.On("GetByID", uuid.Nil).Return(nil, models.ErrNotFound)
+	lists.On("GetOwners", privateID).Return([]*models.ListOwner{{ListID: privateID, OwnerID: owner, OwnerType: models.OwnerTypeUser}}, nil)
+	lists.On("GetOwners", tribeListID).Return([]*models.ListOwner{{ListID: tribeListID, OwnerID: tribeID, OwnerType: models.OwnerTypeTribe}}, nil)
+	lists.On("GetOwners", publicID).Return([]*models.ListOwner{{ListID: publicID, OwnerID: owner, OwnerType: models.OwnerTypeUser}}, nil)
+
+	authz := NewListAuthorizer(lists, NewTribeAuthorizer(tribes))
+
+	t.Run("Owners may edit", func(t *testing.T) {
+		list, err := authz.Authorize(owner, privateID, ListAccessEdit)
+		require.NoError(t, err)
+		assert.Equal(t, privateID, list.ID)
+	})
+
+	t.Run("Tribe members may edit if they can contribute", func(t *testing.T) {
+		_, err := authz.Authorize(member, tribeListID, ListAccessEdit)
+		assert.NoError(t, err)
+
+		_, err = authz.Authorize(guest, tribeListID, ListAccessView)
+		assert.NoError(t, err)
+		_, err = authz.Authorize(guest, tribeListID, ListAccessEdit)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("Public lists are read-only to others", func(t *testing.T) {
+		_, err := authz.Authorize(outsider, publicID, ListAccessView)
+		assert.NoError(t, err)
+		_, err = authz.Authorize(outsider, publicID, ListAccessEdit)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("Hidden and missing lists are not found", func(t *testing.T) {
+		_, err := authz.Authorize(outsider, privateID, ListAccessView)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = authz.Authorize(outsider, tribeListID, ListAccessView)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = authz.Authorize(owner, uuid.Nil, ListAccessView)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("Repository errors are passed on", func(t *testing.T) {
+		failing := new(testutil.MockListRepository)
+		failing.On("GetByID", privateID).Return(nil, errors.New("connection refused"))
+		_, err := NewListAuthorizer(failing, NewTribeAuthorizer(tribes)).Authorize(owner, privateID, ListAccessView)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -22,6 +22,7 @@ func TestAddListItem(t *testing.T) {
 	// Test data
 	listID := uuid.New()
 	itemID := uuid.New()
+	userID := uuid.New()
 	now := time.Now()
 
 	list := &models.List{
@@ -52,7 +53,7 @@ func TestAddListItem(t *testing.T) {
 			item: validItem,
 			mockSetup: func() {
 				mockRepo.On("GetByID", listID).Return(list, nil)
-				mockRepo.On("AddItem", validItem).Return(nil)
+				mockRepo.On("AddItem", validItem, &userID).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -104,7 +105,7 @@ func TestAddListItem(t *testing.T) {
 			item: validItem,
 			mockSetup: func() {
 				mockRepo.On("GetByID", listID).Return(list, nil)
-				mockRepo.On("AddItem", validItem).Return(errors.New("database error"))
+				mockRepo.On("AddItem", validItem, &userID).Return(errors.New("database error"))
 			},
 			wantErr: true,
 			errCheck: func(err error) bool {
@@ -125,7 +126,7 @@ func TestAddListItem(t *testing.T) {
 			}
 
 			// Call the method
-			err := service.AddListItem(tt.item)
+			err := service.AddListItem(tt.item, userID)
 
 			// Check error
 			if tt.wantErr {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/jenglund/rlship-tools/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestListService_BasicCRUD tests the basic CRUD operations of the list service
@@ -90,6 +92,7 @@ func TestListService_ItemManagement(t *testing.T) {
 
 	listID := uuid.New()
 	itemID := uuid.New()
+	userID := uuid.New()
 	item := &models.ListItem{
 		ID:     itemID,
 		ListID: listID,
@@ -99,8 +102,8 @@ func TestListService_ItemManagement(t *testing.T) {
 
 	t.Run("Add Item", func(t *testing.T) {
 		mockRepo.On("GetByID", listID).Return(&models.List{ID: listID}, nil).Once()
-		mockRepo.On("AddItem", item).Return(nil).Once()
-		err := service.AddListItem(item)
+		mockRepo.On("AddItem", item, &userID).Return(nil).Once()
+		err := service.AddListItem(item, userID)
 		assert.NoError(t, err)
 	})
 
@@ -154,6 +157,142 @@ func TestListService_MenuGeneration(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, lists, 1)
 		assert.Equal(t, items, lists[0].Items)
+		require.NotNil(t, params.Seed, "generated seed should be written back to params")
+	})
+}
+
+func TestListService_MenuGenerationSampling(t *testing.T) {
+	listID := uuid.New()
+	lastUsed := time.Now().Add(-24 * time.Hour)
+	cooldownDays := 7
+
+	newItems := func() []*models.ListItem {
+		items := make([]*models.ListItem, 0, 10)
+		for i := 0; i < 10; i++ {
+			items = append(items, &models.ListItem{
+				ID:     uuid.New(),
+				ListID: listID,
+				Name:   fmt.Sprintf("Item %d", i),
+				Weight: float64(i + 1),
+			})
+		}
+		return items
+	}
+
+	generate := func(t *testing.T, list *models.List, items []*models.ListItem, params *models.MenuParams) []*models.ListItem {
+		mockRepo := new(testutil.MockListRepository)
+		defer mockRepo.AssertExpectations(t)
+		mockRepo.On("GetByID", listID).Return(list, nil).Once()
+		mockRepo.On("GetEligibleItems", []uuid.UUID{listID}, params.Filters).Return(items, nil).Once()
+
+		lists, err := NewListService(mockRepo).GenerateMenu(params)
+		require.NoError(t, err)
+		require.Len(t, lists, 1)
+		return lists[0].Items
+	}
+
+	t.Run("Same seed reproduces the same menu", func(t *testing.T) {
+		items := newItems()
+		seed := int64(42)
+
+		first := generate(t, &models.List{ID: listID}, items,
+			&models.MenuParams{ListIDs: []uuid.UUID{listID}, Count: 5, Seed: &seed})
+		second := generate(t, &models.List{ID: listID}, items,
+			&models.MenuParams{ListIDs: []uuid.UUID{listID}, Count: 5, Seed: &seed})
+
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Respects count without duplicates", func(t *testing.T) {
+		seed := int64(7)
+		menu := generate(t, &models.List{ID: listID}, newItems(),
+			&models.MenuParams{ListIDs: []uuid.UUID{listID}, Count: 4, Seed: &seed})
+
+		assert.Len(t, menu, 4)
+		seen := make(map[uuid.UUID]bool)
+		for _, item := range menu {
+			assert.False(t, seen[item.ID], "item %s drawn twice", item.Name)
+			seen[item.ID] = true
+		}
+	})
+
+	t.Run("Max items filter from JSON overrides count", func(t *testing.T) {
+		seed := int64(7)
+		menu := generate(t, &models.List{ID: listID}, newItems(), &models.MenuParams{
+			ListIDs: []uuid.UUID{listID},
+			Count:   8,
+			Filters: map[string]interface{}{"max_items": float64(2)},
+			Seed:    &seed,
+		})
+
+		assert.Len(t, menu, 2)
+	})
+
+	t.Run("Skips excluded, cooled down and zero weight items", func(t *testing.T) {
+		items := newItems()
+		items[0].LastUsed = &lastUsed
+		items[1].Weight = 0
+		seed := int64(1)
+
+		menu := generate(t, &models.List{ID: listID, CooldownDays: &cooldownDays}, items, &models.MenuParams{
+			ListIDs:      []uuid.UUID{listID},
+			ExcludeItems: []uuid.UUID{items[2].ID},
+			Seed:         &seed,
+		})
+
+		assert.Len(t, menu, 7)
+		for _, item := range menu {
+			assert.NotContains(t, []uuid.UUID{items[0].ID, items[1].ID, items[2].ID}, item.ID)
+		}
+	})
+
+	t.Run("Heavier items are drawn first more often", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(99))
+		light := &models.ListItem{ID: uuid.New(), Weight: 1}
+		heavy := &models.ListItem{ID: uuid.New(), Weight: 9}
+
+		heavyFirst := 0
+		const draws = 2000
+		for i := 0; i < draws; i++ {
+			if weightedSample([]*models.ListItem{light, heavy}, 1, rng)[0] == heavy {
+				heavyFirst++
+			}
+		}
+
+		// Expected share is 90%; leave room for sampling noise
+		ratio := float64(heavyFirst) / draws
+		assert.InDelta(t, 0.9, ratio, 0.03)
+	})
+
+	t.Run("Origin sorts the menu nearest first", func(t *testing.T) {
+		lat, lng := 40.0, -74.0
+		far, near, noCoords := 40.3, 40.01, &models.ListItem{ID: uuid.New(), Name: "Nowhere", Weight: 1}
+		items := []*models.ListItem{
+			{ID: uuid.New(), Name: "Far", Weight: 1, Latitude: &far, Longitude: &lng},
+			noCoords,
+			{ID: uuid.New(), Name: "Near", Weight: 1, Latitude: &near, Longitude: &lng},
+		}
+
+		menu := generate(t, &models.List{ID: listID}, items, &models.MenuParams{
+			ListIDs: []uuid.UUID{listID},
+			Filters: map[string]interface{}{models.FilterOriginLatitude: lat, models.FilterOriginLongitude: lng},
+		})
+
+		require.Len(t, menu, 3)
+		assert.Equal(t, "Near", menu[0].Name)
+		assert.Equal(t, "Far", menu[1].Name)
+		assert.Equal(t, "Nowhere", menu[2].Name)
+		require.NotNil(t, menu[0].DistanceKm)
+		assert.InDelta(t, 1.11, *menu[0].DistanceKm, 0.01)
+		assert.Nil(t, menu[2].DistanceKm)
+	})
+
+	t.Run("Invalid origin is rejected before querying", func(t *testing.T) {
+		_, err := NewListService(new(testutil.MockListRepository)).GenerateMenu(&models.MenuParams{
+			ListIDs: []uuid.UUID{listID},
+			Filters: map[string]interface{}{models.FilterRadiusKm: 5.0},
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
 	})
 }
 
@@ -169,12 +308,11 @@ func TestListService_Sync(t *testing.T) {
 		SyncSource: models.SyncSourceGoogleMaps,
 	}
 
-	t.Run("Sync List", func(t *testing.T) {
+	t.Run("Sync List without a provider", func(t *testing.T) {
 		mockRepo.On("GetByID", listID).Return(list, nil).Once()
-		mockRepo.On("UpdateSyncStatus", listID, models.ListSyncStatusPending).Return(nil).Once()
-		mockRepo.On("UpdateSyncStatus", listID, models.ListSyncStatusSynced).Return(nil).Once()
 		err := service.SyncList(listID)
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, models.ErrInvalidSyncSource)
+		assert.False(t, service.CanSync(models.SyncSourceGoogleMaps))
 	})
 }
 
@@ -208,9 +346,25 @@ func TestListService_Conflicts(t *testing.T) {
 	})
 
 	t.Run("Resolve Conflict", func(t *testing.T) {
-		mockRepo.On("ResolveConflict", conflictID).Return(nil).Once()
-		err := service.ResolveListConflict(listID, conflictID, "accept_local")
+		local := &models.ListItem{ID: itemID, ListID: listID, Name: "Local Name", Weight: 1}
+		mockRepo.On("GetConflict", conflictID).Return(conflict, nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{local}, nil).Once()
+		mockRepo.On("ResolveConflict", conflictID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(nil).Once()
+		mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{}, nil).Once()
+		mockRepo.On("GetByID", listID).Return(createValidTestList(listID, models.ListSyncStatusConflict, models.SyncSourceGoogleMaps, "place123"), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusPending
+		})).Return(nil).Once()
+
+		err := service.ResolveListConflict(listID, conflictID, models.ResolutionKeepLocal, nil)
 		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "UpdateItem", mock.Anything)
+	})
+
+	t.Run("Resolve Conflict from another list", func(t *testing.T) {
+		mockRepo.On("GetConflict", conflictID).Return(conflict, nil).Once()
+		err := service.ResolveListConflict(uuid.New(), conflictID, models.ResolutionKeepLocal, nil)
+		assert.ErrorIs(t, err, models.ErrConflictNotFound)
 	})
 }
 
@@ -308,7 +462,7 @@ func TestListService_Sharing(t *testing.T) {
 		mockRepo.On("GetListShares", listID).Return([]*models.ListShare{
 			{ListID: listID, TribeID: tribeID, UserID: userID},
 		}, nil).Once()
-		mockRepo.On("UnshareWithTribe", listID, tribeID).Return(nil).Once()
+		mockRepo.On("UnshareWithTribe", listID, tribeID, userID).Return(nil).Once()
 		err := service.UnshareListWithTribe(listID, tribeID, userID)
 		assert.NoError(t, err)
 	})
@@ -526,8 +680,8 @@ func (m *MockListRepository) List(offset, limit int) ([]*models.List, error) {
 	return args.Get(0).([]*models.List), args.Error(1)
 }
 
-func (m *MockListRepository) AddItem(item *models.ListItem) error {
-	args := m.Called(item)
+func (m *MockListRepository) AddItem(item *models.ListItem, actorID *uuid.UUID) error {
+	args := m.Called(item, actorID)
 	return args.Error(0)
 }
 
@@ -554,6 +708,11 @@ func (m *MockListRepository) UpdateSyncStatus(listID uuid.UUID, status models.Li
 	return args.Error(0)
 }
 
+func (m *MockListRepository) RecordSync(listID uuid.UUID, created, updated, deleted int) error {
+	args := m.Called(listID, created, updated, deleted)
+	return args.Error(0)
+}
+
 func (m *MockListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict, error) {
 	args := m.Called(listID)
 	if args.Get(0) == nil {
@@ -562,8 +721,16 @@ func (m *MockListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConfl
 	return args.Get(0).([]*models.SyncConflict), args.Error(1)
 }
 
-func (m *MockListRepository) ResolveConflict(conflictID uuid.UUID) error {
+func (m *MockListRepository) GetConflict(conflictID uuid.UUID) (*models.SyncConflict, error) {
 	args := m.Called(conflictID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncConflict), args.Error(1)
+}
+
+func (m *MockListRepository) ResolveConflict(conflictID uuid.UUID, resolution string, change *models.ResolvedItem) error {
+	args := m.Called(conflictID, resolution, change)
 	return args.Error(0)
 }
 
@@ -611,8 +778,8 @@ func (m *MockListRepository) ShareWithTribe(share *models.ListShare) error {
 	return args.Error(0)
 }
 
-func (m *MockListRepository) UnshareWithTribe(listID, tribeID uuid.UUID) error {
-	args := m.Called(listID, tribeID)
+func (m *MockListRepository) UnshareWithTribe(listID, tribeID, userID uuid.UUID) error {
+	args := m.Called(listID, tribeID, userID)
 	return args.Error(0)
 }
 
@@ -758,7 +925,7 @@ func TestListService_UnshareListWithTribe(t *testing.T) {
 				mockRepo.On("GetListShares", listID).Return(listShares, nil)
 
 				// Mock UnshareWithTribe to succeed
-				mockRepo.On("UnshareWithTribe", listID, tribeID).Return(nil)
+				mockRepo.On("UnshareWithTribe", listID, tribeID, userID).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -930,7 +1097,7 @@ func TestListService_UnshareListWithTribe(t *testing.T) {
 				mockRepo.On("GetListShares", listID).Return(listShares, nil)
 
 				// Mock UnshareWithTribe to fail
-				mockRepo.On("UnshareWithTribe", listID, tribeID).Return(errInternalServer)
+				mockRepo.On("UnshareWithTribe", listID, tribeID, userID).Return(errInternalServer)
 			},
 			expectedError: errInternalServer,
 		},
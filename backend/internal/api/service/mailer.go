@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MailMessage is a plain text email
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(msg MailMessage) error
+}
+
+// formatMail renders a message as RFC 5322 text. Line breaks are stripped
+// from header values so they cannot inject headers.
+func formatMail(from string, msg MailMessage, now time.Time) []byte {
+	header := func(value string) string {
+		return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", header(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", header(msg.To))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", header(msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	addr   string
+	from   string
+	sender string
+	auth   smtp.Auth
+}
+
+// NewSMTPMailer creates a mailer for the given server. Authentication is
+// skipped when username is empty. The from address may carry a display name,
+// which is shown in the From header but left out of the envelope.
+func NewSMTPMailer(host string, port int, username, password, from string) (*SMTPMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", from, err)
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr:   fmt.Sprintf("%s:%d", host, port),
+		from:   from,
+		sender: sender.Address,
+		auth:   auth,
+	}, nil
+}
+
+// Send implements Mailer
+func (m *SMTPMailer) Send(msg MailMessage) error {
+	if err := smtp.SendMail(m.addr, m.auth, m.sender, []string{msg.To}, formatMail(m.from, msg, time.Now())); err != nil {
+		return fmt.Errorf("error sending mail: %w", err)
+	}
+	return nil
+}
+
+// FileMailer writes each message to its own .eml file in a directory, for
+// development setups without a mail server
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a mailer writing to dir, creating it if needed
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send implements Mailer
+func (m *FileMailer) Send(msg MailMessage) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), uuid.New())
+	if err := os.WriteFile(filepath.Join(m.dir, name), formatMail(m.from, msg, now), 0o600); err != nil {
+		return fmt.Errorf("error writing mail: %w", err)
+	}
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory, for tests
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []MailMessage
+}
+
+// NewMemoryMailer creates an empty in-memory mailer
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send implements Mailer
+func (m *MemoryMailer) Send(msg MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (m *MemoryMailer) Messages() []MailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MailMessage(nil), m.messages...)
+}
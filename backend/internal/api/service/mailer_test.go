@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "Tribe <noreply@example.com>")
+	require.NoError(t, err)
+
+	require.NoError(t, mailer.Send(MailMessage{To: "bob@example.com", Subject: "Hi", Body: "Hello"}))
+	require.NoError(t, mailer.Send(MailMessage{To: "carol@example.com", Subject: "Hi", Body: "Hello"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "From: Tribe <noreply@example.com>\r\n")
+	assert.Contains(t, string(data), "Subject: Hi\r\n")
+}
+
+// fakeSMTPServer accepts one message on loopback and records the commands it
+// was sent
+func fakeSMTPServer(t *testing.T) (string, int, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer conn.Close()
+
+		var received []string
+		defer func() { commands <- received }()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ready")
+		for {
+			line, readErr := reader.ReadString('\n')
+			if readErr != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			received = append(received, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case line == "DATA":
+				reply("354 go ahead")
+				for {
+					dataLine, dataErr := reader.ReadString('\n')
+					if dataErr != nil || dataLine == ".\r\n" {
+						break
+					}
+					received = append(received, strings.TrimRight(dataLine, "\r\n"))
+				}
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return host, portNumber, commands
+}
+
+func TestSMTPMailer(t *testing.T) {
+	_, err := NewSMTPMailer("localhost", 25, "", "", "not an address")
+	assert.Error(t, err)
+
+	host, port, commands := fakeSMTPServer(t)
+	mailer, err := NewSMTPMailer(host, port, "", "", "Tribe <noreply@example.com>")
+	require.NoError(t, err)
+	require.NoError(t, mailer.Send(MailMessage{To: "bob@example.com", Subject: "Hi", Body: "Hello"}))
+
+	// The envelope carries the bare address; the header keeps the display name
+	received := <-commands
+	assert.Contains(t, received, "MAIL FROM:<noreply@example.com>")
+	assert.Contains(t, received, "RCPT TO:<bob@example.com>")
+	assert.Contains(t, received, "From: Tribe <noreply@example.com>")
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+	assert.Empty(t, mailer.Messages())
+
+	msg := MailMessage{To: "bob@example.com", Subject: "Hi", Body: "Hello"}
+	require.NoError(t, mailer.Send(msg))
+
+	messages := mailer.Messages()
+	assert.Equal(t, []MailMessage{msg}, messages)
+
+	messages[0].To = "changed@example.com"
+	assert.Equal(t, "bob@example.com", mailer.Messages()[0].To)
+}
+
+func TestFormatMail(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	raw := string(formatMail("Tribe <noreply@example.com>", MailMessage{
+		To:      "bob@example.com\r\nBcc: eve@example.com",
+		Subject: "Hello\nthere",
+		Body:    "line one\nline two\r\n",
+	}, now))
+
+	headers, body, found := strings.Cut(raw, "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, "To: bob@example.comBcc: eve@example.com\r\n")
+	assert.NotContains(t, headers, "\r\nBcc:")
+	assert.Contains(t, headers, "Subject: Hellothere\r\n")
+	assert.Contains(t, headers, "Date: Fri, 01 Mar 2024 12:00:00 +0000")
+	assert.Equal(t, "line one\r\nline two\r\n", body)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// MenuService defines the interface for persisted menu sessions
+type MenuService interface {
+	// CreateSession generates a menu and records it as a session
+	CreateSession(userID uuid.UUID, tribeID *uuid.UUID, params *models.MenuParams) (*models.MenuSession, error)
+	GetSession(userID, sessionID uuid.UUID) (*models.MenuSession, error)
+	// PickItem records the item picked from a session and updates its stats
+	PickItem(userID, sessionID, itemID uuid.UUID) (*models.MenuSession, error)
+	GetUserSessions(userID uuid.UUID, offset, limit int) ([]*models.MenuSession, error)
+	GetTribeSessions(userID, tribeID uuid.UUID, offset, limit int) ([]*models.MenuSession, error)
+}
+
+// menuService implements the MenuService interface
+type menuService struct {
+	lists    ListService
+	access   ListAuthorizer
+	sessions models.MenuSessionRepository
+	authz    TribeAuthorizer
+}
+
+// NewMenuService creates a new menu service
+func NewMenuService(lists ListService, access ListAuthorizer, sessions models.MenuSessionRepository, authz TribeAuthorizer) MenuService {
+	return &menuService{
+		lists:    lists,
+		access:   access,
+		sessions: sessions,
+		authz:    authz,
+	}
+}
+
+// CreateSession generates a menu and records the offered items
+func (s *menuService) CreateSession(userID uuid.UUID, tribeID *uuid.UUID, params *models.MenuParams) (*models.MenuSession, error) {
+	if len(params.ListIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one list ID is required", models.ErrInvalidInput)
+	}
+
+	if tribeID != nil {
+		if _, err := s.authz.Authorize(userID, *tribeID, models.PermissionContribute); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := authorizeMenuLists(s.access, userID, params.ListIDs); err != nil {
+		return nil, err
+	}
+
+	lists, err := s.lists.GenerateMenu(params)
+	if err != nil {
+		return nil, err
+	}
+
+	session := newMenuSession(userID, tribeID, params, lists)
+
+	if err := s.sessions.Create(session); err != nil {
+		return nil, fmt.Errorf("error saving menu session: %w", err)
+	}
+
+	return session, nil
+}
+
+// authorizeMenuLists checks the user can view every list a menu is drawn
+// from, so that a menu cannot be used to read someone else's list
+func authorizeMenuLists(access ListAuthorizer, userID uuid.UUID, listIDs []uuid.UUID) error {
+	for _, listID := range listIDs {
+		if _, err := access.Authorize(userID, listID, ListAccessView); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newMenuSession snapshots the items of a generated menu into a session
+func newMenuSession(userID uuid.UUID, tribeID *uuid.UUID, params *models.MenuParams, lists []*models.List) *models.MenuSession {
+	items := make(models.MenuSessionItems, 0)
+	for _, list := range lists {
+		for _, item := range list.Items {
+			items = append(items, models.MenuSessionItem{
+				ItemID: item.ID,
+				ListID: list.ID,
+				Name:   item.Name,
+				Weight: item.Weight,
+			})
+		}
+	}
+
+	return &models.MenuSession{
+		UserID:  userID,
+		TribeID: tribeID,
+		Params:  *params,
+		Items:   items,
+		Lists:   lists,
+	}
+}
+
+// GetSession retrieves a session visible to the user
+func (s *menuService) GetSession(userID, sessionID uuid.UUID) (*models.MenuSession, error) {
+	session, err := s.sessions.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireSessionAccess(session, userID); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// PickItem records the picked item and marks it chosen on its list
+func (s *menuService) PickItem(userID, sessionID, itemID uuid.UUID) (*models.MenuSession, error) {
+	session, err := s.GetSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsClosed() {
+		return nil, fmt.Errorf("%w: an item has already been picked for this menu", models.ErrConflict)
+	}
+	if session.VotingRule != nil {
+		return nil, fmt.Errorf("%w: this menu is settled by a tribe vote", models.ErrConflict)
+	}
+	if !session.Items.Contains(itemID) {
+		return nil, fmt.Errorf("%w: item was not offered in this menu", models.ErrInvalidInput)
+	}
+	// Picking for a tribe takes a membership that may contribute to it
+	if session.TribeID != nil {
+		if _, err := s.authz.Authorize(userID, *session.TribeID, models.PermissionContribute); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.sessions.SetChosenItem(sessionID, itemID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.sessions.GetByID(sessionID)
+}
+
+// GetUserSessions retrieves the sessions a user generated
+func (s *menuService) GetUserSessions(userID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	return s.sessions.GetUserSessions(userID, offset, limit)
+}
+
+// GetTribeSessions retrieves a tribe's sessions if the user belongs to it
+func (s *menuService) GetTribeSessions(userID, tribeID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	if _, err := s.authz.Authorize(userID, tribeID, models.PermissionViewTribe); err != nil {
+		return nil, err
+	}
+	return s.sessions.GetTribeSessions(tribeID, offset, limit)
+}
+
+// requireSessionAccess allows the session's creator and members of its tribe
+func (s *menuService) requireSessionAccess(session *models.MenuSession, userID uuid.UUID) error {
+	if session.UserID == userID {
+		return nil
+	}
+	if session.TribeID == nil {
+		return models.ErrForbidden
+	}
+	_, err := s.authz.Authorize(userID, *session.TribeID, models.PermissionViewTribe)
+	return err
+}
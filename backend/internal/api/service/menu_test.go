@@ -0,0 +1,180 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMenuService(members []*models.TribeMember) (MenuService, *testutil.MockListRepository, *testutil.MockMenuSessionRepository) {
+	listRepo := new(testutil.MockListRepository)
+	sessionRepo := new(testutil.MockMenuSessionRepository)
+	tribeRepo := testutil.NewMockTribeRepository()
+	tribeRepo.GetByIDFunc = func(tribeID uuid.UUID) (*models.Tribe, error) {
+		return &models.Tribe{BaseModel: models.BaseModel{ID: tribeID}, Members: members}, nil
+	}
+
+	authz := NewTribeAuthorizer(tribeRepo)
+	access := NewListAuthorizer(listRepo, authz)
+	return NewMenuService(NewListService(listRepo), access, sessionRepo, authz), listRepo, sessionRepo
+}
+
+// userList returns a private list owned by the user
+func userList(listID, userID uuid.UUID) *models.List {
+	ownerType := models.OwnerTypeUser
+	return &models.List{ID: listID, Visibility: models.VisibilityPrivate, OwnerID: &userID, OwnerType: &ownerType}
+}
+
+func TestMenuService_CreateSession(t *testing.T) {
+	userID := uuid.New()
+	tribeID := uuid.New()
+	listID := uuid.New()
+	seed := int64(3)
+	items := []*models.ListItem{
+		{ID: uuid.New(), ListID: listID, Name: "Tacos", Weight: 1},
+		{ID: uuid.New(), ListID: listID, Name: "Ramen", Weight: 2},
+	}
+
+	t.Run("Records offered items", func(t *testing.T) {
+		service, listRepo, sessionRepo := newTestMenuService([]*models.TribeMember{
+			{UserID: userID, MembershipType: models.MembershipFull},
+		})
+		defer listRepo.AssertExpectations(t)
+		defer sessionRepo.AssertExpectations(t)
+
+		params := &models.MenuParams{ListIDs: []uuid.UUID{listID}, Seed: &seed}
+		listRepo.On("GetByID", listID).Return(userList(listID, userID), nil).Twice()
+		listRepo.On("GetEligibleItems", []uuid.UUID{listID}, params.Filters).Return(items, nil).Once()
+		sessionRepo.On("Create", mock.AnythingOfType("*models.MenuSession")).Return(nil).Once()
+
+		session, err := service.CreateSession(userID, &tribeID, params)
+		require.NoError(t, err)
+		assert.Equal(t, userID, session.UserID)
+		assert.Equal(t, &tribeID, session.TribeID)
+		assert.Len(t, session.Items, 2)
+		assert.True(t, session.Items.Contains(items[0].ID))
+		assert.True(t, session.Items.Contains(items[1].ID))
+		assert.Equal(t, seed, *session.Params.Seed)
+	})
+
+	t.Run("Rejects non-members", func(t *testing.T) {
+		service, _, _ := newTestMenuService([]*models.TribeMember{
+			{UserID: userID, MembershipType: models.MembershipPending},
+		})
+
+		_, err := service.CreateSession(userID, &tribeID, &models.MenuParams{ListIDs: []uuid.UUID{listID}})
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("Rejects guests", func(t *testing.T) {
+		service, _, _ := newTestMenuService([]*models.TribeMember{
+			{UserID: userID, MembershipType: models.MembershipGuest},
+		})
+
+		_, err := service.CreateSession(userID, &tribeID, &models.MenuParams{ListIDs: []uuid.UUID{listID}})
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+	})
+
+	t.Run("Rejects lists the user cannot see", func(t *testing.T) {
+		service, listRepo, sessionRepo := newTestMenuService(nil)
+		defer listRepo.AssertExpectations(t)
+		defer sessionRepo.AssertExpectations(t)
+
+		other := uuid.New()
+		listRepo.On("GetByID", listID).Return(userList(listID, other), nil).Once()
+		listRepo.On("GetOwners", listID).Return([]*models.ListOwner{
+			{ListID: listID, OwnerID: other, OwnerType: models.OwnerTypeUser},
+		}, nil).Once()
+
+		_, err := service.CreateSession(userID, nil, &models.MenuParams{ListIDs: []uuid.UUID{listID}})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("Requires lists", func(t *testing.T) {
+		service, _, _ := newTestMenuService(nil)
+
+		_, err := service.CreateSession(userID, nil, &models.MenuParams{})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
+
+func TestMenuService_PickItem(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	itemID := uuid.New()
+
+	newSession := func() *models.MenuSession {
+		session := &models.MenuSession{
+			UserID: userID,
+			Params: models.MenuParams{ListIDs: []uuid.UUID{uuid.New()}},
+			Items:  models.MenuSessionItems{{ItemID: itemID, Name: "Tacos"}},
+		}
+		session.ID = sessionID
+		return session
+	}
+
+	t.Run("Marks the item chosen", func(t *testing.T) {
+		service, listRepo, sessionRepo := newTestMenuService(nil)
+		defer listRepo.AssertExpectations(t)
+		defer sessionRepo.AssertExpectations(t)
+
+		picked := newSession()
+		picked.ChosenItemID = &itemID
+
+		sessionRepo.On("GetByID", sessionID).Return(newSession(), nil).Once()
+		sessionRepo.On("SetChosenItem", sessionID, itemID, userID).Return(nil).Once()
+		sessionRepo.On("GetByID", sessionID).Return(picked, nil).Once()
+
+		session, err := service.PickItem(userID, sessionID, itemID)
+		require.NoError(t, err)
+		assert.Equal(t, &itemID, session.ChosenItemID)
+	})
+
+	t.Run("Rejects items that were not offered", func(t *testing.T) {
+		service, _, sessionRepo := newTestMenuService(nil)
+		sessionRepo.On("GetByID", sessionID).Return(newSession(), nil).Once()
+
+		_, err := service.PickItem(userID, sessionID, uuid.New())
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("Rejects a second pick", func(t *testing.T) {
+		service, _, sessionRepo := newTestMenuService(nil)
+		picked := newSession()
+		picked.ChosenItemID = &itemID
+		sessionRepo.On("GetByID", sessionID).Return(picked, nil).Once()
+
+		_, err := service.PickItem(userID, sessionID, itemID)
+		assert.ErrorIs(t, err, models.ErrConflict)
+	})
+
+	t.Run("Rejects other users' personal sessions", func(t *testing.T) {
+		service, _, sessionRepo := newTestMenuService(nil)
+		sessionRepo.On("GetByID", sessionID).Return(newSession(), nil).Once()
+
+		_, err := service.PickItem(uuid.New(), sessionID, itemID)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("Lets guests see but not pick from tribe sessions", func(t *testing.T) {
+		guestID := uuid.New()
+		tribeID := uuid.New()
+		service, _, sessionRepo := newTestMenuService([]*models.TribeMember{
+			{UserID: guestID, MembershipType: models.MembershipGuest},
+		})
+		tribeSession := newSession()
+		tribeSession.TribeID = &tribeID
+		sessionRepo.On("GetByID", sessionID).Return(tribeSession, nil).Twice()
+
+		_, err := service.GetSession(guestID, sessionID)
+		require.NoError(t, err)
+
+		_, err = service.PickItem(guestID, sessionID, itemID)
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+	})
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// DefaultMaxPhotoSize is the upload limit used when none is configured
+	DefaultMaxPhotoSize = 10 << 20
+	// DefaultPhotoURLTTL is how long signed photo URLs last when not configured
+	DefaultPhotoURLTTL = time.Hour
+)
+
+// photoExtensions maps the accepted photo content types to file extensions
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// PhotoService defines the interface for activity photo uploads
+type PhotoService interface {
+	// Upload validates and stores a photo, returning it with a signed URL
+	Upload(ctx context.Context, activityID uuid.UUID, data io.Reader, caption string) (*models.ActivityPhoto, error)
+	// ListPhotos returns an activity's photos with signed URLs
+	ListPhotos(activityID uuid.UUID) ([]*models.ActivityPhoto, error)
+	// DeletePhoto removes a photo and its stored file
+	DeletePhoto(ctx context.Context, activityID, photoID uuid.UUID) error
+	// OpenSigned opens a stored file after checking its signed URL values
+	OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error)
+	// MaxSize returns the largest photo accepted, in bytes
+	MaxSize() int64
+}
+
+// photoService implements the PhotoService interface
+type photoService struct {
+	photos  models.ActivityPhotosRepository
+	store   BlobStore
+	maxSize int64
+	urlTTL  time.Duration
+}
+
+// NewPhotoService creates a new photo service. A non-positive maxSize or
+// urlTTL falls back to the defaults.
+func NewPhotoService(photos models.ActivityPhotosRepository, store BlobStore, maxSize int64, urlTTL time.Duration) PhotoService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPhotoSize
+	}
+	if urlTTL <= 0 {
+		urlTTL = DefaultPhotoURLTTL
+	}
+	return &photoService{
+		photos:  photos,
+		store:   store,
+		maxSize: maxSize,
+		urlTTL:  urlTTL,
+	}
+}
+
+// MaxSize implements PhotoService
+func (s *photoService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload reads the photo, checks its size and sniffed content type, strips
+// GPS data and stores it under a key derived from the activity and photo IDs
+func (s *photoService) Upload(ctx context.Context, activityID uuid.UUID, data io.Reader, caption string) (*models.ActivityPhoto, error) {
+	content, err := io.ReadAll(io.LimitReader(data, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading photo: %w", err)
+	}
+	if int64(len(content)) > s.maxSize {
+		return nil, fmt.Errorf("%w: photos may be at most %d bytes", models.ErrFileTooLarge, s.maxSize)
+	}
+
+	contentType := http.DetectContentType(content)
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedMediaType, contentType)
+	}
+
+	content, err = stripGPS(content, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &models.ActivityPhoto{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		ActivityID:  activityID,
+		Caption:     caption,
+		Metadata:    models.JSONMap{},
+		ContentType: contentType,
+		SizeBytes:   int64(len(content)),
+	}
+	photo.StorageKey = fmt.Sprintf("activities/%s/%s%s", activityID, photo.ID, ext)
+
+	if err = s.store.Put(ctx, photo.StorageKey, bytes.NewReader(content), contentType); err != nil {
+		return nil, fmt.Errorf("error storing photo: %w", err)
+	}
+	if err = s.photos.Create(photo); err != nil {
+		if deleteErr := s.store.Delete(ctx, photo.StorageKey); deleteErr != nil {
+			log.Printf("Error removing photo %s after failed save: %v", photo.StorageKey, deleteErr)
+		}
+		return nil, fmt.Errorf("error saving photo: %w", err)
+	}
+
+	if err = s.signURL(photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// ListPhotos implements PhotoService
+func (s *photoService) ListPhotos(activityID uuid.UUID) ([]*models.ActivityPhoto, error) {
+	photos, err := s.photos.ListByActivity(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing photos: %w", err)
+	}
+	for _, photo := range photos {
+		if err := s.signURL(photo); err != nil {
+			return nil, err
+		}
+	}
+	return photos, nil
+}
+
+// DeletePhoto removes a photo of the activity. Photos of other activities
+// are reported as not found.
+func (s *photoService) DeletePhoto(ctx context.Context, activityID, photoID uuid.UUID) error {
+	photo, err := s.photos.GetByID(photoID)
+	if err != nil {
+		return err
+	}
+	if photo.ActivityID != activityID {
+		return models.ErrNotFound
+	}
+
+	if err := s.photos.Delete(photoID); err != nil {
+		return err
+	}
+	keys := []string{}
+	if photo.StorageKey != "" {
+		keys = append(keys, photo.StorageKey)
+	}
+	for _, variant := range photo.StoredVariants() {
+		if variant.Key != photo.StorageKey {
+			keys = append(keys, variant.Key)
+		}
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			return fmt.Errorf("error deleting stored photo: %w", err)
+		}
+	}
+	return nil
+}
+
+// OpenSigned implements PhotoService. Stores that serve their own signed
+// URLs never route requests here, so they are refused.
+func (s *photoService) OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+	verifier, ok := s.store.(SignatureVerifier)
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, err
+	}
+
+	blob, err := s.store.Get(ctx, key)
+	if errors.Is(err, models.ErrInvalidInput) {
+		return nil, models.ErrNotFound
+	}
+	return blob, err
+}
+
+// signURL replaces the URL of an uploaded photo with a fresh signed URL and
+// signs any generated variants. Photos that only have an external URL keep it.
+func (s *photoService) signURL(photo *models.ActivityPhoto) error {
+	if photo.StorageKey != "" {
+		signed, err := s.store.SignedURL(photo.StorageKey, s.urlTTL)
+		if err != nil {
+			return fmt.Errorf("error signing photo URL: %w", err)
+		}
+		photo.URL = signed
+	}
+
+	variants := photo.StoredVariants()
+	for name, variant := range variants {
+		signed, err := s.store.SignedURL(variant.Key, s.urlTTL)
+		if err != nil {
+			return fmt.Errorf("error signing %s variant URL: %w", name, err)
+		}
+		variant.URL = signed
+		variant.Key = ""
+		variants[name] = variant
+	}
+	photo.Variants = variants
+	return nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// exifGPSInfoTag is the IFD0 tag pointing at the GPS IFD
+	exifGPSInfoTag = 0x8825
+	// exifOrientationTag is the IFD0 tag saying how to rotate or flip the
+	// image for display
+	exifOrientationTag = 0x0112
+	// tiffEntrySize is the size of one IFD entry
+	tiffEntrySize = 12
+)
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	// xmpHeaders start the APP1 segments holding XMP packets, which can
+	// repeat the EXIF GPS properties
+	xmpHeaders = [][]byte{
+		[]byte("http://ns.adobe.com/xap/1.0/\x00"),
+		[]byte("http://ns.adobe.com/xmp/extension/\x00"),
+	}
+	// pngXMPKeyword starts the iTXt chunk holding a PNG's XMP packet
+	pngXMPKeyword = []byte("XML:com.adobe.xmp\x00")
+)
+
+// tiffTypeSizes maps TIFF field types to the size of one value
+var tiffTypeSizes = map[uint16]uint32{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// stripGPS removes location data from an image. In a JPEG the GPS IFD of the
+// EXIF block is emptied in place, keeping the rest of the EXIF data such as
+// the orientation; an EXIF block that cannot be parsed is dropped entirely.
+// XMP packets, which can repeat the location, are dropped from both JPEGs
+// and PNGs, as are PNG eXIf chunks. Other content types are returned
+// unchanged.
+func stripGPS(data []byte, contentType string) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEGGPS(data)
+	case "image/png":
+		return stripPNGExif(data)
+	}
+	return data, nil
+}
+
+func stripJPEGGPS(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("%w: not a JPEG image", models.ErrInvalidInput)
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xFF || pos+1 >= len(data) {
+			return nil, fmt.Errorf("%w: malformed JPEG segment", models.ErrInvalidInput)
+		}
+		marker := data[pos+1]
+
+		switch {
+		case marker == 0xFF:
+			// Fill byte before a marker
+			out = append(out, data[pos])
+			pos++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			// Entropy-coded image data follows; no more metadata segments
+			return append(out, data[pos:]...), nil
+		}
+
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("%w: truncated JPEG segment", models.ErrInvalidInput)
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+		if end > len(data) || end < pos+4 {
+			return nil, fmt.Errorf("%w: truncated JPEG segment", models.ErrInvalidInput)
+		}
+
+		segment := append([]byte(nil), data[pos:end]...)
+		payload := segment[4:]
+		if marker == 0xE1 && isXMP(payload) {
+			pos = end
+			continue
+		}
+		if marker == 0xE1 && bytes.HasPrefix(payload, exifHeader) {
+			if !clearTIFFGPS(payload[len(exifHeader):]) {
+				pos = end
+				continue
+			}
+		}
+		out = append(out, segment...)
+		pos = end
+	}
+	return out, nil
+}
+
+// isXMP reports whether an APP1 payload is an XMP packet
+func isXMP(payload []byte) bool {
+	for _, header := range xmpHeaders {
+		if bytes.HasPrefix(payload, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, from 1 to 8. Images
+// without a readable orientation are upright, which is 1.
+func jpegOrientation(data []byte) int {
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		switch {
+		case marker == 0xFF:
+			pos++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			pos += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			return 1
+		}
+
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+		if end > len(data) || end < pos+4 {
+			return 1
+		}
+		payload := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(payload, exifHeader) {
+			return tiffOrientation(payload[len(exifHeader):])
+		}
+		pos = end
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation from IFD0 of a TIFF structure
+func tiffOrientation(tiff []byte) int {
+	order, ifd0, entries, ok := tiffIFD0(tiff)
+	if !ok {
+		return 1
+	}
+	for i := uint32(0); i < entries; i++ {
+		entry := tiff[ifd0+2+i*tiffEntrySize:]
+		if order.Uint16(entry[0:2]) != exifOrientationTag || order.Uint16(entry[2:4]) != 3 {
+			continue
+		}
+		if orientation := int(order.Uint16(entry[8:10])); orientation >= 1 && orientation <= 8 {
+			return orientation
+		}
+	}
+	return 1
+}
+
+// tiffIFD0 reads the byte order of a TIFF structure and locates its first
+// IFD, returning false if the header or IFD cannot be parsed
+func tiffIFD0(tiff []byte) (binary.ByteOrder, uint32, uint32, bool) {
+	if len(tiff) < 8 {
+		return nil, 0, 0, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, 0, 0, false
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return nil, 0, 0, false
+	}
+
+	ifd0 := order.Uint32(tiff[4:8])
+	entries, ok := tiffEntries(tiff, ifd0, order)
+	return order, ifd0, entries, ok
+}
+
+// clearTIFFGPS empties the GPS IFD of a TIFF structure in place, zeroing the
+// values it points at. It returns false if the structure cannot be parsed.
+func clearTIFFGPS(tiff []byte) bool {
+	order, ifd0, entries, ok := tiffIFD0(tiff)
+	if !ok {
+		return false
+	}
+
+	for i := uint32(0); i < entries; i++ {
+		entry := tiff[ifd0+2+i*tiffEntrySize:]
+		if order.Uint16(entry[0:2]) != exifGPSInfoTag {
+			continue
+		}
+
+		gps := order.Uint32(entry[8:12])
+		gpsEntries, gpsOK := tiffEntries(tiff, gps, order)
+		if !gpsOK {
+			return false
+		}
+		for j := uint32(0); j < gpsEntries; j++ {
+			field := tiff[gps+2+j*tiffEntrySize:]
+			size := uint64(tiffTypeSizes[order.Uint16(field[2:4])]) * uint64(order.Uint32(field[4:8]))
+			if size <= 4 {
+				continue
+			}
+			offset := uint64(order.Uint32(field[8:12]))
+			if offset+size > uint64(len(tiff)) {
+				return false
+			}
+			clear(tiff[offset : offset+size])
+		}
+		// An IFD with no entries and no next IFD
+		clear(tiff[gps : gps+2+gpsEntries*tiffEntrySize+4])
+	}
+	return true
+}
+
+// tiffEntries returns the entry count of the IFD at offset, checking the IFD
+// and its next-IFD pointer fit in the data
+func tiffEntries(tiff []byte, offset uint32, order binary.ByteOrder) (uint32, bool) {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return 0, false
+	}
+	entries := uint32(order.Uint16(tiff[offset : offset+2]))
+	if uint64(offset)+2+uint64(entries)*tiffEntrySize+4 > uint64(len(tiff)) {
+		return 0, false
+	}
+	return entries, true
+}
+
+func stripPNGExif(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("%w: not a PNG image", models.ErrInvalidInput)
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", models.ErrInvalidInput)
+		}
+		// Length, type, data and CRC
+		end := uint64(pos) + 12 + uint64(binary.BigEndian.Uint32(data[pos:pos+4]))
+		if end > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", models.ErrInvalidInput)
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		xmp := chunkType == "iTXt" && bytes.HasPrefix(data[pos+8:end], pngXMPKeyword)
+		if chunkType != "eXIf" && !xmp {
+			out = append(out, data[pos:end]...)
+		}
+		pos = int(end)
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	return out, nil
+}
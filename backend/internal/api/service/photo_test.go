@@ -0,0 +1,367 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePhotoRepository keeps photos in memory
+type fakePhotoRepository struct {
+	photos    map[uuid.UUID]*models.ActivityPhoto
+	createErr error
+}
+
+func newFakePhotoRepository() *fakePhotoRepository {
+	return &fakePhotoRepository{photos: make(map[uuid.UUID]*models.ActivityPhoto)}
+}
+
+func (r *fakePhotoRepository) Create(photo *models.ActivityPhoto) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	stored := *photo
+	r.photos[photo.ID] = &stored
+	return nil
+}
+
+func (r *fakePhotoRepository) GetByID(id uuid.UUID) (*models.ActivityPhoto, error) {
+	photo, ok := r.photos[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *photo
+	return &copied, nil
+}
+
+func (r *fakePhotoRepository) ListByActivity(activityID uuid.UUID) ([]*models.ActivityPhoto, error) {
+	var photos []*models.ActivityPhoto
+	for _, photo := range r.photos {
+		if photo.ActivityID == activityID {
+			copied := *photo
+			photos = append(photos, &copied)
+		}
+	}
+	return photos, nil
+}
+
+func (r *fakePhotoRepository) ListMissingVariants(maxAttempts, limit int) ([]*models.ActivityPhoto, error) {
+	var photos []*models.ActivityPhoto
+	for _, photo := range r.photos {
+		if !photo.VariantsReady() && photo.VariantAttempts() < maxAttempts && len(photos) < limit {
+			copied := *photo
+			photos = append(photos, &copied)
+		}
+	}
+	return photos, nil
+}
+
+func (r *fakePhotoRepository) Update(photo *models.ActivityPhoto) error {
+	stored := *photo
+	r.photos[photo.ID] = &stored
+	return nil
+}
+
+func (r *fakePhotoRepository) Delete(id uuid.UUID) error {
+	if _, ok := r.photos[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(r.photos, id)
+	return nil
+}
+
+// gpsLatitude is the rational GPS value written into test EXIF data
+var gpsLatitude = []uint32{40, 1, 42, 1, 3046, 100}
+
+// testImage returns a small solid image
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	return img
+}
+
+// exifWithGPS builds a little-endian TIFF block whose IFD0 points at a GPS
+// IFD holding one latitude entry
+func exifWithGPS() []byte {
+	order := binary.LittleEndian
+	tiff := make([]byte, 68)
+	copy(tiff, "II")
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	// IFD0: one entry pointing at the GPS IFD
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifGPSInfoTag)
+	order.PutUint16(tiff[12:], 4)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint32(tiff[18:], 26)
+
+	// GPS IFD: latitude as three rationals stored at offset 44
+	order.PutUint16(tiff[26:], 1)
+	order.PutUint16(tiff[28:], 2)
+	order.PutUint16(tiff[30:], 5)
+	order.PutUint32(tiff[32:], 3)
+	order.PutUint32(tiff[36:], 44)
+	for i, v := range gpsLatitude {
+		order.PutUint32(tiff[44+4*i:], v)
+	}
+	return append(append([]byte(nil), exifHeader...), tiff...)
+}
+
+// xmpWithGPS is an XMP packet carrying a location, as phones and photo
+// editors write it
+const xmpWithGPS = `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+	`<rdf:Description xmlns:exif="http://ns.adobe.com/exif/1.0/" exif:GPSLatitude="41,52.8N" exif:GPSLongitude="87,37.8W"/>` +
+	`</rdf:RDF></x:xmpmeta>`
+
+func jpegWithGPS(t *testing.T) []byte {
+	return jpegWithAPP1(t, exifWithGPS())
+}
+
+// jpegWithAPP1 encodes the test image with an APP1 segment holding payload
+func jpegWithAPP1(t *testing.T, payload []byte) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(), nil))
+	return insertAPP1(buf.Bytes(), payload)
+}
+
+// insertAPP1 adds an APP1 segment right after the SOI marker of a JPEG
+func insertAPP1(encoded, payload []byte) []byte {
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte(nil), encoded[:2]...)
+	out = append(out, segment...)
+	return append(out, encoded[2:]...)
+}
+
+func pngWithExif(t *testing.T) []byte {
+	return pngWithChunk(t, "eXIf", exifWithGPS()[len(exifHeader):])
+}
+
+// pngWithChunk encodes the test image with an extra chunk before IEND
+func pngWithChunk(t *testing.T, chunkType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage()))
+	encoded := buf.Bytes()
+
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	chunk = append(chunk, 0, 0, 0, 0)
+
+	// Insert before the 12-byte IEND chunk
+	iend := len(encoded) - 12
+	out := append([]byte(nil), encoded[:iend]...)
+	out = append(out, chunk...)
+	return append(out, encoded[iend:]...)
+}
+
+func gpsBytes() []byte {
+	raw := make([]byte, 4*len(gpsLatitude))
+	for i, v := range gpsLatitude {
+		binary.LittleEndian.PutUint32(raw[4*i:], v)
+	}
+	return raw
+}
+
+func TestStripGPS(t *testing.T) {
+	t.Run("JPEG keeps EXIF without GPS", func(t *testing.T) {
+		original := jpegWithGPS(t)
+		require.True(t, bytes.Contains(original, gpsBytes()))
+
+		stripped, err := stripGPS(original, "image/jpeg")
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stripped, gpsBytes()))
+		assert.True(t, bytes.Contains(stripped, exifHeader))
+		assert.Len(t, stripped, len(original))
+
+		_, err = jpeg.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+	})
+
+	t.Run("JPEG drops unreadable EXIF", func(t *testing.T) {
+		original := jpegWithGPS(t)
+		// Corrupt the TIFF byte order mark
+		idx := bytes.Index(original, exifHeader) + len(exifHeader)
+		copy(original[idx:], "XX")
+
+		stripped, err := stripGPS(original, "image/jpeg")
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stripped, exifHeader))
+		_, err = jpeg.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+	})
+
+	t.Run("PNG drops eXIf chunks", func(t *testing.T) {
+		stripped, err := stripGPS(pngWithExif(t), "image/png")
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stripped, []byte("eXIf")))
+		_, err = png.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+	})
+
+	t.Run("XMP packets are dropped", func(t *testing.T) {
+		xmp := append([]byte(nil), xmpHeaders[0]...)
+		stripped, err := stripGPS(jpegWithAPP1(t, append(xmp, xmpWithGPS...)), "image/jpeg")
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stripped, []byte("GPSLatitude")))
+		_, err = jpeg.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+
+		itxt := append([]byte(nil), pngXMPKeyword...)
+		itxt = append(itxt, 0, 0, 0, 0)
+		stripped, err = stripGPS(pngWithChunk(t, "iTXt", append(itxt, xmpWithGPS...)), "image/png")
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stripped, []byte("GPSLatitude")))
+		_, err = png.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Truncated image", func(t *testing.T) {
+		_, err := stripGPS([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x10}, "image/jpeg")
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir(), "/api/photos/", []byte("secret"))
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := "activities/a/photo one.jpg"
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("data"), "image/jpeg"))
+	blob, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	assert.Equal(t, "data", string(content))
+
+	signed, err := store.SignedURL(key, time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/photos/activities/a/photo%20one.jpg", parsed.EscapedPath())
+
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+	assert.NoError(t, store.Verify(key, expires, signature))
+	assert.ErrorIs(t, store.Verify("activities/a/other.jpg", expires, signature), models.ErrInvalidSignature)
+	assert.ErrorIs(t, store.Verify(key, expires+"0", signature), models.ErrInvalidSignature)
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.ErrorIs(t, store.Verify(key, expires, signature), models.ErrInvalidSignature)
+
+	for _, bad := range []string{"../escape.jpg", "/abs.jpg", "a/../../b.jpg", ""} {
+		assert.ErrorIs(t, store.Put(ctx, bad, strings.NewReader("x"), ""), models.ErrInvalidInput, bad)
+	}
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func TestPhotoService(t *testing.T) {
+	ctx := context.Background()
+	activityID := uuid.New()
+
+	setup := func(t *testing.T) (PhotoService, *fakePhotoRepository, *LocalBlobStore, string) {
+		dir := t.TempDir()
+		store, err := NewLocalBlobStore(dir, "/api/photos", []byte("secret"))
+		require.NoError(t, err)
+		repo := newFakePhotoRepository()
+		return NewPhotoService(repo, store, 1<<20, time.Minute), repo, store, dir
+	}
+
+	t.Run("Upload strips GPS and signs the URL", func(t *testing.T) {
+		svc, repo, _, dir := setup(t)
+
+		photo, err := svc.Upload(ctx, activityID, bytes.NewReader(jpegWithGPS(t)), "Dinner")
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", photo.ContentType)
+		assert.Equal(t, "Dinner", photo.Caption)
+		assert.Equal(t, fmt.Sprintf("activities/%s/%s.jpg", activityID, photo.ID), photo.StorageKey)
+		assert.Contains(t, repo.photos, photo.ID)
+
+		stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(photo.StorageKey)))
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(stored, gpsBytes()))
+		assert.Equal(t, photo.SizeBytes, int64(len(stored)))
+
+		parsed, err := url.Parse(photo.URL)
+		require.NoError(t, err)
+		blob, err := svc.OpenSigned(ctx, photo.StorageKey, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+		require.NoError(t, err)
+		require.NoError(t, blob.Close())
+
+		_, err = svc.OpenSigned(ctx, photo.StorageKey, parsed.Query().Get("expires"), "bad")
+		assert.ErrorIs(t, err, models.ErrInvalidSignature)
+
+		photos, err := svc.ListPhotos(activityID)
+		require.NoError(t, err)
+		require.Len(t, photos, 1)
+		assert.True(t, strings.HasPrefix(photos[0].URL, "/api/photos/activities/"))
+	})
+
+	t.Run("Rejects oversized photos", func(t *testing.T) {
+		svc, repo, _, _ := setup(t)
+		_, err := svc.Upload(ctx, activityID, bytes.NewReader(make([]byte, 1<<20+1)), "")
+		assert.ErrorIs(t, err, models.ErrFileTooLarge)
+		assert.Empty(t, repo.photos)
+	})
+
+	t.Run("Rejects unsupported content types", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		_, err := svc.Upload(ctx, activityID, strings.NewReader("<html><body>not a photo</body></html>"), "")
+		assert.ErrorIs(t, err, models.ErrUnsupportedMediaType)
+	})
+
+	t.Run("Removes the file when saving fails", func(t *testing.T) {
+		svc, repo, _, dir := setup(t)
+		repo.createErr = fmt.Errorf("db error")
+
+		_, err := svc.Upload(ctx, activityID, bytes.NewReader(pngWithExif(t)), "")
+		assert.Error(t, err)
+
+		entries, err := os.ReadDir(filepath.Join(dir, "activities", activityID.String()))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Delete checks the activity", func(t *testing.T) {
+		svc, repo, store, _ := setup(t)
+		photo, err := svc.Upload(ctx, activityID, bytes.NewReader(pngWithExif(t)), "")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, svc.DeletePhoto(ctx, uuid.New(), photo.ID), models.ErrNotFound)
+		require.NoError(t, svc.DeletePhoto(ctx, activityID, photo.ID))
+		assert.Empty(t, repo.photos)
+
+		_, err = store.Get(ctx, photo.StorageKey)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
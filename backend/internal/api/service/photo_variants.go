@@ -0,0 +1,310 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// photoVariantSizes is the longest edge, in pixels, of each resized variant
+var photoVariantSizes = map[string]int{
+	models.PhotoVariantThumbnail: 320,
+	models.PhotoVariantMedium:    1280,
+}
+
+const (
+	// variantJPEGQuality is the quality resized JPEG variants are encoded at
+	variantJPEGQuality = 85
+	// maxPhotoPixels caps the decoded size of a photo, since a small file can
+	// claim dimensions that take gigabytes to decode
+	maxPhotoPixels = 50_000_000
+)
+
+// PhotoVariantService generates resized variants of activity photos
+type PhotoVariantService interface {
+	// GenerateVariants creates the thumbnail, medium and original variants of
+	// a photo and records them in its metadata. Failures are recorded too, so
+	// the number of attempts survives restarts.
+	GenerateVariants(ctx context.Context, photoID uuid.UUID) error
+}
+
+// photoVariantService implements the PhotoVariantService interface
+type photoVariantService struct {
+	photos  models.ActivityPhotosRepository
+	store   BlobStore
+	client  *http.Client
+	maxSize int64
+}
+
+// NewPhotoVariantService creates a new variant service. Photos with an
+// external URL are downloaded with client, up to maxSize bytes. A nil client
+// uses one that times out after thirty seconds and refuses to connect to
+// loopback, private and link-local addresses, since photo URLs come from
+// users.
+func NewPhotoVariantService(photos models.ActivityPhotosRepository, store BlobStore, client *http.Client, maxSize int64) PhotoVariantService {
+	if client == nil {
+		client = newPublicHTTPClient(30 * time.Second)
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPhotoSize
+	}
+	return &photoVariantService{
+		photos:  photos,
+		store:   store,
+		client:  client,
+		maxSize: maxSize,
+	}
+}
+
+// GenerateVariants implements PhotoVariantService
+func (s *photoVariantService) GenerateVariants(ctx context.Context, photoID uuid.UUID) error {
+	photo, err := s.photos.GetByID(photoID)
+	if err != nil {
+		return err
+	}
+	if photo.VariantsReady() {
+		return nil
+	}
+
+	variants, err := s.buildVariants(ctx, photo)
+	if photo.Metadata == nil {
+		photo.Metadata = models.JSONMap{}
+	}
+	if err != nil {
+		photo.Metadata[models.PhotoMetaVariantTries] = photo.VariantAttempts() + 1
+		photo.Metadata[models.PhotoMetaVariantStatus] = models.PhotoVariantStatusError
+		photo.Metadata[models.PhotoMetaVariantError] = err.Error()
+		if updateErr := s.photos.Update(photo); updateErr != nil {
+			return fmt.Errorf("error recording variant failure: %w (after %v)", updateErr, err)
+		}
+		return err
+	}
+
+	photo.Metadata[models.PhotoMetaVariants] = variants
+	photo.Metadata[models.PhotoMetaVariantStatus] = models.PhotoVariantStatusReady
+	delete(photo.Metadata, models.PhotoMetaVariantError)
+	if err := s.photos.Update(photo); err != nil {
+		return fmt.Errorf("error saving photo variants: %w", err)
+	}
+	return nil
+}
+
+func (s *photoVariantService) buildVariants(ctx context.Context, photo *models.ActivityPhoto) (map[string]models.PhotoVariant, error) {
+	content, err := s.load(ctx, photo)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(content)
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedMediaType, contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding photo: %v", models.ErrInvalidInput, err)
+	}
+	if int64(config.Width)*int64(config.Height) > maxPhotoPixels {
+		return nil, fmt.Errorf("%w: photos may be at most %d pixels", models.ErrFileTooLarge, maxPhotoPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding photo: %v", models.ErrInvalidInput, err)
+	}
+	// The variants are encoded without EXIF data, so they are turned upright
+	// here; the original keeps its orientation tag
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(content))
+	}
+	bounds := img.Bounds()
+
+	// Uploaded photos are already stored; external ones are copied in so the
+	// original is served from the same place as the resized variants
+	originalKey := photo.StorageKey
+	if originalKey == "" {
+		if content, err = stripGPS(content, contentType); err != nil {
+			return nil, err
+		}
+		originalKey = variantKey(photo, models.PhotoVariantOriginal, ext)
+		if err = s.store.Put(ctx, originalKey, bytes.NewReader(content), contentType); err != nil {
+			return nil, fmt.Errorf("error storing original photo: %w", err)
+		}
+	}
+
+	variants := map[string]models.PhotoVariant{
+		models.PhotoVariantOriginal: {
+			Key:         originalKey,
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			ContentType: contentType,
+		},
+	}
+	for name, maxEdge := range photoVariantSizes {
+		resized := resizeToFit(img, maxEdge)
+
+		var buf bytes.Buffer
+		if contentType == "image/png" {
+			err = png.Encode(&buf, resized)
+		} else {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQuality})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s variant: %w", name, err)
+		}
+
+		key := variantKey(photo, name, ext)
+		if err = s.store.Put(ctx, key, &buf, contentType); err != nil {
+			return nil, fmt.Errorf("error storing %s variant: %w", name, err)
+		}
+		variants[name] = models.PhotoVariant{
+			Key:         key,
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			ContentType: contentType,
+		}
+	}
+	return variants, nil
+}
+
+// load reads the photo from the blob store, or downloads it from its URL
+func (s *photoVariantService) load(ctx context.Context, photo *models.ActivityPhoto) ([]byte, error) {
+	var body io.ReadCloser
+	switch {
+	case photo.StorageKey != "":
+		blob, err := s.store.Get(ctx, photo.StorageKey)
+		if err != nil {
+			return nil, fmt.Errorf("error opening stored photo: %w", err)
+		}
+		body = blob
+	case strings.HasPrefix(photo.URL, "http://") || strings.HasPrefix(photo.URL, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, photo.URL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid photo URL: %v", models.ErrInvalidInput, err)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading photo: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("error downloading photo: status %d", resp.StatusCode)
+		}
+		body = resp.Body
+	default:
+		return nil, fmt.Errorf("%w: photo has no stored file or fetchable URL", models.ErrInvalidInput)
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading photo: %w", err)
+	}
+	if int64(len(content)) > s.maxSize {
+		return nil, fmt.Errorf("%w: photos may be at most %d bytes", models.ErrFileTooLarge, s.maxSize)
+	}
+	return content, nil
+}
+
+// variantKey stores variants next to the uploaded photo
+func variantKey(photo *models.ActivityPhoto, variant, ext string) string {
+	return fmt.Sprintf("activities/%s/%s_%s%s", photo.ActivityID, photo.ID, variant, ext)
+}
+
+// applyOrientation rotates and flips an image as an EXIF orientation says it
+// should be displayed
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		// Orientations 5 to 8 swap the width and height
+		dstW, dstH = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored
+				dx, dy = w-1-x, y
+			case 3: // Upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // Upside down and mirrored
+				dx, dy = x, h-1-y
+			case 5: // Transposed
+				dx, dy = y, x
+			case 6: // Turned a quarter counterclockwise
+				dx, dy = h-1-y, x
+			case 7: // Transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // Turned a quarter clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// resizeToFit scales an image down so its longest edge is at most maxEdge,
+// averaging the source pixels that fall in each destination pixel. Images
+// that already fit are copied at their own size.
+func resizeToFit(src image.Image, maxEdge int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > maxEdge || srcH > maxEdge {
+		if srcW >= srcH {
+			dstW, dstH = maxEdge, max(1, srcH*maxEdge/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*maxEdge/srcH), maxEdge
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(src.At(sx, sy)).(color.NRGBA)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n),
+				G: uint8(g / n),
+				B: uint8(b / n),
+				A: uint8(a / n),
+			})
+		}
+	}
+	return dst
+}
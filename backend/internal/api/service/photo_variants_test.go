@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodedImage(t *testing.T, width, height int, format string) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 80, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == "png" {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+// exifWithOrientation builds an EXIF payload whose IFD0 holds only the
+// orientation tag
+func exifWithOrientation(orientation uint16) []byte {
+	order := binary.BigEndian
+	tiff := make([]byte, 26)
+	copy(tiff, "MM")
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	return append(append([]byte(nil), exifHeader...), tiff...)
+}
+
+func TestApplyOrientation(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	// A 2x1 image, red on the left and blue on the right
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	tests := []struct {
+		orientation  int
+		wantW, wantH int
+		redX, redY   int
+		blueX, blueY int
+	}{
+		{1, 2, 1, 0, 0, 1, 0},
+		{2, 2, 1, 1, 0, 0, 0},
+		{3, 2, 1, 1, 0, 0, 0},
+		{4, 2, 1, 0, 0, 1, 0},
+		{5, 1, 2, 0, 0, 0, 1},
+		{6, 1, 2, 0, 0, 0, 1},
+		{7, 1, 2, 0, 1, 0, 0},
+		{8, 1, 2, 0, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		dst := applyOrientation(src, tt.orientation)
+		assert.Equal(t, tt.wantW, dst.Bounds().Dx(), "orientation %d", tt.orientation)
+		assert.Equal(t, tt.wantH, dst.Bounds().Dy(), "orientation %d", tt.orientation)
+		assert.Equal(t, red, color.NRGBAModel.Convert(dst.At(tt.redX, tt.redY)), "orientation %d", tt.orientation)
+		assert.Equal(t, blue, color.NRGBAModel.Convert(dst.At(tt.blueX, tt.blueY)), "orientation %d", tt.orientation)
+	}
+
+	assert.Equal(t, 6, jpegOrientation(insertAPP1(encodedImage(t, 4, 2, "jpeg"), exifWithOrientation(6))))
+	assert.Equal(t, 1, jpegOrientation(encodedImage(t, 4, 2, "jpeg")))
+}
+
+func TestResizeToFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxEdge       int
+		wantW, wantH  int
+	}{
+		{"Landscape", 640, 480, 320, 320, 240},
+		{"Portrait", 300, 900, 90, 30, 90},
+		{"Already fits", 100, 50, 320, 100, 50},
+		{"Very thin", 1000, 2, 100, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			dst := resizeToFit(src, tt.maxEdge)
+			assert.Equal(t, tt.wantW, dst.Bounds().Dx())
+			assert.Equal(t, tt.wantH, dst.Bounds().Dy())
+		})
+	}
+
+	// A solid image keeps its color when averaged down
+	src := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []byte{10, 20, 30, 255})
+	}
+	assert.Equal(t, color.NRGBA{R: 10, G: 20, B: 30, A: 255}, resizeToFit(src, 3).NRGBAAt(1, 1))
+}
+
+func TestPhotoVariantService(t *testing.T) {
+	ctx := context.Background()
+	activityID := uuid.New()
+
+	setup := func(t *testing.T, client *http.Client) (PhotoService, PhotoVariantService, *fakePhotoRepository, *LocalBlobStore) {
+		store, err := NewLocalBlobStore(t.TempDir(), "/api/photos", []byte("secret"))
+		require.NoError(t, err)
+		repo := newFakePhotoRepository()
+		return NewPhotoService(repo, store, 1<<20, time.Minute),
+			NewPhotoVariantService(repo, store, client, 1<<20), repo, store
+	}
+
+	t.Run("Uploaded photo", func(t *testing.T) {
+		photos, variants, repo, store := setup(t, nil)
+		photo, err := photos.Upload(ctx, activityID, bytes.NewReader(encodedImage(t, 1600, 1200, "jpeg")), "")
+		require.NoError(t, err)
+
+		require.NoError(t, variants.GenerateVariants(ctx, photo.ID))
+
+		stored := repo.photos[photo.ID]
+		assert.True(t, stored.VariantsReady())
+		recorded := stored.StoredVariants()
+		require.Len(t, recorded, 3)
+		assert.Equal(t, photo.StorageKey, recorded[models.PhotoVariantOriginal].Key)
+		assert.Equal(t, 1600, recorded[models.PhotoVariantOriginal].Width)
+		assert.Equal(t, 320, recorded[models.PhotoVariantThumbnail].Width)
+		assert.Equal(t, 240, recorded[models.PhotoVariantThumbnail].Height)
+		assert.Equal(t, 1280, recorded[models.PhotoVariantMedium].Width)
+
+		blob, err := store.Get(ctx, recorded[models.PhotoVariantThumbnail].Key)
+		require.NoError(t, err)
+		decoded, err := jpeg.Decode(blob)
+		require.NoError(t, err)
+		require.NoError(t, blob.Close())
+		assert.Equal(t, 320, decoded.Bounds().Dx())
+
+		// Responses carry signed variant URLs rather than storage keys
+		listed, err := photos.ListPhotos(activityID)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		thumbnail := listed[0].Variants[models.PhotoVariantThumbnail]
+		assert.Empty(t, thumbnail.Key)
+		assert.Contains(t, thumbnail.URL, "signature=")
+
+		// Ready photos are left alone
+		require.NoError(t, variants.GenerateVariants(ctx, photo.ID))
+
+		require.NoError(t, photos.DeletePhoto(ctx, activityID, photo.ID))
+		_, err = store.Get(ctx, recorded[models.PhotoVariantMedium].Key)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("Rotated photo", func(t *testing.T) {
+		// Cameras held upright store landscape pixels with orientation 6
+		content := insertAPP1(encodedImage(t, 400, 200, "jpeg"), exifWithOrientation(6))
+		photos, variants, repo, store := setup(t, nil)
+		photo, err := photos.Upload(ctx, activityID, bytes.NewReader(content), "")
+		require.NoError(t, err)
+
+		require.NoError(t, variants.GenerateVariants(ctx, photo.ID))
+
+		recorded := repo.photos[photo.ID].StoredVariants()
+		assert.Equal(t, 200, recorded[models.PhotoVariantOriginal].Width)
+		assert.Equal(t, 400, recorded[models.PhotoVariantOriginal].Height)
+		assert.Equal(t, 160, recorded[models.PhotoVariantThumbnail].Width)
+		assert.Equal(t, 320, recorded[models.PhotoVariantThumbnail].Height)
+
+		blob, err := store.Get(ctx, recorded[models.PhotoVariantThumbnail].Key)
+		require.NoError(t, err)
+		decoded, err := jpeg.Decode(blob)
+		require.NoError(t, err)
+		require.NoError(t, blob.Close())
+		assert.Equal(t, 160, decoded.Bounds().Dx())
+		assert.Equal(t, 320, decoded.Bounds().Dy())
+	})
+
+	t.Run("External photo is fetched and copied", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(encodedImage(t, 200, 100, "png"))
+		}))
+		defer server.Close()
+		_, variants, repo, store := setup(t, server.Client())
+
+		photo := &models.ActivityPhoto{
+			BaseModel:  models.BaseModel{ID: uuid.New()},
+			ActivityID: activityID,
+			URL:        server.URL + "/photo.png",
+			Metadata:   models.JSONMap{},
+		}
+		require.NoError(t, repo.Create(photo))
+		require.NoError(t, variants.GenerateVariants(ctx, photo.ID))
+
+		recorded := repo.photos[photo.ID].StoredVariants()
+		original := recorded[models.PhotoVariantOriginal]
+		assert.True(t, strings.HasSuffix(original.Key, "_original.png"))
+		assert.Equal(t, "image/png", original.ContentType)
+		assert.Equal(t, 200, recorded[models.PhotoVariantMedium].Width)
+
+		blob, err := store.Get(ctx, original.Key)
+		require.NoError(t, err)
+		require.NoError(t, blob.Close())
+	})
+
+	t.Run("Failures are recorded", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		_, variants, repo, _ := setup(t, server.Client())
+
+		photo := &models.ActivityPhoto{
+			BaseModel:  models.BaseModel{ID: uuid.New()},
+			ActivityID: activityID,
+			URL:        server.URL + "/missing.jpg",
+			Metadata:   models.JSONMap{},
+		}
+		require.NoError(t, repo.Create(photo))
+
+		assert.Error(t, variants.GenerateVariants(ctx, photo.ID))
+		assert.Error(t, variants.GenerateVariants(ctx, photo.ID))
+
+		stored := repo.photos[photo.ID]
+		assert.Equal(t, 2, stored.VariantAttempts())
+		assert.Equal(t, models.PhotoVariantStatusError, stored.Metadata[models.PhotoMetaVariantStatus])
+		assert.Contains(t, stored.Metadata[models.PhotoMetaVariantError], "status 404")
+	})
+
+	t.Run("Default client refuses private addresses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(encodedImage(t, 200, 100, "png"))
+		}))
+		defer server.Close()
+		_, variants, repo, _ := setup(t, nil)
+
+		photo := &models.ActivityPhoto{
+			BaseModel:  models.BaseModel{ID: uuid.New()},
+			ActivityID: activityID,
+			URL:        server.URL + "/photo.png",
+			Metadata:   models.JSONMap{},
+		}
+		require.NoError(t, repo.Create(photo))
+
+		assert.Error(t, variants.GenerateVariants(ctx, photo.ID))
+		assert.Contains(t, repo.photos[photo.ID].Metadata[models.PhotoMetaVariantError], "refusing to connect")
+	})
+
+	t.Run("Oversized images are rejected before decoding", func(t *testing.T) {
+		// Claim 100000x100000 pixels in the header of a tiny PNG
+		content := encodedImage(t, 1, 1, "png")
+		binary.BigEndian.PutUint32(content[16:20], 100000)
+		binary.BigEndian.PutUint32(content[20:24], 100000)
+		binary.BigEndian.PutUint32(content[29:33], crc32.ChecksumIEEE(content[12:29]))
+
+		photos, variants, _, _ := setup(t, nil)
+		photo, err := photos.Upload(ctx, activityID, bytes.NewReader(content), "")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, variants.GenerateVariants(ctx, photo.ID), models.ErrFileTooLarge)
+	})
+
+	t.Run("Photos without a fetchable source fail", func(t *testing.T) {
+		_, variants, repo, _ := setup(t, nil)
+		photo := &models.ActivityPhoto{
+			BaseModel:  models.BaseModel{ID: uuid.New()},
+			ActivityID: activityID,
+			URL:        "ftp://example.com/photo.jpg",
+		}
+		require.NoError(t, repo.Create(photo))
+
+		assert.ErrorIs(t, variants.GenerateVariants(ctx, photo.ID), models.ErrInvalidInput)
+		assert.Equal(t, 1, repo.photos[photo.ID].VariantAttempts())
+	})
+}
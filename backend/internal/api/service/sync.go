@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -11,13 +12,321 @@ import (
 
 // SyncService handles list synchronization operations
 type SyncService struct {
-	listRepo models.ListRepository
+	listRepo  models.ListRepository
+	providers *SyncProviderRegistry
 }
 
-// NewSyncService creates a new sync service
-func NewSyncService(listRepo models.ListRepository) *SyncService {
+// NewSyncService creates a new sync service that pulls items from the given
+// providers
+func NewSyncService(listRepo models.ListRepository, providers ...SyncProvider) *SyncService {
 	return &SyncService{
-		listRepo: listRepo,
+		listRepo:  listRepo,
+		providers: NewSyncProviderRegistry(providers...),
+	}
+}
+
+// Providers returns the registry of sync providers
+func (s *SyncService) Providers() *SyncProviderRegistry {
+	return s.providers
+}
+
+// SyncResult summarizes the changes applied by a sync
+type SyncResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Conflicts int `json:"conflicts"`
+}
+
+// SyncList pulls a list's items from its provider and applies the difference
+// to the local items, matching them by ExternalID. Remote items missing
+// locally are created, changed items are updated and local items gone from
+// the source are soft-deleted. Local items without an ExternalID are left
+// alone.
+//
+// Changes are merged field by field against each item's snapshot from the
+// last sync, so local and remote edits to different fields both survive. Only
+// fields changed on both sides become conflicts, and the list is left in the
+// conflict state. Syncing a list that is already in conflict re-checks its
+// open conflicts; once none of them collide any more they are closed and the
+// list is auto-resolved.
+func (s *SyncService) SyncList(ctx context.Context, listID uuid.UUID) (*SyncResult, error) {
+	list, err := s.listRepo.GetByID(listID)
+	if err != nil {
+		if err == models.ErrNotFound {
+			return nil, fmt.Errorf("list not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	if list.SyncSource == "" || list.SyncSource == models.SyncSourceNone {
+		return nil, fmt.Errorf("cannot sync list: %w", models.ErrSyncDisabled)
+	}
+
+	provider, ok := s.providers.Get(list.SyncSource)
+	if !ok {
+		return nil, fmt.Errorf("%w: no provider for %s", models.ErrInvalidSyncSource, list.SyncSource)
+	}
+
+	// Open conflicts by item, so a re-sync neither duplicates nor loses them
+	openConflicts := make(map[uuid.UUID]*models.SyncConflict)
+	inConflict := list.SyncStatus == models.ListSyncStatusConflict
+	if inConflict {
+		open, conflictsErr := s.listRepo.GetConflicts(listID)
+		if conflictsErr != nil {
+			return nil, fmt.Errorf("failed to get conflicts: %w", conflictsErr)
+		}
+		for _, conflict := range open {
+			if conflict.ItemID != nil {
+				openConflicts[*conflict.ItemID] = conflict
+			}
+		}
+	}
+
+	if list.SyncStatus == models.ListSyncStatusSynced {
+		if err := s.UpdateSyncStatus(ctx, listID, models.ListSyncStatusPending, "local_change"); err != nil {
+			return nil, fmt.Errorf("failed to update sync status: %w", err)
+		}
+	}
+
+	remoteItems, err := provider.FetchItems(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote items: %w", err)
+	}
+
+	localItems, err := s.listRepo.GetItems(listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list items: %w", err)
+	}
+
+	localByExternalID := make(map[string]*models.ListItem, len(localItems))
+	for _, item := range localItems {
+		if item.ExternalID != "" {
+			localByExternalID[item.ExternalID] = item
+		}
+	}
+
+	// Items synced before snapshots were kept have no base. They count as
+	// unchanged locally unless edited after the last sync.
+	baseOf := func(item *models.ListItem) *models.SyncSnapshot {
+		if item.SyncSnapshot != nil {
+			return item.SyncSnapshot
+		}
+		if list.LastSyncAt != nil && item.UpdatedAt.After(*list.LastSyncAt) {
+			return nil
+		}
+		return models.NewSyncSnapshot(item)
+	}
+
+	result := &SyncResult{}
+	var conflicts []*models.SyncConflict
+	colliding := make(map[uuid.UUID]bool)
+	seen := make(map[string]bool, len(remoteItems))
+
+	for _, remote := range remoteItems {
+		if seen[remote.ExternalID] {
+			continue
+		}
+		seen[remote.ExternalID] = true
+
+		local, exists := localByExternalID[remote.ExternalID]
+		if !exists {
+			item := newSyncedItem(list, remote)
+			if err := s.listRepo.AddItem(item, nil); err != nil {
+				return nil, fmt.Errorf("failed to create item %s: %w", remote.ExternalID, err)
+			}
+			result.Created++
+			continue
+		}
+
+		base := baseOf(local)
+		localFields := models.NewSyncSnapshot(local)
+		remoteFields := models.NewSyncSnapshot(remote)
+		merged, collisions := models.MergeSnapshots(base, localFields, remoteFields)
+
+		// The new base is the remote side, except where a collision is still
+		// waiting to be resolved
+		var snapshot *models.SyncSnapshot
+		if len(collisions) == 0 {
+			snapshot = remoteFields
+		} else if base != nil {
+			next := *remoteFields
+			next.CopyFields(base, collisions)
+			snapshot = &next
+		}
+
+		changed := !merged.Equal(localFields)
+		if changed || !snapshot.Equal(local.SyncSnapshot) {
+			merged.ApplyTo(local)
+			local.SyncSnapshot = snapshot
+			if err := s.listRepo.UpdateItem(local); err != nil {
+				return nil, fmt.Errorf("failed to update item %s: %w", remote.ExternalID, err)
+			}
+			if changed {
+				result.Updated++
+			}
+		}
+
+		if len(collisions) > 0 {
+			colliding[local.ID] = true
+			if _, open := openConflicts[local.ID]; !open {
+				conflict := newSyncConflict(local, models.ConflictTypeItemUpdate,
+					localFields.FieldData(collisions), remoteFields.FieldData(collisions))
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+
+	for _, local := range localItems {
+		externalID := local.ExternalID
+		if externalID == "" || seen[externalID] {
+			continue
+		}
+
+		// Keep items removed remotely if they were edited locally
+		localFields := models.NewSyncSnapshot(local)
+		var edited []string
+		if base := baseOf(local); base == nil {
+			edited = models.SyncFields
+		} else {
+			for _, field := range models.SyncFields {
+				if !localFields.FieldEqual(base, field) {
+					edited = append(edited, field)
+				}
+			}
+		}
+		if len(edited) > 0 {
+			colliding[local.ID] = true
+			if _, open := openConflicts[local.ID]; !open {
+				conflicts = append(conflicts, newSyncConflict(local, models.ConflictTypeItemDelete,
+					localFields.FieldData(edited), models.JSONMap{
+						"external_id": externalID,
+						"deleted":     true,
+					}))
+			}
+			continue
+		}
+
+		if err := s.listRepo.RemoveItem(listID, local.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete item %s: %w", externalID, err)
+		}
+		result.Deleted++
+	}
+
+	if result.Created+result.Updated+result.Deleted > 0 {
+		if err := s.listRepo.RecordSync(listID, result.Created, result.Updated, result.Deleted); err != nil {
+			return nil, fmt.Errorf("failed to record sync: %w", err)
+		}
+	}
+
+	result.Conflicts = len(conflicts)
+	if inConflict {
+		return s.finishConflictedSync(ctx, list, openConflicts, colliding, conflicts, result)
+	}
+
+	if len(conflicts) > 0 {
+		if err := s.reportConflicts(ctx, conflicts); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if err := s.UpdateSyncStatus(ctx, listID, models.ListSyncStatusSynced, "sync_complete"); err != nil {
+		return nil, fmt.Errorf("failed to update sync status: %w", err)
+	}
+
+	return result, nil
+}
+
+// finishConflictedSync settles a sync of a list that already had open
+// conflicts. Conflicts whose item merged cleanly this time are closed, new
+// collisions are stored alongside the rest, and the list is auto-resolved
+// when nothing remains open.
+func (s *SyncService) finishConflictedSync(ctx context.Context, list *models.List, openConflicts map[uuid.UUID]*models.SyncConflict, colliding map[uuid.UUID]bool, conflicts []*models.SyncConflict, result *SyncResult) (*SyncResult, error) {
+	for itemID, conflict := range openConflicts {
+		if colliding[itemID] {
+			continue
+		}
+		if err := s.listRepo.ResolveConflict(conflict.ID, models.ResolutionMergeFields, nil); err != nil {
+			return nil, fmt.Errorf("failed to resolve conflict: %w", err)
+		}
+	}
+
+	for _, conflict := range conflicts {
+		if err := conflict.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidSyncConfig, err)
+		}
+		if err := s.listRepo.CreateConflict(conflict); err != nil {
+			return nil, fmt.Errorf("failed to create conflict: %w", err)
+		}
+	}
+
+	remaining, err := s.listRepo.GetConflicts(list.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remaining conflicts: %w", err)
+	}
+	result.Conflicts = len(remaining)
+	if len(remaining) > 0 {
+		return result, nil
+	}
+
+	if err := s.UpdateSyncStatus(ctx, list.ID, models.ListSyncStatusSynced, "auto_resolve"); err != nil {
+		return nil, fmt.Errorf("failed to update sync status: %w", err)
+	}
+	return result, nil
+}
+
+// reportConflicts records conflicts found by a sync. The first moves the
+// list into the conflict state; the rest are stored alongside it.
+func (s *SyncService) reportConflicts(ctx context.Context, conflicts []*models.SyncConflict) error {
+	if err := s.CreateConflict(ctx, conflicts[0]); err != nil {
+		return err
+	}
+	for _, conflict := range conflicts[1:] {
+		if err := conflict.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", models.ErrInvalidSyncConfig, err)
+		}
+		if err := s.listRepo.CreateConflict(conflict); err != nil {
+			return fmt.Errorf("failed to create conflict: %w", err)
+		}
+	}
+	return nil
+}
+
+// newSyncedItem builds a local item for a remote item seen for the first time
+func newSyncedItem(list *models.List, remote *models.ListItem) *models.ListItem {
+	item := &models.ListItem{
+		ListID:    list.ID,
+		Weight:    list.DefaultWeight,
+		Available: true,
+	}
+	if item.Weight <= 0 {
+		item.Weight = 1.0
+	}
+	item.ExternalID = remote.ExternalID
+	applyRemoteFields(item, remote)
+	item.SyncSnapshot = models.NewSyncSnapshot(remote)
+	return item
+}
+
+// applyRemoteFields copies the fields owned by the source onto a local item
+func applyRemoteFields(local, remote *models.ListItem) {
+	models.NewSyncSnapshot(remote).ApplyTo(local)
+}
+
+// newSyncConflict records the colliding fields of an item on both sides
+func newSyncConflict(local *models.ListItem, conflictType string, localData, remoteData interface{}) *models.SyncConflict {
+	now := time.Now()
+	itemID := local.ID
+	return &models.SyncConflict{
+		ID:         uuid.New(),
+		ListID:     local.ListID,
+		ItemID:     &itemID,
+		Type:       conflictType,
+		LocalData:  localData,
+		RemoteData: remoteData,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 }
 
@@ -211,41 +520,49 @@ func (s *SyncService) CreateConflict(ctx context.Context, conflict *models.SyncC
 	return nil
 }
 
-// ResolveConflict resolves a sync conflict
-func (s *SyncService) ResolveConflict(ctx context.Context, conflictID uuid.UUID, resolution string) error {
+// ResolveConflict resolves a sync conflict with one of the resolution
+// strategies, applying the result to the conflicting item. The custom
+// strategy applies the caller's values for the source-owned fields. Once the
+// list's last open conflict is resolved, the list goes back to pending for a
+// re-sync.
+func (s *SyncService) ResolveConflict(ctx context.Context, conflictID uuid.UUID, resolution string, custom *models.ListItem) error {
+	return s.ResolveListConflict(ctx, uuid.Nil, conflictID, resolution, custom)
+}
+
+// ResolveListConflict is ResolveConflict for a conflict that must belong to
+// the given list; a nil list ID accepts any list
+func (s *SyncService) ResolveListConflict(ctx context.Context, listID, conflictID uuid.UUID, resolution string, custom *models.ListItem) error {
+	if err := models.ValidateResolution(resolution); err != nil {
+		return err
+	}
+	if resolution == models.ResolutionCustom && custom == nil {
+		return fmt.Errorf("%w: custom resolution requires an item", models.ErrInvalidResolution)
+	}
+
 	// Get the conflict
-	conflicts, err := s.listRepo.GetConflicts(conflictID)
+	conflict, err := s.openConflict(conflictID)
 	if err != nil {
-		return fmt.Errorf("failed to get conflict: %w", err)
+		return err
 	}
-	if len(conflicts) == 0 {
-		return fmt.Errorf("conflict not found: %w", models.ErrNotFound)
+	if listID != uuid.Nil && conflict.ListID != listID {
+		return models.ErrConflictNotFound
 	}
-	conflict := conflicts[0]
 
-	// Check if the conflict is already resolved
-	if conflict.ResolvedAt != nil {
-		return fmt.Errorf("conflict already resolved: %w", models.ErrConflictAlreadyResolved)
+	change, err := s.resolvedItem(conflict, resolution, custom)
+	if err != nil {
+		return err
 	}
 
-	// Try to resolve the conflict
-	err = s.listRepo.ResolveConflict(conflictID)
+	// Apply the change and close the conflict together
+	err = s.listRepo.ResolveConflict(conflictID, resolution, change)
 	if err != nil {
 		if err == models.ErrConcurrentModification {
 			// Check if the conflict was resolved by another operation
-			conflicts, err = s.listRepo.GetConflicts(conflictID)
-			if err != nil {
-				return fmt.Errorf("failed to get latest conflict state: %w", err)
-			}
-			if len(conflicts) == 0 {
-				return fmt.Errorf("conflict not found: %w", models.ErrNotFound)
-			}
-			conflict = conflicts[0]
-			if conflict.ResolvedAt != nil {
-				return fmt.Errorf("conflict already resolved: %w", models.ErrConflictAlreadyResolved)
+			if _, err = s.openConflict(conflictID); err != nil {
+				return err
 			}
 			// Try to resolve again
-			err = s.listRepo.ResolveConflict(conflictID)
+			err = s.listRepo.ResolveConflict(conflictID, resolution, change)
 			if err != nil {
 				return fmt.Errorf("failed to resolve conflict after retry: %w", err)
 			}
@@ -254,10 +571,147 @@ func (s *SyncService) ResolveConflict(ctx context.Context, conflictID uuid.UUID,
 		}
 	}
 
+	// Wait for the remaining conflicts before re-syncing
+	remaining, err := s.listRepo.GetConflicts(conflict.ListID)
+	if err != nil {
+		return fmt.Errorf("failed to get remaining conflicts: %w", err)
+	}
+	if len(remaining) > 0 {
+		return nil
+	}
+
 	// Update list status to pending for re-sync
 	return s.UpdateSyncStatus(ctx, conflict.ListID, models.ListSyncStatusPending, "resolve_conflict")
 }
 
+// openConflict loads a conflict that has not been resolved yet
+func (s *SyncService) openConflict(conflictID uuid.UUID) (*models.SyncConflict, error) {
+	conflict, err := s.listRepo.GetConflict(conflictID)
+	if err != nil {
+		if err == models.ErrConflictNotFound || err == models.ErrNotFound {
+			return nil, fmt.Errorf("conflict not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get conflict: %w", err)
+	}
+	if conflict.ResolvedAt != nil {
+		return nil, fmt.Errorf("conflict already resolved: %w", models.ErrConflictAlreadyResolved)
+	}
+	return conflict, nil
+}
+
+// resolvedItem works out the change a resolution makes to the conflicting
+// item, returning nil when there is nothing to apply, as for conflicts that
+// are not tied to an item. The remote side of the conflict becomes the
+// item's new sync base, so the next sync does not raise the same collision
+// again.
+func (s *SyncService) resolvedItem(conflict *models.SyncConflict, resolution string, custom *models.ListItem) (*models.ResolvedItem, error) {
+	if conflict.ItemID == nil {
+		return nil, nil
+	}
+
+	items, err := s.listRepo.GetItems(conflict.ListID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list items: %w", err)
+	}
+	var local *models.ListItem
+	for _, item := range items {
+		if item.ID == *conflict.ItemID {
+			local = item
+			break
+		}
+	}
+	if local == nil {
+		// The item is already gone, so only the conflict needs closing
+		return nil, nil
+	}
+
+	if conflict.Type == models.ConflictTypeItemDelete {
+		return deleteResolution(local, resolution, custom)
+	}
+
+	remote, fields, decodeErr := conflictFields(conflict.RemoteData)
+	switch resolution {
+	case models.ResolutionTakeRemote, models.ResolutionMergeFields:
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		if resolution == models.ResolutionMergeFields {
+			fields = nonEmptyFields(remote, fields)
+		}
+		resolved := models.NewSyncSnapshot(local)
+		resolved.CopyFields(remote, fields)
+		resolved.ApplyTo(local)
+	case models.ResolutionCustom:
+		applyRemoteFields(local, custom)
+	}
+
+	if decodeErr == nil {
+		rebaseSnapshot(local, remote, fields)
+	} else if resolution == models.ResolutionKeepLocal {
+		// Nothing to apply and no remote side to record
+		return nil, nil
+	}
+
+	return &models.ResolvedItem{Item: local}, nil
+}
+
+// deleteResolution settles an item that was edited locally but removed from
+// the source. Kept items are detached from the source so the next sync does
+// not delete them.
+func deleteResolution(local *models.ListItem, resolution string, custom *models.ListItem) (*models.ResolvedItem, error) {
+	switch resolution {
+	case models.ResolutionTakeRemote:
+		return &models.ResolvedItem{Item: local, Remove: true}, nil
+	case models.ResolutionMergeFields:
+		return nil, fmt.Errorf("%w: %s does not apply to a deleted item", models.ErrInvalidResolution, models.ResolutionMergeFields)
+	case models.ResolutionCustom:
+		applyRemoteFields(local, custom)
+	}
+
+	local.ExternalID = ""
+	return &models.ResolvedItem{Item: local}, nil
+}
+
+// rebaseSnapshot records the remote values of the resolved fields as the
+// item's sync base
+func rebaseSnapshot(local *models.ListItem, remote *models.SyncSnapshot, fields []string) {
+	if local.SyncSnapshot == nil {
+		local.SyncSnapshot = models.NewSyncSnapshot(local)
+	}
+	local.SyncSnapshot.CopyFields(remote, fields)
+}
+
+// nonEmptyFields drops the fields the remote side left empty, so merging
+// keeps the local values for them
+func nonEmptyFields(remote *models.SyncSnapshot, fields []string) []string {
+	var kept []string
+	for _, field := range fields {
+		if !remote.FieldEmpty(field) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
+
+// conflictFields decodes the source-owned fields recorded on one side of a
+// conflict, along with which of them are present. Conflicts read back from
+// the database hold generic JSON.
+func conflictFields(data interface{}) (*models.SyncSnapshot, []string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read conflict data: %w", err)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return nil, nil, fmt.Errorf("failed to read conflict data: %w", err)
+	}
+	snapshot := &models.SyncSnapshot{}
+	if err := json.Unmarshal(raw, snapshot); err != nil {
+		return nil, nil, fmt.Errorf("failed to read conflict data: %w", err)
+	}
+	return snapshot, models.SyncFieldsIn(keys), nil
+}
+
 // GetConflicts returns all unresolved conflicts for a list
 func (s *SyncService) GetConflicts(ctx context.Context, listID uuid.UUID) ([]*models.SyncConflict, error) {
 	conflicts, err := s.listRepo.GetConflicts(listID)
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// FileSyncProvider reads remote items from JSON or CSV files on disk. A
+// list's SyncID names its file relative to the provider's root directory.
+//
+// JSON files hold an array of items using the ListItem field names. CSV files
+// have a header row naming any of external_id, name, description, latitude,
+// longitude and address.
+type FileSyncProvider struct {
+	root   string
+	source models.SyncSource
+}
+
+// NewFileSyncProvider creates a file provider for imported lists
+func NewFileSyncProvider(root string) *FileSyncProvider {
+	return &FileSyncProvider{root: root, source: models.SyncSourceImported}
+}
+
+// Source returns the sync source this provider serves
+func (p *FileSyncProvider) Source() models.SyncSource {
+	return p.source
+}
+
+// FetchItems reads the items in the list's file
+func (p *FileSyncProvider) FetchItems(ctx context.Context, list *models.List) ([]*models.ListItem, error) {
+	if list.SyncID == "" {
+		return nil, fmt.Errorf("%w: imported lists require a file name", models.ErrMissingSyncID)
+	}
+
+	// Clean against the root so a sync ID cannot escape the directory
+	path := filepath.Join(p.root, filepath.Clean("/"+list.SyncID))
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s not found", models.ErrExternalSourceUnavailable, list.SyncID)
+		}
+		return nil, fmt.Errorf("%w: %v", models.ErrExternalSourceUnavailable, err)
+	}
+	defer file.Close()
+
+	var items []*models.ListItem
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		items, err = readJSONItems(file)
+	case ".csv":
+		items, err = readCSVItems(file)
+	default:
+		return nil, fmt.Errorf("%w: unsupported file type %q", models.ErrInvalidSyncConfig, filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", models.ErrExternalSourceError, list.SyncID, err)
+	}
+
+	for i, item := range items {
+		if item.ExternalID == "" {
+			return nil, fmt.Errorf("%w: %s: item %d has no external_id", models.ErrExternalSourceError, list.SyncID, i+1)
+		}
+	}
+
+	return items, nil
+}
+
+func readJSONItems(r io.Reader) ([]*models.ListItem, error) {
+	var items []*models.ListItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func readCSVItems(r io.Reader) ([]*models.ListItem, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["external_id"]; !ok {
+		return nil, errors.New("missing external_id column")
+	}
+
+	items := make([]*models.ListItem, 0, len(records)-1)
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		coordinate := func(name string) (*float64, error) {
+			value := field(name)
+			if value == "" {
+				return nil, nil
+			}
+			f, parseErr := strconv.ParseFloat(value, 64)
+			if parseErr != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", line+2, name, value)
+			}
+			return &f, nil
+		}
+
+		item := &models.ListItem{
+			ExternalID:  field("external_id"),
+			Name:        field("name"),
+			Description: field("description"),
+		}
+		if address := field("address"); address != "" {
+			item.Address = &address
+		}
+		if item.Latitude, err = coordinate("latitude"); err != nil {
+			return nil, err
+		}
+		if item.Longitude, err = coordinate("longitude"); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// DefaultGoogleMapsBaseURL is the Places API endpoint used when none is configured
+const DefaultGoogleMapsBaseURL = "https://places.googleapis.com"
+
+// googleMapsListPrefix marks a sync ID that names a saved list. The Places API
+// has no endpoint for saved lists, so these IDs are rejected.
+const googleMapsListPrefix = "list:"
+
+// googleMapsFieldMask limits responses to the fields mapped onto list items
+const googleMapsFieldMask = "id,displayName,formattedAddress,location"
+
+// GoogleMapsProvider pulls places from Google Maps. A list's SyncID is a
+// comma-separated list of place IDs. Saved lists ("list:<id>") are not
+// supported because the Places API does not expose them.
+type GoogleMapsProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewGoogleMapsProvider creates a Google Maps provider. An empty base URL
+// uses the public Places API; a zero timeout defaults to ten seconds.
+func NewGoogleMapsProvider(baseURL, apiKey string, timeout time.Duration) *GoogleMapsProvider {
+	if baseURL == "" {
+		baseURL = DefaultGoogleMapsBaseURL
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &GoogleMapsProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Source returns the sync source this provider serves
+func (p *GoogleMapsProvider) Source() models.SyncSource {
+	return models.SyncSourceGoogleMaps
+}
+
+// googlePlace is the subset of a Places API place that maps onto a list item
+type googlePlace struct {
+	ID          string `json:"id"`
+	DisplayName struct {
+		Text string `json:"text"`
+	} `json:"displayName"`
+	FormattedAddress string `json:"formattedAddress"`
+	Location         *struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"location"`
+}
+
+// FetchItems returns the places named by the list's place IDs
+func (p *GoogleMapsProvider) FetchItems(ctx context.Context, list *models.List) ([]*models.ListItem, error) {
+	syncID := strings.TrimSpace(list.SyncID)
+	if syncID == "" {
+		return nil, fmt.Errorf("%w: Google Maps lists require a place ID", models.ErrMissingSyncID)
+	}
+
+	if strings.HasPrefix(syncID, googleMapsListPrefix) {
+		return nil, fmt.Errorf("%w: Google Maps saved lists cannot be synced; use place IDs instead", models.ErrInvalidSyncConfig)
+	}
+
+	var places []googlePlace
+	for _, placeID := range strings.Split(syncID, ",") {
+		placeID = strings.TrimSpace(placeID)
+		if placeID == "" {
+			continue
+		}
+		var place googlePlace
+		if err := p.get(ctx, "/v1/places/"+url.PathEscape(placeID), &place); err != nil {
+			return nil, err
+		}
+		places = append(places, place)
+	}
+
+	items := make([]*models.ListItem, 0, len(places))
+	for _, place := range places {
+		if place.ID == "" {
+			return nil, fmt.Errorf("%w: place without an ID", models.ErrExternalSourceError)
+		}
+		items = append(items, place.toListItem())
+	}
+
+	return items, nil
+}
+
+func (place googlePlace) toListItem() *models.ListItem {
+	item := &models.ListItem{
+		ExternalID: place.ID,
+		Name:       place.DisplayName.Text,
+	}
+	if place.FormattedAddress != "" {
+		address := place.FormattedAddress
+		item.Address = &address
+	}
+	if place.Location != nil {
+		lat, lng := place.Location.Latitude, place.Location.Longitude
+		item.Latitude = &lat
+		item.Longitude = &lng
+	}
+	return item
+}
+
+// get fetches a Places API resource, mapping transport and HTTP failures onto
+// the external source errors
+func (p *GoogleMapsProvider) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrExternalSourceError, err)
+	}
+	req.Header.Set("X-Goog-Api-Key", p.apiKey)
+	req.Header.Set("X-Goog-FieldMask", googleMapsFieldMask)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return fmt.Errorf("%w: %v", models.ErrExternalSourceTimeout, err)
+		}
+		return fmt.Errorf("%w: %v", models.ErrExternalSourceUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: Google Maps returned %d", models.ErrExternalSourceTimeout, resp.StatusCode)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%w: Google Maps returned %d", models.ErrExternalSourceUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w: Google Maps returned %d for %s", models.ErrExternalSourceError, resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: invalid response: %v", models.ErrExternalSourceError, err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleMapsProvider(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/places/cafe", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("X-Goog-Api-Key"))
+		assert.NotEmpty(t, r.Header.Get("X-Goog-FieldMask"))
+		_, _ = w.Write([]byte(`{
+			"id": "cafe",
+			"displayName": {"text": "Corner Cafe"},
+			"formattedAddress": "1 Main St",
+			"location": {"latitude": 40.7, "longitude": -74.0}
+		}`))
+	})
+	mux.HandleFunc("/v1/places/park", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "park", "displayName": {"text": "Park"}}`))
+	})
+	mux.HandleFunc("/v1/places/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	mux.HandleFunc("/v1/places/down", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/v1/places/limited", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/v1/places/gateway", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "timeout", http.StatusGatewayTimeout)
+	})
+	mux.HandleFunc("/v1/places/garbled", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": `))
+	})
+	mux.HandleFunc("/v1/places/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := NewGoogleMapsProvider(server.URL, "test-key", 200*time.Millisecond)
+	ctx := context.Background()
+
+	fetch := func(syncID string) ([]*models.ListItem, error) {
+		return provider.FetchItems(ctx, &models.List{SyncSource: models.SyncSourceGoogleMaps, SyncID: syncID})
+	}
+
+	t.Run("Places by ID", func(t *testing.T) {
+		items, err := fetch("cafe, park")
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		cafe := items[0]
+		assert.Equal(t, "cafe", cafe.ExternalID)
+		assert.Equal(t, "Corner Cafe", cafe.Name)
+		require.NotNil(t, cafe.Address)
+		assert.Equal(t, "1 Main St", *cafe.Address)
+		require.NotNil(t, cafe.Latitude)
+		require.NotNil(t, cafe.Longitude)
+		assert.Equal(t, 40.7, *cafe.Latitude)
+		assert.Equal(t, -74.0, *cafe.Longitude)
+
+		park := items[1]
+		assert.Equal(t, "park", park.ExternalID)
+		assert.Nil(t, park.Address)
+		assert.Nil(t, park.Latitude)
+	})
+
+	t.Run("Saved lists are not supported", func(t *testing.T) {
+		_, err := fetch("list:weekend")
+		assert.ErrorIs(t, err, models.ErrInvalidSyncConfig)
+	})
+
+	t.Run("Error mapping", func(t *testing.T) {
+		tests := []struct {
+			syncID  string
+			wantErr error
+		}{
+			{"missing", models.ErrExternalSourceError},
+			{"garbled", models.ErrExternalSourceError},
+			{"down", models.ErrExternalSourceUnavailable},
+			{"limited", models.ErrExternalSourceUnavailable},
+			{"gateway", models.ErrExternalSourceTimeout},
+			{"slow", models.ErrExternalSourceTimeout},
+		}
+		for _, tt := range tests {
+			t.Run(tt.syncID, func(t *testing.T) {
+				_, err := fetch(tt.syncID)
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("Unreachable server", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		_, err := NewGoogleMapsProvider(closed.URL, "test-key", time.Second).
+			FetchItems(ctx, &models.List{SyncID: "cafe"})
+		assert.ErrorIs(t, err, models.ErrExternalSourceUnavailable)
+	})
+
+	t.Run("Missing sync ID", func(t *testing.T) {
+		_, err := fetch("")
+		assert.ErrorIs(t, err, models.ErrMissingSyncID)
+	})
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// SyncProvider fetches the items of a list from an external source
+type SyncProvider interface {
+	// Source returns the sync source this provider serves
+	Source() models.SyncSource
+	// FetchItems returns the remote items for a list. Each item must carry the
+	// ExternalID used to match it against local items.
+	FetchItems(ctx context.Context, list *models.List) ([]*models.ListItem, error)
+}
+
+// SyncProviderRegistry holds the sync providers keyed by source
+type SyncProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[models.SyncSource]SyncProvider
+}
+
+// NewSyncProviderRegistry creates a registry containing the given providers
+func NewSyncProviderRegistry(providers ...SyncProvider) *SyncProviderRegistry {
+	registry := &SyncProviderRegistry{
+		providers: make(map[models.SyncSource]SyncProvider, len(providers)),
+	}
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+	return registry
+}
+
+// Register adds a provider, replacing any provider for the same source
+func (r *SyncProviderRegistry) Register(provider SyncProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[provider.Source()] = provider
+}
+
+// Get returns the provider for a source
+func (r *SyncProviderRegistry) Get(source models.SyncSource) (SyncProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[source]
+	return provider, ok
+}
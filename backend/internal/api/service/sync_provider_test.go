@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSyncFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+}
+
+func TestSyncProviderRegistry(t *testing.T) {
+	registry := NewSyncProviderRegistry()
+	_, ok := registry.Get(models.SyncSourceImported)
+	assert.False(t, ok)
+
+	provider := NewFileSyncProvider(t.TempDir())
+	registry.Register(provider)
+
+	found, ok := registry.Get(models.SyncSourceImported)
+	require.True(t, ok)
+	assert.Same(t, provider, found)
+}
+
+func TestFileSyncProvider(t *testing.T) {
+	dir := t.TempDir()
+	provider := NewFileSyncProvider(dir)
+	ctx := context.Background()
+
+	fetch := func(syncID string) ([]*models.ListItem, error) {
+		return provider.FetchItems(ctx, &models.List{SyncSource: models.SyncSourceImported, SyncID: syncID})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		writeSyncFile(t, dir, "places.json", `[
+			{"external_id": "p1", "name": "Cafe", "latitude": 40.7, "longitude": -74.0, "address": "1 Main St"},
+			{"external_id": "p2", "name": "Park", "description": "Big and green"}
+		]`)
+
+		items, err := fetch("places.json")
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "p1", items[0].ExternalID)
+		require.NotNil(t, items[0].Latitude)
+		assert.Equal(t, 40.7, *items[0].Latitude)
+		assert.Equal(t, "Big and green", items[1].Description)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		writeSyncFile(t, dir, "places.csv", "external_id,name,latitude,longitude,address\n"+
+			"p1,Cafe,40.7,-74.0,1 Main St\n"+
+			"p2,Park,,,\n")
+
+		items, err := fetch("places.csv")
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "Cafe", items[0].Name)
+		require.NotNil(t, items[0].Address)
+		assert.Equal(t, "1 Main St", *items[0].Address)
+		assert.Nil(t, items[1].Latitude)
+		assert.Nil(t, items[1].Address)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := fetch("nope.json")
+		assert.ErrorIs(t, err, models.ErrExternalSourceUnavailable)
+	})
+
+	t.Run("Sync ID cannot escape the root", func(t *testing.T) {
+		outside := filepath.Join(filepath.Dir(dir), "outside.json")
+		require.NoError(t, os.WriteFile(outside, []byte(`[]`), 0o600))
+		defer os.Remove(outside)
+
+		_, err := fetch("../outside.json")
+		assert.ErrorIs(t, err, models.ErrExternalSourceUnavailable)
+	})
+
+	t.Run("Malformed files", func(t *testing.T) {
+		writeSyncFile(t, dir, "broken.json", `{"external_id": "p1"`)
+		_, err := fetch("broken.json")
+		assert.ErrorIs(t, err, models.ErrExternalSourceError)
+
+		writeSyncFile(t, dir, "anonymous.json", `[{"name": "No ID"}]`)
+		_, err = fetch("anonymous.json")
+		assert.ErrorIs(t, err, models.ErrExternalSourceError)
+
+		writeSyncFile(t, dir, "badcoords.csv", "external_id,name,latitude\np1,Cafe,north\n")
+		_, err = fetch("badcoords.csv")
+		assert.ErrorIs(t, err, models.ErrExternalSourceError)
+
+		writeSyncFile(t, dir, "places.txt", "p1")
+		_, err = fetch("places.txt")
+		assert.ErrorIs(t, err, models.ErrInvalidSyncConfig)
+	})
+}
+
+func TestSyncService_SyncList(t *testing.T) {
+	ctx := context.Background()
+	listID := uuid.New()
+
+	setup := func(t *testing.T, contents string) (*SyncService, *testutil.MockListRepository) {
+		dir := t.TempDir()
+		writeSyncFile(t, dir, "places.json", contents)
+		mockRepo := new(testutil.MockListRepository)
+		return NewSyncService(mockRepo, NewFileSyncProvider(dir)), mockRepo
+	}
+	newList := func(status models.ListSyncStatus) *models.List {
+		return createValidTestList(listID, status, models.SyncSourceImported, "places.json")
+	}
+	newItem := func(externalID, name string, updatedAt time.Time) *models.ListItem {
+		return &models.ListItem{ID: uuid.New(), ListID: listID, ExternalID: externalID, Name: name, Weight: 1, UpdatedAt: updatedAt}
+	}
+
+	t.Run("Creates, updates and deletes by external ID", func(t *testing.T) {
+		service, mockRepo := setup(t, `[
+			{"external_id": "a", "name": "New A"},
+			{"external_id": "b", "name": "B"},
+			{"external_id": "e", "name": "E"}
+		]`)
+
+		stale := time.Now().Add(-48 * time.Hour)
+		itemA := newItem("a", "Old A", stale)
+		itemB := newItem("b", "B", stale)
+		itemC := newItem("c", "C", stale)
+		localOnly := newItem("", "Local only", stale)
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA, itemB, itemC, localOnly}, nil).Once()
+		mockRepo.On("AddItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			return item.ExternalID == "e" && item.Name == "E" && item.ListID == listID && item.Available && item.Weight > 0 &&
+				item.SyncSnapshot != nil && item.SyncSnapshot.Name == "E"
+		}), (*uuid.UUID)(nil)).Return(nil).Once()
+		mockRepo.On("UpdateItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			return item.ID == itemA.ID && item.Name == "New A" && item.SyncSnapshot.Name == "New A"
+		})).Return(nil).Once()
+		// Unchanged items still get a sync base recorded
+		mockRepo.On("UpdateItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			return item.ID == itemB.ID && item.SyncSnapshot != nil && item.SyncSnapshot.Name == "B"
+		})).Return(nil).Once()
+		mockRepo.On("RemoveItem", listID, itemC.ID).Return(nil).Once()
+		mockRepo.On("RecordSync", listID, 1, 1, 1).Return(nil).Once()
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusSynced && l.LastSyncAt != nil
+		})).Return(nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, &SyncResult{Created: 1, Updated: 1, Deleted: 1}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Items without a sync base edited since the last sync become conflicts", func(t *testing.T) {
+		service, mockRepo := setup(t, `[{"external_id": "a", "name": "Remote A"}]`)
+
+		itemA := newItem("a", "Local A", time.Now())
+		itemC := newItem("c", "Local C", time.Now())
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusSynced), nil).Twice()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusPending
+		})).Return(nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA, itemC}, nil).Once()
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusConflict
+		})).Return(nil).Once()
+		mockRepo.On("CreateConflict", mock.MatchedBy(func(c *models.SyncConflict) bool {
+			return c.Type == models.ConflictTypeItemUpdate && *c.ItemID == itemA.ID &&
+				assert.ObjectsAreEqual(models.JSONMap{"name": "Local A"}, c.LocalData) &&
+				assert.ObjectsAreEqual(models.JSONMap{"name": "Remote A"}, c.RemoteData)
+		})).Return(nil).Once()
+		mockRepo.On("CreateConflict", mock.MatchedBy(func(c *models.SyncConflict) bool {
+			return c.Type == models.ConflictTypeItemDelete && *c.ItemID == itemC.ID
+		})).Return(nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Conflicts)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "UpdateItem", mock.Anything)
+		mockRepo.AssertNotCalled(t, "RemoveItem", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unavailable source leaves items untouched", func(t *testing.T) {
+		mockRepo := new(testutil.MockListRepository)
+		service := NewSyncService(mockRepo, NewFileSyncProvider(t.TempDir()))
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+
+		_, err := service.SyncList(ctx, listID)
+		assert.ErrorIs(t, err, models.ErrExternalSourceUnavailable)
+		mockRepo.AssertNotCalled(t, "GetItems", mock.Anything)
+	})
+
+	t.Run("Source without a provider", func(t *testing.T) {
+		mockRepo := new(testutil.MockListRepository)
+		service := NewSyncService(mockRepo)
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+
+		_, err := service.SyncList(ctx, listID)
+		assert.ErrorIs(t, err, models.ErrInvalidSyncSource)
+	})
+
+	t.Run("Edits to different fields merge", func(t *testing.T) {
+		service, mockRepo := setup(t, `[
+			{"external_id": "a", "name": "Cafe", "address": "2 Side St"},
+			{"external_id": "b", "name": "Park"}
+		]`)
+
+		oldAddress := "1 Main St"
+		base := &models.SyncSnapshot{Name: "Cafe", Address: &oldAddress}
+		itemA := newItem("a", "Cafe", time.Now())
+		itemA.Description = "Our favourite"
+		itemA.Address = &oldAddress
+		itemA.SyncSnapshot = base
+		// Removed remotely but untouched locally, despite a recent update
+		itemC := newItem("c", "C", time.Now())
+		itemC.SyncSnapshot = &models.SyncSnapshot{Name: "C"}
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA, itemC}, nil).Once()
+		mockRepo.On("AddItem", mock.AnythingOfType("*models.ListItem"), (*uuid.UUID)(nil)).Return(nil).Once()
+		mockRepo.On("UpdateItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			return item.ID == itemA.ID && item.Description == "Our favourite" && *item.Address == "2 Side St" &&
+				item.SyncSnapshot.Description == "" && *item.SyncSnapshot.Address == "2 Side St"
+		})).Return(nil).Once()
+		mockRepo.On("RemoveItem", listID, itemC.ID).Return(nil).Once()
+		mockRepo.On("RecordSync", listID, 1, 1, 1).Return(nil).Once()
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusSynced
+		})).Return(nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, &SyncResult{Created: 1, Updated: 1, Deleted: 1}, result)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateConflict", mock.Anything)
+	})
+
+	t.Run("Only colliding fields become conflicts", func(t *testing.T) {
+		service, mockRepo := setup(t, `[{"external_id": "a", "name": "Their Cafe", "address": "2 Side St"}]`)
+
+		itemA := newItem("a", "Our Cafe", time.Now())
+		itemA.SyncSnapshot = &models.SyncSnapshot{Name: "Cafe"}
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA}, nil).Once()
+		mockRepo.On("UpdateItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			// The address merges; the name keeps its old base until resolved
+			return item.Name == "Our Cafe" && *item.Address == "2 Side St" &&
+				item.SyncSnapshot.Name == "Cafe" && *item.SyncSnapshot.Address == "2 Side St"
+		})).Return(nil).Once()
+		mockRepo.On("RecordSync", listID, 0, 1, 0).Return(nil).Once()
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusConflict
+		})).Return(nil).Once()
+		mockRepo.On("CreateConflict", mock.MatchedBy(func(c *models.SyncConflict) bool {
+			return assert.ObjectsAreEqual(models.JSONMap{"name": "Our Cafe"}, c.LocalData) &&
+				assert.ObjectsAreEqual(models.JSONMap{"name": "Their Cafe"}, c.RemoteData)
+		})).Return(nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, &SyncResult{Updated: 1, Conflicts: 1}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Conflicted list auto-resolves once nothing collides", func(t *testing.T) {
+		service, mockRepo := setup(t, `[{"external_id": "a", "name": "Our Cafe"}]`)
+
+		itemA := newItem("a", "Our Cafe", time.Now())
+		itemA.SyncSnapshot = &models.SyncSnapshot{Name: "Cafe"}
+		open := newSyncConflict(itemA, models.ConflictTypeItemUpdate,
+			models.JSONMap{"name": "Our Cafe"}, models.JSONMap{"name": "Their Cafe"})
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusConflict), nil).Once()
+		mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{open}, nil).Once()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA}, nil).Once()
+		mockRepo.On("UpdateItem", mock.MatchedBy(func(item *models.ListItem) bool {
+			return item.SyncSnapshot.Name == "Our Cafe"
+		})).Return(nil).Once()
+		mockRepo.On("ResolveConflict", open.ID, models.ResolutionMergeFields, (*models.ResolvedItem)(nil)).Return(nil).Once()
+		mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{}, nil).Once()
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusConflict), nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
+			return l.SyncStatus == models.ListSyncStatusSynced
+		})).Return(nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Conflicts)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "RecordSync", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Conflicted list stays in conflict while collisions remain", func(t *testing.T) {
+		service, mockRepo := setup(t, `[{"external_id": "a", "name": "Their Cafe"}]`)
+
+		itemA := newItem("a", "Our Cafe", time.Now())
+		itemA.SyncSnapshot = &models.SyncSnapshot{Name: "Cafe"}
+		open := newSyncConflict(itemA, models.ConflictTypeItemUpdate,
+			models.JSONMap{"name": "Our Cafe"}, models.JSONMap{"name": "Their Cafe"})
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusConflict), nil).Once()
+		mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{open}, nil).Twice()
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{itemA}, nil).Once()
+
+		result, err := service.SyncList(ctx, listID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Conflicts)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateConflict", mock.Anything)
+		mockRepo.AssertNotCalled(t, "ResolveConflict", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("ListService syncs through the provider", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSyncFile(t, dir, "places.json", `[{"external_id": "a", "name": "A"}]`)
+		mockRepo := new(testutil.MockListRepository)
+
+		mockRepo.On("GetByID", listID).Return(newList(models.ListSyncStatusPending), nil).Times(3)
+		mockRepo.On("GetItems", listID).Return([]*models.ListItem{}, nil).Once()
+		mockRepo.On("AddItem", mock.AnythingOfType("*models.ListItem"), (*uuid.UUID)(nil)).Return(nil).Once()
+		mockRepo.On("RecordSync", listID, 1, 0, 0).Return(nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.List")).Return(nil).Once()
+
+		err := NewListService(mockRepo, NewFileSyncProvider(dir)).SyncList(listID)
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
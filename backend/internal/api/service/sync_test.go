@@ -663,10 +663,12 @@ func TestSyncService_ResolveConflict(t *testing.T) {
 		{
 			name:       "resolve valid conflict",
 			conflictID: conflictID,
-			resolution: "local",
+			resolution: models.ResolutionKeepLocal,
 			setup: func() {
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{createValidConflict(false)}, nil).Once()
-				mockRepo.On("ResolveConflict", conflictID).Return(nil).Once()
+				mockRepo.On("GetConflict", conflictID).Return(createValidConflict(false), nil).Once()
+				mockRepo.On("GetItems", listID).Return([]*models.ListItem{}, nil).Once()
+				mockRepo.On("ResolveConflict", conflictID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(nil).Once()
+				mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{}, nil).Once()
 				mockRepo.On("GetByID", listID).Return(createValidTestList(listID, models.ListSyncStatusConflict, models.SyncSourceGoogleMaps, "place123"), nil).Once()
 				mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
 					return l.ID == listID &&
@@ -678,12 +680,14 @@ func TestSyncService_ResolveConflict(t *testing.T) {
 		{
 			name:       "concurrent modification",
 			conflictID: conflictID,
-			resolution: "local",
+			resolution: models.ResolutionKeepLocal,
 			setup: func() {
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{createValidConflict(false)}, nil).Once()
-				mockRepo.On("ResolveConflict", conflictID).Return(models.ErrConcurrentModification).Once()
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{createValidConflict(false)}, nil).Once()
-				mockRepo.On("ResolveConflict", conflictID).Return(nil).Once()
+				mockRepo.On("GetConflict", conflictID).Return(createValidConflict(false), nil).Once()
+				mockRepo.On("GetItems", listID).Return([]*models.ListItem{}, nil).Once()
+				mockRepo.On("ResolveConflict", conflictID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(models.ErrConcurrentModification).Once()
+				mockRepo.On("GetConflict", conflictID).Return(createValidConflict(false), nil).Once()
+				mockRepo.On("ResolveConflict", conflictID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(nil).Once()
+				mockRepo.On("GetConflicts", listID).Return([]*models.SyncConflict{}, nil).Once()
 				mockRepo.On("GetByID", listID).Return(createValidTestList(listID, models.ListSyncStatusConflict, models.SyncSourceGoogleMaps, "place123"), nil).Once()
 				mockRepo.On("Update", mock.MatchedBy(func(l *models.List) bool {
 					return l.ID == listID &&
@@ -695,33 +699,43 @@ func TestSyncService_ResolveConflict(t *testing.T) {
 		{
 			name:       "conflict not found",
 			conflictID: conflictID,
-			resolution: "local",
+			resolution: models.ResolutionKeepLocal,
 			setup: func() {
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{}, nil).Once()
+				mockRepo.On("GetConflict", conflictID).Return(nil, models.ErrConflictNotFound).Once()
 			},
 			wantErr: true,
 			errCheck: func(err error) bool {
-				return errors.Is(err, models.ErrNotFound)
+				return errors.Is(err, models.ErrConflictNotFound)
 			},
 		},
 		{
 			name:       "already resolved",
 			conflictID: conflictID,
-			resolution: "local",
+			resolution: models.ResolutionKeepLocal,
 			setup: func() {
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{createValidConflict(true)}, nil).Once()
+				mockRepo.On("GetConflict", conflictID).Return(createValidConflict(true), nil).Once()
 			},
 			wantErr: true,
 			errCheck: func(err error) bool {
 				return errors.Is(err, models.ErrConflictAlreadyResolved)
 			},
 		},
+		{
+			name:       "unknown resolution",
+			conflictID: conflictID,
+			resolution: "local",
+			setup:      func() {},
+			wantErr:    true,
+			errCheck: func(err error) bool {
+				return errors.Is(err, models.ErrInvalidResolution)
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := service.ResolveConflict(ctx, tt.conflictID, tt.resolution)
+			err := service.ResolveConflict(ctx, tt.conflictID, tt.resolution, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 				if tt.errCheck != nil {
@@ -923,34 +937,30 @@ func TestSyncService_ConcurrentOperations(t *testing.T) {
 		{
 			name: "concurrent conflict resolution",
 			setup: func() {
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{
-					{
-						ID:         conflictID,
-						ListID:     listID,
-						Type:       "item_update",
-						LocalData:  "Local Data",
-						RemoteData: "Remote Data",
-						CreatedAt:  now,
-						UpdatedAt:  now,
-					},
+				mockRepo.On("GetConflict", conflictID).Return(&models.SyncConflict{
+					ID:         conflictID,
+					ListID:     listID,
+					Type:       "item_update",
+					LocalData:  "Local Data",
+					RemoteData: "Remote Data",
+					CreatedAt:  now,
+					UpdatedAt:  now,
 				}, nil).Once()
-				mockRepo.On("ResolveConflict", conflictID).Return(models.ErrConcurrentModification).Once()
+				mockRepo.On("ResolveConflict", conflictID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(models.ErrConcurrentModification).Once()
 				resolvedAt := now
-				mockRepo.On("GetConflicts", conflictID).Return([]*models.SyncConflict{
-					{
-						ID:         conflictID,
-						ListID:     listID,
-						Type:       "item_update",
-						LocalData:  "Local Data",
-						RemoteData: "Remote Data",
-						CreatedAt:  now,
-						UpdatedAt:  now,
-						ResolvedAt: &resolvedAt,
-					},
+				mockRepo.On("GetConflict", conflictID).Return(&models.SyncConflict{
+					ID:         conflictID,
+					ListID:     listID,
+					Type:       "item_update",
+					LocalData:  "Local Data",
+					RemoteData: "Remote Data",
+					CreatedAt:  now,
+					UpdatedAt:  now,
+					ResolvedAt: &resolvedAt,
 				}, nil).Once()
 			},
 			action: func() error {
-				return service.ResolveConflict(ctx, conflictID, "local")
+				return service.ResolveConflict(ctx, conflictID, models.ResolutionKeepLocal, nil)
 			},
 			wantErr: true,
 			check: func(err error) bool {
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// TribeAuthorizer decides what a user may do in a tribe from their role and
+// membership type. Handlers ask it instead of inspecting memberships
+// themselves.
+type TribeAuthorizer interface {
+	// Authorize returns the user's membership if it grants the permission.
+	// It returns ErrNotFound for an unknown tribe, ErrNotTribeMember when the
+	// user has no active membership and ErrTribePermission otherwise.
+	Authorize(userID, tribeID uuid.UUID, permission models.TribePermission) (*models.TribeMember, error)
+	// AuthorizeRemoval checks that the actor may remove the target member
+	AuthorizeRemoval(actorID, tribeID, targetID uuid.UUID) error
+	// AuthorizedTribes returns the tribes in which the user holds the
+	// permission
+	AuthorizedTribes(userID uuid.UUID, permission models.TribePermission) (map[uuid.UUID]bool, error)
+}
+
+// tribeAuthorizer implements the TribeAuthorizer interface
+type tribeAuthorizer struct {
+	tribes models.TribeRepository
+	now    func() time.Time
+}
+
+// NewTribeAuthorizer creates a new tribe authorizer
+func NewTribeAuthorizer(tribes models.TribeRepository) TribeAuthorizer {
+	return &tribeAuthorizer{
+		tribes: tribes,
+		now:    time.Now,
+	}
+}
+
+// Authorize implements TribeAuthorizer
+func (a *tribeAuthorizer) Authorize(userID, tribeID uuid.UUID, permission models.TribePermission) (*models.TribeMember, error) {
+	members, err := a.members(tribeID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := findMember(members, userID)
+	now := a.now()
+	if member == nil || !member.IsActive(now) {
+		return nil, models.ErrNotTribeMember
+	}
+	if !member.Can(permission, now) {
+		return nil, models.ErrTribePermission
+	}
+
+	return member, nil
+}
+
+// AuthorizeRemoval implements TribeAuthorizer
+func (a *tribeAuthorizer) AuthorizeRemoval(actorID, tribeID, targetID uuid.UUID) error {
+	members, err := a.members(tribeID)
+	if err != nil {
+		return err
+	}
+
+	now := a.now()
+	actor := findMember(members, actorID)
+	if actor == nil || !actor.IsActive(now) {
+		return models.ErrNotTribeMember
+	}
+	target := findMember(members, targetID)
+	if target == nil {
+		return fmt.Errorf("%w: tribe member not found", models.ErrNotFound)
+	}
+	if !actor.CanRemove(target, now) {
+		return models.ErrTribePermission
+	}
+
+	return nil
+}
+
+// AuthorizedTribes implements TribeAuthorizer
+func (a *tribeAuthorizer) AuthorizedTribes(userID uuid.UUID, permission models.TribePermission) (map[uuid.UUID]bool, error) {
+	tribes, err := a.tribes.GetUserTribes(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user tribes: %w", err)
+	}
+
+	now := a.now()
+	authorized := make(map[uuid.UUID]bool, len(tribes))
+	for _, tribe := range tribes {
+		if member := findMember(tribe.Members, userID); member != nil && member.Can(permission, now) {
+			authorized[tribe.ID] = true
+		}
+	}
+
+	return authorized, nil
+}
+
+// members loads a tribe's members, translating the repository's missing
+// tribe error
+func (a *tribeAuthorizer) members(tribeID uuid.UUID) ([]*models.TribeMember, error) {
+	tribe, err := a.tribes.GetByID(tribeID)
+	if err != nil {
+		if err.Error() == "tribe not found" {
+			return nil, fmt.Errorf("%w: tribe not found", models.ErrNotFound)
+		}
+		return nil, fmt.Errorf("error getting tribe: %w", err)
+	}
+	return tribe.Members, nil
+}
+
+// findMember returns the user's membership among members, or nil
+func findMember(members []*models.TribeMember, userID uuid.UUID) *models.TribeMember {
+	for _, member := range members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeAuthorizer(t *testing.T) {
+	tribeID, otherTribeID := uuid.New(), uuid.New()
+	owner, admin, member, limited, guest, pending, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	expired := time.Now().Add(-time.Hour)
+
+	members := []*models.TribeMember{
+		{TribeID: tribeID, UserID: owner, MembershipType: models.MembershipFull, Role: models.TribeRoleOwner},
+		{TribeID: tribeID, UserID: admin, MembershipType: models.MembershipFull, Role: models.TribeRoleAdmin},
+		{TribeID: tribeID, UserID: member, MembershipType: models.MembershipFull, Role: models.TribeRoleMember},
+		{TribeID: tribeID, UserID: limited, MembershipType: models.MembershipLimited, Role: models.TribeRoleMember},
+		{TribeID: tribeID, UserID: guest, MembershipType: models.MembershipGuest, Role: models.TribeRoleMember, ExpiresAt: &expired},
+		{TribeID: tribeID, UserID: pending, MembershipType: models.MembershipPending, Role: models.TribeRoleMember},
+	}
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+		if id != tribeID {
+			return nil, errors.New("tribe not found")
+		}
+		return &models.Tribe{BaseModel: models.BaseModel{ID: tribeID}, Members: members}, nil
+	}
+	tribes.GetUserTribesFunc = func(userID uuid.UUID) ([]*models.Tribe, error) {
+		return []*models.Tribe{
+			{BaseModel: models.BaseModel{ID: tribeID}, Members: members},
+			{BaseModel: models.BaseModel{ID: otherTribeID}, Members: []*models.TribeMember{
+				{TribeID: otherTribeID, UserID: limited, MembershipType: models.MembershipGuest},
+			}},
+		}, nil
+	}
+	authz := NewTribeAuthorizer(tribes)
+
+	t.Run("Authorize", func(t *testing.T) {
+		got, err := authz.Authorize(owner, tribeID, models.PermissionDeleteTribe)
+		require.NoError(t, err)
+		assert.Equal(t, owner, got.UserID)
+
+		_, err = authz.Authorize(admin, tribeID, models.PermissionUpdateTribe)
+		assert.NoError(t, err)
+		_, err = authz.Authorize(admin, tribeID, models.PermissionDeleteTribe)
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+
+		_, err = authz.Authorize(member, tribeID, models.PermissionInviteMembers)
+		assert.NoError(t, err)
+		_, err = authz.Authorize(limited, tribeID, models.PermissionInviteMembers)
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+
+		_, err = authz.Authorize(guest, tribeID, models.PermissionViewTribe)
+		assert.ErrorIs(t, err, models.ErrNotTribeMember)
+		_, err = authz.Authorize(pending, tribeID, models.PermissionViewTribe)
+		assert.ErrorIs(t, err, models.ErrNotTribeMember)
+		_, err = authz.Authorize(outsider, tribeID, models.PermissionViewTribe)
+		assert.ErrorIs(t, err, models.ErrNotTribeMember)
+
+		_, err = authz.Authorize(owner, uuid.New(), models.PermissionViewTribe)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("AuthorizeRemoval", func(t *testing.T) {
+		assert.NoError(t, authz.AuthorizeRemoval(owner, tribeID, admin))
+		assert.NoError(t, authz.AuthorizeRemoval(admin, tribeID, member))
+		assert.NoError(t, authz.AuthorizeRemoval(limited, tribeID, limited))
+		assert.ErrorIs(t, authz.AuthorizeRemoval(admin, tribeID, owner), models.ErrTribePermission)
+		assert.ErrorIs(t, authz.AuthorizeRemoval(member, tribeID, limited), models.ErrTribePermission)
+		assert.ErrorIs(t, authz.AuthorizeRemoval(outsider, tribeID, member), models.ErrNotTribeMember)
+		assert.ErrorIs(t, authz.AuthorizeRemoval(owner, tribeID, outsider), models.ErrNotFound)
+	})
+
+	t.Run("AuthorizedTribes", func(t *testing.T) {
+		viewable, err := authz.AuthorizedTribes(limited, models.PermissionViewTribe)
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]bool{tribeID: true, otherTribeID: true}, viewable)
+
+		contributable, err := authz.AuthorizedTribes(limited, models.PermissionContribute)
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]bool{tribeID: true}, contributable)
+	})
+}
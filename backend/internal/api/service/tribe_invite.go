@@ -0,0 +1,146 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// TribeInviteService defines the interface for shareable tribe invite links
+type TribeInviteService interface {
+	// CreateInvite issues an invite for the tribe on behalf of a member
+	// allowed to invite. The expiry defaults to DefaultInviteDuration.
+	CreateInvite(userID uuid.UUID, invite *models.TribeInvite) error
+	// ListInvites returns the tribe's invites with their tokens
+	ListInvites(userID, tribeID uuid.UUID) ([]*models.TribeInvite, error)
+	// RevokeInvite disables an invite. Its creator, tribe owners and admins
+	// may revoke it.
+	RevokeInvite(userID, tribeID, inviteID uuid.UUID) error
+	// RedeemInvite adds the user to the tribe an invite token is for.
+	// Tokens that fail verification are reported as ErrNotFound.
+	RedeemInvite(userID uuid.UUID, token string) (*models.TribeInvite, error)
+}
+
+// tribeInviteService implements the TribeInviteService interface
+type tribeInviteService struct {
+	invites models.TribeInviteRepository
+	authz   TribeAuthorizer
+	key     []byte
+	now     func() time.Time
+}
+
+// NewTribeInviteService creates a new tribe invite service that signs tokens
+// with the given key
+func NewTribeInviteService(invites models.TribeInviteRepository, authz TribeAuthorizer, key []byte) TribeInviteService {
+	return &tribeInviteService{
+		invites: invites,
+		authz:   authz,
+		key:     key,
+		now:     time.Now,
+	}
+}
+
+// CreateInvite implements TribeInviteService
+func (s *tribeInviteService) CreateInvite(userID uuid.UUID, invite *models.TribeInvite) error {
+	if _, err := s.authz.Authorize(userID, invite.TribeID, models.PermissionInviteMembers); err != nil {
+		return err
+	}
+
+	now := s.now()
+	invite.CreatedBy = userID
+	if invite.ExpiresAt.IsZero() {
+		invite.ExpiresAt = now.Add(models.DefaultInviteDuration)
+	}
+	if !invite.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiry must be in the future", models.ErrInvalidInput)
+	}
+	if invite.ExpiresAt.After(now.Add(models.MaxInviteDuration)) {
+		return fmt.Errorf("%w: invites cannot last longer than %d days", models.ErrInvalidInput, int(models.MaxInviteDuration.Hours()/24))
+	}
+
+	if err := s.invites.Create(invite); err != nil {
+		return err
+	}
+	invite.Token = s.sign(invite.ID)
+	return nil
+}
+
+// ListInvites implements TribeInviteService
+func (s *tribeInviteService) ListInvites(userID, tribeID uuid.UUID) ([]*models.TribeInvite, error) {
+	if _, err := s.authz.Authorize(userID, tribeID, models.PermissionInviteMembers); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.invites.ListByTribe(tribeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, invite := range invites {
+		invite.Token = s.sign(invite.ID)
+	}
+	return invites, nil
+}
+
+// RevokeInvite implements TribeInviteService
+func (s *tribeInviteService) RevokeInvite(userID, tribeID, inviteID uuid.UUID) error {
+	invite, err := s.invites.GetByID(inviteID)
+	if err != nil {
+		return err
+	}
+	if invite.TribeID != tribeID {
+		return models.ErrNotFound
+	}
+
+	permission := models.PermissionRemoveMembers
+	if invite.CreatedBy == userID {
+		permission = models.PermissionViewTribe
+	}
+	if _, err = s.authz.Authorize(userID, tribeID, permission); err != nil {
+		return err
+	}
+
+	return s.invites.Revoke(tribeID, inviteID)
+}
+
+// RedeemInvite implements TribeInviteService
+func (s *tribeInviteService) RedeemInvite(userID uuid.UUID, token string) (*models.TribeInvite, error) {
+	inviteID, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.invites.Redeem(inviteID, userID)
+}
+
+// sign returns the token for an invite: its ID and an HMAC of it
+func (s *tribeInviteService) sign(inviteID uuid.UUID) string {
+	return inviteID.String() + "." + base64.RawURLEncoding.EncodeToString(s.mac(inviteID))
+}
+
+// verify returns the invite ID a token was signed for
+func (s *tribeInviteService) verify(token string) (uuid.UUID, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, models.ErrNotFound
+	}
+	inviteID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, models.ErrNotFound
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, s.mac(inviteID)) {
+		return uuid.Nil, models.ErrNotFound
+	}
+	return inviteID, nil
+}
+
+func (s *tribeInviteService) mac(inviteID uuid.UUID) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte("tribe-invite\n" + inviteID.String()))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInviteRepository keeps tribe invites in memory
+type fakeInviteRepository struct {
+	invites  map[uuid.UUID]*models.TribeInvite
+	redeemed []uuid.UUID
+}
+
+func newFakeInviteRepository() *fakeInviteRepository {
+	return &fakeInviteRepository{invites: make(map[uuid.UUID]*models.TribeInvite)}
+}
+
+func (r *fakeInviteRepository) Create(invite *models.TribeInvite) error {
+	if err := invite.Validate(); err != nil {
+		return err
+	}
+	invite.ID = uuid.New()
+	stored := *invite
+	r.invites[invite.ID] = &stored
+	return nil
+}
+
+func (r *fakeInviteRepository) GetByID(id uuid.UUID) (*models.TribeInvite, error) {
+	invite, ok := r.invites[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	copied := *invite
+	return &copied, nil
+}
+
+func (r *fakeInviteRepository) ListByTribe(tribeID uuid.UUID) ([]*models.TribeInvite, error) {
+	var invites []*models.TribeInvite
+	for _, invite := range r.invites {
+		if invite.TribeID == tribeID {
+			copied := *invite
+			invites = append(invites, &copied)
+		}
+	}
+	return invites, nil
+}
+
+func (r *fakeInviteRepository) Revoke(tribeID, inviteID uuid.UUID) error {
+	invite, ok := r.invites[inviteID]
+	if !ok || invite.TribeID != tribeID {
+		return models.ErrNotFound
+	}
+	now := time.Now()
+	invite.RevokedAt = &now
+	return nil
+}
+
+func (r *fakeInviteRepository) Redeem(inviteID, userID uuid.UUID) (*models.TribeInvite, error) {
+	invite, ok := r.invites[inviteID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	if !invite.IsUsable(time.Now()) {
+		return nil, models.ErrInviteUnavailable
+	}
+	invite.UseCount++
+	r.redeemed = append(r.redeemed, userID)
+	copied := *invite
+	return &copied, nil
+}
+
+func TestTribeInviteService(t *testing.T) {
+	tribeID := uuid.New()
+	owner, member, limited, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tribes := testutil.NewMockTribeRepository()
+	tribes.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+		return &models.Tribe{BaseModel: models.BaseModel{ID: tribeID}, Members: []*models.TribeMember{
+			{TribeID: tribeID, UserID: owner, MembershipType: models.MembershipFull, Role: models.TribeRoleOwner},
+			{TribeID: tribeID, UserID: member, MembershipType: models.MembershipFull, Role: models.TribeRoleMember},
+			{TribeID: tribeID, UserID: limited, MembershipType: models.MembershipLimited, Role: models.TribeRoleMember},
+		}}, nil
+	}
+
+	repo := newFakeInviteRepository()
+	svc := NewTribeInviteService(repo, NewTribeAuthorizer(tribes), []byte("invite-key"))
+
+	t.Run("create signs a token", func(t *testing.T) {
+		invite := &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipFull}
+		require.NoError(t, svc.CreateInvite(member, invite))
+		assert.Equal(t, member, invite.CreatedBy)
+		assert.WithinDuration(t, time.Now().Add(models.DefaultInviteDuration), invite.ExpiresAt, time.Minute)
+		assert.True(t, strings.HasPrefix(invite.Token, invite.ID.String()+"."))
+
+		redeemed, err := svc.RedeemInvite(outsider, invite.Token)
+		require.NoError(t, err)
+		assert.Equal(t, tribeID, redeemed.TribeID)
+		assert.Equal(t, []uuid.UUID{outsider}, repo.redeemed)
+
+		invites, err := svc.ListInvites(owner, tribeID)
+		require.NoError(t, err)
+		require.Len(t, invites, 1)
+		assert.Equal(t, invite.Token, invites[0].Token)
+	})
+
+	t.Run("create validation", func(t *testing.T) {
+		err := svc.CreateInvite(limited, &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipFull})
+		assert.ErrorIs(t, err, models.ErrTribePermission)
+
+		err = svc.CreateInvite(owner, &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipFull, ExpiresAt: time.Now().Add(-time.Minute)})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		err = svc.CreateInvite(owner, &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipFull, ExpiresAt: time.Now().Add(models.MaxInviteDuration + time.Hour)})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		err = svc.CreateInvite(owner, &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipGuest})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("forged tokens are not found", func(t *testing.T) {
+		invite := &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipPending}
+		require.NoError(t, svc.CreateInvite(owner, invite))
+
+		other := NewTribeInviteService(repo, NewTribeAuthorizer(tribes), []byte("another-key"))
+		for _, token := range []string{
+			"",
+			invite.ID.String(),
+			invite.ID.String() + ".",
+			invite.ID.String() + ".not-base64!",
+			uuid.New().String() + invite.Token[36:],
+			other.(*tribeInviteService).sign(invite.ID),
+		} {
+			_, err := svc.RedeemInvite(outsider, token)
+			assert.ErrorIs(t, err, models.ErrNotFound, token)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		invite := &models.TribeInvite{TribeID: tribeID, MembershipType: models.MembershipFull, SingleUse: true}
+		require.NoError(t, svc.CreateInvite(member, invite))
+
+		assert.ErrorIs(t, svc.RevokeInvite(limited, tribeID, invite.ID), models.ErrTribePermission)
+		assert.ErrorIs(t, svc.RevokeInvite(member, uuid.New(), invite.ID), models.ErrNotFound)
+		require.NoError(t, svc.RevokeInvite(member, tribeID, invite.ID))
+		require.NoError(t, svc.RevokeInvite(owner, tribeID, invite.ID))
+
+		_, err := svc.RedeemInvite(outsider, invite.Token)
+		assert.ErrorIs(t, err, models.ErrInviteUnavailable)
+	})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// VotingService defines the interface for tribe votes on generated menus
+type VotingService interface {
+	// StartVote generates a menu for a tribe and opens it for voting
+	StartVote(userID, tribeID uuid.UUID, rule models.VotingRule, params *models.MenuParams) (*models.MenuSession, error)
+	// CastVotes records a member's votes, replacing earlier votes on the same items
+	CastVotes(userID, sessionID uuid.UUID, votes []*models.MenuVote) error
+	GetTally(userID, sessionID uuid.UUID) (*models.VoteTally, error)
+	// CloseVote settles the vote under the session's rule and marks the winner chosen
+	CloseVote(userID, sessionID uuid.UUID) (*models.MenuSession, *models.VoteTally, error)
+}
+
+// votingService implements the VotingService interface
+type votingService struct {
+	lists    ListService
+	access   ListAuthorizer
+	sessions models.MenuSessionRepository
+	tribes   models.TribeRepository
+}
+
+// NewVotingService creates a new voting service
+func NewVotingService(lists ListService, access ListAuthorizer, sessions models.MenuSessionRepository, tribes models.TribeRepository) VotingService {
+	return &votingService{
+		lists:    lists,
+		access:   access,
+		sessions: sessions,
+		tribes:   tribes,
+	}
+}
+
+// StartVote generates a menu for the tribe and records it with a voting rule
+func (s *votingService) StartVote(userID, tribeID uuid.UUID, rule models.VotingRule, params *models.MenuParams) (*models.MenuSession, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if len(params.ListIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one list ID is required", models.ErrInvalidInput)
+	}
+
+	voters, err := s.eligibleVoters(tribeID)
+	if err != nil {
+		return nil, err
+	}
+	if !voters[userID] {
+		return nil, models.ErrForbidden
+	}
+
+	if err := authorizeMenuLists(s.access, userID, params.ListIDs); err != nil {
+		return nil, err
+	}
+
+	lists, err := s.lists.GenerateMenu(params)
+	if err != nil {
+		return nil, err
+	}
+
+	session := newMenuSession(userID, &tribeID, params, lists)
+	session.VotingRule = &rule
+
+	if err := s.sessions.Create(session); err != nil {
+		return nil, fmt.Errorf("error saving menu session: %w", err)
+	}
+
+	return session, nil
+}
+
+// CastVotes records the user's votes on an open session
+func (s *votingService) CastVotes(userID, sessionID uuid.UUID, votes []*models.MenuVote) error {
+	if len(votes) == 0 {
+		return fmt.Errorf("%w: at least one vote is required", models.ErrInvalidInput)
+	}
+
+	session, voters, err := s.openSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if !voters[userID] {
+		return models.ErrForbidden
+	}
+
+	for _, vote := range votes {
+		vote.SessionID = sessionID
+		vote.UserID = userID
+		if err := vote.Validate(); err != nil {
+			return err
+		}
+		if !session.Items.Contains(vote.ItemID) {
+			return fmt.Errorf("%w: item %s was not offered in this menu", models.ErrInvalidInput, vote.ItemID)
+		}
+	}
+
+	if err := s.validateBallot(sessionID, userID, votes); err != nil {
+		return err
+	}
+
+	return s.sessions.CastVotes(votes)
+}
+
+// validateBallot checks the new votes together with the user's earlier votes
+// on other items, which they leave in place
+func (s *votingService) validateBallot(sessionID, userID uuid.UUID, votes []*models.MenuVote) error {
+	if err := models.ValidateBallot(votes); err != nil {
+		return err
+	}
+
+	ranked := false
+	replaced := make(map[uuid.UUID]bool, len(votes))
+	for _, vote := range votes {
+		replaced[vote.ItemID] = true
+		ranked = ranked || vote.Kind == models.VoteRank
+	}
+	if !ranked {
+		return nil
+	}
+
+	existing, err := s.sessions.GetVotes(sessionID)
+	if err != nil {
+		return fmt.Errorf("error getting votes: %w", err)
+	}
+	ballot := append([]*models.MenuVote{}, votes...)
+	for _, vote := range existing {
+		if vote.UserID == userID && !replaced[vote.ItemID] {
+			ballot = append(ballot, vote)
+		}
+	}
+
+	return models.ValidateBallot(ballot)
+}
+
+// GetTally counts the votes so far without closing the vote
+func (s *votingService) GetTally(userID, sessionID uuid.UUID) (*models.VoteTally, error) {
+	session, voters, err := s.votingSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !voters[userID] && session.UserID != userID {
+		return nil, models.ErrForbidden
+	}
+
+	return s.tally(session, voters)
+}
+
+// CloseVote picks the winner and marks it chosen. When no item satisfies the
+// rule the vote stays open and ErrNoVoteWinner is returned with the tally.
+func (s *votingService) CloseVote(userID, sessionID uuid.UUID) (*models.MenuSession, *models.VoteTally, error) {
+	session, voters, err := s.openSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !voters[userID] {
+		return nil, nil, models.ErrForbidden
+	}
+
+	tally, err := s.tally(session, voters)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tally.WinnerID == nil {
+		return nil, tally, models.ErrNoVoteWinner
+	}
+
+	if err := s.sessions.SetChosenItem(sessionID, *tally.WinnerID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	session, err = s.sessions.GetByID(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, tally, nil
+}
+
+// votingSession loads a session that was opened for voting along with its
+// tribe's eligible voters
+func (s *votingService) votingSession(sessionID uuid.UUID) (*models.MenuSession, map[uuid.UUID]bool, error) {
+	session, err := s.sessions.GetByID(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.VotingRule == nil || session.TribeID == nil {
+		return nil, nil, fmt.Errorf("%w: this menu is not open for voting", models.ErrInvalidInput)
+	}
+
+	voters, err := s.eligibleVoters(*session.TribeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, voters, nil
+}
+
+// openSession is votingSession for sessions that have not been settled yet
+func (s *votingService) openSession(sessionID uuid.UUID) (*models.MenuSession, map[uuid.UUID]bool, error) {
+	session, voters, err := s.votingSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.IsClosed() {
+		return nil, nil, fmt.Errorf("%w: voting on this menu has closed", models.ErrConflict)
+	}
+	return session, voters, nil
+}
+
+func (s *votingService) tally(session *models.MenuSession, voters map[uuid.UUID]bool) (*models.VoteTally, error) {
+	votes, err := s.sessions.GetVotes(session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting votes: %w", err)
+	}
+	return models.TallyVotes(session.ID, *session.VotingRule, session.Items, votes, voters), nil
+}
+
+// eligibleVoters returns the tribe's full and limited members. Guests and
+// pending invitees cannot vote.
+func (s *votingService) eligibleVoters(tribeID uuid.UUID) (map[uuid.UUID]bool, error) {
+	members, err := s.tribes.GetMembers(tribeID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tribe members: %w", err)
+	}
+
+	now := time.Now()
+	voters := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		if member.ExpiresAt != nil && member.ExpiresAt.Before(now) {
+			continue
+		}
+		if member.MembershipType == models.MembershipFull || member.MembershipType == models.MembershipLimited {
+			voters[member.UserID] = true
+		}
+	}
+
+	return voters, nil
+}
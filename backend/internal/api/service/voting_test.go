@@ -0,0 +1,202 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVotingService(t *testing.T) {
+	tribeID := uuid.New()
+	sessionID := uuid.New()
+	alice, bob, guest := uuid.New(), uuid.New(), uuid.New()
+	tacos, ramen := uuid.New(), uuid.New()
+	expired := time.Now().Add(-time.Hour)
+
+	members := []*models.TribeMember{
+		{UserID: alice, MembershipType: models.MembershipFull},
+		{UserID: bob, MembershipType: models.MembershipLimited},
+		{UserID: guest, MembershipType: models.MembershipGuest},
+		{UserID: uuid.New(), MembershipType: models.MembershipFull, ExpiresAt: &expired},
+	}
+
+	setup := func() (VotingService, *testutil.MockListRepository, *testutil.MockMenuSessionRepository) {
+		listRepo := new(testutil.MockListRepository)
+		sessionRepo := new(testutil.MockMenuSessionRepository)
+		tribeRepo := testutil.NewMockTribeRepository()
+		tribeRepo.GetMembersFunc = func(id uuid.UUID) ([]*models.TribeMember, error) {
+			assert.Equal(t, tribeID, id)
+			return members, nil
+		}
+		tribeRepo.GetByIDFunc = func(id uuid.UUID) (*models.Tribe, error) {
+			return &models.Tribe{BaseModel: models.BaseModel{ID: id}, Members: members}, nil
+		}
+		tribeRepo.GetUserTribesFunc = func(userID uuid.UUID) ([]*models.Tribe, error) {
+			return []*models.Tribe{{BaseModel: models.BaseModel{ID: tribeID}, Members: members}}, nil
+		}
+		access := NewListAuthorizer(listRepo, NewTribeAuthorizer(tribeRepo))
+		return NewVotingService(NewListService(listRepo), access, sessionRepo, tribeRepo), listRepo, sessionRepo
+	}
+
+	newSession := func(rule models.VotingRule) *models.MenuSession {
+		session := &models.MenuSession{
+			UserID:     alice,
+			TribeID:    &tribeID,
+			Params:     models.MenuParams{ListIDs: []uuid.UUID{uuid.New()}},
+			Items:      models.MenuSessionItems{{ItemID: tacos, Name: "Tacos"}, {ItemID: ramen, Name: "Ramen"}},
+			VotingRule: &rule,
+		}
+		session.ID = sessionID
+		return session
+	}
+
+	t.Run("StartVote records the rule", func(t *testing.T) {
+		service, listRepo, sessionRepo := setup()
+		listID := uuid.New()
+		params := &models.MenuParams{ListIDs: []uuid.UUID{listID}}
+		tribeOwner := models.OwnerTypeTribe
+		listRepo.On("GetByID", listID).Return(&models.List{ID: listID, OwnerID: &tribeID, OwnerType: &tribeOwner}, nil).Twice()
+		listRepo.On("GetOwners", listID).Return([]*models.ListOwner{
+			{ListID: listID, OwnerID: tribeID, OwnerType: models.OwnerTypeTribe},
+		}, nil).Once()
+		listRepo.On("GetEligibleItems", []uuid.UUID{listID}, params.Filters).
+			Return([]*models.ListItem{{ID: tacos, Name: "Tacos", Weight: 1}}, nil).Once()
+		sessionRepo.On("Create", mock.MatchedBy(func(s *models.MenuSession) bool {
+			return s.VotingRule != nil && *s.VotingRule == models.VotingRuleUnanimous && *s.TribeID == tribeID
+		})).Return(nil).Once()
+
+		session, err := service.StartVote(alice, tribeID, models.VotingRuleUnanimous, params)
+		require.NoError(t, err)
+		assert.True(t, session.Items.Contains(tacos))
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("StartVote rejects bad rules and guests", func(t *testing.T) {
+		service, _, _ := setup()
+		params := &models.MenuParams{ListIDs: []uuid.UUID{uuid.New()}}
+
+		_, err := service.StartVote(alice, tribeID, "coin_flip", params)
+		assert.ErrorIs(t, err, models.ErrInvalidVotingRule)
+
+		_, err = service.StartVote(guest, tribeID, models.VotingRuleMajority, params)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("StartVote rejects lists the user cannot see", func(t *testing.T) {
+		service, listRepo, sessionRepo := setup()
+		listID, owner := uuid.New(), uuid.New()
+		userOwner := models.OwnerTypeUser
+		listRepo.On("GetByID", listID).Return(&models.List{ID: listID, OwnerID: &owner, OwnerType: &userOwner}, nil).Once()
+		listRepo.On("GetOwners", listID).Return([]*models.ListOwner{
+			{ListID: listID, OwnerID: owner, OwnerType: models.OwnerTypeUser},
+		}, nil).Once()
+
+		_, err := service.StartVote(alice, tribeID, models.VotingRuleMajority, &models.MenuParams{ListIDs: []uuid.UUID{listID}})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		listRepo.AssertExpectations(t)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("CastVotes rejects duplicate ranks", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		first, second := 1, 1
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleMajority), nil)
+
+		err := service.CastVotes(alice, sessionID, []*models.MenuVote{
+			{ItemID: tacos, Kind: models.VoteRank, Rank: &first},
+			{ItemID: ramen, Kind: models.VoteRank, Rank: &second},
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		sessionRepo.On("GetVotes", sessionID).Return([]*models.MenuVote{
+			{SessionID: sessionID, UserID: alice, ItemID: tacos, Kind: models.VoteRank, Rank: &first},
+		}, nil).Once()
+		err = service.CastVotes(alice, sessionID, []*models.MenuVote{
+			{ItemID: ramen, Kind: models.VoteRank, Rank: &second},
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidInput, "ranks clash with the voter's earlier votes")
+		sessionRepo.AssertNotCalled(t, "CastVotes", mock.Anything)
+	})
+
+	t.Run("CastVotes stamps the voter", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleMajority), nil).Once()
+		sessionRepo.On("CastVotes", mock.MatchedBy(func(votes []*models.MenuVote) bool {
+			return len(votes) == 1 && votes[0].UserID == bob && votes[0].SessionID == sessionID
+		})).Return(nil).Once()
+
+		err := service.CastVotes(bob, sessionID, []*models.MenuVote{{ItemID: tacos, Kind: models.VoteApprove}})
+		require.NoError(t, err)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("CastVotes only counts full and limited members", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleMajority), nil).Once()
+
+		err := service.CastVotes(guest, sessionID, []*models.MenuVote{{ItemID: tacos, Kind: models.VoteApprove}})
+		assert.ErrorIs(t, err, models.ErrForbidden)
+	})
+
+	t.Run("CastVotes rejects items not on the menu", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleMajority), nil).Once()
+
+		err := service.CastVotes(alice, sessionID, []*models.MenuVote{{ItemID: uuid.New(), Kind: models.VoteApprove}})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("CloseVote marks the winner chosen", func(t *testing.T) {
+		service, listRepo, sessionRepo := setup()
+		closed := newSession(models.VotingRuleMajority)
+		closed.ChosenItemID = &tacos
+
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleMajority), nil).Once()
+		sessionRepo.On("GetVotes", sessionID).Return([]*models.MenuVote{
+			{SessionID: sessionID, UserID: alice, ItemID: tacos, Kind: models.VoteApprove},
+			{SessionID: sessionID, UserID: bob, ItemID: tacos, Kind: models.VoteApprove},
+			{SessionID: sessionID, UserID: guest, ItemID: ramen, Kind: models.VoteApprove},
+		}, nil).Once()
+		sessionRepo.On("SetChosenItem", sessionID, tacos, bob).Return(nil).Once()
+		sessionRepo.On("GetByID", sessionID).Return(closed, nil).Once()
+
+		session, tally, err := service.CloseVote(bob, sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, &tacos, session.ChosenItemID)
+		assert.Equal(t, 2, tally.EligibleVoters)
+		assert.Equal(t, 2, tally.Voters)
+		sessionRepo.AssertExpectations(t)
+		listRepo.AssertExpectations(t)
+	})
+
+	t.Run("CloseVote without a winner leaves the vote open", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		sessionRepo.On("GetByID", sessionID).Return(newSession(models.VotingRuleUnanimous), nil).Once()
+		sessionRepo.On("GetVotes", sessionID).Return([]*models.MenuVote{
+			{SessionID: sessionID, UserID: alice, ItemID: tacos, Kind: models.VoteApprove},
+			{SessionID: sessionID, UserID: bob, ItemID: tacos, Kind: models.VoteVeto},
+		}, nil).Once()
+
+		_, tally, err := service.CloseVote(alice, sessionID)
+		assert.ErrorIs(t, err, models.ErrNoVoteWinner)
+		require.NotNil(t, tally)
+		assert.Nil(t, tally.WinnerID)
+		sessionRepo.AssertNotCalled(t, "SetChosenItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Closed votes cannot change", func(t *testing.T) {
+		service, _, sessionRepo := setup()
+		closed := newSession(models.VotingRuleMajority)
+		closed.ChosenItemID = &tacos
+		sessionRepo.On("GetByID", sessionID).Return(closed, nil).Once()
+
+		err := service.CastVotes(alice, sessionID, []*models.MenuVote{{ItemID: ramen, Kind: models.VoteApprove}})
+		assert.ErrorIs(t, err, models.ErrConflict)
+	})
+}
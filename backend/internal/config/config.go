@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,6 +14,11 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Firebase FirebaseConfig `mapstructure:"firebase"`
 	Auth     AuthConfig     `mapstructure:"auth"`
+	Sync     SyncConfig     `mapstructure:"sync"`
+	Storage  StorageConfig  `mapstructure:"storage"`
+	Invites  InvitesConfig  `mapstructure:"invites"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	Guests   GuestsConfig   `mapstructure:"guests"`
 }
 
 type ServerConfig struct {
@@ -39,6 +45,63 @@ type AuthConfig struct {
 	FirebaseProjectID string `mapstructure:"firebase_project_id"`
 }
 
+type SyncConfig struct {
+	// ImportDir holds the JSON and CSV files imported lists sync from
+	ImportDir  string           `mapstructure:"import_dir"`
+	GoogleMaps GoogleMapsConfig `mapstructure:"google_maps"`
+	// Interval is how often synced lists are refreshed; zero disables it
+	Interval time.Duration `mapstructure:"interval"`
+	// Concurrency is how many lists are synced at once
+	Concurrency int `mapstructure:"concurrency"`
+	// Jitter is the most each list's sync is randomly delayed
+	Jitter time.Duration `mapstructure:"jitter"`
+}
+
+type StorageConfig struct {
+	// Dir is where uploaded photos are stored on the local filesystem
+	Dir string `mapstructure:"dir"`
+	// SigningKey signs photo URLs; a random key is used when empty
+	SigningKey string `mapstructure:"signing_key"`
+	// MaxUploadSize is the largest photo accepted, in bytes
+	MaxUploadSize int64 `mapstructure:"max_upload_size"`
+	// URLTTL is how long signed photo URLs stay valid
+	URLTTL time.Duration `mapstructure:"url_ttl"`
+}
+
+type InvitesConfig struct {
+	// SigningKey signs tribe invite links; a random key is used when empty
+	SigningKey string `mapstructure:"signing_key"`
+}
+
+type MailConfig struct {
+	// SMTPHost is the outgoing mail server; mail is written to Dir when empty
+	SMTPHost string `mapstructure:"smtp_host"`
+	SMTPPort int    `mapstructure:"smtp_port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	// Dir holds mail written instead of sent, for development
+	Dir string `mapstructure:"dir"`
+	// SignupURL is the page invitation emails link to
+	SignupURL string `mapstructure:"signup_url"`
+}
+
+type GuestsConfig struct {
+	// ExpiryPolicy is "remove" or "downgrade"; see models.GuestExpiryPolicy
+	ExpiryPolicy string `mapstructure:"expiry_policy"`
+	// WarnDays is how many days ahead guests are emailed about expiry; zero
+	// disables warnings
+	WarnDays int `mapstructure:"warn_days"`
+	// CheckInterval is how often guest expiry is processed
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+}
+
+type GoogleMapsConfig struct {
+	APIKey  string        `mapstructure:"api_key"`
+	BaseURL string        `mapstructure:"base_url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
 // Load reads configuration from environment variables and config files
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
@@ -79,11 +142,72 @@ func Load() (*Config, error) {
 	if err := viper.BindEnv("firebase.credentials_file", "FIREBASE_CREDENTIALS_FILE"); err != nil {
 		return nil, fmt.Errorf("error binding environment variable: %w", err)
 	}
+	if err := viper.BindEnv("sync.import_dir", "SYNC_IMPORT_DIR"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("sync.interval", "SYNC_INTERVAL"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("sync.google_maps.api_key", "GOOGLE_MAPS_API_KEY"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("sync.google_maps.base_url", "GOOGLE_MAPS_BASE_URL"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+
+	if err := viper.BindEnv("storage.dir", "STORAGE_DIR"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("storage.signing_key", "STORAGE_SIGNING_KEY"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("invites.signing_key", "INVITE_SIGNING_KEY"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+
+	if err := viper.BindEnv("mail.smtp_host", "SMTP_HOST"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("mail.smtp_port", "SMTP_PORT"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("mail.username", "SMTP_USERNAME"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("mail.password", "SMTP_PASSWORD"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("mail.from", "MAIL_FROM"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("mail.signup_url", "SIGNUP_URL"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("guests.expiry_policy", "GUEST_EXPIRY_POLICY"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
+	if err := viper.BindEnv("guests.warn_days", "GUEST_EXPIRY_WARN_DAYS"); err != nil {
+		return nil, fmt.Errorf("error binding environment variable: %w", err)
+	}
 
 	// Default values
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("sync.google_maps.timeout", 10*time.Second)
+	viper.SetDefault("sync.interval", 15*time.Minute)
+	viper.SetDefault("sync.concurrency", 4)
+	viper.SetDefault("sync.jitter", 30*time.Second)
+	viper.SetDefault("storage.dir", "./data/photos")
+	viper.SetDefault("storage.max_upload_size", 10<<20)
+	viper.SetDefault("storage.url_ttl", time.Hour)
+	viper.SetDefault("mail.smtp_port", 587)
+	viper.SetDefault("mail.from", "Tribe <noreply@localhost>")
+	viper.SetDefault("mail.dir", "./data/mail")
+	viper.SetDefault("mail.signup_url", "http://localhost:3000/signup")
+	viper.SetDefault("guests.expiry_policy", "remove")
+	viper.SetDefault("guests.warn_days", 0)
+	viper.SetDefault("guests.check_interval", 15*time.Minute)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
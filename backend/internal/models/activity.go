@@ -18,18 +18,34 @@ const (
 	ActivityTypeEvent    ActivityType = "event"
 )
 
-// Activity represents any type of activity, interest, location, or list
+// Validate checks if the activity type is valid
+func (t ActivityType) Validate() error {
+	switch t {
+	case ActivityTypeLocation, ActivityTypeInterest, ActivityTypeList, ActivityTypeActivity, ActivityTypeEvent:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid activity type: %s", ErrInvalidInput, t)
+	}
+}
+
+// Activity represents any type of activity, interest, location, or list.
+// Recurring events carry an RFC 5545 RRULE and its DTSTART.
 type Activity struct {
-	ID          uuid.UUID      `json:"id" db:"id"`
-	UserID      uuid.UUID      `json:"user_id" db:"user_id"`
-	Type        ActivityType   `json:"type" db:"type"`
-	Name        string         `json:"name" db:"name"`
-	Description string         `json:"description" db:"description"`
-	Visibility  VisibilityType `json:"visibility" db:"visibility"`
-	Metadata    JSONMap        `json:"metadata,omitempty" db:"metadata"`
-	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
-	DeletedAt   *time.Time     `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID              uuid.UUID      `json:"id" db:"id"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id"`
+	Type            ActivityType   `json:"type" db:"type"`
+	Name            string         `json:"name" db:"name"`
+	Description     string         `json:"description" db:"description"`
+	Visibility      VisibilityType `json:"visibility" db:"visibility"`
+	Metadata        JSONMap        `json:"metadata,omitempty" db:"metadata"`
+	RecurrenceRule  string         `json:"recurrence_rule,omitempty" db:"recurrence_rule"`
+	RecurrenceStart *time.Time     `json:"recurrence_start,omitempty" db:"recurrence_start"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty" db:"deleted_at"`
+	// CreatorName is the creator's name as shown in the tribe the activity
+	// was read through; it is empty when it was not read through a tribe
+	CreatorName string `json:"creator_name,omitempty" db:"-"`
 }
 
 // ActivityOwner represents an owner (user or tribe) of an activity
@@ -58,6 +74,7 @@ type ActivityRepository interface {
 	Update(activity *Activity) error
 	Delete(id uuid.UUID) error
 	List(offset, limit int) ([]*Activity, error)
+	ListForUser(userID uuid.UUID, offset, limit int) ([]*Activity, error)
 
 	// Owner management
 	AddOwner(activityID, ownerID uuid.UUID, ownerType OwnerType) error
@@ -70,6 +87,24 @@ type ActivityRepository interface {
 	ShareWithTribe(activityID, tribeID, userID uuid.UUID, expiresAt *time.Time) error
 	UnshareWithTribe(activityID, tribeID uuid.UUID) error
 	GetSharedActivities(tribeID uuid.UUID) ([]*Activity, error)
+	// GetCreatorName returns the name the activity's creator goes by in a
+	// tribe that owns or was shared the activity and that the viewer belongs
+	// to, or "" if there is none
+	GetCreatorName(activityID, viewerID uuid.UUID) (string, error)
+
+	// Scheduling
+	SetSchedule(schedule *ActivitySchedule) error
+	GetSchedule(activityID uuid.UUID) (*ActivitySchedule, error)
+	DeleteSchedule(activityID uuid.UUID) error
+	SetRSVP(rsvp *ActivityRSVP) error
+	DeleteRSVP(activityID, userID uuid.UUID) error
+	GetRSVPs(activityID uuid.UUID) ([]*ActivityRSVP, error)
+
+	// Recurrence
+	SetRecurrence(activityID uuid.UUID, rule string, start *time.Time) error
+	SetOccurrenceException(exception *OccurrenceException) error
+	DeleteOccurrenceException(activityID uuid.UUID, originalStart time.Time) error
+	GetOccurrenceExceptions(activityID uuid.UUID) ([]*OccurrenceException, error)
 
 	// Cleanup
 	MarkForDeletion(activityID uuid.UUID) error
@@ -99,5 +134,8 @@ func (a *Activity) Validate() error {
 	if a.DeletedAt != nil && a.DeletedAt.Before(a.CreatedAt) {
 		return fmt.Errorf("%w: deleted_at cannot be before created_at", ErrInvalidInput)
 	}
+	if err := a.ValidateRecurrence(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OccurrenceException cancels or moves a single instance of a recurring
+// activity. The instance is identified by the start the rule gives it.
+type OccurrenceException struct {
+	ActivityID    uuid.UUID  `json:"activity_id" db:"activity_id"`
+	OriginalStart time.Time  `json:"original_start" db:"original_start"`
+	Cancelled     bool       `json:"cancelled" db:"cancelled"`
+	StartTime     *time.Time `json:"start_time,omitempty" db:"start_time"`
+	EndTime       *time.Time `json:"end_time,omitempty" db:"end_time"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Validate performs validation on the exception
+func (e *OccurrenceException) Validate() error {
+	if e.ActivityID == uuid.Nil {
+		return ErrInvalidActivityID
+	}
+	if e.OriginalStart.IsZero() {
+		return fmt.Errorf("%w: original start is required", ErrInvalidInput)
+	}
+	if e.Cancelled {
+		if e.StartTime != nil || e.EndTime != nil {
+			return fmt.Errorf("%w: a cancelled occurrence cannot be moved", ErrInvalidInput)
+		}
+		return nil
+	}
+	if e.StartTime == nil || e.EndTime == nil {
+		return fmt.Errorf("%w: a moved occurrence needs a start and end time", ErrInvalidInput)
+	}
+	if !e.EndTime.After(*e.StartTime) {
+		return fmt.Errorf("%w: end time must be after start time", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Occurrence is one concrete instance of a scheduled activity
+type Occurrence struct {
+	ActivityID    uuid.UUID `json:"activity_id"`
+	OriginalStart time.Time `json:"original_start"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	TimeZone      string    `json:"time_zone"`
+	Moved         bool      `json:"moved,omitempty"`
+}
+
+// ValidateRecurrence checks the activity's recurrence rule, if it has one
+func (a *Activity) ValidateRecurrence() error {
+	if a.RecurrenceRule == "" {
+		if a.RecurrenceStart != nil {
+			return fmt.Errorf("%w: recurrence start requires a recurrence rule", ErrInvalidInput)
+		}
+		return nil
+	}
+	if a.RecurrenceStart == nil {
+		return fmt.Errorf("%w: recurrence rule requires a start", ErrInvalidInput)
+	}
+	_, err := ParseRecurrenceRule(a.RecurrenceRule, a.RecurrenceStart.Location())
+	return err
+}
+
+// ExpandOccurrences returns the occurrences of a scheduled activity that
+// overlap [from, to), ordered by start. Activities without a recurrence rule
+// have a single occurrence. Each occurrence lasts as long as the schedule and
+// cancelled or moved instances are applied from exceptions.
+func ExpandOccurrences(activity *Activity, schedule *ActivitySchedule, exceptions []*OccurrenceException, from, to time.Time) ([]Occurrence, error) {
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone: %s", ErrInvalidInput, schedule.TimeZone)
+	}
+	duration := schedule.EndTime.Sub(schedule.StartTime)
+
+	byStart := make(map[time.Time]*OccurrenceException, len(exceptions))
+	for _, exception := range exceptions {
+		byStart[exception.OriginalStart.UTC()] = exception
+	}
+
+	var originals []time.Time
+	includes := func(t time.Time) bool { return t.Equal(schedule.StartTime) }
+	if activity.RecurrenceRule == "" {
+		if schedule.StartTime.Before(to) && schedule.EndTime.After(from) {
+			originals = append(originals, schedule.StartTime)
+		}
+	} else {
+		dtstart := schedule.StartTime
+		if activity.RecurrenceStart != nil {
+			dtstart = *activity.RecurrenceStart
+		}
+		dtstart = dtstart.In(loc)
+
+		rule, parseErr := ParseRecurrenceRule(activity.RecurrenceRule, loc)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		// Instances that began before the window may still overlap it
+		originals = rule.Between(dtstart, from.Add(-duration+time.Nanosecond), to)
+		includes = func(t time.Time) bool { return rule.Includes(dtstart, t.In(loc)) }
+	}
+
+	occurrences := make([]Occurrence, 0, len(originals))
+	emitted := make(map[time.Time]bool, len(originals))
+	add := func(original time.Time) {
+		occurrence := Occurrence{
+			ActivityID:    activity.ID,
+			OriginalStart: original.UTC(),
+			StartTime:     original.In(loc),
+			EndTime:       original.Add(duration).In(loc),
+			TimeZone:      schedule.TimeZone,
+		}
+		if exception, ok := byStart[original.UTC()]; ok {
+			if exception.Cancelled {
+				return
+			}
+			occurrence.StartTime = exception.StartTime.In(loc)
+			occurrence.EndTime = exception.EndTime.In(loc)
+			occurrence.Moved = true
+		}
+		if occurrence.StartTime.Before(to) && occurrence.EndTime.After(from) {
+			emitted[original.UTC()] = true
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	for _, original := range originals {
+		add(original)
+	}
+	// Instances moved into the window from outside it
+	for original, exception := range byStart {
+		if emitted[original] || exception.Cancelled {
+			continue
+		}
+		if exception.StartTime.Before(to) && exception.EndTime.After(from) && includes(original) {
+			add(original)
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+	return occurrences, nil
+}
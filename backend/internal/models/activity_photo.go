@@ -1,16 +1,52 @@
 package models
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 )
 
-// ActivityPhoto represents a photo associated with an activity
+// Photo variant names
+const (
+	PhotoVariantThumbnail = "thumbnail"
+	PhotoVariantMedium    = "medium"
+	PhotoVariantOriginal  = "original"
+)
+
+// Metadata keys used by the variant pipeline
+const (
+	PhotoMetaVariants       = "variants"
+	PhotoMetaVariantStatus  = "variants_status"
+	PhotoMetaVariantTries   = "variants_attempts"
+	PhotoMetaVariantError   = "variants_error"
+	PhotoVariantStatusReady = "ready"
+	PhotoVariantStatusError = "failed"
+)
+
+// PhotoVariant is one resized copy of a photo. Key is where it is stored;
+// URL is only filled in on responses, with a signed URL for the key.
+type PhotoVariant struct {
+	Key         string `json:"key,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+}
+
+// ActivityPhoto represents a photo associated with an activity. Uploaded
+// photos keep their blob store key in StorageKey and are served through
+// signed URLs; URL holds either that signed URL or an external address.
 type ActivityPhoto struct {
 	BaseModel
-	ActivityID uuid.UUID `json:"activity_id"`
-	URL        string    `json:"url"`
-	Caption    string    `json:"caption"`
-	Metadata   JSONMap   `json:"metadata"`
+	ActivityID  uuid.UUID `json:"activity_id"`
+	URL         string    `json:"url"`
+	Caption     string    `json:"caption"`
+	Metadata    JSONMap   `json:"metadata"`
+	StorageKey  string    `json:"-"`
+	ContentType string    `json:"content_type,omitempty"`
+	SizeBytes   int64     `json:"size_bytes,omitempty"`
+	// Variants holds the generated variants with signed URLs on responses
+	Variants map[string]PhotoVariant `json:"variants,omitempty"`
 }
 
 // Validate ensures the activity photo data is valid
@@ -21,16 +57,54 @@ func (p *ActivityPhoto) Validate() error {
 	if p.ActivityID == uuid.Nil {
 		return ErrInvalidActivityID
 	}
-	if p.URL == "" {
+	if p.URL == "" && p.StorageKey == "" {
 		return ErrInvalidURL
 	}
 	return nil
 }
 
+// StoredVariants returns the variants recorded in the photo's metadata
+func (p *ActivityPhoto) StoredVariants() map[string]PhotoVariant {
+	raw, ok := p.Metadata[PhotoMetaVariants]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var variants map[string]PhotoVariant
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil
+	}
+	return variants
+}
+
+// VariantsReady reports whether the photo's variants have been generated
+func (p *ActivityPhoto) VariantsReady() bool {
+	status, _ := p.Metadata[PhotoMetaVariantStatus].(string)
+	return status == PhotoVariantStatusReady
+}
+
+// VariantAttempts returns how many times generating variants has failed
+func (p *ActivityPhoto) VariantAttempts() int {
+	switch n := p.Metadata[PhotoMetaVariantTries].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
 // ActivityPhotosRepository defines the interface for activity photo data operations
 type ActivityPhotosRepository interface {
 	Create(photo *ActivityPhoto) error
 	GetByID(id uuid.UUID) (*ActivityPhoto, error)
+	ListByActivity(activityID uuid.UUID) ([]*ActivityPhoto, error)
+	// ListMissingVariants returns photos whose variants are not ready and
+	// have failed fewer than maxAttempts times, oldest first
+	ListMissingVariants(maxAttempts, limit int) ([]*ActivityPhoto, error)
 	Update(photo *ActivityPhoto) error
 	Delete(id uuid.UUID) error
 }
@@ -134,3 +134,27 @@ func TestActivityPhotoValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestActivityPhotoVariants(t *testing.T) {
+	photo := &ActivityPhoto{Metadata: JSONMap{}}
+	assert.Nil(t, photo.StoredVariants())
+	assert.False(t, photo.VariantsReady())
+	assert.Equal(t, 0, photo.VariantAttempts())
+
+	// Values as they come back from the database
+	photo.Metadata = JSONMap{
+		PhotoMetaVariantStatus: PhotoVariantStatusReady,
+		PhotoMetaVariantTries:  float64(2),
+		PhotoMetaVariants: map[string]interface{}{
+			PhotoVariantThumbnail: map[string]interface{}{
+				"key": "activities/a/p_thumbnail.jpg", "width": float64(320), "height": float64(240),
+				"content_type": "image/jpeg",
+			},
+		},
+	}
+	assert.True(t, photo.VariantsReady())
+	assert.Equal(t, 2, photo.VariantAttempts())
+	assert.Equal(t, map[string]PhotoVariant{
+		PhotoVariantThumbnail: {Key: "activities/a/p_thumbnail.jpg", Width: 320, Height: 240, ContentType: "image/jpeg"},
+	}, photo.StoredVariants())
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RSVPStatus is a member's answer to a scheduled activity
+type RSVPStatus string
+
+const (
+	RSVPYes   RSVPStatus = "yes"
+	RSVPNo    RSVPStatus = "no"
+	RSVPMaybe RSVPStatus = "maybe"
+)
+
+// Validate checks if the RSVP status is valid
+func (s RSVPStatus) Validate() error {
+	switch s {
+	case RSVPYes, RSVPNo, RSVPMaybe:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid RSVP status: %s", ErrInvalidInput, s)
+	}
+}
+
+// ActivitySchedule is when and where an activity takes place. Times are
+// stored as instants; TimeZone is the IANA zone the event was planned in and
+// is used to display it. An activity may point at the list item it came from
+// and at a location. Sequence counts revisions of the timing, as the
+// iCalendar SEQUENCE property does.
+type ActivitySchedule struct {
+	ActivityID uuid.UUID    `json:"activity_id" db:"activity_id"`
+	StartTime  time.Time    `json:"start_time" db:"start_time"`
+	EndTime    time.Time    `json:"end_time" db:"end_time"`
+	TimeZone   string       `json:"time_zone" db:"time_zone"`
+	ListItemID *uuid.UUID   `json:"list_item_id,omitempty" db:"list_item_id"`
+	Location   *LocationRef `json:"location,omitempty" db:"location"`
+	Sequence   int          `json:"sequence" db:"sequence"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Validate performs validation on the schedule
+func (s *ActivitySchedule) Validate() error {
+	if s.ActivityID == uuid.Nil {
+		return ErrInvalidActivityID
+	}
+	if s.StartTime.IsZero() {
+		return fmt.Errorf("%w: start time is required", ErrInvalidInput)
+	}
+	if !s.EndTime.After(s.StartTime) {
+		return fmt.Errorf("%w: end time must be after start time", ErrInvalidInput)
+	}
+	if s.TimeZone == "" {
+		return fmt.Errorf("%w: time zone is required", ErrInvalidInput)
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone: %s", ErrInvalidInput, s.TimeZone)
+	}
+	if s.Location != nil {
+		if err := s.Location.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ActivityRSVP is one member's answer to a scheduled activity
+type ActivityRSVP struct {
+	ActivityID uuid.UUID  `json:"activity_id" db:"activity_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Status     RSVPStatus `json:"status" db:"status"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Validate performs validation on the RSVP
+func (r *ActivityRSVP) Validate() error {
+	if r.ActivityID == uuid.Nil {
+		return ErrInvalidActivityID
+	}
+	if r.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user ID is required", ErrInvalidInput)
+	}
+	return r.Status.Validate()
+}
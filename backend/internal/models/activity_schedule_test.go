@@ -0,0 +1,132 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityScheduleValidation(t *testing.T) {
+	start := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+
+	valid := func() *ActivitySchedule {
+		return &ActivitySchedule{
+			ActivityID: uuid.New(),
+			StartTime:  start,
+			EndTime:    start.Add(2 * time.Hour),
+			TimeZone:   "America/Chicago",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(s *ActivitySchedule)
+		expectErr error
+	}{
+		{
+			name:   "valid schedule",
+			modify: func(s *ActivitySchedule) {},
+		},
+		{
+			name: "valid schedule with location",
+			modify: func(s *ActivitySchedule) {
+				s.Location = &LocationRef{Latitude: 41.88, Longitude: -87.63, Address: "Chicago"}
+			},
+		},
+		{
+			name:      "missing activity",
+			modify:    func(s *ActivitySchedule) { s.ActivityID = uuid.Nil },
+			expectErr: ErrInvalidActivityID,
+		},
+		{
+			name:      "missing start",
+			modify:    func(s *ActivitySchedule) { s.StartTime = time.Time{} },
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name:      "end before start",
+			modify:    func(s *ActivitySchedule) { s.EndTime = start.Add(-time.Hour) },
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name:      "end equals start",
+			modify:    func(s *ActivitySchedule) { s.EndTime = start },
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name:      "missing time zone",
+			modify:    func(s *ActivitySchedule) { s.TimeZone = "" },
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name:      "unknown time zone",
+			modify:    func(s *ActivitySchedule) { s.TimeZone = "Mars/Olympus_Mons" },
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name: "invalid location",
+			modify: func(s *ActivitySchedule) {
+				s.Location = &LocationRef{Latitude: 91, Longitude: 0, Address: "Nowhere"}
+			},
+			expectErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := valid()
+			tt.modify(schedule)
+			err := schedule.Validate()
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestActivityRSVPValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		rsvp      ActivityRSVP
+		expectErr error
+	}{
+		{
+			name: "valid yes",
+			rsvp: ActivityRSVP{ActivityID: uuid.New(), UserID: uuid.New(), Status: RSVPYes},
+		},
+		{
+			name: "valid maybe",
+			rsvp: ActivityRSVP{ActivityID: uuid.New(), UserID: uuid.New(), Status: RSVPMaybe},
+		},
+		{
+			name:      "missing activity",
+			rsvp:      ActivityRSVP{UserID: uuid.New(), Status: RSVPNo},
+			expectErr: ErrInvalidActivityID,
+		},
+		{
+			name:      "missing user",
+			rsvp:      ActivityRSVP{ActivityID: uuid.New(), Status: RSVPNo},
+			expectErr: ErrInvalidInput,
+		},
+		{
+			name:      "invalid status",
+			rsvp:      ActivityRSVP{ActivityID: uuid.New(), UserID: uuid.New(), Status: "perhaps"},
+			expectErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rsvp.Validate()
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
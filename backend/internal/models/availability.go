@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits on slot proposals
+const (
+	DefaultSlotStep  = 30 * time.Minute
+	DefaultSlotLimit = 20
+	MaxSlotLimit     = 100
+	MaxSlotWindow    = 31 * 24 * time.Hour
+)
+
+// AvailabilityCalendar is an iCalendar file a user imported to share when
+// they are busy. Uploaded calendars have no URL; linked calendars keep the
+// URL they were fetched from so they can be refreshed.
+type AvailabilityCalendar struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	Name           string     `json:"name" db:"name"`
+	URL            string     `json:"url,omitempty" db:"url"`
+	LastImportedAt *time.Time `json:"last_imported_at,omitempty" db:"last_imported_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsLinked reports whether the calendar is fetched from a URL
+func (c *AvailabilityCalendar) IsLinked() bool {
+	return c.URL != ""
+}
+
+// Validate performs validation on the calendar
+func (c *AvailabilityCalendar) Validate() error {
+	if c.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user ID is required", ErrInvalidInput)
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: calendar name is required", ErrInvalidInput)
+	}
+	if len(c.Name) > 100 {
+		return fmt.Errorf("%w: calendar name cannot be longer than 100 characters", ErrInvalidInput)
+	}
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("%w: invalid calendar URL", ErrInvalidInput)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "webcal":
+		default:
+			return fmt.Errorf("%w: calendar URL must use http, https or webcal", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// BusyInterval is a span of time in which a user is busy, as read from one
+// of their imported calendars
+type BusyInterval struct {
+	CalendarID uuid.UUID `json:"calendar_id" db:"calendar_id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	StartTime  time.Time `json:"start_time" db:"start_time"`
+	EndTime    time.Time `json:"end_time" db:"end_time"`
+}
+
+// Overlaps reports whether the interval overlaps [start, end)
+func (b *BusyInterval) Overlaps(start, end time.Time) bool {
+	return b.StartTime.Before(end) && b.EndTime.After(start)
+}
+
+// SlotQuery describes the time slots to look for. Zero Step, Quorum and
+// Limit take their defaults; a zero Quorum means every member.
+type SlotQuery struct {
+	From     time.Time
+	To       time.Time
+	Duration time.Duration
+	Step     time.Duration
+	Quorum   int
+	Limit    int
+}
+
+// Validate checks the query against the number of members being matched
+func (q *SlotQuery) Validate(members int) error {
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: to must be after from", ErrInvalidInput)
+	}
+	if q.To.Sub(q.From) > MaxSlotWindow {
+		return fmt.Errorf("%w: window cannot be longer than %d days", ErrInvalidInput, int(MaxSlotWindow.Hours()/24))
+	}
+	if q.Duration <= 0 {
+		return fmt.Errorf("%w: duration must be positive", ErrInvalidInput)
+	}
+	if q.Duration > q.To.Sub(q.From) {
+		return fmt.Errorf("%w: duration is longer than the window", ErrInvalidInput)
+	}
+	if q.Step < 0 || (q.Step > 0 && q.Step < time.Minute) {
+		return fmt.Errorf("%w: step must be at least a minute", ErrInvalidInput)
+	}
+	if q.Quorum < 0 || q.Quorum > members {
+		return fmt.Errorf("%w: quorum must be between 1 and %d", ErrInvalidInput, members)
+	}
+	if q.Limit < 0 || q.Limit > MaxSlotLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, MaxSlotLimit)
+	}
+	return nil
+}
+
+// TimeSlot is a window in which the same members are free. Any meeting of
+// the requested duration starting between Start and End minus the duration
+// suits them.
+type TimeSlot struct {
+	StartTime   time.Time   `json:"start_time"`
+	EndTime     time.Time   `json:"end_time"`
+	Available   []uuid.UUID `json:"available"`
+	Unavailable []uuid.UUID `json:"unavailable"`
+}
+
+// ProposeSlots finds the windows in which at least a quorum of members is
+// free, in chronological order. Candidate meetings start every step from
+// q.From; overlapping candidates that suit the same members are merged into
+// one window.
+func ProposeSlots(members []uuid.UUID, busy []*BusyInterval, q SlotQuery) ([]TimeSlot, error) {
+	if len(members) == 0 {
+		return []TimeSlot{}, nil
+	}
+	if err := q.Validate(len(members)); err != nil {
+		return nil, err
+	}
+	step, quorum, limit := q.Step, q.Quorum, q.Limit
+	if step == 0 {
+		step = DefaultSlotStep
+	}
+	if quorum == 0 {
+		quorum = len(members)
+	}
+	if limit == 0 {
+		limit = DefaultSlotLimit
+	}
+
+	byMember := make(map[uuid.UUID][]*BusyInterval, len(members))
+	for _, interval := range busy {
+		byMember[interval.UserID] = append(byMember[interval.UserID], interval)
+	}
+	for _, intervals := range byMember {
+		sort.Slice(intervals, func(i, j int) bool {
+			return intervals[i].StartTime.Before(intervals[j].StartTime)
+		})
+	}
+	isBusy := func(userID uuid.UUID, start, end time.Time) bool {
+		for _, interval := range byMember[userID] {
+			if !interval.StartTime.Before(end) {
+				return false
+			}
+			if interval.Overlaps(start, end) {
+				return true
+			}
+		}
+		return false
+	}
+
+	slots := make([]TimeSlot, 0)
+	var current *TimeSlot
+	for start := q.From; !start.Add(q.Duration).After(q.To); start = start.Add(step) {
+		end := start.Add(q.Duration)
+		available := make([]uuid.UUID, 0, len(members))
+		unavailable := make([]uuid.UUID, 0)
+		for _, member := range members {
+			if isBusy(member, start, end) {
+				unavailable = append(unavailable, member)
+			} else {
+				available = append(available, member)
+			}
+		}
+		if len(available) < quorum {
+			current = nil
+			continue
+		}
+
+		if current != nil && !current.EndTime.Before(start) && sameMembers(current.Available, available) {
+			current.EndTime = end
+			continue
+		}
+		if len(slots) == limit {
+			break
+		}
+		slots = append(slots, TimeSlot{
+			StartTime:   start,
+			EndTime:     end,
+			Available:   available,
+			Unavailable: unavailable,
+		})
+		current = &slots[len(slots)-1]
+	}
+	return slots, nil
+}
+
+// sameMembers reports whether two member lists, built in the same order,
+// are equal
+func sameMembers(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// AvailabilityRepository defines the interface for imported calendars and
+// the busy intervals read from them
+type AvailabilityRepository interface {
+	CreateCalendar(calendar *AvailabilityCalendar) error
+	GetCalendar(id uuid.UUID) (*AvailabilityCalendar, error)
+	ListCalendars(userID uuid.UUID) ([]*AvailabilityCalendar, error)
+	DeleteCalendar(userID, id uuid.UUID) error
+	// ReplaceBusyIntervals swaps a calendar's intervals for a fresh import
+	ReplaceBusyIntervals(calendarID uuid.UUID, intervals []BusyInterval) error
+	// GetBusyIntervals returns the intervals of the users that overlap
+	// [from, to), ordered by start
+	GetBusyIntervals(userIDs []uuid.UUID, from, to time.Time) ([]*BusyInterval, error)
+}
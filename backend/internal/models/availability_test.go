@@ -0,0 +1,128 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityCalendarValidate(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name     string
+		calendar AvailabilityCalendar
+		wantErr  bool
+	}{
+		{name: "uploaded", calendar: AvailabilityCalendar{UserID: userID, Name: "Work"}},
+		{name: "linked", calendar: AvailabilityCalendar{UserID: userID, Name: "Work", URL: "https://example.com/work.ics"}},
+		{name: "webcal", calendar: AvailabilityCalendar{UserID: userID, Name: "Work", URL: "webcal://example.com/work.ics"}},
+		{name: "missing user", calendar: AvailabilityCalendar{Name: "Work"}, wantErr: true},
+		{name: "missing name", calendar: AvailabilityCalendar{UserID: userID, Name: "  "}, wantErr: true},
+		{name: "unsupported scheme", calendar: AvailabilityCalendar{UserID: userID, Name: "Work", URL: "file:///etc/passwd"}, wantErr: true},
+		{name: "no host", calendar: AvailabilityCalendar{UserID: userID, Name: "Work", URL: "https:///work.ics"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.calendar.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProposeSlots(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	members := []uuid.UUID{alice, bob, carol}
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour-9) * time.Hour) }
+	busy := func(user uuid.UUID, from, to int) *BusyInterval {
+		return &BusyInterval{UserID: user, StartTime: at(from), EndTime: at(to)}
+	}
+
+	t.Run("everyone free is one window", func(t *testing.T) {
+		slots, err := ProposeSlots(members, nil, SlotQuery{From: at(9), To: at(17), Duration: time.Hour})
+		require.NoError(t, err)
+		require.Len(t, slots, 1)
+		assert.Equal(t, at(9), slots[0].StartTime)
+		assert.Equal(t, at(17), slots[0].EndTime)
+		assert.ElementsMatch(t, members, slots[0].Available)
+		assert.Empty(t, slots[0].Unavailable)
+	})
+
+	t.Run("busy time splits windows", func(t *testing.T) {
+		intervals := []*BusyInterval{busy(alice, 10, 12), busy(bob, 14, 15)}
+		slots, err := ProposeSlots(members, intervals, SlotQuery{From: at(9), To: at(17), Duration: time.Hour})
+		require.NoError(t, err)
+		require.Len(t, slots, 3)
+		assert.Equal(t, []time.Time{at(9), at(10)}, []time.Time{slots[0].StartTime, slots[0].EndTime})
+		assert.Equal(t, []time.Time{at(12), at(14)}, []time.Time{slots[1].StartTime, slots[1].EndTime})
+		assert.Equal(t, []time.Time{at(15), at(17)}, []time.Time{slots[2].StartTime, slots[2].EndTime})
+	})
+
+	t.Run("quorum", func(t *testing.T) {
+		intervals := []*BusyInterval{busy(alice, 9, 11), busy(bob, 10, 12)}
+		slots, err := ProposeSlots(members, intervals, SlotQuery{From: at(9), To: at(13), Duration: time.Hour, Quorum: 2})
+		require.NoError(t, err)
+		require.Len(t, slots, 3)
+
+		assert.Equal(t, at(9), slots[0].StartTime)
+		assert.Equal(t, at(10), slots[0].EndTime)
+		assert.Equal(t, []uuid.UUID{bob, carol}, slots[0].Available)
+		assert.Equal(t, []uuid.UUID{alice}, slots[0].Unavailable)
+
+		// 10:00-11:00 only suits carol, so it is skipped
+		assert.Equal(t, at(11), slots[1].StartTime)
+		assert.Equal(t, []uuid.UUID{alice, carol}, slots[1].Available)
+
+		assert.Equal(t, at(12), slots[2].StartTime)
+		assert.Equal(t, at(13), slots[2].EndTime)
+		assert.Equal(t, members, slots[2].Available)
+	})
+
+	t.Run("step and limit", func(t *testing.T) {
+		intervals := []*BusyInterval{busy(alice, 10, 11), busy(alice, 12, 13), busy(alice, 14, 15)}
+		slots, err := ProposeSlots(members, intervals, SlotQuery{
+			From:     at(9),
+			To:       at(17),
+			Duration: 45 * time.Minute,
+			Step:     15 * time.Minute,
+			Limit:    2,
+		})
+		require.NoError(t, err)
+		require.Len(t, slots, 2)
+		assert.Equal(t, at(9), slots[0].StartTime)
+		assert.Equal(t, at(10), slots[0].EndTime)
+		assert.Equal(t, at(11), slots[1].StartTime)
+		assert.Equal(t, at(12), slots[1].EndTime)
+	})
+
+	t.Run("no members", func(t *testing.T) {
+		slots, err := ProposeSlots(nil, nil, SlotQuery{From: at(9), To: at(17), Duration: time.Hour})
+		require.NoError(t, err)
+		assert.Empty(t, slots)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		queries := map[string]SlotQuery{
+			"empty window":       {From: at(9), To: at(9), Duration: time.Hour},
+			"window too long":    {From: at(9), To: at(9).Add(MaxSlotWindow + time.Hour), Duration: time.Hour},
+			"no duration":        {From: at(9), To: at(17)},
+			"duration too long":  {From: at(9), To: at(10), Duration: 2 * time.Hour},
+			"step too short":     {From: at(9), To: at(17), Duration: time.Hour, Step: time.Second},
+			"quorum over member": {From: at(9), To: at(17), Duration: time.Hour, Quorum: 4},
+			"limit too high":     {From: at(9), To: at(17), Duration: time.Hour, Limit: MaxSlotLimit + 1},
+		}
+		for name, query := range queries {
+			t.Run(name, func(t *testing.T) {
+				_, err := ProposeSlots(members, nil, query)
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			})
+		}
+	})
+}
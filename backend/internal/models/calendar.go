@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedToken lets a calendar app read a user's iCalendar feeds
+// without logging in. Only a hash of the token is stored; the token itself
+// is returned once, when it is created.
+type CalendarFeedToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	Token     string     `json:"token,omitempty" db:"-"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsRevoked reports whether the token can no longer be used
+func (t *CalendarFeedToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// CalendarFeedTokenRepository defines the interface for calendar feed token
+// operations
+type CalendarFeedTokenRepository interface {
+	Create(token *CalendarFeedToken) error
+	GetByHash(tokenHash string) (*CalendarFeedToken, error)
+	ListByUser(userID uuid.UUID) ([]*CalendarFeedToken, error)
+	Revoke(userID, tokenID uuid.UUID) error
+	RevokeAll(userID uuid.UUID) error
+}
@@ -1,6 +1,9 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Common application-level errors
 var (
@@ -53,6 +56,28 @@ var (
 	ErrConcurrentModification = errors.New("concurrent modification detected")
 )
 
+// Voting errors
+var (
+	ErrInvalidVotingRule = errors.New("invalid voting rule")
+	ErrNoVoteWinner      = errors.New("no item satisfies the voting rule")
+)
+
+// Tribe permission errors. Both wrap ErrForbidden.
+var (
+	// ErrNotTribeMember is returned when a user has no active membership of a tribe
+	ErrNotTribeMember = fmt.Errorf("%w: not a member of this tribe", ErrForbidden)
+	// ErrTribePermission is returned when a membership does not allow an action
+	ErrTribePermission = fmt.Errorf("%w: tribe role or membership does not allow this", ErrForbidden)
+)
+
+// Tribe membership errors
+var (
+	// ErrLastOwner is returned when a change would leave a tribe without an owner
+	ErrLastOwner = fmt.Errorf("%w: a tribe must keep an owner; transfer ownership or delete the tribe", ErrConflict)
+	// ErrInviteUnavailable is returned when an invite has expired, been revoked or been used up
+	ErrInviteUnavailable = errors.New("invite is no longer available")
+)
+
 // Activity Photo errors
 var (
 	ErrInvalidID         = errors.New("invalid ID")
@@ -61,6 +86,10 @@ var (
 	ErrInvalidCreatedAt  = errors.New("invalid created at time")
 	ErrInvalidUpdatedAt  = errors.New("invalid updated at time")
 	ErrInvalidDeletedAt  = errors.New("deleted at time cannot be before created at time")
+
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrFileTooLarge         = errors.New("file too large")
+	ErrInvalidSignature     = errors.New("invalid or expired signature")
 )
 
 // IsSyncError checks if an error is a sync-related error
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm is the mean radius of the earth used for haversine distances
+const EarthRadiusKm = 6371.0
+
+// Menu filter keys for proximity filtering
+const (
+	FilterOriginLatitude  = "origin_lat"
+	FilterOriginLongitude = "origin_lng"
+	FilterRadiusKm        = "radius_km"
+)
+
+// GeoFilter restricts and orders items by their distance from an origin
+type GeoFilter struct {
+	Latitude  float64
+	Longitude float64
+	// RadiusKm excludes items farther than this from the origin, along with
+	// items that have no coordinates. When nil, items are only sorted.
+	RadiusKm *float64
+}
+
+// GeoFilterFromFilters reads a GeoFilter from menu filters. It returns nil
+// when no origin is given.
+func GeoFilterFromFilters(filters map[string]interface{}) (*GeoFilter, error) {
+	lat, hasLat, err := floatFilter(filters, FilterOriginLatitude)
+	if err != nil {
+		return nil, err
+	}
+	lng, hasLng, err := floatFilter(filters, FilterOriginLongitude)
+	if err != nil {
+		return nil, err
+	}
+	radius, hasRadius, err := floatFilter(filters, FilterRadiusKm)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasLat && !hasLng {
+		if hasRadius {
+			return nil, fmt.Errorf("%w: %s requires an origin", ErrInvalidInput, FilterRadiusKm)
+		}
+		return nil, nil
+	}
+	if hasLat != hasLng {
+		return nil, fmt.Errorf("%w: origin requires both %s and %s", ErrInvalidInput, FilterOriginLatitude, FilterOriginLongitude)
+	}
+
+	geo := &GeoFilter{Latitude: lat, Longitude: lng}
+	if hasRadius {
+		geo.RadiusKm = &radius
+	}
+	if err := geo.Validate(); err != nil {
+		return nil, err
+	}
+	return geo, nil
+}
+
+// Validate performs validation on the geo filter
+func (g *GeoFilter) Validate() error {
+	if g.Latitude < -90 || g.Latitude > 90 {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidInput)
+	}
+	if g.Longitude < -180 || g.Longitude > 180 {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidInput)
+	}
+	if g.RadiusKm != nil && *g.RadiusKm <= 0 {
+		return fmt.Errorf("%w: radius must be positive", ErrInvalidInput)
+	}
+	return nil
+}
+
+// DistanceTo returns the distance in km from the origin to the item, or nil
+// if the item has no coordinates
+func (g *GeoFilter) DistanceTo(item *ListItem) *float64 {
+	if item.Latitude == nil || item.Longitude == nil {
+		return nil
+	}
+	distance := HaversineKm(g.Latitude, g.Longitude, *item.Latitude, *item.Longitude)
+	return &distance
+}
+
+// HaversineKm returns the great-circle distance in km between two points
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// floatFilter reads a numeric filter, which may have been decoded from JSON
+// as a float64
+func floatFilter(filters map[string]interface{}, key string) (float64, bool, error) {
+	value, ok := filters[key]
+	if !ok || value == nil {
+		return 0, false, nil
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, true, nil
+	case float32:
+		return float64(v), true, nil
+	case int:
+		return float64(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	default:
+		return 0, false, fmt.Errorf("%w: %s must be a number", ErrInvalidInput, key)
+	}
+}
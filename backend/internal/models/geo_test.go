@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHaversineKm(t *testing.T) {
+	// London to Paris
+	assert.InDelta(t, 343.5, HaversineKm(51.5074, -0.1278, 48.8566, 2.3522), 1.0)
+	assert.Equal(t, 0.0, HaversineKm(10, 20, 10, 20))
+	// Antipodal points are half the circumference apart
+	assert.InDelta(t, 20015.1, HaversineKm(0, 0, 0, 180), 0.1)
+}
+
+func TestGeoFilterFromFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		want    *GeoFilter
+		wantErr bool
+	}{
+		{
+			name:    "no origin",
+			filters: map[string]interface{}{"max_items": 3},
+		},
+		{
+			name:    "origin only",
+			filters: map[string]interface{}{FilterOriginLatitude: 40.7, FilterOriginLongitude: -74.0},
+			want:    &GeoFilter{Latitude: 40.7, Longitude: -74.0},
+		},
+		{
+			name:    "origin and radius",
+			filters: map[string]interface{}{FilterOriginLatitude: 40.7, FilterOriginLongitude: -74, FilterRadiusKm: 5},
+			want:    &GeoFilter{Latitude: 40.7, Longitude: -74.0, RadiusKm: float64Ptr(5)},
+		},
+		{
+			name:    "radius without origin",
+			filters: map[string]interface{}{FilterRadiusKm: 5.0},
+			wantErr: true,
+		},
+		{
+			name:    "half an origin",
+			filters: map[string]interface{}{FilterOriginLatitude: 40.7},
+			wantErr: true,
+		},
+		{
+			name:    "latitude out of range",
+			filters: map[string]interface{}{FilterOriginLatitude: 91.0, FilterOriginLongitude: 0.0},
+			wantErr: true,
+		},
+		{
+			name:    "non-positive radius",
+			filters: map[string]interface{}{FilterOriginLatitude: 0.0, FilterOriginLongitude: 0.0, FilterRadiusKm: 0.0},
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric origin",
+			filters: map[string]interface{}{FilterOriginLatitude: "north", FilterOriginLongitude: 0.0},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GeoFilterFromFilters(tt.filters)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGeoFilterDistanceTo(t *testing.T) {
+	geo := &GeoFilter{Latitude: 0, Longitude: 0}
+	lat, lng := 0.0, 1.0
+
+	distance := geo.DistanceTo(&ListItem{Latitude: &lat, Longitude: &lng})
+	require.NotNil(t, distance)
+	assert.InDelta(t, 111.19, *distance, 0.01)
+
+	assert.Nil(t, geo.DistanceTo(&ListItem{}))
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
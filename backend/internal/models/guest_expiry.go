@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuestExpiryPolicy is what happens to a guest whose membership expires
+type GuestExpiryPolicy string
+
+const (
+	GuestExpiryRemove    GuestExpiryPolicy = "remove"    // The guest leaves the tribe
+	GuestExpiryDowngrade GuestExpiryPolicy = "downgrade" // The guest stays listed as pending until a member re-invites them
+)
+
+func (p GuestExpiryPolicy) Validate() error {
+	switch p {
+	case GuestExpiryRemove, GuestExpiryDowngrade:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid guest expiry policy: %s", ErrInvalidInput, p)
+	}
+}
+
+// ExpiredGuestMetadataKey marks a pending membership that was downgraded
+// from an expired guest. Such members cannot accept the tribe themselves.
+const ExpiredGuestMetadataKey = "expired_guest"
+
+// IsExpiredGuest reports whether the membership was downgraded from an
+// expired guest
+func (tm *TribeMember) IsExpiredGuest() bool {
+	expired, _ := tm.Metadata[ExpiredGuestMetadataKey].(bool)
+	return tm.MembershipType == MembershipPending && expired
+}
+
+// GuestExpiryRepository defines the interface for acting on guest expiry
+type GuestExpiryRepository interface {
+	// ExpireGuest removes or downgrades an expired guest, drops their
+	// co-ownership of lists the tribe can see and records an audit entry.
+	// It returns ErrNotFound if the member is no longer an expired guest.
+	ExpireGuest(tribeID, userID uuid.UUID, policy GuestExpiryPolicy) (*TribeAuditEntry, error)
+	// GetUnwarnedGuests returns active guests expiring before the given
+	// time who have not been warned about their current expiry
+	GetUnwarnedGuests(before time.Time) ([]*TribeMember, error)
+	// MarkExpiryWarned records that a guest was warned about an expiry
+	MarkExpiryWarned(tribeID, userID uuid.UUID, expiresAt time.Time) error
+}
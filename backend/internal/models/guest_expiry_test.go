@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestExpiryPolicyValidate(t *testing.T) {
+	assert.NoError(t, GuestExpiryRemove.Validate())
+	assert.NoError(t, GuestExpiryDowngrade.Validate())
+	assert.ErrorIs(t, GuestExpiryPolicy("ignore").Validate(), ErrInvalidInput)
+}
+
+func TestTribeMemberIsExpiredGuest(t *testing.T) {
+	member := TribeMember{MembershipType: MembershipPending, Metadata: JSONMap{ExpiredGuestMetadataKey: true}}
+	assert.True(t, member.IsExpiredGuest())
+
+	member.MembershipType = MembershipFull
+	assert.False(t, member.IsExpiredGuest())
+
+	member = TribeMember{MembershipType: MembershipPending}
+	assert.False(t, member.IsExpiredGuest())
+}
+
+func TestTribeAuditEntryValidate(t *testing.T) {
+	entry := TribeAuditEntry{TribeID: uuid.New(), UserID: uuid.New(), Action: AuditGuestExpired}
+	assert.NoError(t, entry.Validate())
+
+	entry.Action = "renamed"
+	assert.ErrorIs(t, entry.Validate(), ErrInvalidInput)
+
+	entry = TribeAuditEntry{TribeID: uuid.New(), Action: AuditGuestExpiryWarned}
+	assert.ErrorIs(t, entry.Validate(), ErrInvalidInput)
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits on interest indicators
+const (
+	DefaultInterestDuration = 2 * time.Hour
+	MaxInterestDuration     = 7 * 24 * time.Hour
+	MaxInterestMoodLength   = 280
+)
+
+// InterestIndicator says a tribe member is up for something between
+// StartsAt and ExpiresAt, optionally narrowed to one of the tribe's lists or
+// to a type of activity. Expired indicators stop matching immediately and
+// are purged in the background.
+type InterestIndicator struct {
+	ID           uuid.UUID     `json:"id" db:"id"`
+	UserID       uuid.UUID     `json:"user_id" db:"user_id"`
+	TribeID      uuid.UUID     `json:"tribe_id" db:"tribe_id"`
+	ListID       *uuid.UUID    `json:"list_id,omitempty" db:"list_id"`
+	ActivityType *ActivityType `json:"activity_type,omitempty" db:"activity_type"`
+	Mood         string        `json:"mood,omitempty" db:"mood"`
+	StartsAt     time.Time     `json:"starts_at" db:"starts_at"`
+	ExpiresAt    time.Time     `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// Validate performs validation on the interest indicator
+func (i *InterestIndicator) Validate() error {
+	if i.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user ID is required", ErrInvalidInput)
+	}
+	if i.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if i.ListID != nil && i.ActivityType != nil {
+		return fmt.Errorf("%w: an interest can name a list or an activity type, not both", ErrInvalidInput)
+	}
+	if i.ActivityType != nil {
+		if err := i.ActivityType.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(i.Mood) > MaxInterestMoodLength {
+		return fmt.Errorf("%w: mood cannot be longer than %d characters", ErrInvalidInput, MaxInterestMoodLength)
+	}
+	if i.StartsAt.IsZero() {
+		return fmt.Errorf("%w: start time is required", ErrInvalidInput)
+	}
+	if !i.ExpiresAt.After(i.StartsAt) {
+		return fmt.Errorf("%w: expiry must be after the start time", ErrInvalidInput)
+	}
+	if i.ExpiresAt.Sub(i.StartsAt) > MaxInterestDuration {
+		return fmt.Errorf("%w: an interest can last at most %d days", ErrInvalidInput, int(MaxInterestDuration.Hours()/24))
+	}
+	return nil
+}
+
+// IsActive reports whether the indicator is in effect at the given time
+func (i *InterestIndicator) IsActive(now time.Time) bool {
+	return !now.Before(i.StartsAt) && now.Before(i.ExpiresAt)
+}
+
+// IsExpired reports whether the indicator has run out at the given time
+func (i *InterestIndicator) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// InterestFilter narrows indicators to those interested in a list or an
+// activity type. The zero filter matches every indicator.
+type InterestFilter struct {
+	ListID       *uuid.UUID
+	ActivityType *ActivityType
+}
+
+// Matches reports whether the indicator suits the filter. Indicators that
+// name neither a list nor an activity type are up for anything, so they
+// match every filter.
+func (i *InterestIndicator) Matches(filter InterestFilter) bool {
+	if i.ListID == nil && i.ActivityType == nil {
+		return true
+	}
+	if filter.ListID != nil && (i.ListID == nil || *i.ListID != *filter.ListID) {
+		return false
+	}
+	if filter.ActivityType != nil && (i.ActivityType == nil || *i.ActivityType != *filter.ActivityType) {
+		return false
+	}
+	return true
+}
+
+// InterestIndicatorRepository defines the interface for interest indicator
+// operations
+type InterestIndicatorRepository interface {
+	Create(indicator *InterestIndicator) error
+	GetByID(id uuid.UUID) (*InterestIndicator, error)
+	Update(indicator *InterestIndicator) error
+	Delete(id uuid.UUID) error
+	// ListByUser returns a user's indicators that have not expired by now
+	ListByUser(userID uuid.UUID, now time.Time) ([]*InterestIndicator, error)
+	// ListActiveByTribe returns the tribe's indicators in effect at now
+	ListActiveByTribe(tribeID uuid.UUID, now time.Time) ([]*InterestIndicator, error)
+	// DeleteExpired removes indicators that expired by now, returning how many
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterestIndicatorValidate(t *testing.T) {
+	userID, tribeID, listID := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	activityType := ActivityTypeLocation
+	badType := ActivityType("nap")
+	valid := func() InterestIndicator {
+		return InterestIndicator{UserID: userID, TribeID: tribeID, StartsAt: start, ExpiresAt: start.Add(2 * time.Hour)}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*InterestIndicator)
+		wantErr bool
+	}{
+		{name: "anything", modify: func(*InterestIndicator) {}},
+		{name: "list", modify: func(i *InterestIndicator) { i.ListID = &listID }},
+		{name: "activity type", modify: func(i *InterestIndicator) { i.ActivityType = &activityType }},
+		{name: "longest span", modify: func(i *InterestIndicator) { i.ExpiresAt = start.Add(MaxInterestDuration) }},
+		{name: "missing user", modify: func(i *InterestIndicator) { i.UserID = uuid.Nil }, wantErr: true},
+		{name: "missing tribe", modify: func(i *InterestIndicator) { i.TribeID = uuid.Nil }, wantErr: true},
+		{name: "list and type", modify: func(i *InterestIndicator) { i.ListID, i.ActivityType = &listID, &activityType }, wantErr: true},
+		{name: "unknown type", modify: func(i *InterestIndicator) { i.ActivityType = &badType }, wantErr: true},
+		{name: "long mood", modify: func(i *InterestIndicator) { i.Mood = strings.Repeat("a", MaxInterestMoodLength+1) }, wantErr: true},
+		{name: "missing start", modify: func(i *InterestIndicator) { i.StartsAt = time.Time{} }, wantErr: true},
+		{name: "expires at start", modify: func(i *InterestIndicator) { i.ExpiresAt = start }, wantErr: true},
+		{name: "span too long", modify: func(i *InterestIndicator) { i.ExpiresAt = start.Add(MaxInterestDuration + time.Minute) }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indicator := valid()
+			tt.modify(&indicator)
+			err := indicator.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInterestIndicatorIsActive(t *testing.T) {
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	indicator := InterestIndicator{StartsAt: start, ExpiresAt: start.Add(time.Hour)}
+
+	assert.False(t, indicator.IsActive(start.Add(-time.Minute)))
+	assert.True(t, indicator.IsActive(start))
+	assert.True(t, indicator.IsActive(start.Add(59*time.Minute)))
+	assert.False(t, indicator.IsActive(start.Add(time.Hour)))
+
+	assert.False(t, indicator.IsExpired(start.Add(59*time.Minute)))
+	assert.True(t, indicator.IsExpired(start.Add(time.Hour)))
+}
+
+func TestInterestIndicatorMatches(t *testing.T) {
+	listID, otherListID := uuid.New(), uuid.New()
+	location, event := ActivityTypeLocation, ActivityTypeEvent
+
+	anything := InterestIndicator{}
+	forList := InterestIndicator{ListID: &listID}
+	forType := InterestIndicator{ActivityType: &location}
+
+	assert.True(t, anything.Matches(InterestFilter{}))
+	assert.True(t, anything.Matches(InterestFilter{ListID: &listID}))
+	assert.True(t, anything.Matches(InterestFilter{ActivityType: &event}))
+
+	assert.True(t, forList.Matches(InterestFilter{}))
+	assert.True(t, forList.Matches(InterestFilter{ListID: &listID}))
+	assert.False(t, forList.Matches(InterestFilter{ListID: &otherListID}))
+	assert.False(t, forList.Matches(InterestFilter{ActivityType: &location}))
+
+	assert.True(t, forType.Matches(InterestFilter{ActivityType: &location}))
+	assert.False(t, forType.Matches(InterestFilter{ActivityType: &event}))
+	assert.False(t, forType.Matches(InterestFilter{ListID: &listID}))
+}
@@ -29,6 +29,21 @@ func (lt ListType) Validate() error {
 	}
 }
 
+// ActivityType returns the kind of activity the list's items are. Saved
+// Google Maps places are locations; general lists hold no one kind.
+func (lt ListType) ActivityType() (ActivityType, bool) {
+	switch lt {
+	case ListTypeLocation, ListTypeGoogleMap:
+		return ActivityTypeLocation, true
+	case ListTypeActivity:
+		return ActivityTypeActivity, true
+	case ListTypeInterest:
+		return ActivityTypeInterest, true
+	default:
+		return "", false
+	}
+}
+
 // ListSyncStatus represents the sync status of a list
 type ListSyncStatus string
 
@@ -175,9 +190,13 @@ type ListItem struct {
 	Seasonal    bool         `json:"seasonal" db:"seasonal"`
 	StartDate   *time.Time   `json:"start_date" db:"start_date"`
 	EndDate     *time.Time   `json:"end_date" db:"end_date"`
-	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
-	DeletedAt   *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+	// DistanceKm is the distance from a menu's origin filter, when one is given
+	DistanceKm *float64 `json:"distance_km,omitempty" db:"-"`
+	// SyncSnapshot is the source-owned fields as of the last sync
+	SyncSnapshot *SyncSnapshot `json:"-" db:"sync_snapshot"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // Validate performs validation on the ListItem
@@ -291,6 +310,9 @@ type ListShare struct {
 	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 	Version   int        `json:"version" db:"version"`
+	// SharedByName is the sharer's name as shown in the tribe, resolved
+	// when shares are listed
+	SharedByName string `json:"shared_by_name,omitempty" db:"-"`
 }
 
 // Validate performs validation on the ListShare
@@ -320,7 +342,9 @@ type ListRepository interface {
 	List(offset, limit int) ([]*List, error)
 
 	// List item operations
-	AddItem(item *ListItem) error
+	// AddItem adds an item on behalf of the user, who is nil for items
+	// created by a sync
+	AddItem(item *ListItem, actorID *uuid.UUID) error
 	UpdateItem(item *ListItem) error
 	RemoveItem(listID, itemID uuid.UUID) error
 	GetItems(listID uuid.UUID) ([]*ListItem, error)
@@ -340,7 +364,7 @@ type ListRepository interface {
 
 	// Share management
 	ShareWithTribe(share *ListShare) error
-	UnshareWithTribe(listID, tribeID uuid.UUID) error
+	UnshareWithTribe(listID, tribeID, userID uuid.UUID) error
 	GetSharedLists(tribeID uuid.UUID) ([]*List, error)
 	GetSharedTribes(listID uuid.UUID) ([]*Tribe, error)
 	GetListShares(listID uuid.UUID) ([]*ListShare, error)
@@ -348,8 +372,14 @@ type ListRepository interface {
 
 	// Sync management
 	UpdateSyncStatus(listID uuid.UUID, status ListSyncStatus) error
+	// RecordSync adds a summary of the items a sync created, updated and
+	// deleted to the feeds of the tribes that can see the list
+	RecordSync(listID uuid.UUID, created, updated, deleted int) error
 	GetConflicts(listID uuid.UUID) ([]*SyncConflict, error)
+	GetConflict(conflictID uuid.UUID) (*SyncConflict, error)
 	CreateConflict(conflict *SyncConflict) error
-	ResolveConflict(conflictID uuid.UUID) error
+	// ResolveConflict closes the conflict and applies the change, if any, to
+	// its item atomically
+	ResolveConflict(conflictID uuid.UUID, resolution string, change *ResolvedItem) error
 	GetListsBySource(source string) ([]*List, error)
 }
@@ -64,6 +64,22 @@ func TestListType_Validate(t *testing.T) {
 	}
 }
 
+func TestListType_ActivityType(t *testing.T) {
+	for listType, want := range map[ListType]ActivityType{
+		ListTypeLocation:  ActivityTypeLocation,
+		ListTypeGoogleMap: ActivityTypeLocation,
+		ListTypeActivity:  ActivityTypeActivity,
+		ListTypeInterest:  ActivityTypeInterest,
+	} {
+		got, ok := listType.ActivityType()
+		assert.True(t, ok, listType)
+		assert.Equal(t, want, got, listType)
+	}
+
+	_, ok := ListTypeGeneral.ActivityType()
+	assert.False(t, ok)
+}
+
 func TestListSyncStatus_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
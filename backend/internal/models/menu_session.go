@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MenuSessionItem is a snapshot of a list item offered in a menu session
+type MenuSessionItem struct {
+	ItemID uuid.UUID `json:"item_id"`
+	ListID uuid.UUID `json:"list_id"`
+	Name   string    `json:"name"`
+	Weight float64   `json:"weight"`
+}
+
+// MenuSessionItems is a slice of offered items stored as JSONB
+type MenuSessionItems []MenuSessionItem
+
+// Value implements the driver.Valuer interface
+func (m MenuSessionItems) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface
+func (m *MenuSessionItems) Scan(value interface{}) error {
+	if value == nil {
+		*m = MenuSessionItems{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("%w: unsupported MenuSessionItems scan type: %T", ErrInvalidInput, value)
+	}
+
+	return json.Unmarshal(bytes, m)
+}
+
+// Contains reports whether the item was offered in the session
+func (m MenuSessionItems) Contains(itemID uuid.UUID) bool {
+	for _, item := range m {
+		if item.ItemID == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements the driver.Valuer interface
+func (m MenuParams) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface
+func (m *MenuParams) Scan(value interface{}) error {
+	if value == nil {
+		*m = MenuParams{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("%w: unsupported MenuParams scan type: %T", ErrInvalidInput, value)
+	}
+
+	return json.Unmarshal(bytes, m)
+}
+
+// MenuSession records a generated menu, the items it offered and the item
+// that was eventually picked
+type MenuSession struct {
+	BaseModel
+	UserID       uuid.UUID        `json:"user_id" db:"user_id"`
+	TribeID      *uuid.UUID       `json:"tribe_id,omitempty" db:"tribe_id"`
+	Params       MenuParams       `json:"params" db:"params"`
+	Items        MenuSessionItems `json:"items" db:"items"`
+	ChosenItemID *uuid.UUID       `json:"chosen_item_id,omitempty" db:"chosen_item_id"`
+	ChosenBy     *uuid.UUID       `json:"chosen_by,omitempty" db:"chosen_by"`
+	ChosenAt     *time.Time       `json:"chosen_at,omitempty" db:"chosen_at"`
+	VotingRule   *VotingRule      `json:"voting_rule,omitempty" db:"voting_rule"`
+	Lists        []*List          `json:"lists,omitempty" db:"-"`
+}
+
+// Validate performs validation on the menu session
+func (s *MenuSession) Validate() error {
+	if err := s.BaseModel.Validate(); err != nil {
+		return err
+	}
+	if s.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+	}
+	if len(s.Params.ListIDs) == 0 {
+		return fmt.Errorf("%w: at least one list ID is required", ErrInvalidInput)
+	}
+	if s.VotingRule != nil {
+		if s.TribeID == nil {
+			return fmt.Errorf("%w: only tribe menus can be voted on", ErrInvalidInput)
+		}
+		if err := s.VotingRule.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.ChosenItemID != nil && !s.Items.Contains(*s.ChosenItemID) {
+		return fmt.Errorf("%w: chosen item was not offered in this session", ErrInvalidInput)
+	}
+	return nil
+}
+
+// IsClosed reports whether an item has already been picked
+func (s *MenuSession) IsClosed() bool {
+	return s.ChosenItemID != nil
+}
+
+// MenuSessionRepository defines the interface for menu session operations
+type MenuSessionRepository interface {
+	Create(session *MenuSession) error
+	GetByID(id uuid.UUID) (*MenuSession, error)
+	// SetChosenItem records the pick and marks the item chosen on its list
+	// atomically
+	SetChosenItem(sessionID, itemID, userID uuid.UUID) error
+	GetUserSessions(userID uuid.UUID, offset, limit int) ([]*MenuSession, error)
+	GetTribeSessions(tribeID uuid.UUID, offset, limit int) ([]*MenuSession, error)
+
+	// Voting
+	CastVotes(votes []*MenuVote) error
+	GetVotes(sessionID uuid.UUID) ([]*MenuVote, error)
+}
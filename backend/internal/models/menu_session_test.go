@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMenuSessionValidation(t *testing.T) {
+	now := time.Now()
+	offeredID := uuid.New()
+	otherID := uuid.New()
+
+	newSession := func() *MenuSession {
+		return &MenuSession{
+			BaseModel: BaseModel{
+				ID:        uuid.New(),
+				CreatedAt: now,
+				UpdatedAt: now,
+				Version:   1,
+			},
+			UserID: uuid.New(),
+			Params: MenuParams{ListIDs: []uuid.UUID{uuid.New()}},
+			Items:  MenuSessionItems{{ItemID: offeredID, Name: "Tacos", Weight: 1}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(s *MenuSession)
+		expectErr bool
+	}{
+		{
+			name:      "valid session",
+			modify:    func(s *MenuSession) {},
+			expectErr: false,
+		},
+		{
+			name:      "valid pick",
+			modify:    func(s *MenuSession) { s.ChosenItemID = &offeredID },
+			expectErr: false,
+		},
+		{
+			name:      "missing user",
+			modify:    func(s *MenuSession) { s.UserID = uuid.Nil },
+			expectErr: true,
+		},
+		{
+			name:      "missing lists",
+			modify:    func(s *MenuSession) { s.Params.ListIDs = nil },
+			expectErr: true,
+		},
+		{
+			name:      "pick not offered",
+			modify:    func(s *MenuSession) { s.ChosenItemID = &otherID },
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newSession()
+			tt.modify(session)
+			err := session.Validate()
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMenuSessionItemsScanValue(t *testing.T) {
+	seed := int64(9)
+	items := MenuSessionItems{{ItemID: uuid.New(), ListID: uuid.New(), Name: "Ramen", Weight: 2.5}}
+
+	value, err := items.Value()
+	require.NoError(t, err)
+
+	var scanned MenuSessionItems
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, items, scanned)
+
+	params := MenuParams{ListIDs: []uuid.UUID{uuid.New()}, Count: 3, Seed: &seed}
+	value, err = params.Value()
+	require.NoError(t, err)
+
+	var scannedParams MenuParams
+	require.NoError(t, scannedParams.Scan(value))
+	assert.Equal(t, params, scannedParams)
+
+	assert.Error(t, scanned.Scan(42))
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VotingRule decides how a tribe's votes on a menu are settled
+type VotingRule string
+
+const (
+	// VotingRuleMajority picks an item approved by more than half of the voters
+	VotingRuleMajority VotingRule = "majority"
+	// VotingRuleUnanimous picks an item approved by every voter
+	VotingRuleUnanimous VotingRule = "unanimous"
+	// VotingRuleFirstVeto drops any vetoed item and picks the best of the rest
+	VotingRuleFirstVeto VotingRule = "first_veto"
+)
+
+// Validate checks if the voting rule is valid
+func (r VotingRule) Validate() error {
+	switch r {
+	case VotingRuleMajority, VotingRuleUnanimous, VotingRuleFirstVeto:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidVotingRule, r)
+	}
+}
+
+// VoteKind is the kind of vote cast on a menu item
+type VoteKind string
+
+const (
+	VoteApprove VoteKind = "approve"
+	VoteVeto    VoteKind = "veto"
+	VoteRank    VoteKind = "rank"
+)
+
+// Validate checks if the vote kind is valid
+func (k VoteKind) Validate() error {
+	switch k {
+	case VoteApprove, VoteVeto, VoteRank:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid vote kind: %s", ErrInvalidInput, k)
+	}
+}
+
+// MenuVote is one member's vote on one item of a menu session. Ranked votes
+// count as approval and also earn points, rank 1 being the favourite.
+type MenuVote struct {
+	SessionID uuid.UUID `json:"session_id" db:"session_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	ItemID    uuid.UUID `json:"item_id" db:"item_id"`
+	Kind      VoteKind  `json:"kind" db:"kind"`
+	Rank      *int      `json:"rank,omitempty" db:"rank"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate performs validation on the vote
+func (v *MenuVote) Validate() error {
+	if v.SessionID == uuid.Nil {
+		return fmt.Errorf("%w: session_id is required", ErrInvalidInput)
+	}
+	if v.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+	}
+	if v.ItemID == uuid.Nil {
+		return fmt.Errorf("%w: item_id is required", ErrInvalidInput)
+	}
+	if err := v.Kind.Validate(); err != nil {
+		return err
+	}
+	if v.Kind == VoteRank {
+		if v.Rank == nil || *v.Rank < 1 {
+			return fmt.Errorf("%w: rank must be at least 1", ErrInvalidInput)
+		}
+	} else if v.Rank != nil {
+		return fmt.Errorf("%w: rank is only allowed on ranked votes", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ValidateBallot checks one member's votes on a session together: each item
+// may be voted on once and no two ranked votes may share a rank
+func ValidateBallot(votes []*MenuVote) error {
+	items := make(map[uuid.UUID]bool, len(votes))
+	ranks := make(map[int]bool, len(votes))
+	for _, vote := range votes {
+		if items[vote.ItemID] {
+			return fmt.Errorf("%w: item %s is voted on more than once", ErrInvalidInput, vote.ItemID)
+		}
+		items[vote.ItemID] = true
+
+		if vote.Kind == VoteRank && vote.Rank != nil {
+			if ranks[*vote.Rank] {
+				return fmt.Errorf("%w: rank %d is given to more than one item", ErrInvalidInput, *vote.Rank)
+			}
+			ranks[*vote.Rank] = true
+		}
+	}
+	return nil
+}
+
+// VoteResult summarises the votes on a single item
+type VoteResult struct {
+	ItemID    uuid.UUID `json:"item_id"`
+	Name      string    `json:"name"`
+	Approvals int       `json:"approvals"`
+	Vetoes    int       `json:"vetoes"`
+	Score     int       `json:"score"`
+}
+
+// VoteTally is the state of the vote on a menu session
+type VoteTally struct {
+	SessionID      uuid.UUID     `json:"session_id"`
+	Rule           VotingRule    `json:"rule"`
+	EligibleVoters int           `json:"eligible_voters"`
+	Voters         int           `json:"voters"`
+	Results        []*VoteResult `json:"results"`
+	WinnerID       *uuid.UUID    `json:"winner_id,omitempty"`
+}
+
+// TallyVotes counts the votes cast by eligible voters on the offered items
+// and picks a winner under the rule. Items satisfying the rule are ordered by
+// approvals, then rank score, then the order they were offered in.
+func TallyVotes(sessionID uuid.UUID, rule VotingRule, items MenuSessionItems, votes []*MenuVote, eligible map[uuid.UUID]bool) *VoteTally {
+	tally := &VoteTally{
+		SessionID:      sessionID,
+		Rule:           rule,
+		EligibleVoters: len(eligible),
+		Results:        make([]*VoteResult, 0, len(items)),
+	}
+
+	results := make(map[uuid.UUID]*VoteResult, len(items))
+	for _, item := range items {
+		result := &VoteResult{ItemID: item.ItemID, Name: item.Name}
+		results[item.ItemID] = result
+		tally.Results = append(tally.Results, result)
+	}
+
+	voters := make(map[uuid.UUID]bool)
+	for _, vote := range votes {
+		result, ok := results[vote.ItemID]
+		if !ok || !eligible[vote.UserID] {
+			continue
+		}
+		voters[vote.UserID] = true
+
+		switch vote.Kind {
+		case VoteApprove:
+			result.Approvals++
+		case VoteVeto:
+			result.Vetoes++
+		case VoteRank:
+			result.Approvals++
+			// Rank 1 earns one point per offered item, the last rank earns one
+			if vote.Rank != nil && *vote.Rank <= len(items) {
+				result.Score += len(items) - *vote.Rank + 1
+			}
+		}
+	}
+	tally.Voters = len(voters)
+
+	var winner *VoteResult
+	for _, result := range tally.Results {
+		if !rule.satisfiedBy(result, tally.EligibleVoters) {
+			continue
+		}
+		if winner == nil ||
+			result.Approvals > winner.Approvals ||
+			(result.Approvals == winner.Approvals && result.Score > winner.Score) {
+			winner = result
+		}
+	}
+	if winner != nil {
+		tally.WinnerID = &winner.ItemID
+	}
+
+	return tally
+}
+
+// satisfiedBy reports whether an item's votes meet the rule
+func (r VotingRule) satisfiedBy(result *VoteResult, eligibleVoters int) bool {
+	switch r {
+	case VotingRuleMajority:
+		return result.Approvals*2 > eligibleVoters
+	case VotingRuleUnanimous:
+		return eligibleVoters > 0 && result.Approvals == eligibleVoters && result.Vetoes == 0
+	case VotingRuleFirstVeto:
+		return result.Vetoes == 0 && result.Approvals > 0
+	default:
+		return false
+	}
+}
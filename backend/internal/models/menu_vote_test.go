@@ -0,0 +1,181 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVotingRuleValidate(t *testing.T) {
+	assert.NoError(t, VotingRuleMajority.Validate())
+	assert.NoError(t, VotingRuleUnanimous.Validate())
+	assert.NoError(t, VotingRuleFirstVeto.Validate())
+	assert.ErrorIs(t, VotingRule("dictator").Validate(), ErrInvalidVotingRule)
+}
+
+func TestMenuVoteValidate(t *testing.T) {
+	rank := 1
+	zero := 0
+	base := func() *MenuVote {
+		return &MenuVote{SessionID: uuid.New(), UserID: uuid.New(), ItemID: uuid.New(), Kind: VoteApprove}
+	}
+
+	assert.NoError(t, base().Validate())
+
+	ranked := base()
+	ranked.Kind = VoteRank
+	ranked.Rank = &rank
+	assert.NoError(t, ranked.Validate())
+
+	missingRank := base()
+	missingRank.Kind = VoteRank
+	assert.ErrorIs(t, missingRank.Validate(), ErrInvalidInput)
+
+	badRank := base()
+	badRank.Kind = VoteRank
+	badRank.Rank = &zero
+	assert.ErrorIs(t, badRank.Validate(), ErrInvalidInput)
+
+	strayRank := base()
+	strayRank.Rank = &rank
+	assert.ErrorIs(t, strayRank.Validate(), ErrInvalidInput)
+
+	badKind := base()
+	badKind.Kind = "maybe"
+	assert.ErrorIs(t, badKind.Validate(), ErrInvalidInput)
+}
+
+func TestValidateBallot(t *testing.T) {
+	one, two := 1, 2
+	tacos, ramen := uuid.New(), uuid.New()
+
+	assert.NoError(t, ValidateBallot([]*MenuVote{
+		{ItemID: tacos, Kind: VoteRank, Rank: &one},
+		{ItemID: ramen, Kind: VoteRank, Rank: &two},
+	}))
+	assert.ErrorIs(t, ValidateBallot([]*MenuVote{
+		{ItemID: tacos, Kind: VoteRank, Rank: &one},
+		{ItemID: ramen, Kind: VoteRank, Rank: &one},
+	}), ErrInvalidInput)
+	assert.ErrorIs(t, ValidateBallot([]*MenuVote{
+		{ItemID: tacos, Kind: VoteApprove},
+		{ItemID: tacos, Kind: VoteVeto},
+	}), ErrInvalidInput)
+}
+
+func TestTallyVotes(t *testing.T) {
+	sessionID := uuid.New()
+	alice, bob, carol, guest := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	tacos, ramen, pizza := uuid.New(), uuid.New(), uuid.New()
+
+	items := MenuSessionItems{
+		{ItemID: tacos, Name: "Tacos"},
+		{ItemID: ramen, Name: "Ramen"},
+		{ItemID: pizza, Name: "Pizza"},
+	}
+	eligible := map[uuid.UUID]bool{alice: true, bob: true, carol: true}
+
+	vote := func(user, item uuid.UUID, kind VoteKind) *MenuVote {
+		return &MenuVote{SessionID: sessionID, UserID: user, ItemID: item, Kind: kind}
+	}
+	rank := func(user, item uuid.UUID, r int) *MenuVote {
+		v := vote(user, item, VoteRank)
+		v.Rank = &r
+		return v
+	}
+
+	tests := []struct {
+		name   string
+		rule   VotingRule
+		votes  []*MenuVote
+		winner *uuid.UUID
+	}{
+		{
+			name: "majority picks the item most members approve",
+			rule: VotingRuleMajority,
+			votes: []*MenuVote{
+				vote(alice, tacos, VoteApprove),
+				vote(bob, tacos, VoteApprove),
+				vote(carol, ramen, VoteApprove),
+			},
+			winner: &tacos,
+		},
+		{
+			name: "majority needs more than half of eligible members",
+			rule: VotingRuleMajority,
+			votes: []*MenuVote{
+				vote(alice, tacos, VoteApprove),
+				vote(bob, ramen, VoteApprove),
+			},
+			winner: nil,
+		},
+		{
+			name: "votes from ineligible members are ignored",
+			rule: VotingRuleMajority,
+			votes: []*MenuVote{
+				vote(alice, pizza, VoteApprove),
+				vote(guest, pizza, VoteApprove),
+			},
+			winner: nil,
+		},
+		{
+			name: "unanimous requires every member",
+			rule: VotingRuleUnanimous,
+			votes: []*MenuVote{
+				vote(alice, tacos, VoteApprove),
+				vote(bob, tacos, VoteApprove),
+				vote(carol, tacos, VoteVeto),
+				vote(alice, ramen, VoteApprove),
+				vote(bob, ramen, VoteApprove),
+				rank(carol, ramen, 1),
+			},
+			winner: &ramen,
+		},
+		{
+			name: "first veto kills an otherwise popular item",
+			rule: VotingRuleFirstVeto,
+			votes: []*MenuVote{
+				vote(alice, tacos, VoteApprove),
+				vote(bob, tacos, VoteApprove),
+				vote(carol, tacos, VoteVeto),
+				vote(carol, pizza, VoteApprove),
+			},
+			winner: &pizza,
+		},
+		{
+			name: "ranks break ties between equally approved items",
+			rule: VotingRuleFirstVeto,
+			votes: []*MenuVote{
+				rank(alice, ramen, 2),
+				rank(alice, pizza, 1),
+				rank(bob, ramen, 1),
+				rank(bob, pizza, 2),
+				rank(carol, ramen, 3),
+				rank(carol, pizza, 1),
+			},
+			winner: &pizza,
+		},
+		{
+			name:   "no votes means no winner",
+			rule:   VotingRuleFirstVeto,
+			votes:  nil,
+			winner: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := TallyVotes(sessionID, tt.rule, items, tt.votes, eligible)
+			assert.Equal(t, 3, tally.EligibleVoters)
+			require.Len(t, tally.Results, len(items))
+			if tt.winner == nil {
+				assert.Nil(t, tally.WinnerID)
+			} else {
+				require.NotNil(t, tally.WinnerID)
+				assert.Equal(t, *tt.winner, *tally.WinnerID)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultEmailInvitationDuration is how long an email invitation waits for
+// the invitee to sign up
+const DefaultEmailInvitationDuration = 30 * 24 * time.Hour
+
+// PendingInvitation invites an email address that has no account yet to a
+// tribe. When a user with that email signs up, the invitation becomes a
+// pending tribe membership.
+type PendingInvitation struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Email       string     `json:"email" db:"email"`
+	TribeID     uuid.UUID  `json:"tribe_id" db:"tribe_id"`
+	InvitedBy   uuid.UUID  `json:"invited_by" db:"invited_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty" db:"converted_at"`
+}
+
+// NormalizeEmail returns the form emails are stored and matched in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Validate performs validation on the invitation
+func (i *PendingInvitation) Validate() error {
+	if i.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if i.InvitedBy == uuid.Nil {
+		return fmt.Errorf("%w: inviter is required", ErrInvalidInput)
+	}
+	if addr, err := mail.ParseAddress(i.Email); err != nil || addr.Address != i.Email || addr.Name != "" {
+		return fmt.Errorf("%w: invalid email address", ErrInvalidInput)
+	}
+	if i.ExpiresAt.IsZero() {
+		return fmt.Errorf("%w: expiry is required", ErrInvalidInput)
+	}
+	return nil
+}
+
+// PendingInvitationRepository defines the interface for email invitation
+// persistence
+type PendingInvitationRepository interface {
+	// Create stores an invitation. It returns ErrDuplicate when the email
+	// already has an open invitation to the tribe.
+	Create(invitation *PendingInvitation) error
+	GetByID(id uuid.UUID) (*PendingInvitation, error)
+	// ListByTribe returns the tribe's open invitations, newest first
+	ListByTribe(tribeID uuid.UUID) ([]*PendingInvitation, error)
+	// Delete removes one of the tribe's open invitations
+	Delete(tribeID, invitationID uuid.UUID) error
+	// ConvertForUser turns the open, unexpired invitations for the user's
+	// email into pending memberships and returns them
+	ConvertForUser(userID uuid.UUID, email string) ([]*PendingInvitation, error)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "bob@example.com", NormalizeEmail("  Bob@Example.COM\n"))
+}
+
+func TestPendingInvitationValidate(t *testing.T) {
+	valid := func() PendingInvitation {
+		return PendingInvitation{
+			Email:     "bob@example.com",
+			TribeID:   uuid.New(),
+			InvitedBy: uuid.New(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	invitation := valid()
+	assert.NoError(t, invitation.Validate())
+
+	for _, email := range []string{"", "bob", "Bob <bob@example.com>", " bob@example.com", "bob@example.com, eve@example.com"} {
+		invitation = valid()
+		invitation.Email = email
+		assert.ErrorIs(t, invitation.Validate(), ErrInvalidInput, email)
+	}
+
+	invitation = valid()
+	invitation.TribeID = uuid.Nil
+	assert.ErrorIs(t, invitation.Validate(), ErrInvalidInput)
+
+	invitation = valid()
+	invitation.InvitedBy = uuid.Nil
+	assert.ErrorIs(t, invitation.Validate(), ErrInvalidInput)
+
+	invitation = valid()
+	invitation.ExpiresAt = time.Time{}
+	assert.ErrorIs(t, invitation.Validate(), ErrInvalidInput)
+}
@@ -0,0 +1,393 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a recurrence rule
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// maxRecurrencePeriods bounds how many periods an expansion walks through so
+// that rules which never produce an instance cannot loop forever
+const maxRecurrencePeriods = 50000
+
+// rruleTimeFormat is the UTC DATE-TIME form used for UNTIL
+const rruleTimeFormat = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is one BYDAY entry. A non-zero Ordinal selects the nth (or, when
+// negative, the nth from last) such weekday of the month.
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// String renders the entry as it appears in an RRULE, e.g. "MO" or "-1FR"
+func (w WeekdayNum) String() string {
+	if w.Ordinal == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return strconv.Itoa(w.Ordinal) + weekdayNames[w.Weekday]
+}
+
+// RecurrenceRule is the subset of an RFC 5545 RRULE supported for events:
+// FREQ, INTERVAL, BYDAY, COUNT and UNTIL
+type RecurrenceRule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    *time.Time
+}
+
+// ParseRecurrenceRule parses an RRULE value such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=10". An optional "RRULE:" prefix
+// is accepted. A floating or date-only UNTIL is read in loc.
+func ParseRecurrenceRule(value string, loc *time.Location) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("%w: recurrence rule is empty", ErrInvalidInput)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if !ok || name == "" || val == "" {
+			return nil, fmt.Errorf("%w: malformed recurrence rule part %q", ErrInvalidInput, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: recurrence rule part %s repeated", ErrInvalidInput, name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid INTERVAL %q", ErrInvalidInput, val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid COUNT %q", ErrInvalidInput, val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val, loc)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, err := parseWeekdayNum(code)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			// Weeks always start on Monday, the RFC 5545 default
+			if strings.ToUpper(val) != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidInput)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported recurrence rule part %s", ErrInvalidInput, name)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(rruleTimeFormat, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	// A date-only UNTIL includes the whole day
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid UNTIL %q", ErrInvalidInput, value)
+}
+
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 2 {
+		return WeekdayNum{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidInput, code)
+	}
+	weekday, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidInput, code)
+	}
+	day := WeekdayNum{Weekday: weekday}
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidInput, code)
+		}
+		day.Ordinal = n
+	}
+	return day, nil
+}
+
+// Validate checks that the rule is complete and only combines supported parts
+func (r *RecurrenceRule) Validate() error {
+	switch r.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	case "":
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidInput)
+	default:
+		return fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidInput, r.Freq)
+	}
+	if r.Interval < 1 {
+		return fmt.Errorf("%w: INTERVAL must be positive", ErrInvalidInput)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("%w: COUNT must be positive", ErrInvalidInput)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return fmt.Errorf("%w: COUNT and UNTIL cannot both be set", ErrInvalidInput)
+	}
+	if r.Freq == FrequencyYearly && len(r.ByDay) > 0 {
+		return fmt.Errorf("%w: BYDAY is not supported with FREQ=YEARLY", ErrInvalidInput)
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != FrequencyMonthly {
+			return fmt.Errorf("%w: numbered BYDAY is only supported with FREQ=MONTHLY", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// String renders the rule in canonical RRULE form, without the "RRULE:" prefix
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(rruleTimeFormat))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the start of every instance beginning in [from, to), in
+// order. dtstart is the first instance, and its location decides the wall
+// clock that instances keep across daylight saving changes.
+func (r *RecurrenceRule) Between(dtstart, from, to time.Time) []time.Time {
+	var starts []time.Time
+	emitted := 0
+	// emit records an instance, reporting false once the expansion is done
+	emit := func(start time.Time) bool {
+		if r.Count > 0 && emitted >= r.Count {
+			return false
+		}
+		if (r.Until != nil && start.After(*r.Until)) || !start.Before(to) {
+			return false
+		}
+		emitted++
+		if !start.Before(from) {
+			starts = append(starts, start)
+		}
+		return true
+	}
+
+	// DTSTART is always the first instance, even when the rule would not
+	// produce it
+	if !emit(dtstart) {
+		return starts
+	}
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, start := range r.periodStarts(dtstart, period) {
+			if !start.After(dtstart) {
+				continue
+			}
+			if !emit(start) {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// SkipTo returns a later start the rule can be expanded from in place of
+// dtstart, so expanding a long-running rule over a recent window does not
+// walk every earlier period. The start is a whole number of periods after
+// dtstart and before t, so expanding from t yields the same instances.
+// Rules with a COUNT, and monthly and yearly rules, keep dtstart.
+func (r *RecurrenceRule) SkipTo(dtstart, t time.Time) time.Time {
+	var periodDays int
+	switch {
+	case r.Count > 0:
+		return dtstart
+	case r.Freq == FrequencyDaily:
+		periodDays = r.Interval
+	case r.Freq == FrequencyWeekly:
+		periodDays = 7 * r.Interval
+	default:
+		return dtstart
+	}
+
+	// Keep a period in hand so daylight saving shifts cannot overshoot t
+	periods := int(t.Sub(dtstart).Hours()/24)/periodDays - 1
+	if periods <= 0 {
+		return dtstart
+	}
+	year, month, day := dtstart.Date()
+	hour, minute, second := dtstart.Clock()
+	return time.Date(year, month, day+periods*periodDays, hour, minute, second, dtstart.Nanosecond(), dtstart.Location())
+}
+
+// Includes reports whether the rule produces an instance starting at t
+func (r *RecurrenceRule) Includes(dtstart, t time.Time) bool {
+	starts := r.Between(dtstart, t, t.Add(time.Second))
+	return len(starts) > 0 && starts[0].Equal(t)
+}
+
+// periodStarts returns the candidate starts of the nth period after the one
+// containing dtstart, in order
+func (r *RecurrenceRule) periodStarts(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	hour, minute, second := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, loc)
+	}
+	year, month, day := dtstart.Date()
+	step := n * r.Interval
+
+	switch r.Freq {
+	case FrequencyDaily:
+		start := at(year, month, day+step)
+		if len(r.ByDay) > 0 && !r.matchesWeekday(start.Weekday()) {
+			return nil
+		}
+		return []time.Time{start}
+
+	case FrequencyWeekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(year, month, day+7*step)}
+		}
+		// Weeks start on Monday
+		monday := day - (int(dtstart.Weekday())+6)%7 + 7*step
+		starts := make([]time.Time, 0, len(r.ByDay))
+		for offset := 0; offset < 7; offset++ {
+			candidate := at(year, month, monday+offset)
+			if r.matchesWeekday(candidate.Weekday()) {
+				starts = append(starts, candidate)
+			}
+		}
+		return starts
+
+	case FrequencyMonthly:
+		first := at(year, month+time.Month(step), 1)
+		if len(r.ByDay) == 0 {
+			candidate := at(first.Year(), first.Month(), day)
+			// Months without the day are skipped, as RFC 5545 requires
+			if candidate.Month() != first.Month() {
+				return nil
+			}
+			return []time.Time{candidate}
+		}
+		return r.monthlyByDay(first, at)
+
+	case FrequencyYearly:
+		candidate := at(year+step, month, day)
+		if candidate.Month() != month {
+			return nil
+		}
+		return []time.Time{candidate}
+	}
+	return nil
+}
+
+// monthlyByDay expands BYDAY within the month starting at first
+func (r *RecurrenceRule) monthlyByDay(first time.Time, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInMonth := at(first.Year(), first.Month()+1, 0).Day()
+	seen := make(map[int]bool)
+	var days []int
+	for _, byDay := range r.ByDay {
+		var matches []int
+		for d := 1; d <= daysInMonth; d++ {
+			if at(first.Year(), first.Month(), d).Weekday() == byDay.Weekday {
+				matches = append(matches, d)
+			}
+		}
+		switch {
+		case byDay.Ordinal == 0:
+		case byDay.Ordinal > 0 && byDay.Ordinal <= len(matches):
+			matches = matches[byDay.Ordinal-1 : byDay.Ordinal]
+		case byDay.Ordinal < 0 && -byDay.Ordinal <= len(matches):
+			matches = matches[len(matches)+byDay.Ordinal : len(matches)+byDay.Ordinal+1]
+		default:
+			matches = nil
+		}
+		for _, d := range matches {
+			if !seen[d] {
+				seen[d] = true
+				days = append(days, d)
+			}
+		}
+	}
+	sort.Ints(days)
+
+	starts := make([]time.Time, len(days))
+	for i, d := range days {
+		starts[i] = at(first.Year(), first.Month(), d)
+	}
+	return starts
+}
+
+func (r *RecurrenceRule) matchesWeekday(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      string
+		expectErr bool
+	}{
+		{name: "weekly", value: "FREQ=WEEKLY", want: "FREQ=WEEKLY"},
+		{name: "prefixed", value: "RRULE:FREQ=DAILY;INTERVAL=2", want: "FREQ=DAILY;INTERVAL=2"},
+		{name: "lower case", value: "freq=weekly;byday=tu,th", want: "FREQ=WEEKLY;BYDAY=TU,TH"},
+		{name: "count", value: "FREQ=MONTHLY;BYDAY=1FR;COUNT=6", want: "FREQ=MONTHLY;BYDAY=1FR;COUNT=6"},
+		{name: "last weekday", value: "FREQ=MONTHLY;BYDAY=-1SU", want: "FREQ=MONTHLY;BYDAY=-1SU"},
+		{name: "utc until", value: "FREQ=DAILY;UNTIL=20240110T120000Z", want: "FREQ=DAILY;UNTIL=20240110T120000Z"},
+		{name: "date until", value: "FREQ=DAILY;UNTIL=20240110", want: "FREQ=DAILY;UNTIL=20240110T235959Z"},
+		{name: "interval one is dropped", value: "FREQ=YEARLY;INTERVAL=1", want: "FREQ=YEARLY"},
+		{name: "default week start", value: "FREQ=WEEKLY;WKST=MO", want: "FREQ=WEEKLY"},
+		{name: "empty", value: "", expectErr: true},
+		{name: "missing freq", value: "INTERVAL=2", expectErr: true},
+		{name: "unknown freq", value: "FREQ=HOURLY", expectErr: true},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0", expectErr: true},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=2;UNTIL=20240110", expectErr: true},
+		{name: "bad weekday", value: "FREQ=WEEKLY;BYDAY=XX", expectErr: true},
+		{name: "numbered weekday outside monthly", value: "FREQ=WEEKLY;BYDAY=2MO", expectErr: true},
+		{name: "yearly by day", value: "FREQ=YEARLY;BYDAY=MO", expectErr: true},
+		{name: "unsupported part", value: "FREQ=MONTHLY;BYMONTHDAY=1", expectErr: true},
+		{name: "repeated part", value: "FREQ=DAILY;FREQ=WEEKLY", expectErr: true},
+		{name: "malformed part", value: "FREQ=DAILY;COUNT", expectErr: true},
+		{name: "other week start", value: "FREQ=WEEKLY;WKST=SU", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.value, time.UTC)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+		})
+	}
+}
+
+func TestRecurrenceRuleBetween(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+
+	dates := func(starts []time.Time) []string {
+		out := make([]string, len(starts))
+		for i, start := range starts {
+			out[i] = start.Format("2006-01-02 15:04")
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		from    time.Time
+		to      time.Time
+		want    []string
+	}{
+		{
+			name:    "daily with count",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-01 19:00", "2024-01-02 19:00", "2024-01-03 19:00"},
+		},
+		{
+			name:    "count includes instances before the window",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-02 19:00", "2024-01-03 19:00"},
+		},
+		{
+			name:    "weekly date night every other friday",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR",
+			dtstart: time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-05 19:00", "2024-01-19 19:00", "2024-02-02 19:00"},
+		},
+		{
+			name:    "weekly on two days",
+			rule:    "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4",
+			dtstart: time.Date(2024, 1, 2, 18, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-02 18:00", "2024-01-04 18:00", "2024-01-09 18:00", "2024-01-11 18:00"},
+		},
+		{
+			name:    "dtstart off the rule is still the first instance",
+			rule:    "FREQ=WEEKLY;BYDAY=MO;COUNT=2",
+			dtstart: time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-03 18:00", "2024-01-08 18:00"},
+		},
+		{
+			name:    "monthly game night on the first friday",
+			rule:    "FREQ=MONTHLY;BYDAY=1FR",
+			dtstart: time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-05 20:00", "2024-02-02 20:00", "2024-03-01 20:00"},
+		},
+		{
+			name:    "last sunday of the month",
+			rule:    "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3",
+			dtstart: time.Date(2024, 1, 28, 10, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-28 10:00", "2024-02-25 10:00", "2024-03-31 10:00"},
+		},
+		{
+			name:    "monthly skips months without the day",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-31 09:00", "2024-03-31 09:00", "2024-05-31 09:00"},
+		},
+		{
+			name:    "yearly skips missing leap days",
+			rule:    "FREQ=YEARLY;COUNT=2",
+			dtstart: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-02-29 09:00", "2028-02-29 09:00"},
+		},
+		{
+			name:    "until is inclusive",
+			rule:    "FREQ=DAILY;UNTIL=20240103T190000Z",
+			dtstart: time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-01 19:00", "2024-01-02 19:00", "2024-01-03 19:00"},
+		},
+		{
+			name:    "daily limited by weekday",
+			rule:    "FREQ=DAILY;BYDAY=SA,SU",
+			dtstart: time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC),
+			from:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			want:    []string{"2024-01-06 09:00", "2024-01-07 09:00", "2024-01-13 09:00", "2024-01-14 09:00"},
+		},
+		{
+			name:    "wall clock kept across daylight saving",
+			rule:    "FREQ=WEEKLY",
+			dtstart: time.Date(2024, 3, 1, 19, 0, 0, 0, chicago),
+			from:    time.Date(2024, 3, 1, 0, 0, 0, 0, chicago),
+			to:      time.Date(2024, 3, 16, 0, 0, 0, 0, chicago),
+			want:    []string{"2024-03-01 19:00", "2024-03-08 19:00", "2024-03-15 19:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule, tt.dtstart.Location())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, dates(rule.Between(tt.dtstart, tt.from, tt.to)))
+		})
+	}
+
+	t.Run("offset changes across daylight saving", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=WEEKLY", chicago)
+		require.NoError(t, err)
+		dtstart := time.Date(2024, 3, 1, 19, 0, 0, 0, chicago)
+		starts := rule.Between(dtstart, dtstart, dtstart.AddDate(0, 0, 15))
+		require.Len(t, starts, 3)
+		assert.Equal(t, 7*24*time.Hour, starts[1].Sub(starts[0]))
+		assert.Equal(t, 7*24*time.Hour-time.Hour, starts[2].Sub(starts[1]))
+	})
+
+	t.Run("includes", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=FR", time.UTC)
+		require.NoError(t, err)
+		dtstart := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+		assert.True(t, rule.Includes(dtstart, time.Date(2024, 1, 12, 19, 0, 0, 0, time.UTC)))
+		assert.False(t, rule.Includes(dtstart, time.Date(2024, 1, 12, 20, 0, 0, 0, time.UTC)))
+		assert.False(t, rule.Includes(dtstart, time.Date(2024, 1, 13, 19, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("skipping ahead keeps the instances", func(t *testing.T) {
+		dtstart := time.Date(2001, 1, 1, 19, 0, 0, 0, chicago)
+		from := time.Date(2024, 3, 1, 0, 0, 0, 0, chicago)
+		to := from.AddDate(0, 0, 21)
+		for _, text := range []string{"FREQ=DAILY;INTERVAL=5", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SA", "FREQ=MONTHLY"} {
+			rule, err := ParseRecurrenceRule(text, chicago)
+			require.NoError(t, err)
+			skipped := rule.SkipTo(dtstart, from)
+			assert.True(t, skipped.Before(from), text)
+			assert.Equal(t, dates(rule.Between(dtstart, from, to)), dates(rule.Between(skipped, from, to)), text)
+		}
+
+		counted, err := ParseRecurrenceRule("FREQ=DAILY;COUNT=10000", chicago)
+		require.NoError(t, err)
+		assert.Equal(t, dtstart, counted.SkipTo(dtstart, from))
+	})
+}
+
+func TestExpandOccurrences(t *testing.T) {
+	start := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+	activity := &Activity{ID: uuid.New(), RecurrenceRule: "FREQ=WEEKLY;COUNT=4", RecurrenceStart: &start}
+	schedule := &ActivitySchedule{
+		ActivityID: activity.ID,
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Hour),
+		TimeZone:   "UTC",
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("plain expansion", func(t *testing.T) {
+		occurrences, err := ExpandOccurrences(activity, schedule, nil, from, to)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 4)
+		assert.Equal(t, start.Add(2*time.Hour), occurrences[0].EndTime)
+		assert.Equal(t, start.AddDate(0, 0, 21), occurrences[3].StartTime)
+	})
+
+	t.Run("instance overlapping the window start", func(t *testing.T) {
+		occurrences, err := ExpandOccurrences(activity, schedule, nil, start.Add(time.Hour), to)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 4)
+		assert.Equal(t, start, occurrences[0].StartTime)
+	})
+
+	t.Run("cancelled and moved instances", func(t *testing.T) {
+		movedStart := start.AddDate(0, 0, 15)
+		movedEnd := movedStart.Add(time.Hour)
+		exceptions := []*OccurrenceException{
+			{ActivityID: activity.ID, OriginalStart: start.AddDate(0, 0, 7), Cancelled: true},
+			{ActivityID: activity.ID, OriginalStart: start.AddDate(0, 0, 14), StartTime: &movedStart, EndTime: &movedEnd},
+		}
+		occurrences, err := ExpandOccurrences(activity, schedule, exceptions, from, to)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 3)
+		assert.Equal(t, movedStart, occurrences[1].StartTime)
+		assert.Equal(t, start.AddDate(0, 0, 14), occurrences[1].OriginalStart)
+		assert.True(t, occurrences[1].Moved)
+	})
+
+	t.Run("instance moved into the window", func(t *testing.T) {
+		movedStart := time.Date(2024, 2, 20, 19, 0, 0, 0, time.UTC)
+		movedEnd := movedStart.Add(time.Hour)
+		exceptions := []*OccurrenceException{
+			{ActivityID: activity.ID, OriginalStart: start, StartTime: &movedStart, EndTime: &movedEnd},
+		}
+		windowStart := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+		occurrences, err := ExpandOccurrences(activity, schedule, exceptions, windowStart, windowStart.AddDate(0, 0, 10))
+		require.NoError(t, err)
+		require.Len(t, occurrences, 1)
+		assert.Equal(t, movedStart, occurrences[0].StartTime)
+	})
+
+	t.Run("activity without recurrence", func(t *testing.T) {
+		single := &Activity{ID: activity.ID}
+		occurrences, err := ExpandOccurrences(single, schedule, nil, from, to)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 1)
+		assert.Equal(t, start, occurrences[0].StartTime)
+
+		occurrences, err = ExpandOccurrences(single, schedule, nil, to, to.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Empty(t, occurrences)
+	})
+}
+
+func TestOccurrenceExceptionValidation(t *testing.T) {
+	start := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+	later := start.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		exception OccurrenceException
+		expectErr bool
+	}{
+		{name: "cancelled", exception: OccurrenceException{ActivityID: uuid.New(), OriginalStart: start, Cancelled: true}},
+		{name: "moved", exception: OccurrenceException{ActivityID: uuid.New(), OriginalStart: start, StartTime: &start, EndTime: &later}},
+		{name: "missing original", exception: OccurrenceException{ActivityID: uuid.New(), Cancelled: true}, expectErr: true},
+		{name: "cancelled with times", exception: OccurrenceException{ActivityID: uuid.New(), OriginalStart: start, Cancelled: true, StartTime: &start}, expectErr: true},
+		{name: "moved without end", exception: OccurrenceException{ActivityID: uuid.New(), OriginalStart: start, StartTime: &start}, expectErr: true},
+		{name: "moved backwards", exception: OccurrenceException{ActivityID: uuid.New(), OriginalStart: start, StartTime: &later, EndTime: &start}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.exception.Validate()
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -98,6 +98,47 @@ func ValidateTransition(from, to ListSyncStatus, action string) error {
 		from, to, action)
 }
 
+// Sync conflict types
+const (
+	// ConflictTypeItemUpdate is an item changed both locally and remotely
+	ConflictTypeItemUpdate = "item_update"
+	// ConflictTypeItemDelete is an item changed locally but removed remotely
+	ConflictTypeItemDelete = "item_delete"
+)
+
+// Conflict resolution strategies
+const (
+	// ResolutionKeepLocal keeps the local item as it is
+	ResolutionKeepLocal = "keep_local"
+	// ResolutionTakeRemote overwrites the local item with the remote version
+	ResolutionTakeRemote = "take_remote"
+	// ResolutionMergeFields takes each field the remote side provides and
+	// keeps local values for the rest
+	ResolutionMergeFields = "merge_fields"
+	// ResolutionCustom applies the caller's values for the source-owned
+	// fields (name, description, latitude, longitude and address). Other
+	// fields, such as weight, are not part of the conflict and are changed
+	// by editing the item.
+	ResolutionCustom = "custom"
+)
+
+// ValidateResolution checks if a conflict resolution strategy is supported
+func ValidateResolution(resolution string) error {
+	switch resolution {
+	case ResolutionKeepLocal, ResolutionTakeRemote, ResolutionMergeFields, ResolutionCustom:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidResolution, resolution)
+	}
+}
+
+// ResolvedItem is the change a conflict resolution makes to its item. It is
+// written in the same transaction that closes the conflict.
+type ResolvedItem struct {
+	Item   *ListItem
+	Remove bool // Remove deletes the item instead of updating it
+}
+
 // SyncConflict represents a sync conflict that needs resolution
 type SyncConflict struct {
 	ID         uuid.UUID   `json:"id" db:"id"`
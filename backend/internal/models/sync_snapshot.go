@@ -0,0 +1,208 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Fields owned by a sync source, compared one by one during a three-way merge.
+// The location field covers the latitude and longitude together.
+const (
+	SyncFieldName        = "name"
+	SyncFieldDescription = "description"
+	SyncFieldLocation    = "location"
+	SyncFieldAddress     = "address"
+)
+
+// SyncFields lists the source-owned fields in merge order
+var SyncFields = []string{SyncFieldName, SyncFieldDescription, SyncFieldLocation, SyncFieldAddress}
+
+// SyncSnapshot holds the source-owned fields of an item as they were at the
+// last sync. It is the common base when local and remote changes are merged.
+type SyncSnapshot struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Address     *string  `json:"address"`
+}
+
+// NewSyncSnapshot captures the source-owned fields of an item
+func NewSyncSnapshot(item *ListItem) *SyncSnapshot {
+	return &SyncSnapshot{
+		Name:        item.Name,
+		Description: item.Description,
+		Latitude:    item.Latitude,
+		Longitude:   item.Longitude,
+		Address:     item.Address,
+	}
+}
+
+// ApplyTo copies the snapshot's fields onto an item
+func (s *SyncSnapshot) ApplyTo(item *ListItem) {
+	item.Name = s.Name
+	item.Description = s.Description
+	item.Latitude = s.Latitude
+	item.Longitude = s.Longitude
+	item.Address = s.Address
+}
+
+// FieldEqual reports whether a field holds the same value in both snapshots
+func (s *SyncSnapshot) FieldEqual(other *SyncSnapshot, field string) bool {
+	switch field {
+	case SyncFieldName:
+		return s.Name == other.Name
+	case SyncFieldDescription:
+		return s.Description == other.Description
+	case SyncFieldLocation:
+		return equalFloatPtr(s.Latitude, other.Latitude) && equalFloatPtr(s.Longitude, other.Longitude)
+	case SyncFieldAddress:
+		return equalStringPtr(s.Address, other.Address)
+	}
+	return true
+}
+
+// Equal reports whether every field matches
+func (s *SyncSnapshot) Equal(other *SyncSnapshot) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	for _, field := range SyncFields {
+		if !s.FieldEqual(other, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// FieldEmpty reports whether a field has no value
+func (s *SyncSnapshot) FieldEmpty(field string) bool {
+	switch field {
+	case SyncFieldName:
+		return s.Name == ""
+	case SyncFieldDescription:
+		return s.Description == ""
+	case SyncFieldLocation:
+		return s.Latitude == nil || s.Longitude == nil
+	case SyncFieldAddress:
+		return s.Address == nil
+	}
+	return true
+}
+
+// CopyFields copies the named fields from another snapshot
+func (s *SyncSnapshot) CopyFields(src *SyncSnapshot, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case SyncFieldName:
+			s.Name = src.Name
+		case SyncFieldDescription:
+			s.Description = src.Description
+		case SyncFieldLocation:
+			s.Latitude = src.Latitude
+			s.Longitude = src.Longitude
+		case SyncFieldAddress:
+			s.Address = src.Address
+		}
+	}
+}
+
+// FieldData returns the named fields keyed by their ListItem JSON names, so a
+// conflict can record just the fields that collided
+func (s *SyncSnapshot) FieldData(fields []string) JSONMap {
+	data := make(JSONMap, len(fields))
+	for _, field := range fields {
+		switch field {
+		case SyncFieldName:
+			data["name"] = s.Name
+		case SyncFieldDescription:
+			data["description"] = s.Description
+		case SyncFieldLocation:
+			data["latitude"] = s.Latitude
+			data["longitude"] = s.Longitude
+		case SyncFieldAddress:
+			data["address"] = s.Address
+		}
+	}
+	return data
+}
+
+// SyncFieldsIn returns the source-owned fields present in conflict data
+// produced by FieldData or a serialized ListItem
+func SyncFieldsIn(data map[string]json.RawMessage) []string {
+	var fields []string
+	for _, field := range SyncFields {
+		present := false
+		if field == SyncFieldLocation {
+			_, lat := data["latitude"]
+			_, lng := data["longitude"]
+			present = lat || lng
+		} else {
+			_, present = data[field]
+		}
+		if present {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// MergeSnapshots performs a field-by-field three-way merge. A field changed on
+// only one side since the base takes that side's value; a field changed on
+// both sides to different values is a collision and keeps the local value.
+// Without a base every differing field collides.
+func MergeSnapshots(base, local, remote *SyncSnapshot) (*SyncSnapshot, []string) {
+	merged := *local
+	var collisions []string
+	for _, field := range SyncFields {
+		switch {
+		case local.FieldEqual(remote, field):
+		case base != nil && local.FieldEqual(base, field):
+			merged.CopyFields(remote, []string{field})
+		case base != nil && remote.FieldEqual(base, field):
+		default:
+			collisions = append(collisions, field)
+		}
+	}
+	return &merged, collisions
+}
+
+// Value implements the driver.Valuer interface
+func (s SyncSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface
+func (s *SyncSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		*s = SyncSnapshot{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("%w: unsupported SyncSnapshot scan type: %T", ErrInvalidInput, value)
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSnapshots(t *testing.T) {
+	address := "1 Main St"
+	moved := "2 Side St"
+	lat, lng := 40.7, -74.0
+
+	base := &SyncSnapshot{Name: "Cafe", Address: &address}
+
+	tests := []struct {
+		name           string
+		base           *SyncSnapshot
+		local          *SyncSnapshot
+		remote         *SyncSnapshot
+		wantMerged     *SyncSnapshot
+		wantCollisions []string
+	}{
+		{
+			name:       "Nothing changed",
+			base:       base,
+			local:      &SyncSnapshot{Name: "Cafe", Address: &address},
+			remote:     &SyncSnapshot{Name: "Cafe", Address: &address},
+			wantMerged: &SyncSnapshot{Name: "Cafe", Address: &address},
+		},
+		{
+			name:       "Remote change only",
+			base:       base,
+			local:      &SyncSnapshot{Name: "Cafe", Address: &address},
+			remote:     &SyncSnapshot{Name: "Cafe", Address: &moved, Latitude: &lat, Longitude: &lng},
+			wantMerged: &SyncSnapshot{Name: "Cafe", Address: &moved, Latitude: &lat, Longitude: &lng},
+		},
+		{
+			name:       "Edits to different fields",
+			base:       base,
+			local:      &SyncSnapshot{Name: "Our Cafe", Address: &address},
+			remote:     &SyncSnapshot{Name: "Cafe", Description: "Cozy", Address: &moved},
+			wantMerged: &SyncSnapshot{Name: "Our Cafe", Description: "Cozy", Address: &moved},
+		},
+		{
+			name:       "Same edit on both sides",
+			base:       base,
+			local:      &SyncSnapshot{Name: "New Cafe", Address: &address},
+			remote:     &SyncSnapshot{Name: "New Cafe", Address: &address},
+			wantMerged: &SyncSnapshot{Name: "New Cafe", Address: &address},
+		},
+		{
+			name:           "Collision keeps the local value",
+			base:           base,
+			local:          &SyncSnapshot{Name: "Our Cafe", Address: &address},
+			remote:         &SyncSnapshot{Name: "Their Cafe", Address: &moved},
+			wantMerged:     &SyncSnapshot{Name: "Our Cafe", Address: &moved},
+			wantCollisions: []string{SyncFieldName},
+		},
+		{
+			name:           "No base collides on every difference",
+			local:          &SyncSnapshot{Name: "Our Cafe", Address: &address},
+			remote:         &SyncSnapshot{Name: "Their Cafe", Address: &address},
+			wantMerged:     &SyncSnapshot{Name: "Our Cafe", Address: &address},
+			wantCollisions: []string{SyncFieldName},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, collisions := MergeSnapshots(tt.base, tt.local, tt.remote)
+			assert.True(t, tt.wantMerged.Equal(merged), "merged = %+v", merged)
+			assert.Equal(t, tt.wantCollisions, collisions)
+		})
+	}
+}
+
+func TestSyncSnapshot_FieldData(t *testing.T) {
+	lat, lng := 40.7, -74.0
+	snapshot := &SyncSnapshot{Name: "Cafe", Latitude: &lat, Longitude: &lng}
+
+	data := snapshot.FieldData([]string{SyncFieldName, SyncFieldLocation})
+	assert.Equal(t, JSONMap{"name": "Cafe", "latitude": &lat, "longitude": &lng}, data)
+
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	var keys map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &keys))
+	assert.Equal(t, []string{SyncFieldName, SyncFieldLocation}, SyncFieldsIn(keys))
+}
+
+func TestSyncSnapshot_Scan(t *testing.T) {
+	var snapshot SyncSnapshot
+	require.NoError(t, snapshot.Scan([]byte(`{"name": "Cafe", "address": "1 Main St"}`)))
+	assert.Equal(t, "Cafe", snapshot.Name)
+	require.NotNil(t, snapshot.Address)
+	assert.Equal(t, "1 Main St", *snapshot.Address)
+
+	assert.Error(t, snapshot.Scan(42))
+}
@@ -116,13 +116,18 @@ func (t *Tribe) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// TribeMember represents a user's membership in a tribe
+// TribeMember represents a user's membership in a tribe. DisplayName and
+// AvatarURL are resolved when read: the member's per-tribe profile where set,
+// otherwise their account name and avatar.
 type TribeMember struct {
 	BaseModel
 	TribeID        uuid.UUID      `json:"tribe_id" db:"tribe_id"`
 	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
 	MembershipType MembershipType `json:"membership_type" db:"membership_type"`
+	Role           TribeRole      `json:"role" db:"role"`
 	DisplayName    string         `json:"display_name" db:"display_name"`
+	AvatarURL      string         `json:"avatar_url,omitempty" db:"avatar_url"`
+	Pronouns       string         `json:"pronouns,omitempty" db:"pronouns"`
 	ExpiresAt      *time.Time     `json:"expires_at,omitempty" db:"expires_at"`
 	InvitedBy      *uuid.UUID     `json:"invited_by,omitempty" db:"invited_by"`
 	InvitedAt      *time.Time     `json:"invited_at,omitempty" db:"invited_at"`
@@ -130,6 +135,15 @@ type TribeMember struct {
 	User           *User          `json:"user,omitempty" db:"-"`
 }
 
+// IsActive reports whether the membership grants access at the given time.
+// Pending invitations, removed members and expired guests do not.
+func (tm *TribeMember) IsActive(now time.Time) bool {
+	if tm.DeletedAt != nil || tm.MembershipType == MembershipPending {
+		return false
+	}
+	return tm.ExpiresAt == nil || tm.ExpiresAt.After(now)
+}
+
 // Validate performs validation on the tribe member
 func (tm *TribeMember) Validate() error {
 	if err := tm.BaseModel.Validate(); err != nil {
@@ -144,6 +158,14 @@ func (tm *TribeMember) Validate() error {
 	if err := tm.MembershipType.Validate(); err != nil {
 		return err
 	}
+	if tm.Role != "" {
+		if err := tm.Role.Validate(); err != nil {
+			return err
+		}
+		if tm.Role != TribeRoleMember && tm.MembershipType != MembershipFull {
+			return fmt.Errorf("%w: only full members can be owners or admins", ErrInvalidInput)
+		}
+	}
 	if tm.DisplayName == "" {
 		return fmt.Errorf("%w: display_name is required", ErrInvalidInput)
 	}
@@ -175,6 +197,9 @@ type TribeRepository interface {
 	GetUserTribes(userID uuid.UUID) ([]*Tribe, error)
 	CheckFormerTribeMember(tribeID, userID uuid.UUID) (bool, error)
 	ReinviteMember(tribeID, userID uuid.UUID, memberType MembershipType, expiresAt *time.Time, invitedBy *uuid.UUID) error
+	ReinviteExpiredGuest(tribeID, userID uuid.UUID, invitedBy *uuid.UUID) error
+	SetMemberRole(tribeID, userID uuid.UUID, role TribeRole) error
+	UpdateMemberProfile(tribeID, userID uuid.UUID, profile *TribeMemberProfile) error
 
 	// Queries
 	GetByType(tribeType TribeType, offset, limit int) ([]*Tribe, error)
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TribeAuditAction names something recorded in a tribe's audit trail
+type TribeAuditAction string
+
+const (
+	AuditGuestExpired      TribeAuditAction = "guest_expired"       // An expired guest was removed or downgraded
+	AuditGuestExpiryWarned TribeAuditAction = "guest_expiry_warned" // A guest was told their access ends soon
+)
+
+func (a TribeAuditAction) Validate() error {
+	switch a {
+	case AuditGuestExpired, AuditGuestExpiryWarned:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid audit action: %s", ErrInvalidInput, a)
+	}
+}
+
+// TribeAuditEntry records a change made to a tribe's membership. ActorID is
+// nil for changes made by the system rather than a member.
+type TribeAuditEntry struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	TribeID   uuid.UUID        `json:"tribe_id" db:"tribe_id"`
+	UserID    uuid.UUID        `json:"user_id" db:"user_id"`
+	ActorID   *uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	Action    TribeAuditAction `json:"action" db:"action"`
+	Details   JSONMap          `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// Validate performs validation on the audit entry
+func (e *TribeAuditEntry) Validate() error {
+	if e.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if e.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user ID is required", ErrInvalidInput)
+	}
+	return e.Action.Validate()
+}
+
+// TribeAuditRepository defines the interface for the tribe audit trail.
+// Entries are never changed once written.
+type TribeAuditRepository interface {
+	Create(entry *TribeAuditEntry) error
+	// ListByTribe returns the tribe's most recent entries, newest first
+	ListByTribe(tribeID uuid.UUID, limit int) ([]*TribeAuditEntry, error)
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feed page sizes
+const (
+	DefaultTribeFeedLimit = 20
+	MaxTribeFeedLimit     = 100
+)
+
+// TribeEventType names something that happened in a tribe
+type TribeEventType string
+
+const (
+	EventMemberJoined      TribeEventType = "member_joined"      // A user became an active member
+	EventMemberLeft        TribeEventType = "member_left"        // An active member left, was removed or expired
+	EventListShared        TribeEventType = "list_shared"        // A list was shared with the tribe
+	EventListUnshared      TribeEventType = "list_unshared"      // A list stopped being shared with the tribe
+	EventListItemAdded     TribeEventType = "list_item_added"    // An item was added to a list the tribe can see
+	EventListSynced        TribeEventType = "list_synced"        // A list the tribe can see was updated from its sync source
+	EventMenuPicked        TribeEventType = "menu_picked"        // An item was picked from a tribe menu
+	EventActivityScheduled TribeEventType = "activity_scheduled" // An activity the tribe can see was scheduled
+)
+
+func (t TribeEventType) Validate() error {
+	switch t {
+	case EventMemberJoined, EventMemberLeft, EventListShared, EventListUnshared,
+		EventListItemAdded, EventListSynced, EventMenuPicked, EventActivityScheduled:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid tribe event type: %s", ErrInvalidInput, t)
+	}
+}
+
+// TribeEvent is one entry in a tribe's feed. SubjectID is what the event is
+// about: the member for membership events, otherwise the list, item, menu
+// session or activity. ActorID is nil when the system made the change.
+// ActorName is resolved through the actor's tribe display name when read.
+type TribeEvent struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	TribeID   uuid.UUID      `json:"tribe_id" db:"tribe_id"`
+	Type      TribeEventType `json:"type" db:"event_type"`
+	ActorID   *uuid.UUID     `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName string         `json:"actor_name,omitempty" db:"-"`
+	SubjectID uuid.UUID      `json:"subject_id" db:"subject_id"`
+	Details   JSONMap        `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Validate performs validation on the event
+func (e *TribeEvent) Validate() error {
+	if e.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if e.SubjectID == uuid.Nil {
+		return fmt.Errorf("%w: subject ID is required", ErrInvalidInput)
+	}
+	return e.Type.Validate()
+}
+
+// TribeEventCursor marks a position in a tribe's feed. Feeds are ordered
+// newest first, so a page continues with the events older than the cursor.
+type TribeEventCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter returns the cursor that continues a feed after the event
+func (e *TribeEvent) CursorAfter() *TribeEventCursor {
+	return &TribeEventCursor{CreatedAt: e.CreatedAt, ID: e.ID}
+}
+
+// String encodes the cursor as an opaque token for clients
+func (c *TribeEventCursor) String() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTribeEventCursor decodes a cursor token produced by String
+func ParseTribeEventCursor(token string) (*TribeEventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	cursor := &TribeEventCursor{}
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	return cursor, nil
+}
+
+// TribeEventRepository defines the interface for tribe feeds. Events are
+// written alongside the changes they describe and never changed afterwards.
+type TribeEventRepository interface {
+	Create(event *TribeEvent) error
+	// ListByTribe returns up to limit events older than the cursor, newest
+	// first; a nil cursor starts from the most recent event
+	ListByTribe(tribeID uuid.UUID, before *TribeEventCursor, limit int) ([]*TribeEvent, error)
+}
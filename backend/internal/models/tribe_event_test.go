@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeEventValidate(t *testing.T) {
+	event := TribeEvent{TribeID: uuid.New(), SubjectID: uuid.New(), Type: EventListShared}
+	assert.NoError(t, event.Validate())
+
+	event.Type = "list_renamed"
+	assert.ErrorIs(t, event.Validate(), ErrInvalidInput)
+
+	event = TribeEvent{TribeID: uuid.New(), Type: EventMemberJoined}
+	assert.ErrorIs(t, event.Validate(), ErrInvalidInput)
+
+	event = TribeEvent{SubjectID: uuid.New(), Type: EventMemberJoined}
+	assert.ErrorIs(t, event.Validate(), ErrInvalidInput)
+}
+
+func TestTribeEventCursor(t *testing.T) {
+	event := TribeEvent{ID: uuid.New(), CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.FixedZone("EST", -5*3600))}
+
+	cursor, err := ParseTribeEventCursor(event.CursorAfter().String())
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, cursor.ID)
+	assert.True(t, event.CreatedAt.Equal(cursor.CreatedAt))
+
+	for _, token := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "eWVzdGVyZGF5fDEyMw"} {
+		_, err = ParseTribeEventCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidInput, token)
+	}
+}
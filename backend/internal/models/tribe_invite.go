@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultInviteDuration is how long an invite link works when no expiry is
+// given
+const DefaultInviteDuration = 7 * 24 * time.Hour
+
+// MaxInviteDuration is the longest an invite link may work
+const MaxInviteDuration = 90 * 24 * time.Hour
+
+// TribeInvite is a shareable link that adds whoever redeems it to a tribe
+// with the invite's membership type. The link carries a signed token derived
+// from the invite ID, so it needs no lookup by secret; revoking or expiring
+// the invite disables it.
+type TribeInvite struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	TribeID        uuid.UUID      `json:"tribe_id" db:"tribe_id"`
+	CreatedBy      uuid.UUID      `json:"created_by" db:"created_by"`
+	MembershipType MembershipType `json:"membership_type" db:"membership_type"`
+	SingleUse      bool           `json:"single_use" db:"single_use"`
+	UseCount       int            `json:"use_count" db:"use_count"`
+	ExpiresAt      time.Time      `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	RevokedAt      *time.Time     `json:"revoked_at,omitempty" db:"revoked_at"`
+	Token          string         `json:"token,omitempty" db:"-"`
+}
+
+// Validate performs validation on the invite. Guests need a per-person
+// expiry, so invites cannot grant guest membership.
+func (i *TribeInvite) Validate() error {
+	if i.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if i.CreatedBy == uuid.Nil {
+		return fmt.Errorf("%w: creator is required", ErrInvalidInput)
+	}
+	if err := i.MembershipType.Validate(); err != nil {
+		return err
+	}
+	if i.MembershipType == MembershipGuest {
+		return fmt.Errorf("%w: invites cannot grant guest membership", ErrInvalidInput)
+	}
+	if i.ExpiresAt.IsZero() {
+		return fmt.Errorf("%w: expiry is required", ErrInvalidInput)
+	}
+	return nil
+}
+
+// IsUsable reports whether the invite can still be redeemed at the given time
+func (i *TribeInvite) IsUsable(now time.Time) bool {
+	if i.RevokedAt != nil || !now.Before(i.ExpiresAt) {
+		return false
+	}
+	return !i.SingleUse || i.UseCount == 0
+}
+
+// TribeInviteRepository defines the interface for invite link persistence
+type TribeInviteRepository interface {
+	Create(invite *TribeInvite) error
+	GetByID(id uuid.UUID) (*TribeInvite, error)
+	// ListByTribe returns the tribe's invites, newest first
+	ListByTribe(tribeID uuid.UUID) ([]*TribeInvite, error)
+	// Revoke disables one of the tribe's invites
+	Revoke(tribeID, inviteID uuid.UUID) error
+	// Redeem adds the user to the invite's tribe and counts the use in one
+	// transaction. It returns ErrInviteUnavailable for an expired, revoked
+	// or used up invite, or one whose creator is no longer a full member of
+	// the tribe, ErrDuplicate if the user is already a member and
+	// ErrForbidden for former members, who must be reinvited directly.
+	Redeem(inviteID, userID uuid.UUID) (*TribeInvite, error)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTribeInviteValidate(t *testing.T) {
+	valid := func() TribeInvite {
+		return TribeInvite{
+			TribeID:        uuid.New(),
+			CreatedBy:      uuid.New(),
+			MembershipType: MembershipFull,
+			ExpiresAt:      time.Now().Add(time.Hour),
+		}
+	}
+
+	invite := valid()
+	assert.NoError(t, invite.Validate())
+	invite.MembershipType = MembershipPending
+	assert.NoError(t, invite.Validate())
+
+	invite = valid()
+	invite.MembershipType = MembershipGuest
+	assert.ErrorIs(t, invite.Validate(), ErrInvalidInput)
+
+	invite = valid()
+	invite.MembershipType = "member"
+	assert.ErrorIs(t, invite.Validate(), ErrInvalidInput)
+
+	invite = valid()
+	invite.CreatedBy = uuid.Nil
+	assert.ErrorIs(t, invite.Validate(), ErrInvalidInput)
+
+	invite = valid()
+	invite.ExpiresAt = time.Time{}
+	assert.ErrorIs(t, invite.Validate(), ErrInvalidInput)
+}
+
+func TestTribeInviteIsUsable(t *testing.T) {
+	now := time.Now()
+	invite := TribeInvite{ExpiresAt: now.Add(time.Hour), UseCount: 3}
+	assert.True(t, invite.IsUsable(now))
+	assert.False(t, invite.IsUsable(now.Add(time.Hour)))
+
+	invite.SingleUse = true
+	assert.False(t, invite.IsUsable(now))
+	invite.UseCount = 0
+	assert.True(t, invite.IsUsable(now))
+
+	invite.RevokedAt = &now
+	assert.False(t, invite.IsUsable(now))
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Limits on per-tribe profile fields
+const (
+	MaxMemberDisplayNameLength = 100
+	MaxMemberPronounsLength    = 50
+	MaxMemberAvatarURLLength   = 2048
+)
+
+// TribeMemberProfile is how a member presents themselves within one tribe.
+// A nil field is left unchanged on update; an empty one clears the override
+// so the account's value is shown instead.
+type TribeMemberProfile struct {
+	DisplayName *string `json:"display_name,omitempty"`
+	AvatarURL   *string `json:"avatar_url,omitempty"`
+	Pronouns    *string `json:"pronouns,omitempty"`
+}
+
+// Normalize trims surrounding whitespace from every set field
+func (p *TribeMemberProfile) Normalize() {
+	for _, field := range []*string{p.DisplayName, p.AvatarURL, p.Pronouns} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+}
+
+// Validate performs validation on the profile
+func (p *TribeMemberProfile) Validate() error {
+	if p.DisplayName == nil && p.AvatarURL == nil && p.Pronouns == nil {
+		return fmt.Errorf("%w: no profile fields to update", ErrInvalidInput)
+	}
+	if p.DisplayName != nil && len(*p.DisplayName) > MaxMemberDisplayNameLength {
+		return fmt.Errorf("%w: display name cannot be longer than %d characters", ErrInvalidInput, MaxMemberDisplayNameLength)
+	}
+	if p.Pronouns != nil && len(*p.Pronouns) > MaxMemberPronounsLength {
+		return fmt.Errorf("%w: pronouns cannot be longer than %d characters", ErrInvalidInput, MaxMemberPronounsLength)
+	}
+	if p.AvatarURL != nil && *p.AvatarURL != "" {
+		if len(*p.AvatarURL) > MaxMemberAvatarURLLength {
+			return fmt.Errorf("%w: avatar URL cannot be longer than %d characters", ErrInvalidInput, MaxMemberAvatarURLLength)
+		}
+		u, err := url.Parse(*p.AvatarURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("%w: invalid avatar URL", ErrInvalidInput)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https":
+		default:
+			return fmt.Errorf("%w: avatar URL must use http or https", ErrInvalidInput)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTribeMemberProfileValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		profile TribeMemberProfile
+		wantErr bool
+	}{
+		{"display name only", TribeMemberProfile{DisplayName: str("Sam")}, false},
+		{"clear all overrides", TribeMemberProfile{DisplayName: str(""), AvatarURL: str(""), Pronouns: str("")}, false},
+		{"full profile", TribeMemberProfile{DisplayName: str("Sam"), AvatarURL: str("https://example.com/sam.png"), Pronouns: str("they/them")}, false},
+		{"nothing to update", TribeMemberProfile{}, true},
+		{"display name too long", TribeMemberProfile{DisplayName: str(strings.Repeat("a", MaxMemberDisplayNameLength+1))}, true},
+		{"pronouns too long", TribeMemberProfile{Pronouns: str(strings.Repeat("a", MaxMemberPronounsLength+1))}, true},
+		{"relative avatar URL", TribeMemberProfile{AvatarURL: str("/sam.png")}, true},
+		{"non-http avatar URL", TribeMemberProfile{AvatarURL: str("javascript://example.com/x")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTribeMemberProfileNormalize(t *testing.T) {
+	name, pronouns := "  Sam ", "\tthey/them\n"
+	profile := TribeMemberProfile{DisplayName: &name, Pronouns: &pronouns}
+	profile.Normalize()
+	assert.Equal(t, "Sam", *profile.DisplayName)
+	assert.Equal(t, "they/them", *profile.Pronouns)
+	assert.Nil(t, profile.AvatarURL)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OwnershipTransferStatus is where an ownership transfer stands
+type OwnershipTransferStatus string
+
+const (
+	OwnershipTransferPending   OwnershipTransferStatus = "pending"   // Waiting for the recipient
+	OwnershipTransferAccepted  OwnershipTransferStatus = "accepted"  // The recipient took ownership
+	OwnershipTransferDeclined  OwnershipTransferStatus = "declined"  // The recipient turned it down
+	OwnershipTransferCancelled OwnershipTransferStatus = "cancelled" // An owner withdrew it
+)
+
+func (s OwnershipTransferStatus) Validate() error {
+	switch s {
+	case OwnershipTransferPending, OwnershipTransferAccepted, OwnershipTransferDeclined, OwnershipTransferCancelled:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid ownership transfer status: %s", ErrInvalidInput, s)
+	}
+}
+
+// TribeOwnershipTransfer offers a tribe owner's role to another full member.
+// Nothing changes until the recipient accepts; the sender then steps down to
+// admin. A tribe has at most one pending transfer.
+type TribeOwnershipTransfer struct {
+	ID          uuid.UUID               `json:"id" db:"id"`
+	TribeID     uuid.UUID               `json:"tribe_id" db:"tribe_id"`
+	FromUserID  uuid.UUID               `json:"from_user_id" db:"from_user_id"`
+	ToUserID    uuid.UUID               `json:"to_user_id" db:"to_user_id"`
+	Status      OwnershipTransferStatus `json:"status" db:"status"`
+	CreatedAt   time.Time               `json:"created_at" db:"created_at"`
+	RespondedAt *time.Time              `json:"responded_at,omitempty" db:"responded_at"`
+}
+
+// Validate performs validation on the ownership transfer
+func (t *TribeOwnershipTransfer) Validate() error {
+	if t.TribeID == uuid.Nil {
+		return fmt.Errorf("%w: tribe ID is required", ErrInvalidInput)
+	}
+	if t.FromUserID == uuid.Nil || t.ToUserID == uuid.Nil {
+		return fmt.Errorf("%w: both users are required", ErrInvalidInput)
+	}
+	if t.FromUserID == t.ToUserID {
+		return fmt.Errorf("%w: cannot transfer ownership to yourself", ErrInvalidInput)
+	}
+	return t.Status.Validate()
+}
+
+// TribeOwnershipTransferRepository defines the interface for ownership
+// transfer persistence
+type TribeOwnershipTransferRepository interface {
+	// Create stores a pending transfer. It returns ErrDuplicate when the
+	// tribe already has one.
+	Create(transfer *TribeOwnershipTransfer) error
+	// GetPending returns the tribe's pending transfer, or ErrNotFound
+	GetPending(tribeID uuid.UUID) (*TribeOwnershipTransfer, error)
+	// Resolve closes a pending transfer with the given status. Accepting
+	// makes the recipient an owner and the sender an admin in the same
+	// transaction, and returns ErrConflict if either has since lost the
+	// standing the transfer needs.
+	Resolve(transferID uuid.UUID, status OwnershipTransferStatus) error
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTribeOwnershipTransferValidate(t *testing.T) {
+	valid := func() TribeOwnershipTransfer {
+		return TribeOwnershipTransfer{
+			TribeID:    uuid.New(),
+			FromUserID: uuid.New(),
+			ToUserID:   uuid.New(),
+			Status:     OwnershipTransferPending,
+		}
+	}
+
+	transfer := valid()
+	assert.NoError(t, transfer.Validate())
+
+	transfer = valid()
+	transfer.TribeID = uuid.Nil
+	assert.ErrorIs(t, transfer.Validate(), ErrInvalidInput)
+
+	transfer = valid()
+	transfer.ToUserID = uuid.Nil
+	assert.ErrorIs(t, transfer.Validate(), ErrInvalidInput)
+
+	transfer = valid()
+	transfer.ToUserID = transfer.FromUserID
+	assert.ErrorIs(t, transfer.Validate(), ErrInvalidInput)
+
+	transfer = valid()
+	transfer.Status = "lost"
+	assert.ErrorIs(t, transfer.Validate(), ErrInvalidInput)
+
+	assert.ErrorIs(t, ErrLastOwner, ErrConflict)
+}
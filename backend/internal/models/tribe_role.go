@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TribeRole represents a member's standing in a tribe. Roles sit on top of
+// the membership type: only full members may hold a role above member.
+type TribeRole string
+
+const (
+	TribeRoleOwner  TribeRole = "owner"  // Runs the tribe, its admins and its deletion
+	TribeRoleAdmin  TribeRole = "admin"  // Manages tribe details and members
+	TribeRoleMember TribeRole = "member" // No management rights beyond the membership type
+)
+
+func (r TribeRole) Validate() error {
+	switch r {
+	case TribeRoleOwner, TribeRoleAdmin, TribeRoleMember:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid tribe role: %s", ErrInvalidInput, r)
+	}
+}
+
+// rank orders roles so that a role can only act on those below it
+func (r TribeRole) rank() int {
+	switch r {
+	case TribeRoleOwner:
+		return 2
+	case TribeRoleAdmin:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Outranks reports whether the role is above the other
+func (r TribeRole) Outranks(other TribeRole) bool {
+	return r.rank() > other.rank()
+}
+
+// TribePermission is something a member may be allowed to do in a tribe
+type TribePermission string
+
+const (
+	PermissionViewTribe     TribePermission = "view_tribe"     // See the tribe and what is shared with it
+	PermissionContribute    TribePermission = "contribute"     // Create, edit and share lists and activities for the tribe
+	PermissionInviteMembers TribePermission = "invite_members" // Invite new members
+	PermissionUpdateTribe   TribePermission = "update_tribe"   // Change the tribe's details
+	PermissionRemoveMembers TribePermission = "remove_members" // Remove members ranked below them
+	PermissionManageRoles   TribePermission = "manage_roles"   // Promote and demote admins
+	PermissionDeleteTribe   TribePermission = "delete_tribe"   // Delete the tribe
+
+	PermissionTransferOwnership TribePermission = "transfer_ownership" // Offer ownership to another member
+)
+
+// membershipPermissions is what each membership type allows on its own.
+// Limited members cannot invite, guests are read-only and pending members
+// can do nothing until they accept.
+var membershipPermissions = map[MembershipType][]TribePermission{
+	MembershipFull:    {PermissionViewTribe, PermissionContribute, PermissionInviteMembers},
+	MembershipLimited: {PermissionViewTribe, PermissionContribute},
+	MembershipGuest:   {PermissionViewTribe},
+}
+
+// rolePermissions is what each role adds for full members
+var rolePermissions = map[TribeRole][]TribePermission{
+	TribeRoleAdmin: {PermissionUpdateTribe, PermissionRemoveMembers},
+	TribeRoleOwner: {PermissionUpdateTribe, PermissionRemoveMembers, PermissionManageRoles, PermissionDeleteTribe, PermissionTransferOwnership},
+}
+
+// EffectiveRole returns the member's role, treating an unset role as member
+func (tm *TribeMember) EffectiveRole() TribeRole {
+	if tm.Role == "" || tm.MembershipType != MembershipFull {
+		return TribeRoleMember
+	}
+	return tm.Role
+}
+
+// Can reports whether the membership grants the permission at the given
+// time. Inactive memberships grant nothing.
+func (tm *TribeMember) Can(permission TribePermission, now time.Time) bool {
+	if !tm.IsActive(now) {
+		return false
+	}
+	for _, granted := range membershipPermissions[tm.MembershipType] {
+		if granted == permission {
+			return true
+		}
+	}
+	for _, granted := range rolePermissions[tm.EffectiveRole()] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRemove reports whether the member may remove the target from the tribe.
+// Anyone may leave; removing someone else needs PermissionRemoveMembers and
+// a role above theirs.
+func (tm *TribeMember) CanRemove(target *TribeMember, now time.Time) bool {
+	if tm.UserID == target.UserID {
+		return true
+	}
+	return tm.Can(PermissionRemoveMembers, now) && tm.EffectiveRole().Outranks(target.EffectiveRole())
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTribeRoleValidate(t *testing.T) {
+	for _, role := range []TribeRole{TribeRoleOwner, TribeRoleAdmin, TribeRoleMember} {
+		assert.NoError(t, role.Validate())
+	}
+	assert.ErrorIs(t, TribeRole("captain").Validate(), ErrInvalidInput)
+	assert.ErrorIs(t, TribeRole("").Validate(), ErrInvalidInput)
+
+	assert.True(t, TribeRoleOwner.Outranks(TribeRoleAdmin))
+	assert.True(t, TribeRoleAdmin.Outranks(TribeRoleMember))
+	assert.False(t, TribeRoleAdmin.Outranks(TribeRoleAdmin))
+	assert.False(t, TribeRoleMember.Outranks(TribeRoleOwner))
+}
+
+func TestTribeMemberCan(t *testing.T) {
+	now := time.Now()
+	allPermissions := []TribePermission{
+		PermissionViewTribe,
+		PermissionContribute,
+		PermissionInviteMembers,
+		PermissionUpdateTribe,
+		PermissionRemoveMembers,
+		PermissionManageRoles,
+		PermissionDeleteTribe,
+		PermissionTransferOwnership,
+	}
+
+	tests := []struct {
+		name    string
+		member  TribeMember
+		granted []TribePermission
+	}{
+		{
+			name:    "owner",
+			member:  TribeMember{MembershipType: MembershipFull, Role: TribeRoleOwner},
+			granted: allPermissions,
+		},
+		{
+			name:    "admin",
+			member:  TribeMember{MembershipType: MembershipFull, Role: TribeRoleAdmin},
+			granted: []TribePermission{PermissionViewTribe, PermissionContribute, PermissionInviteMembers, PermissionUpdateTribe, PermissionRemoveMembers},
+		},
+		{
+			name:    "full member",
+			member:  TribeMember{MembershipType: MembershipFull, Role: TribeRoleMember},
+			granted: []TribePermission{PermissionViewTribe, PermissionContribute, PermissionInviteMembers},
+		},
+		{
+			name:    "unset role",
+			member:  TribeMember{MembershipType: MembershipFull},
+			granted: []TribePermission{PermissionViewTribe, PermissionContribute, PermissionInviteMembers},
+		},
+		{
+			name:    "limited member",
+			member:  TribeMember{MembershipType: MembershipLimited, Role: TribeRoleMember},
+			granted: []TribePermission{PermissionViewTribe, PermissionContribute},
+		},
+		{
+			name:    "guest",
+			member:  TribeMember{MembershipType: MembershipGuest, Role: TribeRoleMember},
+			granted: []TribePermission{PermissionViewTribe},
+		},
+		{
+			name:   "pending",
+			member: TribeMember{MembershipType: MembershipPending, Role: TribeRoleMember},
+		},
+		{
+			name:   "expired guest",
+			member: TribeMember{MembershipType: MembershipGuest, Role: TribeRoleMember, ExpiresAt: ptrTime(now.Add(-time.Hour))},
+		},
+		{
+			name:    "admin role on a limited membership",
+			member:  TribeMember{MembershipType: MembershipLimited, Role: TribeRoleAdmin},
+			granted: []TribePermission{PermissionViewTribe, PermissionContribute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, permission := range allPermissions {
+				assert.Equal(t, contains(tt.granted, permission), tt.member.Can(permission, now), permission)
+			}
+		})
+	}
+}
+
+func TestTribeMemberCanRemove(t *testing.T) {
+	now := time.Now()
+	member := func(role TribeRole) *TribeMember {
+		return &TribeMember{UserID: uuid.New(), MembershipType: MembershipFull, Role: role}
+	}
+	owner, admin, otherAdmin, plain := member(TribeRoleOwner), member(TribeRoleAdmin), member(TribeRoleAdmin), member(TribeRoleMember)
+	guest := &TribeMember{UserID: uuid.New(), MembershipType: MembershipGuest, Role: TribeRoleMember}
+
+	assert.True(t, owner.CanRemove(admin, now))
+	assert.True(t, owner.CanRemove(plain, now))
+	assert.True(t, admin.CanRemove(plain, now))
+	assert.True(t, admin.CanRemove(guest, now))
+	assert.False(t, admin.CanRemove(otherAdmin, now))
+	assert.False(t, admin.CanRemove(owner, now))
+	assert.False(t, plain.CanRemove(guest, now))
+	assert.True(t, plain.CanRemove(plain, now))
+	assert.True(t, guest.CanRemove(guest, now))
+}
+
+func TestTribeMemberValidateRole(t *testing.T) {
+	now := time.Now()
+	valid := func() TribeMember {
+		return TribeMember{
+			BaseModel:      BaseModel{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Version: 1},
+			TribeID:        uuid.New(),
+			UserID:         uuid.New(),
+			MembershipType: MembershipFull,
+			Role:           TribeRoleOwner,
+			DisplayName:    "Owner",
+		}
+	}
+
+	member := valid()
+	assert.NoError(t, member.Validate())
+
+	member = valid()
+	member.Role = "captain"
+	assert.Error(t, member.Validate())
+
+	member = valid()
+	member.MembershipType = MembershipGuest
+	expires := now.Add(time.Hour)
+	member.ExpiresAt = &expires
+	assert.Error(t, member.Validate())
+
+	member.Role = TribeRoleMember
+	assert.NoError(t, member.Validate())
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func contains(permissions []TribePermission, permission TribePermission) bool {
+	for _, granted := range permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
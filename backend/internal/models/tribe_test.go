@@ -434,6 +434,30 @@ func TestTribeMember_Validate(t *testing.T) {
 	}
 }
 
+func TestTribeMember_IsActive(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		tm   TribeMember
+		want bool
+	}{
+		{"full member", TribeMember{MembershipType: MembershipFull}, true},
+		{"guest before expiry", TribeMember{MembershipType: MembershipGuest, ExpiresAt: &future}, true},
+		{"guest after expiry", TribeMember{MembershipType: MembershipGuest, ExpiresAt: &past}, false},
+		{"pending invitation", TribeMember{MembershipType: MembershipPending}, false},
+		{"removed member", TribeMember{BaseModel: BaseModel{DeletedAt: &past}, MembershipType: MembershipFull}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tm.IsActive(now))
+		})
+	}
+}
+
 func TestTribe_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		name      string
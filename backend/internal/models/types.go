@@ -111,6 +111,9 @@ type MenuParams struct {
 	Count        int                    `json:"count"`
 	Filters      map[string]interface{} `json:"filters,omitempty"`
 	ExcludeItems []uuid.UUID            `json:"exclude_items,omitempty"`
+	// Seed makes sampling reproducible. When omitted, one is generated and
+	// written back so it can be echoed to the caller.
+	Seed *int64 `json:"seed,omitempty"`
 }
 
 // Validate performs validation on the menu parameters
@@ -17,6 +17,36 @@ type ActivityRepository struct {
 	tm *TransactionManager
 }
 
+// viewerTribeJoins finds the tribe an activity reached a viewer through, as
+// vt: a tribe that owns it, otherwise one it is shared with, among those the
+// viewer ($1) actively belongs to. It joins the creator's membership of that
+// tribe as tm and their account as u, and expects activities aliased as a.
+const viewerTribeJoins = `
+			LEFT JOIN LATERAL (
+				SELECT t.tribe_id
+				FROM (
+					SELECT ao.owner_id AS tribe_id, 0 AS priority
+					FROM activity_owners ao
+					WHERE ao.activity_id = a.id AND ao.owner_type = 'tribe' AND ao.deleted_at IS NULL
+					UNION ALL
+					SELECT s.tribe_id, 1 AS priority
+					FROM activity_shares s
+					WHERE s.activity_id = a.id AND s.deleted_at IS NULL
+					AND (s.expires_at IS NULL OR s.expires_at > NOW())
+				) t
+				JOIN tribe_members vm ON vm.tribe_id = t.tribe_id AND vm.user_id = $1
+					AND vm.deleted_at IS NULL AND vm.membership_type <> 'pending'
+					AND (vm.expires_at IS NULL OR vm.expires_at > NOW())
+				ORDER BY t.priority, t.tribe_id
+				LIMIT 1
+			) vt ON true
+			LEFT JOIN tribe_members tm ON tm.tribe_id = vt.tribe_id AND tm.user_id = a.user_id AND tm.deleted_at IS NULL
+			LEFT JOIN users u ON u.id = a.user_id`
+
+// viewerCreatorNameColumn names the creator as the tribe found by
+// viewerTribeJoins knows them, or leaves the name empty without one
+const viewerCreatorNameColumn = `CASE WHEN vt.tribe_id IS NULL THEN '' ELSE ` + memberName + ` END as creator_name`
+
 // NewActivityRepository creates a new PostgreSQL activity repository
 func NewActivityRepository(db interface{}) models.ActivityRepository {
 	baseRepo := NewBaseRepository(db)
@@ -97,7 +127,8 @@ func (r *ActivityRepository) GetByID(id uuid.UUID) (*models.Activity, error) {
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT id, type, name, description, visibility, metadata, created_at, updated_at, deleted_at
+			SELECT id, type, name, description, visibility, metadata,
+				COALESCE(recurrence_rule, ''), recurrence_start, created_at, updated_at, deleted_at
 			FROM activities
 			WHERE id = $1 AND deleted_at IS NULL`
 
@@ -110,6 +141,8 @@ func (r *ActivityRepository) GetByID(id uuid.UUID) (*models.Activity, error) {
 			&activity.Description,
 			&activity.Visibility,
 			&metadata,
+			&activity.RecurrenceRule,
+			&activity.RecurrenceStart,
 			&activity.CreatedAt,
 			&activity.UpdatedAt,
 			&activity.DeletedAt,
@@ -230,7 +263,8 @@ func (r *ActivityRepository) List(offset, limit int) ([]*models.Activity, error)
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT id, type, name, description, visibility, metadata, created_at, updated_at, deleted_at
+			SELECT id, type, name, description, visibility, metadata,
+				COALESCE(recurrence_rule, ''), recurrence_start, created_at, updated_at, deleted_at
 			FROM activities
 			WHERE deleted_at IS NULL
 			ORDER BY created_at DESC
@@ -253,6 +287,8 @@ func (r *ActivityRepository) List(offset, limit int) ([]*models.Activity, error)
 				&activity.Description,
 				&activity.Visibility,
 				&metadata,
+				&activity.RecurrenceRule,
+				&activity.RecurrenceStart,
 				&activity.CreatedAt,
 				&activity.UpdatedAt,
 				&activity.DeletedAt,
@@ -284,6 +320,88 @@ func (r *ActivityRepository) List(offset, limit int) ([]*models.Activity, error)
 	return activities, nil
 }
 
+// ListForUser returns a page of the activities a user owns, either directly or
+// through a tribe they are an active member of
+func (r *ActivityRepository) ListForUser(userID uuid.UUID, offset, limit int) ([]*models.Activity, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var activities []*models.Activity
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT a.id, a.type, a.name, a.description, a.visibility, a.metadata,
+				COALESCE(a.recurrence_rule, ''), a.recurrence_start, a.created_at, a.updated_at, a.deleted_at,
+				` + viewerCreatorNameColumn + `
+			FROM activities a` + viewerTribeJoins + `
+			WHERE a.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM activity_owners ao
+				WHERE ao.activity_id = a.id
+				AND ao.deleted_at IS NULL
+				AND (
+					(ao.owner_type = 'user' AND ao.owner_id = $1)
+					OR (ao.owner_type = 'tribe' AND ao.owner_id IN (
+						SELECT tm.tribe_id FROM tribe_members tm
+						WHERE tm.user_id = $1
+						AND tm.deleted_at IS NULL
+						AND tm.membership_type <> 'pending'
+						AND (tm.expires_at IS NULL OR tm.expires_at > NOW())
+					))
+				)
+			)
+			ORDER BY a.created_at DESC, a.id
+			LIMIT $2 OFFSET $3`
+
+		rows, err := tx.Query(query, userID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("error listing user activities: %w", err)
+		}
+		defer safeClose(rows)
+
+		activities = make([]*models.Activity, 0)
+		for rows.Next() {
+			activity := &models.Activity{}
+			var metadataBytes []byte
+			if err := rows.Scan(
+				&activity.ID,
+				&activity.Type,
+				&activity.Name,
+				&activity.Description,
+				&activity.Visibility,
+				&metadataBytes,
+				&activity.RecurrenceRule,
+				&activity.RecurrenceStart,
+				&activity.CreatedAt,
+				&activity.UpdatedAt,
+				&activity.DeletedAt,
+				&activity.CreatorName,
+			); err != nil {
+				return fmt.Errorf("error scanning activity row: %w", err)
+			}
+
+			metadata, metadataErr := convertMetadata(metadataBytes)
+			if metadataErr != nil {
+				return fmt.Errorf("error converting metadata: %w", metadataErr)
+			}
+			activity.Metadata = metadata
+
+			activities = append(activities, activity)
+		}
+
+		if rowErr := rows.Err(); rowErr != nil {
+			return fmt.Errorf("error iterating activity rows: %w", rowErr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
 // AddOwner adds an owner to an activity
 func (r *ActivityRepository) AddOwner(activityID, ownerID uuid.UUID, ownerType models.OwnerType) error {
 	ctx := context.Background()
@@ -387,7 +505,8 @@ func (r *ActivityRepository) GetUserActivities(userID uuid.UUID) ([]*models.Acti
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT a.id, a.type, a.name, a.description, a.visibility, a.metadata, a.created_at, a.updated_at, a.deleted_at
+			SELECT a.id, a.type, a.name, a.description, a.visibility, a.metadata,
+				COALESCE(a.recurrence_rule, ''), a.recurrence_start, a.created_at, a.updated_at, a.deleted_at
 			FROM activities a
 			JOIN activity_owners ao ON ao.activity_id = a.id
 			WHERE ao.owner_id = $1 
@@ -412,6 +531,8 @@ func (r *ActivityRepository) GetUserActivities(userID uuid.UUID) ([]*models.Acti
 				&activity.Description,
 				&activity.Visibility,
 				&metadataBytes,
+				&activity.RecurrenceRule,
+				&activity.RecurrenceStart,
 				&activity.CreatedAt,
 				&activity.UpdatedAt,
 				&activity.DeletedAt,
@@ -450,7 +571,8 @@ func (r *ActivityRepository) GetTribeActivities(tribeID uuid.UUID) ([]*models.Ac
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT a.id, a.type, a.name, a.description, a.visibility, a.metadata, a.created_at, a.updated_at, a.deleted_at
+			SELECT a.id, a.type, a.name, a.description, a.visibility, a.metadata,
+				COALESCE(a.recurrence_rule, ''), a.recurrence_start, a.created_at, a.updated_at, a.deleted_at
 			FROM activities a
 			JOIN activity_owners ao ON ao.activity_id = a.id
 			WHERE ao.owner_id = $1 
@@ -475,6 +597,8 @@ func (r *ActivityRepository) GetTribeActivities(tribeID uuid.UUID) ([]*models.Ac
 				&activity.Description,
 				&activity.Visibility,
 				&metadataBytes,
+				&activity.RecurrenceRule,
+				&activity.RecurrenceStart,
 				&activity.CreatedAt,
 				&activity.UpdatedAt,
 				&activity.DeletedAt,
@@ -661,9 +785,13 @@ func (r *ActivityRepository) GetSharedActivities(tribeID uuid.UUID) ([]*models.A
 			SELECT DISTINCT
 				a.id, a.user_id, a.type, a.name, a.description, a.visibility, 
 				COALESCE(a.metadata, '{}'::jsonb) as metadata,
-				a.created_at, a.updated_at, a.deleted_at
+				COALESCE(a.recurrence_rule, ''), a.recurrence_start,
+				a.created_at, a.updated_at, a.deleted_at,
+				` + memberName + ` as creator_name
 			FROM activities a
 			JOIN valid_shares vs ON a.id = vs.activity_id
+			LEFT JOIN tribe_members tm ON tm.tribe_id = $1 AND tm.user_id = a.user_id AND tm.deleted_at IS NULL
+			LEFT JOIN users u ON u.id = a.user_id
 			WHERE a.deleted_at IS NULL
 			ORDER BY a.created_at DESC`
 
@@ -685,9 +813,12 @@ func (r *ActivityRepository) GetSharedActivities(tribeID uuid.UUID) ([]*models.A
 				&activity.Description,
 				&activity.Visibility,
 				&metadataBytes,
+				&activity.RecurrenceRule,
+				&activity.RecurrenceStart,
 				&activity.CreatedAt,
 				&activity.UpdatedAt,
 				&activity.DeletedAt,
+				&activity.CreatorName,
 			); err != nil {
 				return fmt.Errorf("error scanning activity row: %w", err)
 			}
@@ -715,6 +846,35 @@ func (r *ActivityRepository) GetSharedActivities(tribeID uuid.UUID) ([]*models.A
 	return activities, nil
 }
 
+// GetCreatorName returns the name the activity's creator goes by in the tribe
+// the activity reached the viewer through, or "" if it did not reach them
+// through a tribe
+func (r *ActivityRepository) GetCreatorName(activityID, viewerID uuid.UUID) (string, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var name string
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			SELECT `+viewerCreatorNameColumn+`
+			FROM activities a`+viewerTribeJoins+`
+			WHERE a.id = $2 AND a.deleted_at IS NULL`, viewerID, activityID).Scan(&name)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: activity not found", models.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("error getting activity creator name: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
 // CleanupOrphanedActivities removes activities that have no owners
 func (r *ActivityRepository) CleanupOrphanedActivities() error {
 	ctx := context.Background()
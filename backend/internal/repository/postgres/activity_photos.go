@@ -42,8 +42,9 @@ func (r *ActivityPhotosRepository) Create(photo *models.ActivityPhoto) error {
 
 		query := `
 			INSERT INTO activity_photos (
-				id, activity_id, url, caption, metadata, created_at, updated_at, version
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				id, activity_id, url, caption, metadata, storage_key, content_type, size_bytes,
+				created_at, updated_at, version
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 			RETURNING version
 		`
 
@@ -53,6 +54,9 @@ func (r *ActivityPhotosRepository) Create(photo *models.ActivityPhoto) error {
 			photo.URL,
 			photo.Caption,
 			photo.Metadata,
+			photo.StorageKey,
+			photo.ContentType,
+			photo.SizeBytes,
 			photo.CreatedAt,
 			photo.UpdatedAt,
 			1, // Initial version
@@ -73,7 +77,8 @@ func (r *ActivityPhotosRepository) GetByID(id uuid.UUID) (*models.ActivityPhoto,
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT id, activity_id, url, caption, metadata, created_at, updated_at, version
+			SELECT id, activity_id, url, caption, metadata, storage_key, content_type, size_bytes,
+				created_at, updated_at, version
 			FROM activity_photos
 			WHERE id = $1 AND deleted_at IS NULL
 		`
@@ -84,6 +89,9 @@ func (r *ActivityPhotosRepository) GetByID(id uuid.UUID) (*models.ActivityPhoto,
 			&photo.URL,
 			&photo.Caption,
 			&photo.Metadata,
+			&photo.StorageKey,
+			&photo.ContentType,
+			&photo.SizeBytes,
 			&photo.CreatedAt,
 			&photo.UpdatedAt,
 			&photo.Version,
@@ -106,6 +114,77 @@ func (r *ActivityPhotosRepository) GetByID(id uuid.UUID) (*models.ActivityPhoto,
 	return photo, nil
 }
 
+// ListByActivity returns the photos of an activity, oldest first
+func (r *ActivityPhotosRepository) ListByActivity(activityID uuid.UUID) ([]*models.ActivityPhoto, error) {
+	return r.listPhotos(`
+		SELECT id, activity_id, url, caption, metadata, storage_key, content_type, size_bytes,
+			created_at, updated_at, version
+		FROM activity_photos
+		WHERE activity_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`, activityID)
+}
+
+// ListMissingVariants returns photos still waiting for their variants
+func (r *ActivityPhotosRepository) ListMissingVariants(maxAttempts, limit int) ([]*models.ActivityPhoto, error) {
+	return r.listPhotos(`
+		SELECT id, activity_id, url, caption, metadata, storage_key, content_type, size_bytes,
+			created_at, updated_at, version
+		FROM activity_photos
+		WHERE deleted_at IS NULL
+			AND COALESCE(metadata->>'`+models.PhotoMetaVariantStatus+`', '') <> '`+models.PhotoVariantStatusReady+`'
+			AND COALESCE((metadata->>'`+models.PhotoMetaVariantTries+`')::int, 0) < $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`, maxAttempts, limit)
+}
+
+func (r *ActivityPhotosRepository) listPhotos(query string, args ...interface{}) ([]*models.ActivityPhoto, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var photos []*models.ActivityPhoto
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("error listing activity photos: %w", err)
+		}
+		defer safeClose(rows)
+
+		photos = make([]*models.ActivityPhoto, 0)
+		for rows.Next() {
+			photo := &models.ActivityPhoto{}
+			if err := rows.Scan(
+				&photo.ID,
+				&photo.ActivityID,
+				&photo.URL,
+				&photo.Caption,
+				&photo.Metadata,
+				&photo.StorageKey,
+				&photo.ContentType,
+				&photo.SizeBytes,
+				&photo.CreatedAt,
+				&photo.UpdatedAt,
+				&photo.Version,
+			); err != nil {
+				return fmt.Errorf("error scanning activity photo: %w", err)
+			}
+			photos = append(photos, photo)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating activity photos: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
 func (r *ActivityPhotosRepository) Update(photo *models.ActivityPhoto) error {
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
@@ -115,14 +194,18 @@ func (r *ActivityPhotosRepository) Update(photo *models.ActivityPhoto) error {
 
 		query := `
 			UPDATE activity_photos
-			SET url = $1, caption = $2, metadata = $3, updated_at = $4
-			WHERE id = $5 AND deleted_at IS NULL
+			SET url = $1, caption = $2, metadata = $3, storage_key = $4, content_type = $5,
+				size_bytes = $6, updated_at = $7
+			WHERE id = $8 AND deleted_at IS NULL
 			RETURNING version
 		`
 		result, err := tx.Exec(query,
 			photo.URL,
 			photo.Caption,
 			photo.Metadata,
+			photo.StorageKey,
+			photo.ContentType,
+			photo.SizeBytes,
 			photo.UpdatedAt,
 			photo.ID,
 		)
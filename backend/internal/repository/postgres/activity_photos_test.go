@@ -225,6 +225,59 @@ func TestActivityPhotosRepository(t *testing.T) {
 		assert.Equal(t, photo.Version, retrieved.Version)
 	})
 
+	t.Run("ListByActivity", func(t *testing.T) {
+		uploaded := &models.ActivityPhoto{
+			ActivityID:  photo.ActivityID,
+			Caption:     "Uploaded",
+			Metadata:    models.JSONMap{},
+			StorageKey:  "activities/" + photo.ActivityID.String() + "/uploaded.jpg",
+			ContentType: "image/jpeg",
+			SizeBytes:   1024,
+		}
+		require.NoError(t, repo.Create(uploaded))
+
+		photos, err := repo.ListByActivity(photo.ActivityID)
+		require.NoError(t, err)
+		require.Len(t, photos, 2)
+		assert.Equal(t, photo.ID, photos[0].ID)
+		assert.Equal(t, uploaded.StorageKey, photos[1].StorageKey)
+		assert.Equal(t, uploaded.ContentType, photos[1].ContentType)
+		assert.Equal(t, uploaded.SizeBytes, photos[1].SizeBytes)
+
+		photos, err = repo.ListByActivity(uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, photos)
+
+		require.NoError(t, repo.Delete(uploaded.ID))
+	})
+
+	t.Run("ListMissingVariants", func(t *testing.T) {
+		ready := &models.ActivityPhoto{
+			ActivityID: photo.ActivityID,
+			URL:        "https://example.com/ready.jpg",
+			Metadata:   models.JSONMap{models.PhotoMetaVariantStatus: models.PhotoVariantStatusReady},
+		}
+		failing := &models.ActivityPhoto{
+			ActivityID: photo.ActivityID,
+			URL:        "https://example.com/failing.jpg",
+			Metadata:   models.JSONMap{models.PhotoMetaVariantTries: 3},
+		}
+		require.NoError(t, repo.Create(ready))
+		require.NoError(t, repo.Create(failing))
+
+		pending, err := repo.ListMissingVariants(3, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, photo.ID, pending[0].ID)
+
+		pending, err = repo.ListMissingVariants(4, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+
+		require.NoError(t, repo.Delete(ready.ID))
+		require.NoError(t, repo.Delete(failing.ID))
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		updated := &models.ActivityPhoto{
 			BaseModel: models.BaseModel{
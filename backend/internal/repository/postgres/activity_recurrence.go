@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// SetRecurrence sets or, with an empty rule, clears the recurrence of an
+// activity. Exceptions name instances of the old rule, so they are dropped
+// whenever the rule or its start changes.
+func (r *ActivityRepository) SetRecurrence(activityID uuid.UUID, rule string, start *time.Time) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var currentRule sql.NullString
+		var currentStart sql.NullTime
+		err := tx.QueryRow(`
+			SELECT recurrence_rule, recurrence_start
+			FROM activities
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE`, activityID).Scan(&currentRule, &currentStart)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting activity recurrence: %w", err)
+		}
+
+		var newRule sql.NullString
+		var newStart sql.NullTime
+		if rule != "" {
+			if start == nil {
+				return fmt.Errorf("%w: recurrence rule requires a start", models.ErrInvalidInput)
+			}
+			newRule = sql.NullString{String: rule, Valid: true}
+			newStart = sql.NullTime{Time: *start, Valid: true}
+		}
+
+		if _, err = tx.Exec(`
+			UPDATE activities
+			SET recurrence_rule = $1, recurrence_start = $2, updated_at = $3
+			WHERE id = $4`, newRule, newStart, time.Now(), activityID); err != nil {
+			return fmt.Errorf("error setting activity recurrence: %w", err)
+		}
+
+		unchanged := currentRule == newRule && currentStart.Valid == newStart.Valid &&
+			currentStart.Time.Equal(newStart.Time)
+		if unchanged {
+			return nil
+		}
+		if _, err = tx.Exec(`DELETE FROM activity_occurrence_exceptions WHERE activity_id = $1`, activityID); err != nil {
+			return fmt.Errorf("error deleting occurrence exceptions: %w", err)
+		}
+
+		return bumpScheduleSequence(tx, activityID)
+	})
+}
+
+// SetOccurrenceException cancels or moves one instance of a recurring
+// activity, replacing any earlier exception for that instance
+func (r *ActivityRepository) SetOccurrenceException(exception *models.OccurrenceException) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		now := time.Now()
+		query := `
+			INSERT INTO activity_occurrence_exceptions (
+				activity_id, original_start, cancelled, start_time, end_time, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $6)
+			ON CONFLICT (activity_id, original_start) DO UPDATE SET
+				cancelled = EXCLUDED.cancelled,
+				start_time = EXCLUDED.start_time,
+				end_time = EXCLUDED.end_time,
+				updated_at = EXCLUDED.updated_at
+			RETURNING created_at, updated_at`
+
+		err := tx.QueryRow(query,
+			exception.ActivityID,
+			exception.OriginalStart,
+			exception.Cancelled,
+			exception.StartTime,
+			exception.EndTime,
+			now,
+		).Scan(&exception.CreatedAt, &exception.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error setting occurrence exception: %w", err)
+		}
+
+		return bumpScheduleSequence(tx, exception.ActivityID)
+	})
+}
+
+// DeleteOccurrenceException restores an instance to the one the rule gives
+func (r *ActivityRepository) DeleteOccurrenceException(activityID uuid.UUID, originalStart time.Time) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			DELETE FROM activity_occurrence_exceptions
+			WHERE activity_id = $1 AND original_start = $2`, activityID, originalStart)
+		if err != nil {
+			return fmt.Errorf("error deleting occurrence exception: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		return bumpScheduleSequence(tx, activityID)
+	})
+}
+
+// GetOccurrenceExceptions returns every exception of an activity, ordered by
+// the instance they apply to
+func (r *ActivityRepository) GetOccurrenceExceptions(activityID uuid.UUID) ([]*models.OccurrenceException, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var exceptions []*models.OccurrenceException
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT activity_id, original_start, cancelled, start_time, end_time, created_at, updated_at
+			FROM activity_occurrence_exceptions
+			WHERE activity_id = $1
+			ORDER BY original_start`
+
+		rows, err := tx.Query(query, activityID)
+		if err != nil {
+			return fmt.Errorf("error getting occurrence exceptions: %w", err)
+		}
+		defer safeClose(rows)
+
+		exceptions = make([]*models.OccurrenceException, 0)
+		for rows.Next() {
+			exception := &models.OccurrenceException{}
+			if err := rows.Scan(
+				&exception.ActivityID,
+				&exception.OriginalStart,
+				&exception.Cancelled,
+				&exception.StartTime,
+				&exception.EndTime,
+				&exception.CreatedAt,
+				&exception.UpdatedAt,
+			); err != nil {
+				return fmt.Errorf("error scanning occurrence exception: %w", err)
+			}
+			exceptions = append(exceptions, exception)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating occurrence exceptions: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exceptions, nil
+}
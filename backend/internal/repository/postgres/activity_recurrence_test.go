@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRecurrence(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewActivityRepository(db.UnwrapDB())
+	testUser := testutil.CreateTestUser(t, db)
+
+	activity := &models.Activity{
+		UserID:     testUser.ID,
+		Type:       models.ActivityTypeEvent,
+		Name:       "Date Night",
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repo.Create(activity))
+
+	start := time.Date(2024, 1, 5, 19, 0, 0, 0, time.UTC)
+
+	t.Run("set recurrence", func(t *testing.T) {
+		require.NoError(t, repo.SetRecurrence(activity.ID, "FREQ=WEEKLY;BYDAY=FR", &start))
+
+		got, err := repo.GetByID(activity.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=FR", got.RecurrenceRule)
+		require.NotNil(t, got.RecurrenceStart)
+		assert.True(t, start.Equal(*got.RecurrenceStart))
+	})
+
+	t.Run("exceptions", func(t *testing.T) {
+		moved := start.AddDate(0, 0, 15)
+		movedEnd := moved.Add(time.Hour)
+		require.NoError(t, repo.SetOccurrenceException(&models.OccurrenceException{
+			ActivityID: activity.ID, OriginalStart: start.AddDate(0, 0, 7), Cancelled: true,
+		}))
+		require.NoError(t, repo.SetOccurrenceException(&models.OccurrenceException{
+			ActivityID: activity.ID, OriginalStart: start.AddDate(0, 0, 14), StartTime: &moved, EndTime: &movedEnd,
+		}))
+
+		exceptions, err := repo.GetOccurrenceExceptions(activity.ID)
+		require.NoError(t, err)
+		require.Len(t, exceptions, 2)
+		assert.True(t, exceptions[0].Cancelled)
+		require.NotNil(t, exceptions[1].StartTime)
+		assert.True(t, moved.Equal(*exceptions[1].StartTime))
+
+		require.NoError(t, repo.DeleteOccurrenceException(activity.ID, start.AddDate(0, 0, 7)))
+		assert.ErrorIs(t, repo.DeleteOccurrenceException(activity.ID, start.AddDate(0, 0, 7)), models.ErrNotFound)
+	})
+
+	t.Run("unchanged rule keeps exceptions", func(t *testing.T) {
+		require.NoError(t, repo.SetRecurrence(activity.ID, "FREQ=WEEKLY;BYDAY=FR", &start))
+
+		exceptions, err := repo.GetOccurrenceExceptions(activity.ID)
+		require.NoError(t, err)
+		assert.Len(t, exceptions, 1)
+	})
+
+	t.Run("clearing recurrence drops exceptions", func(t *testing.T) {
+		require.NoError(t, repo.SetRecurrence(activity.ID, "", nil))
+
+		got, err := repo.GetByID(activity.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.RecurrenceRule)
+		assert.Nil(t, got.RecurrenceStart)
+
+		exceptions, err := repo.GetOccurrenceExceptions(activity.ID)
+		require.NoError(t, err)
+		assert.Empty(t, exceptions)
+	})
+
+	t.Run("rule without start", func(t *testing.T) {
+		err := repo.SetRecurrence(activity.ID, "FREQ=DAILY", nil)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
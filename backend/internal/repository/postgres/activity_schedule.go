@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// SetSchedule creates or replaces the schedule of an activity
+func (r *ActivityRepository) SetSchedule(schedule *models.ActivitySchedule) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var location interface{}
+		if schedule.Location != nil {
+			data, err := json.Marshal(schedule.Location)
+			if err != nil {
+				return fmt.Errorf("error encoding location: %w", err)
+			}
+			location = data
+		}
+
+		now := time.Now()
+		query := `
+			INSERT INTO activity_schedules (
+				activity_id, start_time, end_time, time_zone, list_item_id, location, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+			ON CONFLICT (activity_id) DO UPDATE SET
+				start_time = EXCLUDED.start_time,
+				end_time = EXCLUDED.end_time,
+				time_zone = EXCLUDED.time_zone,
+				list_item_id = EXCLUDED.list_item_id,
+				location = EXCLUDED.location,
+				sequence = activity_schedules.sequence + 1,
+				updated_at = EXCLUDED.updated_at
+			RETURNING sequence, created_at, updated_at`
+
+		err := tx.QueryRow(query,
+			schedule.ActivityID,
+			schedule.StartTime,
+			schedule.EndTime,
+			schedule.TimeZone,
+			schedule.ListItemID,
+			location,
+			now,
+		).Scan(&schedule.Sequence, &schedule.CreatedAt, &schedule.UpdatedAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return fmt.Errorf("%w: activity or list item does not exist", models.ErrInvalidInput)
+			}
+			return fmt.Errorf("error setting activity schedule: %w", err)
+		}
+
+		tribeIDs, err := activityTribeIDs(tx, schedule.ActivityID)
+		if err != nil || len(tribeIDs) == 0 {
+			return err
+		}
+		var name string
+		if err = tx.QueryRow(`SELECT name FROM activities WHERE id = $1`, schedule.ActivityID).Scan(&name); err != nil {
+			return fmt.Errorf("error getting activity name: %w", err)
+		}
+		return insertTribeEvents(tx, tribeIDs, models.TribeEvent{
+			Type:      models.EventActivityScheduled,
+			SubjectID: schedule.ActivityID,
+			Details: models.JSONMap{
+				"activity_name": name,
+				"start_time":    schedule.StartTime.UTC().Format(time.RFC3339),
+				"rescheduled":   schedule.Sequence > 0,
+			},
+		})
+	})
+}
+
+// GetSchedule retrieves the schedule of an activity
+func (r *ActivityRepository) GetSchedule(activityID uuid.UUID) (*models.ActivitySchedule, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var schedule *models.ActivitySchedule
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT activity_id, start_time, end_time, time_zone, list_item_id, location, sequence, created_at, updated_at
+			FROM activity_schedules
+			WHERE activity_id = $1`
+
+		var location []byte
+		schedule = &models.ActivitySchedule{}
+		err := tx.QueryRow(query, activityID).Scan(
+			&schedule.ActivityID,
+			&schedule.StartTime,
+			&schedule.EndTime,
+			&schedule.TimeZone,
+			&schedule.ListItemID,
+			&location,
+			&schedule.Sequence,
+			&schedule.CreatedAt,
+			&schedule.UpdatedAt,
+		)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting activity schedule: %w", err)
+		}
+
+		if len(location) > 0 {
+			schedule.Location = &models.LocationRef{}
+			if err := json.Unmarshal(location, schedule.Location); err != nil {
+				return fmt.Errorf("error decoding location: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// DeleteSchedule removes the schedule of an activity along with its RSVPs
+func (r *ActivityRepository) DeleteSchedule(activityID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM activity_schedules WHERE activity_id = $1`, activityID)
+		if err != nil {
+			return fmt.Errorf("error deleting activity schedule: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		if _, err := tx.Exec(`DELETE FROM activity_rsvps WHERE activity_id = $1`, activityID); err != nil {
+			return fmt.Errorf("error deleting activity RSVPs: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// SetRSVP records or changes a user's RSVP to an activity
+func (r *ActivityRepository) SetRSVP(rsvp *models.ActivityRSVP) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		now := time.Now()
+		query := `
+			INSERT INTO activity_rsvps (activity_id, user_id, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (activity_id, user_id) DO UPDATE SET
+				status = EXCLUDED.status,
+				updated_at = EXCLUDED.updated_at
+			RETURNING created_at, updated_at`
+
+		err := tx.QueryRow(query, rsvp.ActivityID, rsvp.UserID, rsvp.Status, now).
+			Scan(&rsvp.CreatedAt, &rsvp.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error setting RSVP: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteRSVP withdraws a user's RSVP to an activity
+func (r *ActivityRepository) DeleteRSVP(activityID, userID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM activity_rsvps WHERE activity_id = $1 AND user_id = $2`,
+			activityID, userID)
+		if err != nil {
+			return fmt.Errorf("error deleting RSVP: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// GetRSVPs returns every RSVP to an activity, oldest first
+func (r *ActivityRepository) GetRSVPs(activityID uuid.UUID) ([]*models.ActivityRSVP, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var rsvps []*models.ActivityRSVP
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT activity_id, user_id, status, created_at, updated_at
+			FROM activity_rsvps
+			WHERE activity_id = $1
+			ORDER BY created_at, user_id`
+
+		rows, err := tx.Query(query, activityID)
+		if err != nil {
+			return fmt.Errorf("error getting RSVPs: %w", err)
+		}
+		defer safeClose(rows)
+
+		rsvps = make([]*models.ActivityRSVP, 0)
+		for rows.Next() {
+			rsvp := &models.ActivityRSVP{}
+			if err := rows.Scan(
+				&rsvp.ActivityID,
+				&rsvp.UserID,
+				&rsvp.Status,
+				&rsvp.CreatedAt,
+				&rsvp.UpdatedAt,
+			); err != nil {
+				return fmt.Errorf("error scanning RSVP: %w", err)
+			}
+			rsvps = append(rsvps, rsvp)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating RSVPs: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rsvps, nil
+}
+
+// bumpScheduleSequence records a revision of an activity's timing
+func bumpScheduleSequence(tx *sql.Tx, activityID uuid.UUID) error {
+	if _, err := tx.Exec(`
+		UPDATE activity_schedules
+		SET sequence = sequence + 1
+		WHERE activity_id = $1`, activityID); err != nil {
+		return fmt.Errorf("error updating schedule sequence: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivitySchedule(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewActivityRepository(db.UnwrapDB())
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+
+	activity := &models.Activity{
+		UserID:     testUser.ID,
+		Type:       models.ActivityTypeEvent,
+		Name:       "Dinner",
+		Visibility: models.VisibilityPrivate,
+		Metadata:   models.JSONMap{},
+	}
+	require.NoError(t, repo.Create(activity))
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	t.Run("get missing schedule", func(t *testing.T) {
+		_, err := repo.GetSchedule(activity.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("set and replace schedule", func(t *testing.T) {
+		schedule := &models.ActivitySchedule{
+			ActivityID: activity.ID,
+			StartTime:  start,
+			EndTime:    start.Add(2 * time.Hour),
+			TimeZone:   "America/New_York",
+			Location:   &models.LocationRef{Latitude: 40.7, Longitude: -74.0, Address: "New York"},
+		}
+		require.NoError(t, repo.SetSchedule(schedule))
+		assert.False(t, schedule.CreatedAt.IsZero())
+		assert.Equal(t, 0, schedule.Sequence)
+
+		got, err := repo.GetSchedule(activity.ID)
+		require.NoError(t, err)
+		assert.True(t, start.Equal(got.StartTime))
+		assert.Equal(t, "America/New_York", got.TimeZone)
+		require.NotNil(t, got.Location)
+		assert.Equal(t, "New York", got.Location.Address)
+
+		schedule.EndTime = start.Add(3 * time.Hour)
+		schedule.Location = nil
+		require.NoError(t, repo.SetSchedule(schedule))
+
+		got, err = repo.GetSchedule(activity.ID)
+		require.NoError(t, err)
+		assert.True(t, start.Add(3*time.Hour).Equal(got.EndTime))
+		assert.Nil(t, got.Location)
+		assert.Equal(t, 1, got.Sequence, "rescheduling bumps the sequence")
+	})
+
+	t.Run("unknown list item", func(t *testing.T) {
+		itemID := uuid.New()
+		err := repo.SetSchedule(&models.ActivitySchedule{
+			ActivityID: activity.ID,
+			StartTime:  start,
+			EndTime:    start.Add(time.Hour),
+			TimeZone:   "UTC",
+			ListItemID: &itemID,
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("RSVPs", func(t *testing.T) {
+		require.NoError(t, repo.SetRSVP(&models.ActivityRSVP{
+			ActivityID: activity.ID, UserID: testUser.ID, Status: models.RSVPYes,
+		}))
+		require.NoError(t, repo.SetRSVP(&models.ActivityRSVP{
+			ActivityID: activity.ID, UserID: otherUser.ID, Status: models.RSVPMaybe,
+		}))
+		require.NoError(t, repo.SetRSVP(&models.ActivityRSVP{
+			ActivityID: activity.ID, UserID: otherUser.ID, Status: models.RSVPNo,
+		}))
+
+		rsvps, err := repo.GetRSVPs(activity.ID)
+		require.NoError(t, err)
+		require.Len(t, rsvps, 2)
+		statuses := map[uuid.UUID]models.RSVPStatus{}
+		for _, rsvp := range rsvps {
+			statuses[rsvp.UserID] = rsvp.Status
+		}
+		assert.Equal(t, models.RSVPYes, statuses[testUser.ID])
+		assert.Equal(t, models.RSVPNo, statuses[otherUser.ID])
+
+		require.NoError(t, repo.DeleteRSVP(activity.ID, otherUser.ID))
+		assert.ErrorIs(t, repo.DeleteRSVP(activity.ID, otherUser.ID), models.ErrNotFound)
+	})
+
+	t.Run("delete schedule removes RSVPs", func(t *testing.T) {
+		require.NoError(t, repo.DeleteSchedule(activity.ID))
+
+		rsvps, err := repo.GetRSVPs(activity.ID)
+		require.NoError(t, err)
+		assert.Empty(t, rsvps)
+
+		assert.ErrorIs(t, repo.DeleteSchedule(activity.ID), models.ErrNotFound)
+	})
+}
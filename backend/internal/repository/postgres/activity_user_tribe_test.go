@@ -268,3 +268,69 @@ func extractNames(activities []*models.Activity) []string {
 	}
 	return names
 }
+
+func TestActivityRepository_CreatorName(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewActivityRepository(db)
+	creator := testutil.CreateTestUser(t, db)
+	viewer := testutil.CreateTestUser(t, db)
+	outsider := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{creator, viewer})
+
+	_, err := db.Exec("UPDATE tribe_members SET display_name = 'Chef' WHERE tribe_id = $1 AND user_id = $2", tribe.ID, creator.ID)
+	require.NoError(t, err)
+
+	create := func(name string) *models.Activity {
+		activity := &models.Activity{
+			UserID:     creator.ID,
+			Type:       models.ActivityTypeLocation,
+			Name:       name,
+			Visibility: models.VisibilityShared,
+			Metadata:   models.JSONMap{},
+		}
+		require.NoError(t, repo.Create(activity))
+		return activity
+	}
+
+	owned := create("Tribe Activity")
+	require.NoError(t, repo.AddOwner(owned.ID, tribe.ID, models.OwnerTypeTribe))
+	shared := create("Shared Activity")
+	require.NoError(t, repo.AddOwner(shared.ID, creator.ID, models.OwnerTypeUser))
+	require.NoError(t, repo.ShareWithTribe(shared.ID, tribe.ID, creator.ID, nil))
+
+	t.Run("Owning tribe", func(t *testing.T) {
+		activities, err := repo.ListForUser(viewer.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, activities, 1)
+		assert.Equal(t, "Chef", activities[0].CreatorName)
+
+		name, err := repo.GetCreatorName(owned.ID, viewer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Chef", name)
+	})
+
+	t.Run("Shared with a tribe", func(t *testing.T) {
+		name, err := repo.GetCreatorName(shared.ID, viewer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Chef", name)
+
+		_, err = db.Exec("UPDATE tribe_members SET display_name = NULL WHERE tribe_id = $1 AND user_id = $2", tribe.ID, creator.ID)
+		require.NoError(t, err)
+		_, err = db.Exec("UPDATE users SET name = '' WHERE id = $1", creator.ID)
+		require.NoError(t, err)
+		name, err = repo.GetCreatorName(shared.ID, viewer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Member", name)
+	})
+
+	t.Run("No tribe in common", func(t *testing.T) {
+		name, err := repo.GetCreatorName(owned.ID, outsider.ID)
+		require.NoError(t, err)
+		assert.Empty(t, name)
+
+		_, err = repo.GetCreatorName(uuid.New(), viewer.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// AvailabilityRepository implements models.AvailabilityRepository using
+// PostgreSQL
+type AvailabilityRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewAvailabilityRepository creates a new PostgreSQL availability repository
+func NewAvailabilityRepository(db interface{}) models.AvailabilityRepository {
+	baseRepo := NewBaseRepository(db)
+	return &AvailabilityRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// CreateCalendar stores a new imported calendar
+func (r *AvailabilityRepository) CreateCalendar(calendar *models.AvailabilityCalendar) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if calendar.ID == uuid.Nil {
+			calendar.ID = uuid.New()
+		}
+		now := time.Now()
+		calendar.CreatedAt = now
+		calendar.UpdatedAt = now
+
+		_, err := tx.Exec(`
+			INSERT INTO availability_calendars (id, user_id, name, url, created_at, updated_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $5)`,
+			calendar.ID, calendar.UserID, calendar.Name, calendar.URL, now)
+		if err != nil {
+			return fmt.Errorf("error creating availability calendar: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetCalendar retrieves an imported calendar by ID
+func (r *AvailabilityRepository) GetCalendar(id uuid.UUID) (*models.AvailabilityCalendar, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var calendar *models.AvailabilityCalendar
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		calendar = &models.AvailabilityCalendar{}
+		err := tx.QueryRow(`
+			SELECT id, user_id, name, COALESCE(url, ''), last_imported_at, created_at, updated_at
+			FROM availability_calendars
+			WHERE id = $1`, id).Scan(
+			&calendar.ID,
+			&calendar.UserID,
+			&calendar.Name,
+			&calendar.URL,
+			&calendar.LastImportedAt,
+			&calendar.CreatedAt,
+			&calendar.UpdatedAt,
+		)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting availability calendar: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// ListCalendars returns a user's imported calendars, oldest first
+func (r *AvailabilityRepository) ListCalendars(userID uuid.UUID) ([]*models.AvailabilityCalendar, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var calendars []*models.AvailabilityCalendar
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT id, user_id, name, COALESCE(url, ''), last_imported_at, created_at, updated_at
+			FROM availability_calendars
+			WHERE user_id = $1
+			ORDER BY created_at, id`, userID)
+		if err != nil {
+			return fmt.Errorf("error listing availability calendars: %w", err)
+		}
+		defer safeClose(rows)
+
+		calendars = make([]*models.AvailabilityCalendar, 0)
+		for rows.Next() {
+			calendar := &models.AvailabilityCalendar{}
+			if err := rows.Scan(
+				&calendar.ID,
+				&calendar.UserID,
+				&calendar.Name,
+				&calendar.URL,
+				&calendar.LastImportedAt,
+				&calendar.CreatedAt,
+				&calendar.UpdatedAt,
+			); err != nil {
+				return fmt.Errorf("error scanning availability calendar: %w", err)
+			}
+			calendars = append(calendars, calendar)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating availability calendars: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// DeleteCalendar removes one of a user's calendars along with its busy
+// intervals. Calendars of other users are reported as not found.
+func (r *AvailabilityRepository) DeleteCalendar(userID, id uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			DELETE FROM availability_calendars
+			WHERE id = $1 AND user_id = $2`, id, userID)
+		if err != nil {
+			return fmt.Errorf("error deleting availability calendar: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// ReplaceBusyIntervals replaces every busy interval of a calendar and marks
+// it as imported now
+func (r *AvailabilityRepository) ReplaceBusyIntervals(calendarID uuid.UUID, intervals []models.BusyInterval) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var userID uuid.UUID
+		err := tx.QueryRow(`
+			UPDATE availability_calendars
+			SET last_imported_at = $1, updated_at = $1
+			WHERE id = $2
+			RETURNING user_id`, time.Now(), calendarID).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error updating availability calendar: %w", err)
+		}
+
+		if _, err = tx.Exec(`DELETE FROM busy_intervals WHERE calendar_id = $1`, calendarID); err != nil {
+			return fmt.Errorf("error deleting busy intervals: %w", err)
+		}
+
+		stmt, err := tx.Prepare(`
+			INSERT INTO busy_intervals (calendar_id, user_id, start_time, end_time)
+			VALUES ($1, $2, $3, $4)`)
+		if err != nil {
+			return fmt.Errorf("error preparing busy interval insert: %w", err)
+		}
+		defer safeClose(stmt)
+
+		for _, interval := range intervals {
+			if _, err = stmt.Exec(calendarID, userID, interval.StartTime, interval.EndTime); err != nil {
+				return fmt.Errorf("error inserting busy interval: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetBusyIntervals returns the busy intervals of the users that overlap
+// [from, to), ordered by start
+func (r *AvailabilityRepository) GetBusyIntervals(userIDs []uuid.UUID, from, to time.Time) ([]*models.BusyInterval, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var intervals []*models.BusyInterval
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		ids := make([]string, len(userIDs))
+		for i, id := range userIDs {
+			ids[i] = id.String()
+		}
+
+		rows, err := tx.Query(`
+			SELECT calendar_id, user_id, start_time, end_time
+			FROM busy_intervals
+			WHERE user_id = ANY($1::uuid[]) AND start_time < $3 AND end_time > $2
+			ORDER BY start_time, end_time`, pq.Array(ids), from, to)
+		if err != nil {
+			return fmt.Errorf("error getting busy intervals: %w", err)
+		}
+		defer safeClose(rows)
+
+		intervals = make([]*models.BusyInterval, 0)
+		for rows.Next() {
+			interval := &models.BusyInterval{}
+			if err := rows.Scan(
+				&interval.CalendarID,
+				&interval.UserID,
+				&interval.StartTime,
+				&interval.EndTime,
+			); err != nil {
+				return fmt.Errorf("error scanning busy interval: %w", err)
+			}
+			intervals = append(intervals, interval)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating busy intervals: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewAvailabilityRepository(db.UnwrapDB())
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+
+	uploaded := &models.AvailabilityCalendar{UserID: testUser.ID, Name: "Work"}
+	linked := &models.AvailabilityCalendar{UserID: testUser.ID, Name: "Team", URL: "https://example.com/team.ics"}
+	other := &models.AvailabilityCalendar{UserID: otherUser.ID, Name: "Other"}
+	for _, calendar := range []*models.AvailabilityCalendar{uploaded, linked, other} {
+		require.NoError(t, repo.CreateCalendar(calendar))
+	}
+
+	t.Run("get and list", func(t *testing.T) {
+		got, err := repo.GetCalendar(linked.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Team", got.Name)
+		assert.Equal(t, "https://example.com/team.ics", got.URL)
+		assert.Nil(t, got.LastImportedAt)
+
+		got, err = repo.GetCalendar(uploaded.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.URL)
+
+		_, err = repo.GetCalendar(uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		calendars, err := repo.ListCalendars(testUser.ID)
+		require.NoError(t, err)
+		assert.Len(t, calendars, 2)
+	})
+
+	t.Run("replace busy intervals", func(t *testing.T) {
+		require.NoError(t, repo.ReplaceBusyIntervals(uploaded.ID, []models.BusyInterval{
+			{StartTime: start, EndTime: start.Add(time.Hour)},
+			{StartTime: start.Add(3 * time.Hour), EndTime: start.Add(4 * time.Hour)},
+		}))
+		require.NoError(t, repo.ReplaceBusyIntervals(other.ID, []models.BusyInterval{
+			{StartTime: start, EndTime: start.Add(2 * time.Hour)},
+		}))
+
+		got, err := repo.GetCalendar(uploaded.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.LastImportedAt)
+
+		busy, err := repo.GetBusyIntervals([]uuid.UUID{testUser.ID}, start, start.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, busy, 2)
+		assert.Equal(t, testUser.ID, busy[0].UserID)
+		assert.Equal(t, uploaded.ID, busy[0].CalendarID)
+		assert.True(t, start.Equal(busy[0].StartTime))
+
+		busy, err = repo.GetBusyIntervals([]uuid.UUID{testUser.ID, otherUser.ID}, start.Add(30*time.Minute), start.Add(90*time.Minute))
+		require.NoError(t, err)
+		assert.Len(t, busy, 2)
+
+		// A new import replaces the old intervals
+		require.NoError(t, repo.ReplaceBusyIntervals(uploaded.ID, nil))
+		busy, err = repo.GetBusyIntervals([]uuid.UUID{testUser.ID}, start, start.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, busy)
+
+		assert.ErrorIs(t, repo.ReplaceBusyIntervals(uuid.New(), nil), models.ErrNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.ErrorIs(t, repo.DeleteCalendar(testUser.ID, other.ID), models.ErrNotFound)
+		require.NoError(t, repo.DeleteCalendar(otherUser.ID, other.ID))
+
+		busy, err := repo.GetBusyIntervals([]uuid.UUID{otherUser.ID}, start, start.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, busy, "deleting a calendar removes its busy time")
+		assert.ErrorIs(t, repo.DeleteCalendar(otherUser.ID, other.ID), models.ErrNotFound)
+	})
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// CalendarFeedTokenRepository implements models.CalendarFeedTokenRepository
+// using PostgreSQL
+type CalendarFeedTokenRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewCalendarFeedTokenRepository creates a new PostgreSQL calendar feed
+// token repository
+func NewCalendarFeedTokenRepository(db interface{}) models.CalendarFeedTokenRepository {
+	baseRepo := NewBaseRepository(db)
+	return &CalendarFeedTokenRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// Create stores a new feed token
+func (r *CalendarFeedTokenRepository) Create(token *models.CalendarFeedToken) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if token.ID == uuid.Nil {
+			token.ID = uuid.New()
+		}
+		if token.CreatedAt.IsZero() {
+			token.CreatedAt = time.Now()
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO calendar_feed_tokens (id, user_id, token_hash, created_at)
+			VALUES ($1, $2, $3, $4)`,
+			token.ID, token.UserID, token.TokenHash, token.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating calendar feed token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByHash retrieves a feed token, revoked or not, by the hash of its value
+func (r *CalendarFeedTokenRepository) GetByHash(tokenHash string) (*models.CalendarFeedToken, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var token *models.CalendarFeedToken
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		token = &models.CalendarFeedToken{}
+		err := tx.QueryRow(`
+			SELECT id, user_id, token_hash, created_at, revoked_at
+			FROM calendar_feed_tokens
+			WHERE token_hash = $1`, tokenHash).Scan(
+			&token.ID,
+			&token.UserID,
+			&token.TokenHash,
+			&token.CreatedAt,
+			&token.RevokedAt,
+		)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting calendar feed token: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// ListByUser returns a user's feed tokens, newest first
+func (r *CalendarFeedTokenRepository) ListByUser(userID uuid.UUID) ([]*models.CalendarFeedToken, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var tokens []*models.CalendarFeedToken
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT id, user_id, token_hash, created_at, revoked_at
+			FROM calendar_feed_tokens
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id`, userID)
+		if err != nil {
+			return fmt.Errorf("error listing calendar feed tokens: %w", err)
+		}
+		defer safeClose(rows)
+
+		tokens = make([]*models.CalendarFeedToken, 0)
+		for rows.Next() {
+			token := &models.CalendarFeedToken{}
+			if err := rows.Scan(
+				&token.ID,
+				&token.UserID,
+				&token.TokenHash,
+				&token.CreatedAt,
+				&token.RevokedAt,
+			); err != nil {
+				return fmt.Errorf("error scanning calendar feed token: %w", err)
+			}
+			tokens = append(tokens, token)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating calendar feed tokens: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke revokes one of a user's feed tokens. Tokens of other users and
+// tokens already revoked are reported as not found.
+func (r *CalendarFeedTokenRepository) Revoke(userID, tokenID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE calendar_feed_tokens
+			SET revoked_at = $1
+			WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`,
+			time.Now(), tokenID, userID)
+		if err != nil {
+			return fmt.Errorf("error revoking calendar feed token: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// RevokeAll revokes every active feed token of a user
+func (r *CalendarFeedTokenRepository) RevokeAll(userID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE calendar_feed_tokens
+			SET revoked_at = $1
+			WHERE user_id = $2 AND revoked_at IS NULL`,
+			time.Now(), userID); err != nil {
+			return fmt.Errorf("error revoking calendar feed tokens: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeedTokenRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewCalendarFeedTokenRepository(db.UnwrapDB())
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+
+	first := &models.CalendarFeedToken{UserID: testUser.ID, TokenHash: "hash-one"}
+	second := &models.CalendarFeedToken{UserID: testUser.ID, TokenHash: "hash-two"}
+	other := &models.CalendarFeedToken{UserID: otherUser.ID, TokenHash: "hash-three"}
+	for _, token := range []*models.CalendarFeedToken{first, second, other} {
+		require.NoError(t, repo.Create(token))
+	}
+
+	t.Run("duplicate hash", func(t *testing.T) {
+		err := repo.Create(&models.CalendarFeedToken{UserID: testUser.ID, TokenHash: "hash-one"})
+		assert.Error(t, err)
+	})
+
+	t.Run("get by hash", func(t *testing.T) {
+		got, err := repo.GetByHash("hash-one")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, got.ID)
+		assert.Equal(t, testUser.ID, got.UserID)
+		assert.False(t, got.IsRevoked())
+
+		_, err = repo.GetByHash("missing")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		tokens, err := repo.ListByUser(testUser.ID)
+		require.NoError(t, err)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		require.NoError(t, repo.Revoke(testUser.ID, first.ID))
+		got, err := repo.GetByHash("hash-one")
+		require.NoError(t, err)
+		assert.True(t, got.IsRevoked())
+
+		assert.ErrorIs(t, repo.Revoke(testUser.ID, first.ID), models.ErrNotFound)
+		assert.ErrorIs(t, repo.Revoke(testUser.ID, other.ID), models.ErrNotFound)
+		assert.ErrorIs(t, repo.Revoke(testUser.ID, uuid.New()), models.ErrNotFound)
+	})
+
+	t.Run("revoke all", func(t *testing.T) {
+		require.NoError(t, repo.RevokeAll(testUser.ID))
+		got, err := repo.GetByHash("hash-two")
+		require.NoError(t, err)
+		assert.True(t, got.IsRevoked())
+
+		got, err = repo.GetByHash("hash-three")
+		require.NoError(t, err)
+		assert.False(t, got.IsRevoked())
+	})
+}
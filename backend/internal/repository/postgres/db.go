@@ -135,12 +135,22 @@ func NewDB(host string, port int, user, password, dbname, sslmode string) (*sql.
 
 // Repositories holds all repository implementations
 type Repositories struct {
-	Users          models.UserRepository
-	Tribes         models.TribeRepository
-	Activities     models.ActivityRepository
-	ActivityPhotos models.ActivityPhotosRepository
-	Lists          models.ListRepository
-	db             *sql.DB
+	Users              models.UserRepository
+	Tribes             models.TribeRepository
+	Activities         models.ActivityRepository
+	ActivityPhotos     models.ActivityPhotosRepository
+	Lists              models.ListRepository
+	MenuSessions       models.MenuSessionRepository
+	CalendarTokens     models.CalendarFeedTokenRepository
+	Availability       models.AvailabilityRepository
+	Interests          models.InterestIndicatorRepository
+	OwnershipTransfers models.TribeOwnershipTransferRepository
+	Invites            models.TribeInviteRepository
+	EmailInvitations   models.PendingInvitationRepository
+	GuestExpiry        models.GuestExpiryRepository
+	Audit              models.TribeAuditRepository
+	Events             models.TribeEventRepository
+	db                 *sql.DB
 }
 
 // NewRepositories creates new instances of all repositories
@@ -162,12 +172,22 @@ func NewRepositories(db interface{}) *Repositories {
 	}
 
 	return &Repositories{
-		Users:          NewUserRepository(db),
-		Tribes:         NewTribeRepository(db),
-		Activities:     NewActivityRepository(db),
-		ActivityPhotos: NewActivityPhotosRepository(db),
-		Lists:          NewListRepository(db),
-		db:             sqlDB,
+		Users:              NewUserRepository(db),
+		Tribes:             NewTribeRepository(db),
+		Activities:         NewActivityRepository(db),
+		ActivityPhotos:     NewActivityPhotosRepository(db),
+		Lists:              NewListRepository(db),
+		MenuSessions:       NewMenuSessionRepository(db),
+		CalendarTokens:     NewCalendarFeedTokenRepository(db),
+		Availability:       NewAvailabilityRepository(db),
+		Interests:          NewInterestIndicatorRepository(db),
+		OwnershipTransfers: NewTribeOwnershipTransferRepository(db),
+		Invites:            NewTribeInviteRepository(db),
+		EmailInvitations:   NewPendingInvitationRepository(db),
+		GuestExpiry:        NewGuestExpiryRepository(db),
+		Audit:              NewTribeAuditRepository(db),
+		Events:             NewTribeEventRepository(db),
+		db:                 sqlDB,
 	}
 }
 
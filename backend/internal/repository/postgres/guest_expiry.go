@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// GuestExpiryRepository implements models.GuestExpiryRepository using
+// PostgreSQL
+type GuestExpiryRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewGuestExpiryRepository creates a new PostgreSQL guest expiry repository
+func NewGuestExpiryRepository(db interface{}) models.GuestExpiryRepository {
+	baseRepo := NewBaseRepository(db)
+	return &GuestExpiryRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// ExpireGuest removes or downgrades an expired guest. Their co-ownership of
+// lists shared with or owned by the tribe is dropped, except for lists they
+// created, and the change is recorded in the tribe's audit trail.
+func (r *GuestExpiryRepository) ExpireGuest(tribeID, userID uuid.UUID, policy models.GuestExpiryPolicy) (*models.TribeAuditEntry, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var entry *models.TribeAuditEntry
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		var memberID uuid.UUID
+		var expiresAt time.Time
+		err := tx.QueryRow(`
+			SELECT id, expires_at
+			FROM tribe_members
+			WHERE tribe_id = $1 AND user_id = $2
+			AND membership_type = 'guest'
+			AND expires_at < $3
+			AND deleted_at IS NULL
+			FOR UPDATE`,
+			tribeID, userID, now).Scan(&memberID, &expiresAt)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting expired guest: %w", err)
+		}
+
+		if policy == models.GuestExpiryRemove {
+			_, err = tx.Exec(`
+				UPDATE tribe_members
+				SET deleted_at = $1, role = 'member'
+				WHERE id = $2`,
+				now, memberID)
+		} else {
+			_, err = tx.Exec(`
+				UPDATE tribe_members
+				SET membership_type = 'pending',
+					expires_at = NULL,
+					metadata = metadata || jsonb_build_object($1::text, true)
+				WHERE id = $2`,
+				models.ExpiredGuestMetadataKey, memberID)
+		}
+		if err != nil {
+			return fmt.Errorf("error expiring guest: %w", err)
+		}
+
+		result, err := tx.Exec(`
+			UPDATE list_owners lo
+			SET deleted_at = $1, updated_at = $1
+			FROM lists l
+			WHERE lo.list_id = l.id
+			AND lo.owner_id = $2 AND lo.owner_type = 'user' AND lo.deleted_at IS NULL
+			AND NOT (l.owner_id = $2 AND l.owner_type = 'user')
+			AND (
+				EXISTS (
+					SELECT 1 FROM list_sharing ls
+					WHERE ls.list_id = l.id AND ls.tribe_id = $3 AND ls.deleted_at IS NULL
+				)
+				OR EXISTS (
+					SELECT 1 FROM list_owners t
+					WHERE t.list_id = l.id AND t.owner_id = $3 AND t.owner_type = 'tribe' AND t.deleted_at IS NULL
+				)
+			)`,
+			now, userID, tribeID)
+		if err != nil {
+			return fmt.Errorf("error revoking list access: %w", err)
+		}
+		revoked, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+
+		entry = &models.TribeAuditEntry{
+			TribeID: tribeID,
+			UserID:  userID,
+			Action:  models.AuditGuestExpired,
+			Details: models.JSONMap{
+				"policy":              string(policy),
+				"expired_at":          expiresAt.UTC().Format(time.RFC3339),
+				"revoked_list_owners": revoked,
+			},
+			CreatedAt: now,
+		}
+		if err = insertTribeAuditEntry(tx, entry); err != nil {
+			return err
+		}
+
+		return insertTribeEvent(tx, &models.TribeEvent{
+			TribeID:   tribeID,
+			Type:      models.EventMemberLeft,
+			SubjectID: userID,
+			Details:   models.JSONMap{"reason": "guest_expired"},
+			CreatedAt: now,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetUnwarnedGuests retrieves active guests expiring before the given time
+// who have not yet been warned about their current expiry
+func (r *GuestExpiryRepository) GetUnwarnedGuests(before time.Time) ([]*models.TribeMember, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var members []*models.TribeMember
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT
+				tm.id, tm.tribe_id, tm.user_id, tm.membership_type, tm.role,
+				` + memberDisplayNameColumn + `, tm.expires_at, tm.invited_by, tm.invited_at, tm.metadata,
+				tm.created_at, tm.updated_at, tm.deleted_at, tm.version
+			FROM tribe_members tm
+			JOIN tribes t ON t.id = tm.tribe_id AND t.deleted_at IS NULL
+			WHERE tm.membership_type = 'guest'
+			AND tm.expires_at > $1 AND tm.expires_at <= $2
+			AND tm.expiry_warned_for IS DISTINCT FROM tm.expires_at
+			AND tm.deleted_at IS NULL
+			ORDER BY tm.expires_at`
+
+		rows, err := tx.Query(query, time.Now(), before)
+		if err != nil {
+			return fmt.Errorf("error getting expiring guests: %w", err)
+		}
+		defer safeClose(rows)
+
+		members = make([]*models.TribeMember, 0)
+		for rows.Next() {
+			member := &models.TribeMember{}
+			scanErr := rows.Scan(
+				&member.ID,
+				&member.TribeID,
+				&member.UserID,
+				&member.MembershipType,
+				&member.Role,
+				&member.DisplayName,
+				&member.ExpiresAt,
+				&member.InvitedBy,
+				&member.InvitedAt,
+				&member.Metadata,
+				&member.CreatedAt,
+				&member.UpdatedAt,
+				&member.DeletedAt,
+				&member.Version,
+			)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning expiring guest: %w", scanErr)
+			}
+			members = append(members, member)
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// MarkExpiryWarned records that a guest was warned about an expiry, so they
+// are warned again only if their expiry changes
+func (r *GuestExpiryRepository) MarkExpiryWarned(tribeID, userID uuid.UUID, expiresAt time.Time) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE tribe_members
+			SET expiry_warned_for = $1
+			WHERE tribe_id = $2 AND user_id = $3
+			AND membership_type = 'guest' AND deleted_at IS NULL`,
+			expiresAt, tribeID, userID)
+		if err != nil {
+			return fmt.Errorf("error marking guest warned: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		return insertTribeAuditEntry(tx, &models.TribeAuditEntry{
+			TribeID: tribeID,
+			UserID:  userID,
+			Action:  models.AuditGuestExpiryWarned,
+			Details: models.JSONMap{"expires_at": expiresAt.UTC().Format(time.RFC3339)},
+		})
+	})
+}
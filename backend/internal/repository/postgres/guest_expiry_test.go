@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestExpiryRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewGuestExpiryRepository(db.UnwrapDB())
+	tribes := NewTribeRepository(db.UnwrapDB())
+	lists := NewListRepository(db.UnwrapDB())
+	audit := NewTribeAuditRepository(db.UnwrapDB())
+	owner := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{owner})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{owner})
+	now := time.Now()
+
+	addGuest := func(expiresAt time.Time) testutil.TestUser {
+		guest := testutil.CreateTestUser(t, db)
+		require.NoError(t, tribes.AddMember(tribe.ID, guest.ID, models.MembershipGuest, &expiresAt, nil))
+		return guest
+	}
+	memberOf := func(userID uuid.UUID) *models.TribeMember {
+		members, err := tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, member := range members {
+			if member.UserID == userID {
+				return member
+			}
+		}
+		return nil
+	}
+	coOwns := func(listID, userID uuid.UUID) bool {
+		owners, err := lists.GetOwners(listID)
+		require.NoError(t, err)
+		for _, listOwner := range owners {
+			if listOwner.OwnerID == userID && listOwner.OwnerType == models.OwnerTypeUser {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("remove revokes list access", func(t *testing.T) {
+		guest := addGuest(now.Add(-time.Hour))
+		tribeList := testutil.CreateTestList(t, db, tribe)
+		otherList := testutil.CreateTestList(t, db, otherTribe)
+		for _, listID := range []uuid.UUID{tribeList.ID, otherList.ID} {
+			require.NoError(t, lists.AddOwner(&models.ListOwner{ListID: listID, OwnerID: guest.ID, OwnerType: models.OwnerTypeUser}))
+		}
+
+		entry, err := repo.ExpireGuest(tribe.ID, guest.ID, models.GuestExpiryRemove)
+		require.NoError(t, err)
+		assert.Equal(t, models.AuditGuestExpired, entry.Action)
+		assert.Nil(t, entry.ActorID)
+
+		assert.Nil(t, memberOf(guest.ID))
+		assert.False(t, coOwns(tribeList.ID, guest.ID))
+		assert.True(t, coOwns(otherList.ID, guest.ID), "lists outside the tribe are untouched")
+
+		entries, err := audit.ListByTribe(tribe.ID, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		assert.Equal(t, entry.ID, entries[0].ID)
+		assert.Equal(t, guest.ID, entries[0].UserID)
+		assert.Equal(t, "remove", entries[0].Details["policy"])
+		assert.EqualValues(t, 1, entries[0].Details["revoked_list_owners"])
+
+		_, err = repo.ExpireGuest(tribe.ID, guest.ID, models.GuestExpiryRemove)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("downgrade keeps a pending membership", func(t *testing.T) {
+		guest := addGuest(now.Add(-time.Hour))
+
+		_, err := repo.ExpireGuest(tribe.ID, guest.ID, models.GuestExpiryDowngrade)
+		require.NoError(t, err)
+
+		member := memberOf(guest.ID)
+		require.NotNil(t, member)
+		assert.Equal(t, models.MembershipPending, member.MembershipType)
+		assert.Nil(t, member.ExpiresAt)
+		assert.True(t, member.IsExpiredGuest())
+
+		require.NoError(t, tribes.RemoveMember(tribe.ID, guest.ID))
+		require.NoError(t, tribes.ReinviteMember(tribe.ID, guest.ID, models.MembershipPending, nil, &owner.ID))
+		member = memberOf(guest.ID)
+		require.NotNil(t, member)
+		assert.False(t, member.IsExpiredGuest(), "re-invited guests can accept again")
+	})
+
+	t.Run("active guests are not expired", func(t *testing.T) {
+		guest := addGuest(now.Add(time.Hour))
+		_, err := repo.ExpireGuest(tribe.ID, guest.ID, models.GuestExpiryRemove)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = repo.ExpireGuest(tribe.ID, owner.ID, models.GuestExpiryRemove)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		soon := addGuest(now.Add(24 * time.Hour))
+		later := addGuest(now.Add(10 * 24 * time.Hour))
+
+		unwarned, err := repo.GetUnwarnedGuests(now.Add(3 * 24 * time.Hour))
+		require.NoError(t, err)
+		var ids []uuid.UUID
+		for _, member := range unwarned {
+			ids = append(ids, member.UserID)
+		}
+		assert.Contains(t, ids, soon.ID)
+		assert.NotContains(t, ids, later.ID)
+
+		member := memberOf(soon.ID)
+		require.NotNil(t, member)
+		require.NoError(t, repo.MarkExpiryWarned(tribe.ID, soon.ID, *member.ExpiresAt))
+
+		unwarned, err = repo.GetUnwarnedGuests(now.Add(3 * 24 * time.Hour))
+		require.NoError(t, err)
+		for _, guest := range unwarned {
+			assert.NotEqual(t, soon.ID, guest.UserID)
+		}
+
+		entries, err := audit.ListByTribe(tribe.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, models.AuditGuestExpiryWarned, entries[0].Action)
+
+		assert.ErrorIs(t, repo.MarkExpiryWarned(tribe.ID, owner.ID, now), models.ErrNotFound)
+	})
+}
@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// interestColumns lists the interest indicator columns in scan order
+const interestColumns = `id, user_id, tribe_id, list_id, activity_type, mood, starts_at, expires_at, created_at, updated_at`
+
+// InterestIndicatorRepository implements models.InterestIndicatorRepository
+// using PostgreSQL
+type InterestIndicatorRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewInterestIndicatorRepository creates a new PostgreSQL interest indicator
+// repository
+func NewInterestIndicatorRepository(db interface{}) models.InterestIndicatorRepository {
+	baseRepo := NewBaseRepository(db)
+	return &InterestIndicatorRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// scanInterest scans one interest indicator row
+func scanInterest(row interface{ Scan(...interface{}) error }) (*models.InterestIndicator, error) {
+	indicator := &models.InterestIndicator{}
+	err := row.Scan(
+		&indicator.ID,
+		&indicator.UserID,
+		&indicator.TribeID,
+		&indicator.ListID,
+		&indicator.ActivityType,
+		&indicator.Mood,
+		&indicator.StartsAt,
+		&indicator.ExpiresAt,
+		&indicator.CreatedAt,
+		&indicator.UpdatedAt,
+	)
+	return indicator, err
+}
+
+// Create stores a new interest indicator
+func (r *InterestIndicatorRepository) Create(indicator *models.InterestIndicator) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if indicator.ID == uuid.Nil {
+			indicator.ID = uuid.New()
+		}
+		now := time.Now()
+		indicator.CreatedAt = now
+		indicator.UpdatedAt = now
+
+		_, err := tx.Exec(`
+			INSERT INTO interest_indicators (
+				id, user_id, tribe_id, list_id, activity_type, mood, starts_at, expires_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)`,
+			indicator.ID,
+			indicator.UserID,
+			indicator.TribeID,
+			indicator.ListID,
+			indicator.ActivityType,
+			indicator.Mood,
+			indicator.StartsAt,
+			indicator.ExpiresAt,
+			now,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return fmt.Errorf("%w: user, tribe or list does not exist", models.ErrNotFound)
+			}
+			return fmt.Errorf("error creating interest indicator: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves an interest indicator, expired or not
+func (r *InterestIndicatorRepository) GetByID(id uuid.UUID) (*models.InterestIndicator, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var indicator *models.InterestIndicator
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var err error
+		indicator, err = scanInterest(tx.QueryRow(`
+			SELECT `+interestColumns+`
+			FROM interest_indicators
+			WHERE id = $1`, id))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting interest indicator: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return indicator, nil
+}
+
+// Update saves the target, mood and times of an interest indicator
+func (r *InterestIndicatorRepository) Update(indicator *models.InterestIndicator) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`
+			UPDATE interest_indicators
+			SET list_id = $1, activity_type = $2, mood = $3, starts_at = $4, expires_at = $5, updated_at = $6
+			WHERE id = $7
+			RETURNING updated_at`,
+			indicator.ListID,
+			indicator.ActivityType,
+			indicator.Mood,
+			indicator.StartsAt,
+			indicator.ExpiresAt,
+			time.Now(),
+			indicator.ID,
+		).Scan(&indicator.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return fmt.Errorf("%w: list does not exist", models.ErrNotFound)
+			}
+			return fmt.Errorf("error updating interest indicator: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes an interest indicator
+func (r *InterestIndicatorRepository) Delete(id uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM interest_indicators WHERE id = $1`, id)
+		if err != nil {
+			return fmt.Errorf("error deleting interest indicator: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// ListByUser returns a user's current and upcoming indicators, soonest first
+func (r *InterestIndicatorRepository) ListByUser(userID uuid.UUID, now time.Time) ([]*models.InterestIndicator, error) {
+	return r.list(`
+		SELECT `+interestColumns+`
+		FROM interest_indicators
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY starts_at, id`, userID, now)
+}
+
+// ListActiveByTribe returns the tribe's indicators in effect at now, those
+// expiring soonest first
+func (r *InterestIndicatorRepository) ListActiveByTribe(tribeID uuid.UUID, now time.Time) ([]*models.InterestIndicator, error) {
+	return r.list(`
+		SELECT `+interestColumns+`
+		FROM interest_indicators
+		WHERE tribe_id = $1 AND starts_at <= $2 AND expires_at > $2
+		ORDER BY expires_at, id`, tribeID, now)
+}
+
+// list runs a query returning interest indicator rows
+func (r *InterestIndicatorRepository) list(query string, args ...interface{}) ([]*models.InterestIndicator, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var indicators []*models.InterestIndicator
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("error listing interest indicators: %w", err)
+		}
+		defer safeClose(rows)
+
+		indicators = make([]*models.InterestIndicator, 0)
+		for rows.Next() {
+			indicator, scanErr := scanInterest(rows)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning interest indicator: %w", scanErr)
+			}
+			indicators = append(indicators, indicator)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating interest indicators: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return indicators, nil
+}
+
+// DeleteExpired removes the indicators that expired by now
+func (r *InterestIndicatorRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	opts := DefaultTransactionOptions()
+	var deleted int
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM interest_indicators WHERE expires_at <= $1`, now)
+		if err != nil {
+			return fmt.Errorf("error deleting expired interest indicators: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		deleted = int(rows)
+		return nil
+	})
+
+	return deleted, err
+}
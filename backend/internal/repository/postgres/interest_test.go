@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterestIndicatorRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewInterestIndicatorRepository(db.UnwrapDB())
+	testUser := testutil.CreateTestUser(t, db)
+	otherUser := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{testUser, otherUser})
+	list := testutil.CreateTestList(t, db, tribe)
+	now := time.Now().UTC().Truncate(time.Second)
+	location := models.ActivityTypeLocation
+
+	current := &models.InterestIndicator{
+		UserID:    testUser.ID,
+		TribeID:   tribe.ID,
+		ListID:    &list.ID,
+		Mood:      "hungry",
+		StartsAt:  now.Add(-time.Hour),
+		ExpiresAt: now.Add(time.Hour),
+	}
+	upcoming := &models.InterestIndicator{
+		UserID:       otherUser.ID,
+		TribeID:      tribe.ID,
+		ActivityType: &location,
+		StartsAt:     now.Add(2 * time.Hour),
+		ExpiresAt:    now.Add(3 * time.Hour),
+	}
+	expired := &models.InterestIndicator{
+		UserID:    testUser.ID,
+		TribeID:   tribe.ID,
+		StartsAt:  now.Add(-3 * time.Hour),
+		ExpiresAt: now.Add(-2 * time.Hour),
+	}
+	for _, indicator := range []*models.InterestIndicator{current, upcoming, expired} {
+		require.NoError(t, repo.Create(indicator))
+	}
+
+	t.Run("create with unknown tribe", func(t *testing.T) {
+		err := repo.Create(&models.InterestIndicator{
+			UserID:    testUser.ID,
+			TribeID:   uuid.New(),
+			StartsAt:  now,
+			ExpiresAt: now.Add(time.Hour),
+		})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		got, err := repo.GetByID(current.ID)
+		require.NoError(t, err)
+		assert.Equal(t, list.ID, *got.ListID)
+		assert.Nil(t, got.ActivityType)
+		assert.Equal(t, "hungry", got.Mood)
+		assert.True(t, current.ExpiresAt.Equal(got.ExpiresAt))
+
+		got, err = repo.GetByID(upcoming.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.ListID)
+		assert.Equal(t, location, *got.ActivityType)
+
+		_, err = repo.GetByID(uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		indicators, err := repo.ListByUser(testUser.ID, now)
+		require.NoError(t, err)
+		require.Len(t, indicators, 1)
+		assert.Equal(t, current.ID, indicators[0].ID)
+	})
+
+	t.Run("list active by tribe", func(t *testing.T) {
+		indicators, err := repo.ListActiveByTribe(tribe.ID, now)
+		require.NoError(t, err)
+		require.Len(t, indicators, 1)
+		assert.Equal(t, current.ID, indicators[0].ID)
+
+		indicators, err = repo.ListActiveByTribe(tribe.ID, now.Add(150*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, indicators, 1)
+		assert.Equal(t, upcoming.ID, indicators[0].ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		current.ListID = nil
+		current.ActivityType = &location
+		current.Mood = "anywhere"
+		current.ExpiresAt = now.Add(90 * time.Minute)
+		require.NoError(t, repo.Update(current))
+
+		got, err := repo.GetByID(current.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.ListID)
+		assert.Equal(t, location, *got.ActivityType)
+		assert.Equal(t, "anywhere", got.Mood)
+		assert.True(t, current.ExpiresAt.Equal(got.ExpiresAt))
+
+		assert.ErrorIs(t, repo.Update(&models.InterestIndicator{ID: uuid.New(), StartsAt: now, ExpiresAt: now.Add(time.Hour)}), models.ErrNotFound)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		deleted, err := repo.DeleteExpired(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		_, err = repo.GetByID(expired.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = repo.GetByID(upcoming.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(upcoming.ID))
+		assert.ErrorIs(t, repo.Delete(upcoming.ID), models.ErrNotFound)
+	})
+}
@@ -224,6 +224,7 @@ func (r *ListRepository) loadListData(tx *sql.Tx, list *models.List) error {
 			metadata, external_id, 
 			latitude, longitude, address, 
 			weight, last_chosen, chosen_count,
+			cooldown, available, seasonal, start_date, end_date,
 			created_at, updated_at, deleted_at
 		FROM list_items
 		WHERE list_id = $1 AND deleted_at IS NULL
@@ -247,6 +248,7 @@ func (r *ListRepository) loadListData(tx *sql.Tx, list *models.List) error {
 				&metadata, &item.ExternalID,
 				&item.Latitude, &item.Longitude, &item.Address,
 				&item.Weight, &item.LastChosen, &item.ChosenCount,
+				&item.Cooldown, &item.Available, &item.Seasonal, &item.StartDate, &item.EndDate,
 				&item.CreatedAt, &item.UpdatedAt, &item.DeletedAt,
 			); err != nil {
 				log.Printf("Error scanning list item: %v", err)
@@ -762,8 +764,9 @@ func (r *ListRepository) List(offset, limit int) ([]*models.List, error) {
 	return lists, nil
 }
 
-// AddItem adds a new item to a list
-func (r *ListRepository) AddItem(item *models.ListItem) error {
+// AddItem adds a new item to a list. Items created by a sync are left out
+// of tribe feeds; the sync records a single summary instead.
+func (r *ListRepository) AddItem(item *models.ListItem, actorID *uuid.UUID) error {
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
 
@@ -788,13 +791,17 @@ func (r *ListRepository) AddItem(item *models.ListItem) error {
 				id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
+				sync_snapshot,
 				created_at, updated_at
 			) VALUES (
 				$1, $2, $3, $4,
 				$5, $6,
 				$7, $8, $9,
-				$10, $11, $12,
+				$10, $11, $12, $13, $14,
+				$15, $16, $17,
+				$18,
 				NOW(), NOW()
 			) RETURNING created_at, updated_at`
 
@@ -802,13 +809,32 @@ func (r *ListRepository) AddItem(item *models.ListItem) error {
 			item.ID, item.ListID, item.Name, item.Description,
 			metadata, item.ExternalID,
 			item.Weight, item.LastChosen, item.ChosenCount,
+			item.Cooldown, item.Available, item.Seasonal, item.StartDate, item.EndDate,
 			item.Latitude, item.Longitude, item.Address,
+			item.SyncSnapshot,
 		).Scan(&item.CreatedAt, &item.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("error adding list item: %w", err)
 		}
 
-		return nil
+		if item.ExternalID != "" {
+			return nil
+		}
+		tribeIDs, err := listTribeIDs(tx, item.ListID)
+		if err != nil || len(tribeIDs) == 0 {
+			return err
+		}
+		details, err := listEventDetails(tx, item.ListID)
+		if err != nil {
+			return err
+		}
+		details["item_name"] = item.Name
+		return insertTribeEvents(tx, tribeIDs, models.TribeEvent{
+			Type:      models.EventListItemAdded,
+			ActorID:   actorID,
+			SubjectID: item.ID,
+			Details:   details,
+		})
 	})
 }
 
@@ -818,50 +844,65 @@ func (r *ListRepository) UpdateItem(item *models.ListItem) error {
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
-		metadata, err := json.Marshal(item.Metadata)
-		if err != nil {
-			return fmt.Errorf("error marshaling metadata: %w", err)
-		}
-
-		item.UpdatedAt = time.Now()
+		return updateItem(tx, item)
+	})
+}
 
-		query := `
-			UPDATE list_items SET
-				name = $1,
-				description = $2,
-				metadata = $3,
-				external_id = $4,
-				weight = $5,
-				last_chosen = $6,
-				chosen_count = $7,
-				latitude = $8,
-				longitude = $9,
-				address = $10,
-				updated_at = $11
-			WHERE id = $12 AND list_id = $13 AND deleted_at IS NULL`
-
-		result, err := tx.Exec(query,
-			item.Name, item.Description,
-			metadata, item.ExternalID,
-			item.Weight, item.LastChosen, item.ChosenCount,
-			item.Latitude, item.Longitude, item.Address,
-			item.UpdatedAt,
-			item.ID, item.ListID,
-		)
-		if err != nil {
-			return fmt.Errorf("error updating list item: %w", err)
-		}
+// updateItem updates a list item inside an existing transaction. The sync
+// snapshot is only written when the item carries one, since items edited
+// through the API arrive without it.
+func updateItem(tx *sql.Tx, item *models.ListItem) error {
+	metadata, err := json.Marshal(item.Metadata)
+	if err != nil {
+		return fmt.Errorf("error marshaling metadata: %w", err)
+	}
+
+	item.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE list_items SET
+			name = $1,
+			description = $2,
+			metadata = $3,
+			external_id = $4,
+			weight = $5,
+			last_chosen = $6,
+			chosen_count = $7,
+			latitude = $8,
+			longitude = $9,
+			address = $10,
+			cooldown = $11,
+			available = $12,
+			seasonal = $13,
+			start_date = $14,
+			end_date = $15,
+			sync_snapshot = COALESCE($16, sync_snapshot),
+			updated_at = $17
+		WHERE id = $18 AND list_id = $19 AND deleted_at IS NULL`
+
+	result, err := tx.Exec(query,
+		item.Name, item.Description,
+		metadata, item.ExternalID,
+		item.Weight, item.LastChosen, item.ChosenCount,
+		item.Latitude, item.Longitude, item.Address,
+		item.Cooldown, item.Available, item.Seasonal, item.StartDate, item.EndDate,
+		item.SyncSnapshot,
+		item.UpdatedAt,
+		item.ID, item.ListID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating list item: %w", err)
+	}
 
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return fmt.Errorf("error getting rows affected: %w", err)
-		}
-		if rows == 0 {
-			return models.ErrNotFound
-		}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
 
-		return nil
-	})
+	return nil
 }
 
 // RemoveItem soft-deletes an item from a list
@@ -870,26 +911,31 @@ func (r *ListRepository) RemoveItem(listID, itemID uuid.UUID) error {
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
-		query := `
-			UPDATE list_items SET
-				deleted_at = NOW()
-			WHERE id = $1 AND list_id = $2 AND deleted_at IS NULL`
+		return removeItem(tx, listID, itemID)
+	})
+}
 
-		result, err := tx.Exec(query, itemID, listID)
-		if err != nil {
-			return err
-		}
+// removeItem soft-deletes a list item inside an existing transaction
+func removeItem(tx *sql.Tx, listID, itemID uuid.UUID) error {
+	query := `
+		UPDATE list_items SET
+			deleted_at = NOW()
+		WHERE id = $1 AND list_id = $2 AND deleted_at IS NULL`
 
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return err
-		}
-		if rows == 0 {
-			return fmt.Errorf("item not found: %v", itemID)
-		}
+	result, err := tx.Exec(query, itemID, listID)
+	if err != nil {
+		return err
+	}
 
-		return nil
-	})
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("item not found: %v", itemID)
+	}
+
+	return nil
 }
 
 // GetItems retrieves all items from a list
@@ -905,7 +951,9 @@ func (r *ListRepository) GetItems(listID uuid.UUID) ([]*models.ListItem, error)
 			SELECT id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
+				sync_snapshot,
 				created_at, updated_at, deleted_at
 			FROM list_items
 			WHERE list_id = $1 AND deleted_at IS NULL
@@ -924,7 +972,9 @@ func (r *ListRepository) GetItems(listID uuid.UUID) ([]*models.ListItem, error)
 				&item.ID, &item.ListID, &item.Name, &item.Description,
 				&metadata, &item.ExternalID,
 				&item.Weight, &item.LastChosen, &item.ChosenCount,
+				&item.Cooldown, &item.Available, &item.Seasonal, &item.StartDate, &item.EndDate,
 				&item.Latitude, &item.Longitude, &item.Address,
+				&item.SyncSnapshot,
 				&item.CreatedAt, &item.UpdatedAt, &item.DeletedAt,
 			); err != nil {
 				return fmt.Errorf("error scanning list item: %w", err)
@@ -955,38 +1005,99 @@ func (r *ListRepository) GetItems(listID uuid.UUID) ([]*models.ListItem, error)
 	return items, nil
 }
 
-// GetEligibleItems retrieves items eligible for menu generation
+// maxEligibleItems is a safety cap on the candidates read for one menu
+const maxEligibleItems = 10000
+
+// GetEligibleItems retrieves items eligible for menu generation. An item is
+// eligible when it is available, today falls inside its seasonal window (if
+// it has one) and its cooldown has passed. Seasonal windows recur yearly: only
+// the month and day of start_date and end_date are compared, so a window from
+// December to February wraps the year end. The item's own cooldown takes
+// precedence over the cooldown_days filter, which takes precedence over the
+// list's cooldown_days. The "at" filter overrides the current time.
+//
+// The "max_items" filter is not applied here: menus sample from every
+// eligible item and cap the result afterwards, so limiting the ordered rows
+// would always offer the same ones. Only maxEligibleItems bounds the query.
+//
+// When the filters carry an origin, each item's haversine distance from it is
+// returned in DistanceKm and items are ordered nearest first. A radius_km
+// filter drops items outside the radius and items without coordinates.
 func (r *ListRepository) GetEligibleItems(listIDs []uuid.UUID, filters map[string]interface{}) ([]*models.ListItem, error) {
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
 	var items []*models.ListItem
 
-	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
-		query := `
-			SELECT 
-				i.id, i.list_id, i.name, i.description,
-				i.metadata, i.external_id,
-				i.weight, i.last_chosen, i.chosen_count,
-				i.latitude, i.longitude, i.address,
-				i.created_at, i.updated_at, i.deleted_at,
-				l.default_weight, l.cooldown_days
-			FROM list_items i
-			JOIN lists l ON i.list_id = l.id
-			WHERE i.list_id = ANY($1)
-			AND i.deleted_at IS NULL
-			AND l.deleted_at IS NULL`
+	now := time.Now()
+	if at, ok := filters["at"].(time.Time); ok {
+		now = at
+	}
 
-		// Apply filters
-		if cooldown, ok := filters["cooldown_days"].(int); ok {
-			query += fmt.Sprintf(" AND (i.last_chosen IS NULL OR i.last_chosen < NOW() - INTERVAL '%d days')", cooldown)
-		}
-		if maxItems, ok := filters["max_items"].(int); ok {
-			query += fmt.Sprintf(" LIMIT %d", maxItems)
-		}
+	var cooldownFilter *int
+	if cooldown, ok := intFilter(filters, "cooldown_days"); ok {
+		cooldownFilter = &cooldown
+	}
 
-		rows, err := tx.Query(query, pq.Array(listIDs))
+	geo, err := models.GeoFilterFromFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+	var originLat, originLng, radiusKm *float64
+	if geo != nil {
+		originLat, originLng, radiusKm = &geo.Latitude, &geo.Longitude, geo.RadiusKm
+	}
+
+	err = r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			WITH candidates AS (
+				SELECT
+					i.*,
+					l.default_weight,
+					COALESCE(i.cooldown, $3, l.cooldown_days) AS effective_cooldown,
+					to_char(i.start_date, 'MMDD') AS season_start,
+					to_char(i.end_date, 'MMDD') AS season_end,
+					to_char($2::timestamptz, 'MMDD') AS today,
+					CASE WHEN $4::float8 IS NULL OR i.latitude IS NULL OR i.longitude IS NULL THEN NULL
+					ELSE 2 * $8::float8 * asin(least(1, sqrt(
+						power(sin(radians(i.latitude::float8 - $4::float8) / 2), 2) +
+						cos(radians($4::float8)) * cos(radians(i.latitude::float8)) *
+						power(sin(radians(i.longitude::float8 - $5::float8) / 2), 2)
+					))) END AS distance_km
+				FROM list_items i
+				JOIN lists l ON i.list_id = l.id
+				WHERE i.list_id = ANY($1)
+				AND i.deleted_at IS NULL
+				AND l.deleted_at IS NULL
+			)
+			SELECT
+				id, list_id, name, description,
+				metadata, external_id,
+				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
+				latitude, longitude, address,
+				created_at, updated_at, deleted_at,
+				default_weight, distance_km
+			FROM candidates
+			WHERE available
+			AND (
+				NOT seasonal
+				OR season_start IS NULL
+				OR season_end IS NULL
+				OR (season_start <= season_end AND today BETWEEN season_start AND season_end)
+				OR (season_start > season_end AND (today >= season_start OR today <= season_end))
+			)
+			AND (
+				last_chosen IS NULL
+				OR effective_cooldown IS NULL
+				OR last_chosen <= $2::timestamptz - make_interval(days => effective_cooldown)
+			)
+			AND ($6::float8 IS NULL OR distance_km <= $6::float8)
+			ORDER BY distance_km NULLS LAST, created_at
+			LIMIT $7`
+
+		rows, err := tx.Query(query, pq.Array(listIDs), now, cooldownFilter, originLat, originLng, radiusKm, maxEligibleItems, models.EarthRadiusKm)
 		if err != nil {
-			return err
+			return fmt.Errorf("error querying eligible items: %w", err)
 		}
 		defer safeClose(rows)
 
@@ -994,16 +1105,20 @@ func (r *ListRepository) GetEligibleItems(listIDs []uuid.UUID, filters map[strin
 			item := &models.ListItem{}
 			var metadata []byte
 			var defaultWeight float64
-			var cooldownDays *int
+			var distance sql.NullFloat64
 			if err := rows.Scan(
 				&item.ID, &item.ListID, &item.Name, &item.Description,
 				&metadata, &item.ExternalID,
 				&item.Weight, &item.LastChosen, &item.ChosenCount,
+				&item.Cooldown, &item.Available, &item.Seasonal, &item.StartDate, &item.EndDate,
 				&item.Latitude, &item.Longitude, &item.Address,
 				&item.CreatedAt, &item.UpdatedAt, &item.DeletedAt,
-				&defaultWeight, &cooldownDays,
+				&defaultWeight, &distance,
 			); err != nil {
-				return err
+				return fmt.Errorf("error scanning eligible item: %w", err)
+			}
+			if distance.Valid {
+				item.DistanceKm = &distance.Float64
 			}
 
 			if len(metadata) > 0 {
@@ -1022,7 +1137,7 @@ func (r *ListRepository) GetEligibleItems(listIDs []uuid.UUID, filters map[strin
 			items = append(items, item)
 		}
 
-		return nil
+		return rows.Err()
 	})
 
 	if err != nil {
@@ -1032,34 +1147,52 @@ func (r *ListRepository) GetEligibleItems(listIDs []uuid.UUID, filters map[strin
 	return items, nil
 }
 
+// intFilter reads an integer filter that may have been decoded from JSON as
+// a float64
+func intFilter(filters map[string]interface{}, key string) (int, bool) {
+	switch v := filters[key].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
 // UpdateItemStats updates the statistics for a list item
 func (r *ListRepository) UpdateItemStats(itemID uuid.UUID, chosen bool) error {
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
-		query := `
-			UPDATE list_items SET
-				last_chosen = CASE WHEN $2 THEN NOW() ELSE last_chosen END,
-				chosen_count = chosen_count + CASE WHEN $2 THEN 1 ELSE 0 END,
-				updated_at = NOW()
-			WHERE id = $1 AND deleted_at IS NULL`
+		return updateItemStats(tx, itemID, chosen)
+	})
+}
 
-		result, err := tx.Exec(query, itemID, chosen)
-		if err != nil {
-			return fmt.Errorf("error updating item stats: %w", err)
-		}
+// updateItemStats updates an item's stats inside an existing transaction
+func updateItemStats(tx *sql.Tx, itemID uuid.UUID, chosen bool) error {
+	query := `
+		UPDATE list_items SET
+			last_chosen = CASE WHEN $2 THEN NOW() ELSE last_chosen END,
+			chosen_count = chosen_count + CASE WHEN $2 THEN 1 ELSE 0 END,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
 
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return fmt.Errorf("error getting rows affected: %w", err)
-		}
-		if rows == 0 {
-			return models.ErrNotFound
-		}
+	result, err := tx.Exec(query, itemID, chosen)
+	if err != nil {
+		return fmt.Errorf("error updating item stats: %w", err)
+	}
 
-		return nil
-	})
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
 }
 
 // UpdateSyncStatus updates the sync status of a list
@@ -1095,6 +1228,32 @@ func (r *ListRepository) UpdateSyncStatus(listID uuid.UUID, status models.ListSy
 	})
 }
 
+// RecordSync adds a summary of a sync to the feeds of the tribes that can
+// see the list
+func (r *ListRepository) RecordSync(listID uuid.UUID, created, updated, deleted int) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		tribeIDs, err := listTribeIDs(tx, listID)
+		if err != nil || len(tribeIDs) == 0 {
+			return err
+		}
+		details, err := listEventDetails(tx, listID)
+		if err != nil {
+			return err
+		}
+		details["created"] = created
+		details["updated"] = updated
+		details["deleted"] = deleted
+		return insertTribeEvents(tx, tribeIDs, models.TribeEvent{
+			Type:      models.EventListSynced,
+			SubjectID: listID,
+			Details:   details,
+		})
+	})
+}
+
 // GetListsBySource retrieves all lists from a specific sync source
 func (r *ListRepository) GetListsBySource(source string) ([]*models.List, error) {
 	// Get test schema context if available
@@ -1231,18 +1390,19 @@ func (r *ListRepository) CreateConflict(conflict *models.SyncConflict) error {
 
 		query := `
 			INSERT INTO list_conflicts (
-				id, list_id, type,
+				id, list_id, item_id, type,
 				local_data, remote_data,
 				created_at, updated_at
 			) VALUES (
-				$1, $2, $3,
-				$4, $5,
+				$1, $2, $3, $4,
+				$5, $6,
 				NOW(), NOW()
 			)`
 
 		_, err = tx.Exec(query,
 			conflict.ID,
 			conflict.ListID,
+			conflict.ItemID,
 			conflict.Type,
 			localData,
 			remoteData,
@@ -1255,6 +1415,50 @@ func (r *ListRepository) CreateConflict(conflict *models.SyncConflict) error {
 	})
 }
 
+// conflictColumns lists the list_conflicts columns read by scanConflict
+const conflictColumns = `id, list_id, item_id, type,
+				local_data, remote_data, resolution,
+				created_at, updated_at, resolved_at`
+
+// scanConflict scans a list_conflicts row selected with conflictColumns
+func scanConflict(row rowScanner) (*models.SyncConflict, error) {
+	conflict := &models.SyncConflict{}
+	var itemID uuid.NullUUID
+	var resolution sql.NullString
+	var localData, remoteData []byte
+
+	err := row.Scan(
+		&conflict.ID,
+		&conflict.ListID,
+		&itemID,
+		&conflict.Type,
+		&localData,
+		&remoteData,
+		&resolution,
+		&conflict.CreatedAt,
+		&conflict.UpdatedAt,
+		&conflict.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if itemID.Valid {
+		conflict.ItemID = &itemID.UUID
+	}
+	conflict.Resolution = resolution.String
+
+	// Parse JSON data
+	if err := json.Unmarshal(localData, &conflict.LocalData); err != nil {
+		return nil, fmt.Errorf("error parsing local data: %w", err)
+	}
+	if err := json.Unmarshal(remoteData, &conflict.RemoteData); err != nil {
+		return nil, fmt.Errorf("error parsing remote data: %w", err)
+	}
+
+	return conflict, nil
+}
+
 // GetConflicts retrieves all unresolved conflicts for a list
 func (r *ListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict, error) {
 	ctx := context.Background()
@@ -1263,9 +1467,7 @@ func (r *ListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict,
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT id, list_id, type,
-				local_data, remote_data,
-				created_at, updated_at, resolved_at
+			SELECT ` + conflictColumns + `
 			FROM list_conflicts
 			WHERE list_id = $1
 				AND resolved_at IS NULL
@@ -1279,35 +1481,14 @@ func (r *ListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict,
 		defer safeClose(rows)
 
 		for rows.Next() {
-			conflict := &models.SyncConflict{}
-			var localData, remoteData []byte
-
-			err := rows.Scan(
-				&conflict.ID,
-				&conflict.ListID,
-				&conflict.Type,
-				&localData,
-				&remoteData,
-				&conflict.CreatedAt,
-				&conflict.UpdatedAt,
-				&conflict.ResolvedAt,
-			)
+			conflict, err := scanConflict(rows)
 			if err != nil {
 				return fmt.Errorf("error scanning conflict: %w", err)
 			}
-
-			// Parse JSON data
-			if err := json.Unmarshal(localData, &conflict.LocalData); err != nil {
-				return fmt.Errorf("error parsing local data: %w", err)
-			}
-			if err := json.Unmarshal(remoteData, &conflict.RemoteData); err != nil {
-				return fmt.Errorf("error parsing remote data: %w", err)
-			}
-
 			conflicts = append(conflicts, conflict)
 		}
 
-		return nil
+		return rows.Err()
 	})
 
 	if err != nil {
@@ -1317,18 +1498,60 @@ func (r *ListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict,
 	return conflicts, nil
 }
 
-// ResolveConflict marks a conflict as resolved
-func (r *ListRepository) ResolveConflict(conflictID uuid.UUID) error {
+// GetConflict retrieves a single conflict, resolved or not
+func (r *ListRepository) GetConflict(conflictID uuid.UUID) (*models.SyncConflict, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var conflict *models.SyncConflict
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			SELECT ` + conflictColumns + `
+			FROM list_conflicts
+			WHERE id = $1 AND deleted_at IS NULL`
+
+		var err error
+		conflict, err = scanConflict(tx.QueryRow(query, conflictID))
+		if err == sql.ErrNoRows {
+			return models.ErrConflictNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting conflict: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conflict, nil
+}
+
+// ResolveConflict marks a conflict as resolved with the given strategy
+func (r *ListRepository) ResolveConflict(conflictID uuid.UUID, resolution string, change *models.ResolvedItem) error {
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if change != nil {
+			var err error
+			if change.Remove {
+				err = removeItem(tx, change.Item.ListID, change.Item.ID)
+			} else {
+				err = updateItem(tx, change.Item)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
 		query := `
 			UPDATE list_conflicts
-			SET resolved_at = NOW(), updated_at = NOW()
+			SET resolution = $2, resolved_at = NOW(), updated_at = NOW()
 			WHERE id = $1 AND resolved_at IS NULL`
 
-		result, err := tx.Exec(query, conflictID)
+		result, err := tx.Exec(query, conflictID, resolution)
 		if err != nil {
 			return fmt.Errorf("error resolving conflict: %w", err)
 		}
@@ -1526,6 +1749,7 @@ func (r *ListRepository) GetUserLists(userID uuid.UUID) ([]*models.List, error)
 			SELECT id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
 				created_at, updated_at, deleted_at
 			FROM list_items
@@ -1552,6 +1776,11 @@ func (r *ListRepository) GetUserLists(userID uuid.UUID) ([]*models.List, error)
 				&item.Weight,
 				&item.LastChosen,
 				&item.ChosenCount,
+				&item.Cooldown,
+				&item.Available,
+				&item.Seasonal,
+				&item.StartDate,
+				&item.EndDate,
 				&item.Latitude,
 				&item.Longitude,
 				&item.Address,
@@ -1716,6 +1945,7 @@ func (r *ListRepository) GetTribeLists(tribeID uuid.UUID) ([]*models.List, error
 			SELECT id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
 				created_at, updated_at, deleted_at
 			FROM list_items
@@ -1742,6 +1972,11 @@ func (r *ListRepository) GetTribeLists(tribeID uuid.UUID) ([]*models.List, error
 				&item.Weight,
 				&item.LastChosen,
 				&item.ChosenCount,
+				&item.Cooldown,
+				&item.Available,
+				&item.Seasonal,
+				&item.StartDate,
+				&item.EndDate,
 				&item.Latitude,
 				&item.Longitude,
 				&item.Address,
@@ -2025,6 +2260,21 @@ func (r *ListRepository) ShareWithTribe(share *models.ListShare) error {
 					return fmt.Errorf("error adding tribe as list owner: %w", err)
 				}
 			}
+
+			details, detailsErr := listEventDetails(tx, share.ListID)
+			if detailsErr != nil {
+				return detailsErr
+			}
+			err = insertTribeEvent(tx, &models.TribeEvent{
+				TribeID:   share.TribeID,
+				Type:      models.EventListShared,
+				ActorID:   &share.UserID,
+				SubjectID: share.ListID,
+				Details:   details,
+			})
+			if err != nil {
+				return err
+			}
 		} else {
 			// Share already exists, update it
 			// Use CTE to get the updated row back
@@ -2082,8 +2332,8 @@ func (r *ListRepository) ShareWithTribe(share *models.ListShare) error {
 	return nil
 }
 
-// UnshareWithTribe removes a tribe's access to a list
-func (r *ListRepository) UnshareWithTribe(listID, tribeID uuid.UUID) error {
+// UnshareWithTribe removes a tribe's access to a list on behalf of the user
+func (r *ListRepository) UnshareWithTribe(listID, tribeID, userID uuid.UUID) error {
 	// Get test schema context if available
 	var ctx context.Context
 	if testSchema := testutil.GetCurrentTestSchema(); testSchema != "" {
@@ -2175,6 +2425,21 @@ func (r *ListRepository) UnshareWithTribe(listID, tribeID uuid.UUID) error {
 		fmt.Printf("[UnshareWithTribe] INFO: Successfully unshared list %s from tribe %s (new version: %d)\n",
 			listID, tribeID, newVersion)
 
+		details, err := listEventDetails(tx, listID)
+		if err != nil {
+			return err
+		}
+		err = insertTribeEvent(tx, &models.TribeEvent{
+			TribeID:   tribeID,
+			Type:      models.EventListUnshared,
+			ActorID:   &userID,
+			SubjectID: listID,
+			Details:   details,
+		})
+		if err != nil {
+			return err
+		}
+
 		// Remove the tribe as an owner if they are one
 		ownerQuery := `
 			UPDATE list_owners
@@ -2231,10 +2496,13 @@ func (r *ListRepository) GetListShares(listID uuid.UUID) ([]*models.ListShare, e
 
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
-			SELECT list_id, tribe_id, user_id, created_at, updated_at, expires_at, deleted_at, version
-			FROM list_sharing
-			WHERE list_id = $1 AND deleted_at IS NULL
-			ORDER BY created_at DESC`
+			SELECT ls.list_id, ls.tribe_id, ls.user_id, ls.created_at, ls.updated_at, ls.expires_at, ls.deleted_at, ls.version,
+				` + memberName + ` as shared_by_name
+			FROM list_sharing ls
+			LEFT JOIN tribe_members tm ON tm.tribe_id = ls.tribe_id AND tm.user_id = ls.user_id AND tm.deleted_at IS NULL
+			LEFT JOIN users u ON u.id = ls.user_id
+			WHERE ls.list_id = $1 AND ls.deleted_at IS NULL
+			ORDER BY ls.created_at DESC`
 
 		rows, err := tx.Query(query, listID)
 		if err != nil {
@@ -2253,6 +2521,7 @@ func (r *ListRepository) GetListShares(listID uuid.UUID) ([]*models.ListShare, e
 				&share.ExpiresAt,
 				&share.DeletedAt,
 				&share.Version,
+				&share.SharedByName,
 			)
 			if err != nil {
 				return fmt.Errorf("error scanning list share: %w", err)
@@ -2370,6 +2639,7 @@ func (r *ListRepository) GetSharedLists(tribeID uuid.UUID) ([]*models.List, erro
 			SELECT id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
 				created_at, updated_at, deleted_at
 			FROM list_items
@@ -2396,6 +2666,11 @@ func (r *ListRepository) GetSharedLists(tribeID uuid.UUID) ([]*models.List, erro
 				&item.Weight,
 				&item.LastChosen,
 				&item.ChosenCount,
+				&item.Cooldown,
+				&item.Available,
+				&item.Seasonal,
+				&item.StartDate,
+				&item.EndDate,
 				&item.Latitude,
 				&item.Longitude,
 				&item.Address,
@@ -2876,6 +3151,7 @@ func (r *ListRepository) GetListsByOwner(ownerID uuid.UUID, ownerType models.Own
 			SELECT id, list_id, name, description,
 				metadata, external_id,
 				weight, last_chosen, chosen_count,
+				cooldown, available, seasonal, start_date, end_date,
 				latitude, longitude, address,
 				created_at, updated_at, deleted_at
 			FROM list_items
@@ -2897,6 +3173,7 @@ func (r *ListRepository) GetListsByOwner(ownerID uuid.UUID, ownerType models.Own
 				&item.ID, &item.ListID, &item.Name, &item.Description,
 				&metadata, &item.ExternalID,
 				&item.Weight, &item.LastChosen, &item.ChosenCount,
+				&item.Cooldown, &item.Available, &item.Seasonal, &item.StartDate, &item.EndDate,
 				&item.Latitude, &item.Longitude, &item.Address,
 				&item.CreatedAt, &item.UpdatedAt, &item.DeletedAt,
 			); err != nil {
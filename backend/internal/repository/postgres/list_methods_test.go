@@ -64,7 +64,7 @@ func TestListRepository_List(t *testing.T) {
 				Available:   true,
 			}
 
-			err := repo.AddItem(item)
+			err := repo.AddItem(item, nil)
 			require.NoError(t, err)
 		}
 
@@ -228,7 +228,7 @@ func TestListRepository_ItemOperations(t *testing.T) {
 
 	// Add items to list
 	for _, item := range items {
-		err := repo.AddItem(item)
+		err := repo.AddItem(item, nil)
 		require.NoError(t, err)
 		assert.NotEqual(t, uuid.Nil, item.ID, "Item ID should be set after creation")
 	}
@@ -448,14 +448,12 @@ func TestListRepository_GetEligibleItems(t *testing.T) {
 	}
 
 	for _, item := range allItems {
-		err := repo.AddItem(item)
+		err := repo.AddItem(item, nil)
 		require.NoError(t, err)
 	}
 
 	t.Run("GetEligibleItems - All Available", func(t *testing.T) {
 		// Get all eligible items with minimal filters
-		// Note: The current implementation doesn't check for 'available_only'
-		// It simply returns all items from the specified lists
 		filters := map[string]interface{}{
 			"cooldown_days": 3, // Add cooldown days filter to exclude recently used items
 		}
@@ -472,9 +470,11 @@ func TestListRepository_GetEligibleItems(t *testing.T) {
 		// These should be present
 		assert.True(t, itemMap["Available Item 1"], "Available Item 1 should be eligible")
 		assert.True(t, itemMap["Available Item 2"], "Available Item 2 should be eligible")
-		assert.True(t, itemMap["Unavailable Item"], "Unavailable Item should be present (available flag not supported)")
 		assert.True(t, itemMap["In Season Item"], "In Season Item should be eligible")
-		assert.True(t, itemMap["Out of Season Item"], "Out of Season Item should be present (seasonality not supported)")
+
+		// These should be excluded as unavailable or out of season
+		assert.False(t, itemMap["Unavailable Item"], "Unavailable Item should not be eligible")
+		assert.False(t, itemMap["Out of Season Item"], "Out of Season Item should not be eligible")
 
 		// This should be excluded due to cooldown
 		assert.False(t, itemMap["Recently Used Item"], "Recently Used Item should not be eligible due to cooldown")
@@ -521,9 +521,9 @@ func TestListRepository_GetEligibleItems(t *testing.T) {
 		// Check items from first list
 		assert.True(t, itemMap["Available Item 1"], "Available Item 1 should be eligible")
 		assert.True(t, itemMap["Available Item 2"], "Available Item 2 should be eligible")
-		assert.True(t, itemMap["Unavailable Item"], "Unavailable Item should be present")
+		assert.False(t, itemMap["Unavailable Item"], "Unavailable Item should not be eligible")
 		assert.True(t, itemMap["In Season Item"], "In Season Item should be eligible")
-		assert.True(t, itemMap["Out of Season Item"], "Out of Season Item should be present")
+		assert.False(t, itemMap["Out of Season Item"], "Out of Season Item should not be eligible")
 		assert.False(t, itemMap["Recently Used Item"], "Recently Used Item should not be eligible due to cooldown")
 
 		// Check item from second list
@@ -540,6 +540,195 @@ func TestListRepository_GetEligibleItems(t *testing.T) {
 		require.NoError(t, err)
 		assert.Empty(t, items, "Should return empty result for empty list IDs")
 	})
+
+	t.Run("GetEligibleItems - Season Wrapping Year End", func(t *testing.T) {
+		winterList := &models.List{
+			Type:          models.ListTypeActivity,
+			Name:          "Winter List " + uuid.New().String()[:8],
+			Visibility:    models.VisibilityPrivate,
+			DefaultWeight: 1.0,
+			SyncStatus:    models.ListSyncStatusNone,
+			SyncSource:    models.SyncSourceNone,
+			OwnerID:       &testUser.ID,
+			OwnerType:     &ownerType,
+			Owners:        []*models.ListOwner{{OwnerID: testUser.ID, OwnerType: models.OwnerTypeUser}},
+		}
+		require.NoError(t, repo.Create(winterList))
+
+		// November through February, stored in a past year to show the window recurs
+		winterItem := &models.ListItem{
+			ListID:    winterList.ID,
+			Name:      "Ice Skating",
+			Weight:    1.0,
+			Available: true,
+			Seasonal:  true,
+			StartDate: timePtr(time.Date(2020, time.November, 1, 0, 0, 0, 0, time.UTC)),
+			EndDate:   timePtr(time.Date(2021, time.February, 28, 0, 0, 0, 0, time.UTC)),
+		}
+		require.NoError(t, repo.AddItem(winterItem, nil))
+
+		tests := []struct {
+			at       time.Time
+			eligible bool
+		}{
+			{time.Date(2030, time.December, 31, 12, 0, 0, 0, time.UTC), true},
+			{time.Date(2030, time.January, 15, 12, 0, 0, 0, time.UTC), true},
+			{time.Date(2030, time.November, 1, 12, 0, 0, 0, time.UTC), true},
+			{time.Date(2030, time.February, 28, 12, 0, 0, 0, time.UTC), true},
+			{time.Date(2030, time.July, 4, 12, 0, 0, 0, time.UTC), false},
+			{time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC), false},
+		}
+
+		for _, tt := range tests {
+			items, err := repo.GetEligibleItems([]uuid.UUID{winterList.ID}, map[string]interface{}{"at": tt.at})
+			require.NoError(t, err)
+			if tt.eligible {
+				assert.Len(t, items, 1, "item should be in season on %s", tt.at.Format("Jan 2"))
+			} else {
+				assert.Empty(t, items, "item should be out of season on %s", tt.at.Format("Jan 2"))
+			}
+		}
+	})
+
+	t.Run("GetEligibleItems - Item Cooldown Overrides List", func(t *testing.T) {
+		listCooldown := 30
+		cooldownList := &models.List{
+			Type:          models.ListTypeActivity,
+			Name:          "Cooldown List " + uuid.New().String()[:8],
+			Visibility:    models.VisibilityPrivate,
+			DefaultWeight: 1.0,
+			CooldownDays:  &listCooldown,
+			SyncStatus:    models.ListSyncStatusNone,
+			SyncSource:    models.SyncSourceNone,
+			OwnerID:       &testUser.ID,
+			OwnerType:     &ownerType,
+			Owners:        []*models.ListOwner{{OwnerID: testUser.ID, OwnerType: models.OwnerTypeUser}},
+		}
+		require.NoError(t, repo.Create(cooldownList))
+
+		tenDaysAgo := timePtr(time.Now().Add(-10 * 24 * time.Hour))
+		shortCooldown := &models.ListItem{
+			ListID:     cooldownList.ID,
+			Name:       "Short Cooldown",
+			Weight:     1.0,
+			Available:  true,
+			LastChosen: tenDaysAgo,
+			Cooldown:   intPtr(2),
+		}
+		listDefault := &models.ListItem{
+			ListID:     cooldownList.ID,
+			Name:       "List Cooldown",
+			Weight:     1.0,
+			Available:  true,
+			LastChosen: tenDaysAgo,
+		}
+		noCooldown := &models.ListItem{
+			ListID:     cooldownList.ID,
+			Name:       "No Cooldown",
+			Weight:     1.0,
+			Available:  true,
+			LastChosen: timePtr(time.Now()),
+			Cooldown:   intPtr(0),
+		}
+		for _, item := range []*models.ListItem{shortCooldown, listDefault, noCooldown} {
+			require.NoError(t, repo.AddItem(item, nil))
+		}
+
+		names := func(filters map[string]interface{}) map[string]bool {
+			items, err := repo.GetEligibleItems([]uuid.UUID{cooldownList.ID}, filters)
+			require.NoError(t, err)
+			found := make(map[string]bool)
+			for _, item := range items {
+				found[item.Name] = true
+			}
+			return found
+		}
+
+		// The list's 30 day cooldown applies only where the item has none
+		found := names(map[string]interface{}{})
+		assert.True(t, found["Short Cooldown"])
+		assert.False(t, found["List Cooldown"])
+		assert.True(t, found["No Cooldown"])
+
+		// A request-level cooldown replaces the list's but not the item's
+		found = names(map[string]interface{}{"cooldown_days": float64(5)})
+		assert.True(t, found["Short Cooldown"])
+		assert.True(t, found["List Cooldown"])
+		assert.True(t, found["No Cooldown"])
+	})
+
+	t.Run("GetEligibleItems - Radius From Origin", func(t *testing.T) {
+		placesList := &models.List{
+			Type:          models.ListTypeLocation,
+			Name:          "Places List " + uuid.New().String()[:8],
+			Visibility:    models.VisibilityPrivate,
+			DefaultWeight: 1.0,
+			SyncStatus:    models.ListSyncStatusNone,
+			SyncSource:    models.SyncSourceNone,
+			OwnerID:       &testUser.ID,
+			OwnerType:     &ownerType,
+			Owners:        []*models.ListOwner{{OwnerID: testUser.ID, OwnerType: models.OwnerTypeUser}},
+		}
+		require.NoError(t, repo.Create(placesList))
+
+		place := func(name string, lat, lng float64) *models.ListItem {
+			address := name + " Street"
+			return &models.ListItem{
+				ListID:    placesList.ID,
+				Name:      name,
+				Weight:    1.0,
+				Available: true,
+				Latitude:  &lat,
+				Longitude: &lng,
+				Address:   &address,
+			}
+		}
+		// Roughly 1 km, 3 km and 50 km north of the origin
+		for _, item := range []*models.ListItem{
+			place("Far", 40.45, -74.0),
+			place("Near", 40.009, -74.0),
+			place("Nearby", 40.027, -74.0),
+			{ListID: placesList.ID, Name: "Nowhere", Weight: 1.0, Available: true},
+		} {
+			require.NoError(t, repo.AddItem(item, nil))
+		}
+
+		origin := map[string]interface{}{
+			models.FilterOriginLatitude:  40.0,
+			models.FilterOriginLongitude: -74.0,
+		}
+		items, err := repo.GetEligibleItems([]uuid.UUID{placesList.ID}, origin)
+		require.NoError(t, err)
+		require.Len(t, items, 4)
+		assert.Equal(t, "Near", items[0].Name)
+		assert.Equal(t, "Nearby", items[1].Name)
+		assert.Equal(t, "Far", items[2].Name)
+		assert.Equal(t, "Nowhere", items[3].Name)
+		require.NotNil(t, items[0].DistanceKm)
+		assert.InDelta(t, 1.0, *items[0].DistanceKm, 0.05)
+		assert.Nil(t, items[3].DistanceKm)
+
+		origin[models.FilterRadiusKm] = 5.0
+		items, err = repo.GetEligibleItems([]uuid.UUID{placesList.ID}, origin)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "Near", items[0].Name)
+		assert.Equal(t, "Nearby", items[1].Name)
+
+		origin[models.FilterRadiusKm] = -1.0
+		_, err = repo.GetEligibleItems([]uuid.UUID{placesList.ID}, origin)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("GetEligibleItems - Max Items", func(t *testing.T) {
+		all, err := repo.GetEligibleItems([]uuid.UUID{list.ID, list2.ID}, nil)
+		require.NoError(t, err)
+		require.Greater(t, len(all), 2)
+
+		items, err := repo.GetEligibleItems([]uuid.UUID{list.ID, list2.ID}, map[string]interface{}{"max_items": 2})
+		require.NoError(t, err)
+		assert.Len(t, items, len(all), "menus cap their size after sampling, not in the query")
+	})
 }
 
 // Helper function to create time pointers
@@ -584,7 +773,7 @@ func TestSchemaPathOnDirectQueries(t *testing.T) {
 		Name:   "Test Item",
 		Weight: 1.0,
 	}
-	err = listRepo.AddItem(item)
+	err = listRepo.AddItem(item, nil)
 	require.NoError(t, err)
 
 	// Test List method (previously used direct query)
@@ -612,11 +801,12 @@ func TestSchemaPathOnDirectQueries(t *testing.T) {
 	// Test GetEligibleItems (previously used direct query)
 	// First add a new item since we removed the previous one
 	newItem := &models.ListItem{
-		ListID: list.ID,
-		Name:   "New Test Item",
-		Weight: 1.0,
+		ListID:    list.ID,
+		Name:      "New Test Item",
+		Weight:    1.0,
+		Available: true,
 	}
-	err = listRepo.AddItem(newItem)
+	err = listRepo.AddItem(newItem, nil)
 	require.NoError(t, err)
 
 	// Now test GetEligibleItems with the list ID
@@ -416,24 +416,66 @@ func TestListRepository_ResolveConflict(t *testing.T) {
 
 	t.Run("resolve conflict", func(t *testing.T) {
 		// Resolve the conflict
-		err = repo.ResolveConflict(conflict.ID)
+		err = repo.ResolveConflict(conflict.ID, models.ResolutionTakeRemote, nil)
 		require.NoError(t, err)
 
 		// Verify conflict is resolved
 		conflicts, err := repo.GetConflicts(list.ID)
 		require.NoError(t, err)
 		assert.Empty(t, conflicts, "Should have no active conflicts after resolution")
+
+		// The strategy is stored with the conflict
+		resolved, err := repo.GetConflict(conflict.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ResolutionTakeRemote, resolved.Resolution)
+		assert.NotNil(t, resolved.ResolvedAt)
 	})
 
 	t.Run("non-existent conflict", func(t *testing.T) {
-		err := repo.ResolveConflict(uuid.New())
+		err := repo.ResolveConflict(uuid.New(), models.ResolutionKeepLocal, nil)
 		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = repo.GetConflict(uuid.New())
+		assert.ErrorIs(t, err, models.ErrConflictNotFound)
 	})
 
 	t.Run("already resolved conflict", func(t *testing.T) {
 		// Try to resolve the already resolved conflict
-		err = repo.ResolveConflict(conflict.ID)
+		err = repo.ResolveConflict(conflict.ID, models.ResolutionKeepLocal, nil)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("item change is applied with the resolution", func(t *testing.T) {
+		item := &models.ListItem{ListID: list.ID, Name: "Local Name", Weight: 1}
+		require.NoError(t, repo.AddItem(item, nil))
+
+		itemConflict := &models.SyncConflict{
+			ID:         uuid.New(),
+			ListID:     list.ID,
+			ItemID:     &item.ID,
+			Type:       models.ConflictTypeItemUpdate,
+			LocalData:  localData,
+			RemoteData: remoteData,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		require.NoError(t, repo.CreateConflict(itemConflict))
+
+		item.Name = "Remote Name"
+		require.NoError(t, repo.ResolveConflict(itemConflict.ID, models.ResolutionTakeRemote, &models.ResolvedItem{Item: item}))
+
+		items, err := repo.GetItems(list.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "Remote Name", items[0].Name)
+
+		// A resolution that fails leaves the item untouched
+		item.Name = "Too Late"
+		err = repo.ResolveConflict(itemConflict.ID, models.ResolutionCustom, &models.ResolvedItem{Item: item})
 		assert.ErrorIs(t, err, models.ErrNotFound)
+		items, err = repo.GetItems(list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Remote Name", items[0].Name)
 	})
 }
 
@@ -132,7 +132,7 @@ func TestListRepository(t *testing.T) {
 			Weight:      1.0,
 			Metadata:    models.JSONMap{"test": "data"},
 		}
-		err = repo.AddItem(item)
+		err = repo.AddItem(item, nil)
 		require.NoError(t, err)
 
 		// Test retrieval
@@ -253,7 +253,7 @@ func TestListRepository(t *testing.T) {
 			Description: "Test Item Description",
 			Weight:      1.0,
 		}
-		err = repo.AddItem(item)
+		err = repo.AddItem(item, nil)
 		require.NoError(t, err)
 
 		// Delete list
@@ -397,7 +397,7 @@ func TestListRepository(t *testing.T) {
 			Address:     &addr,
 		}
 
-		err = repo.AddItem(item)
+		err = repo.AddItem(item, nil)
 		assert.NoError(t, err)
 		assert.NotEqual(t, uuid.Nil, item.ID)
 		assert.False(t, item.CreatedAt.IsZero())
@@ -452,7 +452,7 @@ func TestListRepository(t *testing.T) {
 			Weight:      1.0,
 			Metadata:    models.JSONMap{"test": "data"},
 		}
-		err = repo.AddItem(item)
+		err = repo.AddItem(item, nil)
 		require.NoError(t, err)
 
 		// Update item
@@ -527,7 +527,7 @@ func TestListRepository(t *testing.T) {
 			Description: "Test Item Description",
 			Weight:      1.0,
 		}
-		err = repo.AddItem(item)
+		err = repo.AddItem(item, nil)
 		require.NoError(t, err)
 
 		// Update stats
@@ -934,7 +934,7 @@ func TestListRepository_UnshareWithTribe(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		err := repo.UnshareWithTribe(listID, tribe.ID)
+		err := repo.UnshareWithTribe(listID, tribe.ID, user.ID)
 		require.NoError(t, err)
 
 		// Verify tribe was removed as owner
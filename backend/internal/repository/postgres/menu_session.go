@@ -0,0 +1,357 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+type MenuSessionRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+func NewMenuSessionRepository(db interface{}) models.MenuSessionRepository {
+	baseRepo := NewBaseRepository(db)
+	return &MenuSessionRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+const menuSessionColumns = `
+	id, user_id, tribe_id, params, items, chosen_item_id, chosen_by, chosen_at,
+	voting_rule, created_at, updated_at, version`
+
+// Create persists a new menu session
+func (r *MenuSessionRepository) Create(session *models.MenuSession) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if session.ID == uuid.Nil {
+			session.ID = uuid.New()
+		}
+
+		now := time.Now()
+		if session.CreatedAt.IsZero() {
+			session.CreatedAt = now
+		}
+		if session.UpdatedAt.IsZero() {
+			session.UpdatedAt = now
+		}
+
+		query := `
+			INSERT INTO menu_sessions (
+				id, user_id, tribe_id, params, items, voting_rule, created_at, updated_at, version
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING version
+		`
+
+		err := tx.QueryRow(query,
+			session.ID,
+			session.UserID,
+			session.TribeID,
+			session.Params,
+			session.Items,
+			session.VotingRule,
+			session.CreatedAt,
+			session.UpdatedAt,
+			1, // Initial version
+		).Scan(&session.Version)
+
+		if err != nil {
+			return fmt.Errorf("error creating menu session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves a menu session by ID
+func (r *MenuSessionRepository) GetByID(id uuid.UUID) (*models.MenuSession, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var session *models.MenuSession
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `SELECT` + menuSessionColumns + `
+			FROM menu_sessions
+			WHERE id = $1 AND deleted_at IS NULL`
+
+		var err error
+		session, err = scanMenuSession(tx.QueryRow(query, id))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting menu session: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// SetChosenItem records the item picked from a session and marks it chosen on
+// its list in the same transaction. A session can only be picked from once.
+func (r *MenuSessionRepository) SetChosenItem(sessionID, itemID, userID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			UPDATE menu_sessions
+			SET chosen_item_id = $1, chosen_by = $2, chosen_at = NOW()
+			WHERE id = $3 AND chosen_item_id IS NULL AND deleted_at IS NULL
+		`
+		result, err := tx.Exec(query, itemID, userID, sessionID)
+		if err != nil {
+			return fmt.Errorf("error setting chosen item: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rows > 0 {
+			if err := updateItemStats(tx, itemID, true); err != nil {
+				return fmt.Errorf("error marking item chosen: %w", err)
+			}
+			return recordMenuPick(tx, sessionID, itemID, userID)
+		}
+
+		// Distinguish a missing session from one that was already picked
+		var exists bool
+		err = tx.QueryRow(`
+			SELECT EXISTS(SELECT 1 FROM menu_sessions WHERE id = $1 AND deleted_at IS NULL)
+		`, sessionID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("error checking menu session: %w", err)
+		}
+		if !exists {
+			return models.ErrNotFound
+		}
+		return fmt.Errorf("%w: an item has already been picked for this menu", models.ErrConflict)
+	})
+}
+
+// recordMenuPick adds a pick from a tribe menu to the tribe's feed. Picks
+// from personal menus are not recorded.
+func recordMenuPick(tx *sql.Tx, sessionID, itemID, userID uuid.UUID) error {
+	var tribeID uuid.NullUUID
+	var itemName sql.NullString
+	err := tx.QueryRow(`
+		SELECT s.tribe_id, li.name
+		FROM menu_sessions s
+		LEFT JOIN list_items li ON li.id = $2
+		WHERE s.id = $1`, sessionID, itemID).Scan(&tribeID, &itemName)
+	if err != nil {
+		return fmt.Errorf("error getting menu session: %w", err)
+	}
+	if !tribeID.Valid {
+		return nil
+	}
+
+	return insertTribeEvent(tx, &models.TribeEvent{
+		TribeID:   tribeID.UUID,
+		Type:      models.EventMenuPicked,
+		ActorID:   &userID,
+		SubjectID: sessionID,
+		Details: models.JSONMap{
+			"item_id":   itemID.String(),
+			"item_name": itemName.String,
+		},
+	})
+}
+
+// GetUserSessions retrieves menu sessions created by a user, newest first
+func (r *MenuSessionRepository) GetUserSessions(userID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	query := `SELECT` + menuSessionColumns + `
+		FROM menu_sessions
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	return r.listSessions(query, userID, limit, offset)
+}
+
+// GetTribeSessions retrieves menu sessions generated for a tribe, newest first
+func (r *MenuSessionRepository) GetTribeSessions(tribeID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	query := `SELECT` + menuSessionColumns + `
+		FROM menu_sessions
+		WHERE tribe_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	return r.listSessions(query, tribeID, limit, offset)
+}
+
+func (r *MenuSessionRepository) listSessions(query string, args ...interface{}) ([]*models.MenuSession, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var sessions []*models.MenuSession
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("error listing menu sessions: %w", err)
+		}
+		defer safeClose(rows)
+
+		sessions = make([]*models.MenuSession, 0)
+		for rows.Next() {
+			session, err := scanMenuSession(rows)
+			if err != nil {
+				return fmt.Errorf("error scanning menu session: %w", err)
+			}
+			sessions = append(sessions, session)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// CastVotes records votes, replacing any earlier vote by the same member on
+// the same item
+func (r *MenuSessionRepository) CastVotes(votes []*models.MenuVote) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO menu_votes (session_id, user_id, item_id, kind, rank, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			ON CONFLICT (session_id, user_id, item_id)
+			DO UPDATE SET kind = EXCLUDED.kind, rank = EXCLUDED.rank, updated_at = NOW()
+			RETURNING created_at, updated_at
+		`
+		for _, vote := range votes {
+			err := tx.QueryRow(query,
+				vote.SessionID,
+				vote.UserID,
+				vote.ItemID,
+				vote.Kind,
+				vote.Rank,
+			).Scan(&vote.CreatedAt, &vote.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("error casting vote: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetVotes retrieves all votes cast on a session
+func (r *MenuSessionRepository) GetVotes(sessionID uuid.UUID) ([]*models.MenuVote, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var votes []*models.MenuVote
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT session_id, user_id, item_id, kind, rank, created_at, updated_at
+			FROM menu_votes
+			WHERE session_id = $1
+			ORDER BY created_at
+		`, sessionID)
+		if err != nil {
+			return fmt.Errorf("error getting votes: %w", err)
+		}
+		defer safeClose(rows)
+
+		votes = make([]*models.MenuVote, 0)
+		for rows.Next() {
+			vote := &models.MenuVote{}
+			var rank sql.NullInt64
+			if err := rows.Scan(
+				&vote.SessionID,
+				&vote.UserID,
+				&vote.ItemID,
+				&vote.Kind,
+				&rank,
+				&vote.CreatedAt,
+				&vote.UpdatedAt,
+			); err != nil {
+				return fmt.Errorf("error scanning vote: %w", err)
+			}
+			if rank.Valid {
+				rankValue := int(rank.Int64)
+				vote.Rank = &rankValue
+			}
+			votes = append(votes, vote)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMenuSession(row rowScanner) (*models.MenuSession, error) {
+	session := &models.MenuSession{}
+	var tribeID, chosenItemID, chosenBy uuid.NullUUID
+	var chosenAt sql.NullTime
+	var votingRule sql.NullString
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&tribeID,
+		&session.Params,
+		&session.Items,
+		&chosenItemID,
+		&chosenBy,
+		&chosenAt,
+		&votingRule,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+		&session.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if tribeID.Valid {
+		session.TribeID = &tribeID.UUID
+	}
+	if chosenItemID.Valid {
+		session.ChosenItemID = &chosenItemID.UUID
+	}
+	if chosenBy.Valid {
+		session.ChosenBy = &chosenBy.UUID
+	}
+	if chosenAt.Valid {
+		session.ChosenAt = &chosenAt.Time
+	}
+	if votingRule.Valid {
+		rule := models.VotingRule(votingRule.String)
+		session.VotingRule = &rule
+	}
+
+	return session, nil
+}
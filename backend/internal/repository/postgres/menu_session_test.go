@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMenuSessionRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewMenuSessionRepository(db)
+	listRepo := NewListRepository(db)
+	testUser := testutil.CreateTestUser(t, db)
+	testTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{testUser})
+	testList := testutil.CreateTestList(t, db, testTribe)
+
+	item := &models.ListItem{
+		ListID: testList.ID,
+		Name:   "Tacos",
+		Weight: 1.0,
+	}
+	require.NoError(t, listRepo.AddItem(item, nil))
+
+	seed := int64(11)
+	session := &models.MenuSession{
+		UserID:  testUser.ID,
+		TribeID: &testTribe.ID,
+		Params:  models.MenuParams{ListIDs: []uuid.UUID{testList.ID}, Count: 1, Seed: &seed},
+		Items: models.MenuSessionItems{
+			{ItemID: item.ID, ListID: testList.ID, Name: item.Name, Weight: item.Weight},
+		},
+	}
+
+	t.Run("Create and GetByID", func(t *testing.T) {
+		require.NoError(t, repo.Create(session))
+		assert.NotEqual(t, uuid.Nil, session.ID)
+
+		found, err := repo.GetByID(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, session.UserID, found.UserID)
+		assert.Equal(t, session.TribeID, found.TribeID)
+		assert.Equal(t, session.Items, found.Items)
+		require.NotNil(t, found.Params.Seed)
+		assert.Equal(t, seed, *found.Params.Seed)
+		assert.Nil(t, found.ChosenItemID)
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+		_, err := repo.GetByID(uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("SetChosenItem", func(t *testing.T) {
+		require.NoError(t, repo.SetChosenItem(session.ID, item.ID, testUser.ID))
+
+		found, err := repo.GetByID(session.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.ChosenItemID)
+		assert.Equal(t, item.ID, *found.ChosenItemID)
+		assert.Equal(t, testUser.ID, *found.ChosenBy)
+		assert.NotNil(t, found.ChosenAt)
+
+		items, err := listRepo.GetItems(testList.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, 1, items[0].ChosenCount, "the pick is counted on the list item")
+		assert.NotNil(t, items[0].LastChosen)
+
+		err = repo.SetChosenItem(session.ID, item.ID, testUser.ID)
+		assert.ErrorIs(t, err, models.ErrConflict)
+
+		err = repo.SetChosenItem(uuid.New(), item.ID, testUser.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("List sessions", func(t *testing.T) {
+		userSessions, err := repo.GetUserSessions(testUser.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, userSessions, 1)
+		assert.Equal(t, session.ID, userSessions[0].ID)
+
+		tribeSessions, err := repo.GetTribeSessions(testTribe.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, tribeSessions, 1)
+		assert.Equal(t, session.ID, tribeSessions[0].ID)
+	})
+
+	t.Run("Cast and replace votes", func(t *testing.T) {
+		rule := models.VotingRuleMajority
+		voteSession := &models.MenuSession{
+			UserID:     testUser.ID,
+			TribeID:    &testTribe.ID,
+			Params:     models.MenuParams{ListIDs: []uuid.UUID{testList.ID}},
+			Items:      models.MenuSessionItems{{ItemID: item.ID, ListID: testList.ID, Name: item.Name}},
+			VotingRule: &rule,
+		}
+		require.NoError(t, repo.Create(voteSession))
+
+		found, err := repo.GetByID(voteSession.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.VotingRule)
+		assert.Equal(t, rule, *found.VotingRule)
+
+		require.NoError(t, repo.CastVotes([]*models.MenuVote{
+			{SessionID: voteSession.ID, UserID: testUser.ID, ItemID: item.ID, Kind: models.VoteVeto},
+		}))
+
+		rank := 1
+		require.NoError(t, repo.CastVotes([]*models.MenuVote{
+			{SessionID: voteSession.ID, UserID: testUser.ID, ItemID: item.ID, Kind: models.VoteRank, Rank: &rank},
+		}))
+
+		votes, err := repo.GetVotes(voteSession.ID)
+		require.NoError(t, err)
+		require.Len(t, votes, 1)
+		assert.Equal(t, models.VoteRank, votes[0].Kind)
+		require.NotNil(t, votes[0].Rank)
+		assert.Equal(t, 1, *votes[0].Rank)
+	})
+}
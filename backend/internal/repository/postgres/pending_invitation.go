@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// pendingInvitationColumns lists the pending invitation columns in scan order
+const pendingInvitationColumns = `id, email, tribe_id, invited_by, created_at, expires_at, converted_at`
+
+// PendingInvitationRepository implements models.PendingInvitationRepository
+// using PostgreSQL
+type PendingInvitationRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewPendingInvitationRepository creates a new PostgreSQL pending invitation
+// repository
+func NewPendingInvitationRepository(db interface{}) models.PendingInvitationRepository {
+	baseRepo := NewBaseRepository(db)
+	return &PendingInvitationRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// scanPendingInvitation scans one pending invitation row
+func scanPendingInvitation(row interface{ Scan(...interface{}) error }) (*models.PendingInvitation, error) {
+	invitation := &models.PendingInvitation{}
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.Email,
+		&invitation.TribeID,
+		&invitation.InvitedBy,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+		&invitation.ConvertedAt,
+	)
+	return invitation, err
+}
+
+// Create stores a new invitation, replacing an expired one for the same
+// email and tribe
+func (r *PendingInvitationRepository) Create(invitation *models.PendingInvitation) error {
+	invitation.Email = models.NormalizeEmail(invitation.Email)
+	if err := invitation.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if invitation.ID == uuid.Nil {
+			invitation.ID = uuid.New()
+		}
+		invitation.CreatedAt = time.Now()
+		invitation.ConvertedAt = nil
+
+		_, err := tx.Exec(`
+			DELETE FROM pending_invitations
+			WHERE tribe_id = $1 AND email = $2 AND converted_at IS NULL AND expires_at <= $3`,
+			invitation.TribeID, invitation.Email, invitation.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error clearing expired invitation: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO pending_invitations (
+				id, email, tribe_id, invited_by, created_at, expires_at
+			) VALUES ($1, $2, $3, $4, $5, $6)`,
+			invitation.ID,
+			invitation.Email,
+			invitation.TribeID,
+			invitation.InvitedBy,
+			invitation.CreatedAt,
+			invitation.ExpiresAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case "23505":
+					return fmt.Errorf("%w: email already has an open invitation to this tribe", models.ErrDuplicate)
+				case "23503":
+					return fmt.Errorf("%w: tribe or user does not exist", models.ErrNotFound)
+				}
+			}
+			return fmt.Errorf("error creating pending invitation: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves an invitation, open or converted
+func (r *PendingInvitationRepository) GetByID(id uuid.UUID) (*models.PendingInvitation, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var invitation *models.PendingInvitation
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var err error
+		invitation, err = scanPendingInvitation(tx.QueryRow(`
+			SELECT `+pendingInvitationColumns+`
+			FROM pending_invitations
+			WHERE id = $1`, id))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting pending invitation: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// ListByTribe retrieves a tribe's open invitations, newest first
+func (r *PendingInvitationRepository) ListByTribe(tribeID uuid.UUID) ([]*models.PendingInvitation, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var invitations []*models.PendingInvitation
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT `+pendingInvitationColumns+`
+			FROM pending_invitations
+			WHERE tribe_id = $1 AND converted_at IS NULL
+			ORDER BY created_at DESC`, tribeID)
+		if err != nil {
+			return fmt.Errorf("error listing pending invitations: %w", err)
+		}
+		defer safeClose(rows)
+
+		invitations = make([]*models.PendingInvitation, 0)
+		for rows.Next() {
+			invitation, scanErr := scanPendingInvitation(rows)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning pending invitation: %w", scanErr)
+			}
+			invitations = append(invitations, invitation)
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// Delete removes one of a tribe's open invitations
+func (r *PendingInvitationRepository) Delete(tribeID, invitationID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			DELETE FROM pending_invitations
+			WHERE id = $1 AND tribe_id = $2 AND converted_at IS NULL`,
+			invitationID, tribeID)
+		if err != nil {
+			return fmt.Errorf("error deleting pending invitation: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// ConvertForUser turns the open invitations for an email into pending
+// memberships of the user. Invitations to tribes the user already belongs
+// to, or has left, are closed without adding a membership.
+func (r *PendingInvitationRepository) ConvertForUser(userID uuid.UUID, email string) ([]*models.PendingInvitation, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var converted []*models.PendingInvitation
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		now := time.Now()
+		rows, err := tx.Query(`
+			SELECT `+pendingInvitationColumns+`
+			FROM pending_invitations
+			WHERE email = $1 AND converted_at IS NULL AND expires_at > $2
+			ORDER BY created_at
+			FOR UPDATE`, models.NormalizeEmail(email), now)
+		if err != nil {
+			return fmt.Errorf("error getting pending invitations: %w", err)
+		}
+
+		var invitations []*models.PendingInvitation
+		for rows.Next() {
+			invitation, scanErr := scanPendingInvitation(rows)
+			if scanErr != nil {
+				safeClose(rows)
+				return fmt.Errorf("error scanning pending invitation: %w", scanErr)
+			}
+			invitations = append(invitations, invitation)
+		}
+		iterErr := rows.Err()
+		safeClose(rows)
+		if iterErr != nil {
+			return fmt.Errorf("error iterating pending invitations: %w", iterErr)
+		}
+
+		converted = make([]*models.PendingInvitation, 0, len(invitations))
+		for _, invitation := range invitations {
+			_, err = tx.Exec(`
+				INSERT INTO tribe_members (
+					tribe_id, user_id, membership_type, invited_by, invited_at, created_at, updated_at
+				)
+				SELECT t.id, u.id, 'pending', $3, $4, $5, $5
+				FROM tribes t, users u
+				WHERE t.id = $1 AND t.deleted_at IS NULL AND u.id = $2
+				ON CONFLICT (tribe_id, user_id) DO NOTHING`,
+				invitation.TribeID, userID, invitation.InvitedBy, invitation.CreatedAt, now)
+			if err != nil {
+				return fmt.Errorf("error adding invited member: %w", err)
+			}
+
+			_, err = tx.Exec(`
+				UPDATE pending_invitations
+				SET converted_at = $1
+				WHERE id = $2`,
+				now, invitation.ID)
+			if err != nil {
+				return fmt.Errorf("error closing pending invitation: %w", err)
+			}
+
+			invitation.ConvertedAt = &now
+			converted = append(converted, invitation)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingInvitationRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewPendingInvitationRepository(db.UnwrapDB())
+	tribes := NewTribeRepository(db.UnwrapDB())
+	inviter := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{inviter})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{inviter})
+	now := time.Now()
+
+	create := func(tribeID uuid.UUID, email string, expiresAt time.Time) *models.PendingInvitation {
+		invitation := &models.PendingInvitation{
+			Email:     email,
+			TribeID:   tribeID,
+			InvitedBy: inviter.ID,
+			ExpiresAt: expiresAt,
+		}
+		require.NoError(t, repo.Create(invitation))
+		return invitation
+	}
+	memberOf := func(tribeID, userID uuid.UUID) *models.TribeMember {
+		members, err := tribes.GetMembers(tribeID)
+		require.NoError(t, err)
+		for _, member := range members {
+			if member.UserID == userID {
+				return member
+			}
+		}
+		return nil
+	}
+
+	t.Run("create and list", func(t *testing.T) {
+		err := repo.Create(&models.PendingInvitation{Email: "x@example.com", TribeID: uuid.New(), InvitedBy: inviter.ID, ExpiresAt: now.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		first := create(tribe.ID, "First@Example.com", now.Add(time.Hour))
+		assert.Equal(t, "first@example.com", first.Email)
+		second := create(tribe.ID, "second@example.com", now.Add(time.Hour))
+
+		err = repo.Create(&models.PendingInvitation{Email: "FIRST@example.com", TribeID: tribe.ID, InvitedBy: inviter.ID, ExpiresAt: now.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrDuplicate)
+
+		invitations, err := repo.ListByTribe(tribe.ID)
+		require.NoError(t, err)
+		require.Len(t, invitations, 2)
+		assert.Equal(t, second.ID, invitations[0].ID)
+		assert.Equal(t, first.ID, invitations[1].ID)
+
+		require.NoError(t, repo.Delete(tribe.ID, first.ID))
+		require.NoError(t, repo.Delete(tribe.ID, second.ID))
+		assert.ErrorIs(t, repo.Delete(tribe.ID, second.ID), models.ErrNotFound)
+	})
+
+	t.Run("expired invitation can be replaced", func(t *testing.T) {
+		expired := create(tribe.ID, "late@example.com", now.Add(-time.Hour))
+		replacement := create(tribe.ID, "late@example.com", now.Add(time.Hour))
+
+		_, err := repo.GetByID(expired.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		require.NoError(t, repo.Delete(tribe.ID, replacement.ID))
+	})
+
+	t.Run("convert for new user", func(t *testing.T) {
+		user := testutil.CreateTestUser(t, db)
+		email := strings.ToUpper(user.Email)
+		first := create(tribe.ID, user.Email, now.Add(time.Hour))
+		second := create(otherTribe.ID, user.Email, now.Add(time.Hour))
+		expired := create(tribe.ID, "someone-else@example.com", now.Add(-time.Hour))
+
+		converted, err := repo.ConvertForUser(user.ID, email)
+		require.NoError(t, err)
+		require.Len(t, converted, 2)
+		assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, []uuid.UUID{converted[0].ID, converted[1].ID})
+
+		for _, tribeID := range []uuid.UUID{tribe.ID, otherTribe.ID} {
+			member := memberOf(tribeID, user.ID)
+			require.NotNil(t, member)
+			assert.Equal(t, models.MembershipPending, member.MembershipType)
+			require.NotNil(t, member.InvitedBy)
+			assert.Equal(t, inviter.ID, *member.InvitedBy)
+		}
+
+		stored, err := repo.GetByID(first.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.ConvertedAt)
+		assert.ErrorIs(t, repo.Delete(tribe.ID, first.ID), models.ErrNotFound)
+
+		converted, err = repo.ConvertForUser(user.ID, user.Email)
+		require.NoError(t, err)
+		assert.Empty(t, converted)
+
+		stored, err = repo.GetByID(expired.ID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.ConvertedAt)
+	})
+
+	t.Run("existing members keep their membership", func(t *testing.T) {
+		user := testutil.CreateTestUser(t, db)
+		require.NoError(t, tribes.AddMember(tribe.ID, user.ID, models.MembershipFull, nil, nil))
+		invitation := create(tribe.ID, user.Email, now.Add(time.Hour))
+
+		converted, err := repo.ConvertForUser(user.ID, user.Email)
+		require.NoError(t, err)
+		require.Len(t, converted, 1)
+		assert.Equal(t, invitation.ID, converted[0].ID)
+
+		member := memberOf(tribe.ID, user.ID)
+		require.NotNil(t, member)
+		assert.Equal(t, models.MembershipFull, member.MembershipType)
+	})
+}
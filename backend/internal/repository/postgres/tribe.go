@@ -10,6 +10,25 @@ import (
 	"github.com/jenglund/rlship-tools/internal/models"
 )
 
+// memberNameFallback is the name shown for someone with neither a tribe
+// display name nor an account name
+const memberNameFallback = `'Member'`
+
+// memberDisplayNameColumn resolves a member's name within a tribe: the
+// member's own display name when set, otherwise their account name. It
+// expects tribe_members to be aliased as tm.
+const memberDisplayNameColumn = `COALESCE(tm.display_name, (SELECT NULLIF(du.name, '') FROM users du WHERE du.id = tm.user_id), ` + memberNameFallback + `) as display_name`
+
+// memberName resolves a user's name within a tribe the same way for rows
+// that refer to the user, such as shares and events. It expects the user's
+// tribe_members row, which may be outer joined, as tm and their users row
+// as u.
+const memberName = `COALESCE(tm.display_name, NULLIF(u.name, ''), ` + memberNameFallback + `)`
+
+// memberProfileColumns resolves the rest of a member's per-tribe profile,
+// falling back to the account avatar. It expects tm and users as u.
+const memberProfileColumns = `COALESCE(tm.avatar_url, u.avatar_url, '') as member_avatar_url, COALESCE(tm.pronouns, '') as pronouns`
+
 // TribeRepository implements models.TribeRepository using PostgreSQL
 type TribeRepository struct {
 	BaseRepository
@@ -112,7 +131,9 @@ func (r *TribeRepository) GetByID(id uuid.UUID) (*models.Tribe, error) {
 				tm.tribe_id, 
 				tm.user_id, 
 				tm.membership_type, 
-				COALESCE(tm.display_name, 'Member') as display_name, 
+				tm.role,
+				` + memberDisplayNameColumn + `,
+				` + memberProfileColumns + `,
 				tm.expires_at, 
 				tm.invited_by,
 				tm.invited_at,
@@ -161,7 +182,10 @@ func (r *TribeRepository) GetByID(id uuid.UUID) (*models.Tribe, error) {
 				&member.TribeID,
 				&member.UserID,
 				&member.MembershipType,
+				&member.Role,
 				&member.DisplayName,
+				&member.AvatarURL,
+				&member.Pronouns,
 				&member.ExpiresAt,
 				&invitedBy,
 				&invitedAt,
@@ -409,8 +433,8 @@ func (r *TribeRepository) List(offset, limit int) ([]*models.Tribe, error) {
 		// For each tribe, get its members in a separate query to avoid the parse error
 		for i, tribe := range tribesList {
 			membersQuery := `
-				SELECT tm.id, tm.user_id, tm.tribe_id, COALESCE(tm.display_name, 'Member') as display_name, 
-					   tm.membership_type, tm.metadata, tm.expires_at,
+				SELECT tm.id, tm.user_id, tm.tribe_id, ` + memberDisplayNameColumn + `, 
+					   tm.membership_type, tm.role, tm.metadata, tm.expires_at,
 					   tm.created_at, tm.updated_at, tm.deleted_at, tm.version
 				FROM tribe_members tm
 				WHERE tm.tribe_id = $1
@@ -431,6 +455,7 @@ func (r *TribeRepository) List(offset, limit int) ([]*models.Tribe, error) {
 					&member.TribeID,
 					&member.DisplayName,
 					&member.MembershipType,
+					&member.Role,
 					&member.Metadata,
 					&member.ExpiresAt,
 					&member.CreatedAt,
@@ -517,19 +542,6 @@ func (r *TribeRepository) AddMember(tribeID, userID uuid.UUID, memberType models
 			return fmt.Errorf("user is already a member of this tribe")
 		}
 
-		// Get user's name for display_name
-		var displayName sql.NullString
-		err = tx.QueryRow("SELECT name FROM users WHERE id = $1", userID).Scan(&displayName)
-		if err != nil {
-			return fmt.Errorf("error getting user name: %w", err)
-		}
-
-		// Use displayName if valid, otherwise use a default name
-		finalDisplayName := "Member"
-		if displayName.Valid && displayName.String != "" {
-			finalDisplayName = displayName.String
-		}
-
 		now := time.Now()
 		id := uuid.New()
 
@@ -540,16 +552,15 @@ func (r *TribeRepository) AddMember(tribeID, userID uuid.UUID, memberType models
 			// Use the columns if they exist
 			query = `
 				INSERT INTO tribe_members (
-					id, tribe_id, user_id, membership_type, display_name,
+					id, tribe_id, user_id, membership_type,
 					expires_at, metadata, created_at, updated_at, invited_by, invited_at
-				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 			args = []interface{}{
 				id,
 				tribeID,
 				userID,
 				memberType,
-				finalDisplayName,
 				expiresAt,
 				models.JSONMap{},
 				now,
@@ -561,16 +572,15 @@ func (r *TribeRepository) AddMember(tribeID, userID uuid.UUID, memberType models
 			// Fall back to not using those columns if they don't exist
 			query = `
 				INSERT INTO tribe_members (
-					id, tribe_id, user_id, membership_type, display_name,
+					id, tribe_id, user_id, membership_type,
 					expires_at, metadata, created_at, updated_at
-				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
 			args = []interface{}{
 				id,
 				tribeID,
 				userID,
 				memberType,
-				finalDisplayName,
 				expiresAt,
 				models.JSONMap{},
 				now,
@@ -583,7 +593,7 @@ func (r *TribeRepository) AddMember(tribeID, userID uuid.UUID, memberType models
 			return fmt.Errorf("error adding tribe member: %w", err)
 		}
 
-		return nil
+		return recordMemberJoined(tx, tribeID, userID, memberType, invitedBy)
 	})
 }
 
@@ -595,7 +605,8 @@ func (r *TribeRepository) UpdateMember(tribeID, userID uuid.UUID, memberType mod
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		// First get the current version
 		var currentVersion int
-		err := tx.QueryRow("SELECT version FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NULL", tribeID, userID).Scan(&currentVersion)
+		var currentType models.MembershipType
+		err := tx.QueryRow("SELECT version, membership_type FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NULL", tribeID, userID).Scan(&currentVersion, &currentType)
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("tribe member not found")
 		}
@@ -623,24 +634,40 @@ func (r *TribeRepository) UpdateMember(tribeID, userID uuid.UUID, memberType mod
 			return fmt.Errorf("error updating tribe member: %w", err)
 		}
 
+		// Accepting an invitation is a join
+		if currentType == models.MembershipPending {
+			return recordMemberJoined(tx, tribeID, userID, memberType, &userID)
+		}
 		return nil
 	})
 }
 
-// RemoveMember removes a user from a tribe
-func (r *TribeRepository) RemoveMember(tribeID, userID uuid.UUID) error {
+// SetMemberRole changes an active member's role. The last owner cannot be
+// demoted.
+func (r *TribeRepository) SetMemberRole(tribeID, userID uuid.UUID, role models.TribeRole) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if role != models.TribeRoleOwner {
+			if err := ensureOtherOwner(tx, tribeID, userID); err != nil {
+				return err
+			}
+		}
+
 		query := `
 			UPDATE tribe_members
-			SET deleted_at = $1
-			WHERE tribe_id = $2 AND user_id = $3 AND deleted_at IS NULL`
+			SET role = $1,
+				updated_at = $2
+			WHERE tribe_id = $3 AND user_id = $4 AND deleted_at IS NULL`
 
-		result, err := tx.Exec(query, time.Now(), tribeID, userID)
+		result, err := tx.Exec(query, role, time.Now(), tribeID, userID)
 		if err != nil {
-			return fmt.Errorf("error removing tribe member: %w", err)
+			return fmt.Errorf("error setting tribe member role: %w", err)
 		}
 
 		rowsAffected, err := result.RowsAffected()
@@ -656,6 +683,121 @@ func (r *TribeRepository) RemoveMember(tribeID, userID uuid.UUID) error {
 	})
 }
 
+// UpdateMemberProfile sets a member's per-tribe profile. Fields left nil are
+// unchanged and empty ones are cleared.
+func (r *TribeRepository) UpdateMemberProfile(tribeID, userID uuid.UUID, profile *models.TribeMemberProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		query := `
+			UPDATE tribe_members
+			SET display_name = CASE WHEN $1::text IS NULL THEN display_name ELSE NULLIF($1, '') END,
+				avatar_url = CASE WHEN $2::text IS NULL THEN avatar_url ELSE NULLIF($2, '') END,
+				pronouns = CASE WHEN $3::text IS NULL THEN pronouns ELSE NULLIF($3, '') END,
+				updated_at = $4
+			WHERE tribe_id = $5 AND user_id = $6 AND deleted_at IS NULL`
+
+		result, err := tx.Exec(query, profile.DisplayName, profile.AvatarURL, profile.Pronouns, time.Now(), tribeID, userID)
+		if err != nil {
+			return fmt.Errorf("error updating tribe member profile: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("%w: tribe member not found", models.ErrNotFound)
+		}
+
+		return nil
+	})
+}
+
+// ensureOtherOwner returns ErrLastOwner if the user is the tribe's only
+// owner. It locks the tribe's owner rows so that two owners cannot step down
+// at the same time.
+func ensureOtherOwner(tx *sql.Tx, tribeID, userID uuid.UUID) error {
+	rows, err := tx.Query(`
+		SELECT user_id
+		FROM tribe_members
+		WHERE tribe_id = $1 AND role = 'owner' AND deleted_at IS NULL
+		FOR UPDATE`, tribeID)
+	if err != nil {
+		return fmt.Errorf("error checking tribe owners: %w", err)
+	}
+	defer safeClose(rows)
+
+	isOwner, others := false, 0
+	for rows.Next() {
+		var ownerID uuid.UUID
+		if scanErr := rows.Scan(&ownerID); scanErr != nil {
+			return fmt.Errorf("error scanning tribe owner: %w", scanErr)
+		}
+		if ownerID == userID {
+			isOwner = true
+		} else {
+			others++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating tribe owners: %w", err)
+	}
+
+	if isOwner && others == 0 {
+		return models.ErrLastOwner
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a tribe. The last owner cannot be removed.
+func (r *TribeRepository) RemoveMember(tribeID, userID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		return removeMember(tx, tribeID, userID)
+	})
+}
+
+// removeMember marks a membership deleted inside a transaction
+func removeMember(tx *sql.Tx, tribeID, userID uuid.UUID) error {
+	if err := ensureOtherOwner(tx, tribeID, userID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tribe_members
+		SET deleted_at = $1, role = 'member'
+		WHERE tribe_id = $2 AND user_id = $3 AND deleted_at IS NULL
+		RETURNING membership_type`
+
+	var memberType models.MembershipType
+	err := tx.QueryRow(query, time.Now(), tribeID, userID).Scan(&memberType)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("tribe member not found")
+	}
+	if err != nil {
+		return fmt.Errorf("error removing tribe member: %w", err)
+	}
+
+	// Declined and withdrawn invitations never joined
+	if memberType == models.MembershipPending {
+		return nil
+	}
+	return insertTribeEvent(tx, &models.TribeEvent{
+		TribeID:   tribeID,
+		Type:      models.EventMemberLeft,
+		SubjectID: userID,
+	})
+}
+
 // GetMembers retrieves all members of a tribe
 func (r *TribeRepository) GetMembers(tribeID uuid.UUID) ([]*models.TribeMember, error) {
 	ctx := context.Background()
@@ -681,7 +823,9 @@ func (r *TribeRepository) GetMembers(tribeID uuid.UUID) ([]*models.TribeMember,
 				tm.tribe_id, 
 				tm.user_id, 
 				tm.membership_type, 
-				COALESCE(tm.display_name, 'Member') as display_name, 
+				tm.role,
+				` + memberDisplayNameColumn + `,
+				` + memberProfileColumns + `,
 				tm.expires_at, 
 				tm.invited_by,
 				tm.invited_at,
@@ -730,7 +874,10 @@ func (r *TribeRepository) GetMembers(tribeID uuid.UUID) ([]*models.TribeMember,
 				&member.TribeID,
 				&member.UserID,
 				&member.MembershipType,
+				&member.Role,
 				&member.DisplayName,
+				&member.AvatarURL,
+				&member.Pronouns,
 				&member.ExpiresAt,
 				&invitedBy,
 				&invitedAt,
@@ -828,10 +975,10 @@ func (r *TribeRepository) GetExpiredGuestMemberships() ([]*models.TribeMember, e
 	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
 		query := `
 			SELECT 
-				id, tribe_id, user_id, membership_type,
-				display_name, expires_at, invited_by, invited_at, metadata,
+				id, tribe_id, user_id, membership_type, role,
+				` + memberDisplayNameColumn + `, expires_at, invited_by, invited_at, metadata,
 				created_at, updated_at, deleted_at, version
-			FROM tribe_members
+			FROM tribe_members tm
 			WHERE membership_type = 'guest'
 			AND expires_at < NOW()
 			AND deleted_at IS NULL`
@@ -852,6 +999,7 @@ func (r *TribeRepository) GetExpiredGuestMemberships() ([]*models.TribeMember, e
 				&member.TribeID,
 				&member.UserID,
 				&member.MembershipType,
+				&member.Role,
 				&member.DisplayName,
 				&member.ExpiresAt,
 				&member.InvitedBy,
@@ -949,8 +1097,9 @@ func (r *TribeRepository) GetUserTribes(userID uuid.UUID) ([]*models.Tribe, erro
 					tm.id, 
 					tm.user_id, 
 					tm.tribe_id, 
-					COALESCE(tm.display_name, 'Member') as display_name, 
+					` + memberDisplayNameColumn + `, 
 					tm.membership_type, 
+					tm.role,
 					tm.invited_by,
 					tm.invited_at,
 					tm.metadata, 
@@ -978,6 +1127,7 @@ func (r *TribeRepository) GetUserTribes(userID uuid.UUID) ([]*models.Tribe, erro
 					&member.TribeID,
 					&member.DisplayName,
 					&member.MembershipType,
+					&member.Role,
 					&member.InvitedBy,
 					&member.InvitedAt,
 					&member.Metadata,
@@ -1072,7 +1222,8 @@ func (r *TribeRepository) GetByType(tribeType models.TribeType, offset, limit in
 					tm.tribe_id, 
 					tm.user_id, 
 					tm.membership_type, 
-					COALESCE(tm.display_name, 'Member') as display_name, 
+					tm.role,
+					` + memberDisplayNameColumn + `, 
 					tm.expires_at, 
 					tm.invited_by,
 					tm.invited_at,
@@ -1104,6 +1255,7 @@ func (r *TribeRepository) GetByType(tribeType models.TribeType, offset, limit in
 					&member.TribeID,
 					&member.UserID,
 					&member.MembershipType,
+					&member.Role,
 					&member.DisplayName,
 					&member.ExpiresAt,
 					&invitedBy,
@@ -1212,7 +1364,8 @@ func (r *TribeRepository) Search(query string, offset, limit int) ([]*models.Tri
 					tm.tribe_id, 
 					tm.user_id, 
 					tm.membership_type, 
-					COALESCE(tm.display_name, 'Member') as display_name, 
+					tm.role,
+					` + memberDisplayNameColumn + `, 
 					tm.expires_at, 
 					tm.invited_by,
 					tm.invited_at,
@@ -1244,6 +1397,7 @@ func (r *TribeRepository) Search(query string, offset, limit int) ([]*models.Tri
 					&member.TribeID,
 					&member.UserID,
 					&member.MembershipType,
+					&member.Role,
 					&member.DisplayName,
 					&member.ExpiresAt,
 					&invitedBy,
@@ -1329,195 +1483,201 @@ func (r *TribeRepository) ReinviteMember(tribeID, userID uuid.UUID, memberType m
 	opts := DefaultTransactionOptions()
 
 	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
-		// Check if the columns exist first
-		var hasInvitedBy, hasInvitedAt bool
-		err := tx.QueryRow(`
-			SELECT 
-				COUNT(*) > 0 FROM information_schema.columns 
-				WHERE table_name = 'tribe_members' AND column_name = 'invited_by'
-		`).Scan(&hasInvitedBy)
-		if err != nil {
-			return fmt.Errorf("error checking for invited_by column: %w", err)
-		}
-
-		err = tx.QueryRow(`
-			SELECT 
-				COUNT(*) > 0 FROM information_schema.columns 
-				WHERE table_name = 'tribe_members' AND column_name = 'invited_at'
-		`).Scan(&hasInvitedAt)
-		if err != nil {
-			return fmt.Errorf("error checking for invited_at column: %w", err)
-		}
-
-		// Check if tribe exists
-		var tribeExists bool
-		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribes WHERE id = $1 AND deleted_at IS NULL)", tribeID).Scan(&tribeExists)
-		if err != nil {
-			return fmt.Errorf("error checking if tribe exists: %w", err)
-		}
-		if !tribeExists {
-			return fmt.Errorf("tribe not found")
-		}
-
-		// Check if user exists
-		var userExists bool
-		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&userExists)
-		if err != nil {
-			return fmt.Errorf("error checking if user exists: %w", err)
-		}
-		if !userExists {
-			return fmt.Errorf("user not found")
-		}
-
-		// Check if user is already an active member
-		var activeMemberExists bool
-		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NULL)", tribeID, userID).Scan(&activeMemberExists)
-		if err != nil {
-			return fmt.Errorf("error checking if active member exists: %w", err)
-		}
-		if activeMemberExists {
-			return fmt.Errorf("user is already a member of this tribe")
-		}
-
-		// Check if the user exists as a former member (with deleted_at)
-		var formerMemberExists bool
-		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NOT NULL)", tribeID, userID).Scan(&formerMemberExists)
-		if err != nil {
-			return fmt.Errorf("error checking if former member exists: %w", err)
-		}
-
-		now := time.Now()
-
-		if formerMemberExists {
-			// Reactivate the former member by updating the existing record
-			var updateQuery string
-			var args []interface{}
-
-			if hasInvitedBy && hasInvitedAt {
-				updateQuery = `
-					UPDATE tribe_members
-					SET deleted_at = NULL,
-						membership_type = $1,
-						expires_at = $2,
-						updated_at = $3,
-						invited_by = $4,
-						invited_at = $5
-					WHERE tribe_id = $6
-					AND user_id = $7
-					AND deleted_at IS NOT NULL
-				`
-				args = []interface{}{
-					memberType,
-					expiresAt,
-					now,
-					invitedBy,
-					now,
-					tribeID,
-					userID,
-				}
-			} else {
-				updateQuery = `
-					UPDATE tribe_members
-					SET deleted_at = NULL,
-						membership_type = $1,
-						expires_at = $2,
-						updated_at = $3
-					WHERE tribe_id = $4
-					AND user_id = $5
-					AND deleted_at IS NOT NULL
-				`
-				args = []interface{}{
-					memberType,
-					expiresAt,
-					now,
-					tribeID,
-					userID,
-				}
-			}
+		return reinviteMember(tx, tribeID, userID, memberType, expiresAt, invitedBy)
+	})
+}
 
-			result, err := tx.Exec(updateQuery, args...)
-			if err != nil {
-				return fmt.Errorf("error reactivating tribe member: %w", err)
-			}
+// reinviteMember re-adds a former member inside a transaction
+func reinviteMember(tx *sql.Tx, tribeID, userID uuid.UUID, memberType models.MembershipType, expiresAt *time.Time, invitedBy *uuid.UUID) error {
+	// Check if the columns exist first
+	var hasInvitedBy, hasInvitedAt bool
+	err := tx.QueryRow(`
+		SELECT 
+			COUNT(*) > 0 FROM information_schema.columns 
+			WHERE table_name = 'tribe_members' AND column_name = 'invited_by'
+	`).Scan(&hasInvitedBy)
+	if err != nil {
+		return fmt.Errorf("error checking for invited_by column: %w", err)
+	}
 
-			rowsAffected, err := result.RowsAffected()
-			if err != nil {
-				return fmt.Errorf("error checking rows affected: %w", err)
-			}
+	err = tx.QueryRow(`
+		SELECT 
+			COUNT(*) > 0 FROM information_schema.columns 
+			WHERE table_name = 'tribe_members' AND column_name = 'invited_at'
+	`).Scan(&hasInvitedAt)
+	if err != nil {
+		return fmt.Errorf("error checking for invited_at column: %w", err)
+	}
 
-			if rowsAffected == 0 {
-				return fmt.Errorf("no former member found to reactivate")
-			}
+	// Check if tribe exists
+	var tribeExists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribes WHERE id = $1 AND deleted_at IS NULL)", tribeID).Scan(&tribeExists)
+	if err != nil {
+		return fmt.Errorf("error checking if tribe exists: %w", err)
+	}
+	if !tribeExists {
+		return fmt.Errorf("tribe not found")
+	}
 
-			return nil
-		}
+	// Check if user exists
+	var userExists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&userExists)
+	if err != nil {
+		return fmt.Errorf("error checking if user exists: %w", err)
+	}
+	if !userExists {
+		return fmt.Errorf("user not found")
+	}
 
-		// Get user's name for display_name
-		var displayName sql.NullString
-		err = tx.QueryRow("SELECT name FROM users WHERE id = $1", userID).Scan(&displayName)
-		if err != nil {
-			return fmt.Errorf("error getting user name: %w", err)
-		}
+	// Check if user is already an active member
+	var activeMemberExists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NULL)", tribeID, userID).Scan(&activeMemberExists)
+	if err != nil {
+		return fmt.Errorf("error checking if active member exists: %w", err)
+	}
+	if activeMemberExists {
+		return fmt.Errorf("user is already a member of this tribe")
+	}
 
-		// Use displayName if valid, otherwise use a default name
-		finalDisplayName := "Member"
-		if displayName.Valid && displayName.String != "" {
-			finalDisplayName = displayName.String
-		}
+	// Check if the user exists as a former member (with deleted_at)
+	var formerMemberExists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribe_members WHERE tribe_id = $1 AND user_id = $2 AND deleted_at IS NOT NULL)", tribeID, userID).Scan(&formerMemberExists)
+	if err != nil {
+		return fmt.Errorf("error checking if former member exists: %w", err)
+	}
 
-		id := uuid.New()
+	now := time.Now()
 
-		var query string
+	if formerMemberExists {
+		// Reactivate the former member by updating the existing record
+		var updateQuery string
 		var args []interface{}
 
-		// If no former member exists, create a new record
 		if hasInvitedBy && hasInvitedAt {
-			// Use the columns if they exist
-			query = `
-				INSERT INTO tribe_members (
-					id, tribe_id, user_id, membership_type, display_name,
-					expires_at, metadata, created_at, updated_at, invited_by, invited_at
-				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-
+			updateQuery = `
+				UPDATE tribe_members
+				SET deleted_at = NULL,
+					membership_type = $1,
+					expires_at = $2,
+					updated_at = $3,
+					invited_by = $4,
+					invited_at = $5,
+					metadata = metadata - 'expired_guest'
+				WHERE tribe_id = $6
+				AND user_id = $7
+				AND deleted_at IS NOT NULL
+			`
 			args = []interface{}{
-				id,
-				tribeID,
-				userID,
 				memberType,
-				finalDisplayName,
 				expiresAt,
-				models.JSONMap{},
-				now,
 				now,
 				invitedBy,
 				now,
+				tribeID,
+				userID,
 			}
 		} else {
-			// Fall back to not using those columns if they don't exist
-			query = `
-				INSERT INTO tribe_members (
-					id, tribe_id, user_id, membership_type, display_name,
-					expires_at, metadata, created_at, updated_at
-				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-
+			updateQuery = `
+				UPDATE tribe_members
+				SET deleted_at = NULL,
+					membership_type = $1,
+					expires_at = $2,
+					updated_at = $3,
+					metadata = metadata - 'expired_guest'
+				WHERE tribe_id = $4
+				AND user_id = $5
+				AND deleted_at IS NOT NULL
+			`
 			args = []interface{}{
-				id,
-				tribeID,
-				userID,
 				memberType,
-				finalDisplayName,
 				expiresAt,
-				models.JSONMap{},
-				now,
 				now,
+				tribeID,
+				userID,
 			}
 		}
 
-		_, err = tx.Exec(query, args...)
+		result, err := tx.Exec(updateQuery, args...)
 		if err != nil {
-			return fmt.Errorf("error adding tribe member: %w", err)
+			return fmt.Errorf("error reactivating tribe member: %w", err)
 		}
 
-		return nil
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("no former member found to reactivate")
+		}
+
+		return recordMemberJoined(tx, tribeID, userID, memberType, invitedBy)
+	}
+
+	id := uuid.New()
+
+	var query string
+	var args []interface{}
+
+	// If no former member exists, create a new record
+	if hasInvitedBy && hasInvitedAt {
+		// Use the columns if they exist
+		query = `
+			INSERT INTO tribe_members (
+				id, tribe_id, user_id, membership_type,
+				expires_at, metadata, created_at, updated_at, invited_by, invited_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+		args = []interface{}{
+			id,
+			tribeID,
+			userID,
+			memberType,
+			expiresAt,
+			models.JSONMap{},
+			now,
+			now,
+			invitedBy,
+			now,
+		}
+	} else {
+		// Fall back to not using those columns if they don't exist
+		query = `
+			INSERT INTO tribe_members (
+				id, tribe_id, user_id, membership_type,
+				expires_at, metadata, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+		args = []interface{}{
+			id,
+			tribeID,
+			userID,
+			memberType,
+			expiresAt,
+			models.JSONMap{},
+			now,
+			now,
+		}
+	}
+
+	_, err = tx.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("error adding tribe member: %w", err)
+	}
+
+	return recordMemberJoined(tx, tribeID, userID, memberType, invitedBy)
+}
+
+// ReinviteExpiredGuest removes a guest whose access expired and invites
+// them again from scratch in a single transaction
+func (r *TribeRepository) ReinviteExpiredGuest(tribeID, userID uuid.UUID, invitedBy *uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if err := removeMember(tx, tribeID, userID); err != nil {
+			return err
+		}
+		return reinviteMember(tx, tribeID, userID, models.MembershipPending, nil, invitedBy)
 	})
 }
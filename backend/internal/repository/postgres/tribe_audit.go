@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// tribeAuditColumns lists the audit entry columns in scan order
+const tribeAuditColumns = `id, tribe_id, user_id, actor_id, action, details, created_at`
+
+// TribeAuditRepository implements models.TribeAuditRepository using
+// PostgreSQL
+type TribeAuditRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewTribeAuditRepository creates a new PostgreSQL tribe audit repository
+func NewTribeAuditRepository(db interface{}) models.TribeAuditRepository {
+	baseRepo := NewBaseRepository(db)
+	return &TribeAuditRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// scanTribeAuditEntry scans one audit entry row
+func scanTribeAuditEntry(row interface{ Scan(...interface{}) error }) (*models.TribeAuditEntry, error) {
+	entry := &models.TribeAuditEntry{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.TribeID,
+		&entry.UserID,
+		&entry.ActorID,
+		&entry.Action,
+		&entry.Details,
+		&entry.CreatedAt,
+	)
+	return entry, err
+}
+
+// insertTribeAuditEntry writes an audit entry inside an existing transaction
+func insertTribeAuditEntry(tx *sql.Tx, entry *models.TribeAuditEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.Details == nil {
+		entry.Details = models.JSONMap{}
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO tribe_audit_entries (
+			id, tribe_id, user_id, actor_id, action, details, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		entry.ID,
+		entry.TribeID,
+		entry.UserID,
+		entry.ActorID,
+		entry.Action,
+		entry.Details,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%w: tribe or user does not exist", models.ErrNotFound)
+		}
+		return fmt.Errorf("error creating audit entry: %w", err)
+	}
+	return nil
+}
+
+// Create records an audit entry
+func (r *TribeAuditRepository) Create(entry *models.TribeAuditEntry) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		return insertTribeAuditEntry(tx, entry)
+	})
+}
+
+// ListByTribe retrieves a tribe's most recent audit entries, newest first
+func (r *TribeAuditRepository) ListByTribe(tribeID uuid.UUID, limit int) ([]*models.TribeAuditEntry, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var entries []*models.TribeAuditEntry
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT `+tribeAuditColumns+`
+			FROM tribe_audit_entries
+			WHERE tribe_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2`, tribeID, limit)
+		if err != nil {
+			return fmt.Errorf("error listing audit entries: %w", err)
+		}
+		defer safeClose(rows)
+
+		entries = make([]*models.TribeAuditEntry, 0)
+		for rows.Next() {
+			entry, scanErr := scanTribeAuditEntry(rows)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning audit entry: %w", scanErr)
+			}
+			entries = append(entries, entry)
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// TribeEventRepository implements models.TribeEventRepository using
+// PostgreSQL
+type TribeEventRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewTribeEventRepository creates a new PostgreSQL tribe event repository
+func NewTribeEventRepository(db interface{}) models.TribeEventRepository {
+	baseRepo := NewBaseRepository(db)
+	return &TribeEventRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// insertTribeEvent writes a feed event inside an existing transaction, so
+// that it is only recorded if the change it describes is
+func insertTribeEvent(tx *sql.Tx, event *models.TribeEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Details == nil {
+		event.Details = models.JSONMap{}
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO tribe_events (
+			id, tribe_id, event_type, actor_id, subject_id, details, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.ID,
+		event.TribeID,
+		event.Type,
+		event.ActorID,
+		event.SubjectID,
+		event.Details,
+		event.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%w: tribe or actor does not exist", models.ErrNotFound)
+		}
+		return fmt.Errorf("error creating tribe event: %w", err)
+	}
+	return nil
+}
+
+// insertTribeEvents writes a copy of the event to each tribe's feed
+func insertTribeEvents(tx *sql.Tx, tribeIDs []uuid.UUID, event models.TribeEvent) error {
+	for _, tribeID := range tribeIDs {
+		tribeEvent := event
+		tribeEvent.TribeID = tribeID
+		if err := insertTribeEvent(tx, &tribeEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordMemberJoined adds a join to the tribe's feed. Pending invitations
+// are not joins; they are recorded when accepted.
+func recordMemberJoined(tx *sql.Tx, tribeID, userID uuid.UUID, memberType models.MembershipType, actorID *uuid.UUID) error {
+	if memberType == models.MembershipPending {
+		return nil
+	}
+	return insertTribeEvent(tx, &models.TribeEvent{
+		TribeID:   tribeID,
+		Type:      models.EventMemberJoined,
+		ActorID:   actorID,
+		SubjectID: userID,
+		Details:   models.JSONMap{"membership_type": string(memberType)},
+	})
+}
+
+// queryTribeIDs collects the tribe IDs returned by a query
+func queryTribeIDs(tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(rows)
+
+	var tribeIDs []uuid.UUID
+	for rows.Next() {
+		var tribeID uuid.UUID
+		if scanErr := rows.Scan(&tribeID); scanErr != nil {
+			return nil, scanErr
+		}
+		tribeIDs = append(tribeIDs, tribeID)
+	}
+	return tribeIDs, rows.Err()
+}
+
+// listTribeIDs returns the tribes that can currently see a list: those that
+// own it and those it is shared with
+func listTribeIDs(tx *sql.Tx, listID uuid.UUID) ([]uuid.UUID, error) {
+	tribeIDs, err := queryTribeIDs(tx, `
+		SELECT owner_id FROM list_owners
+		WHERE list_id = $1 AND owner_type = 'tribe' AND deleted_at IS NULL
+		UNION
+		SELECT tribe_id FROM list_sharing
+		WHERE list_id = $1 AND deleted_at IS NULL
+		AND (expires_at IS NULL OR expires_at > NOW())`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting list tribes: %w", err)
+	}
+	return tribeIDs, nil
+}
+
+// activityTribeIDs returns the tribes that can currently see an activity:
+// those that own it and those it is shared with
+func activityTribeIDs(tx *sql.Tx, activityID uuid.UUID) ([]uuid.UUID, error) {
+	tribeIDs, err := queryTribeIDs(tx, `
+		SELECT owner_id FROM activity_owners
+		WHERE activity_id = $1 AND owner_type = 'tribe' AND deleted_at IS NULL
+		UNION
+		SELECT tribe_id FROM activity_shares
+		WHERE activity_id = $1 AND deleted_at IS NULL
+		AND (expires_at IS NULL OR expires_at > NOW())`, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting activity tribes: %w", err)
+	}
+	return tribeIDs, nil
+}
+
+// listEventDetails describes a list for its feed events
+func listEventDetails(tx *sql.Tx, listID uuid.UUID) (models.JSONMap, error) {
+	var name string
+	if err := tx.QueryRow(`SELECT name FROM lists WHERE id = $1`, listID).Scan(&name); err != nil {
+		return nil, fmt.Errorf("error getting list name: %w", err)
+	}
+	return models.JSONMap{"list_name": name}, nil
+}
+
+// Create records a feed event
+func (r *TribeEventRepository) Create(event *models.TribeEvent) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		return insertTribeEvent(tx, event)
+	})
+}
+
+// ListByTribe retrieves a page of a tribe's feed, newest first
+func (r *TribeEventRepository) ListByTribe(tribeID uuid.UUID, before *models.TribeEventCursor, limit int) ([]*models.TribeEvent, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var events []*models.TribeEvent
+
+	var beforeAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeAt, beforeID = &before.CreatedAt, &before.ID
+	}
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT e.id, e.tribe_id, e.event_type, e.actor_id, e.subject_id, e.details, e.created_at,
+				CASE WHEN e.actor_id IS NULL THEN '' ELSE `+memberName+` END as actor_name
+			FROM tribe_events e
+			LEFT JOIN tribe_members tm ON tm.tribe_id = e.tribe_id AND tm.user_id = e.actor_id AND tm.deleted_at IS NULL
+			LEFT JOIN users u ON u.id = e.actor_id
+			WHERE e.tribe_id = $1
+			AND ($2::timestamptz IS NULL OR (e.created_at, e.id) < ($2, $3::uuid))
+			ORDER BY e.created_at DESC, e.id DESC
+			LIMIT $4`, tribeID, beforeAt, beforeID, limit)
+		if err != nil {
+			return fmt.Errorf("error listing tribe events: %w", err)
+		}
+		defer safeClose(rows)
+
+		events = make([]*models.TribeEvent, 0)
+		for rows.Next() {
+			event := &models.TribeEvent{}
+			scanErr := rows.Scan(
+				&event.ID,
+				&event.TribeID,
+				&event.Type,
+				&event.ActorID,
+				&event.SubjectID,
+				&event.Details,
+				&event.CreatedAt,
+				&event.ActorName,
+			)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning tribe event: %w", scanErr)
+			}
+			events = append(events, event)
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeEventRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewTribeEventRepository(db.UnwrapDB())
+	tribes := NewTribeRepository(db.UnwrapDB())
+	lists := NewListRepository(db.UnwrapDB())
+	owner := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{owner})
+	otherTribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{owner})
+
+	types := func(events []*models.TribeEvent) []models.TribeEventType {
+		var eventTypes []models.TribeEventType
+		for _, event := range events {
+			eventTypes = append(eventTypes, event.Type)
+		}
+		return eventTypes
+	}
+
+	t.Run("membership changes", func(t *testing.T) {
+		joiner := testutil.CreateTestUser(t, db)
+		invitee := testutil.CreateTestUser(t, db)
+
+		require.NoError(t, tribes.AddMember(tribe.ID, joiner.ID, models.MembershipFull, nil, &owner.ID))
+		require.NoError(t, tribes.AddMember(tribe.ID, invitee.ID, models.MembershipPending, nil, &owner.ID))
+		require.NoError(t, tribes.UpdateMember(tribe.ID, invitee.ID, models.MembershipFull, nil))
+		require.NoError(t, tribes.RemoveMember(tribe.ID, joiner.ID))
+
+		events, err := repo.ListByTribe(tribe.ID, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TribeEventType{models.EventMemberLeft, models.EventMemberJoined, models.EventMemberJoined}, types(events))
+
+		assert.Equal(t, joiner.ID, events[0].SubjectID)
+		assert.Nil(t, events[0].ActorID)
+		assert.Equal(t, invitee.ID, events[1].SubjectID)
+		assert.Equal(t, invitee.Name, events[1].ActorName, "accepting an invitation is done by the invitee")
+		assert.Equal(t, joiner.ID, events[2].SubjectID)
+		assert.Equal(t, owner.ID, *events[2].ActorID)
+
+		declined := testutil.CreateTestUser(t, db)
+		require.NoError(t, tribes.AddMember(tribe.ID, declined.ID, models.MembershipPending, nil, &owner.ID))
+		require.NoError(t, tribes.RemoveMember(tribe.ID, declined.ID))
+		after, err := repo.ListByTribe(tribe.ID, nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, after, len(events), "invitations that are never accepted are not in the feed")
+	})
+
+	t.Run("list changes", func(t *testing.T) {
+		list := testutil.CreateTestList(t, db, tribe)
+		item := &models.ListItem{ListID: list.ID, Name: "Tacos", Weight: 1}
+		require.NoError(t, lists.AddItem(item, &owner.ID))
+
+		events, err := repo.ListByTribe(tribe.ID, nil, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.EventListItemAdded, events[0].Type)
+		assert.Equal(t, item.ID, events[0].SubjectID)
+		assert.Equal(t, owner.ID, *events[0].ActorID)
+		assert.Equal(t, "Tacos", events[0].Details["item_name"])
+		assert.Equal(t, list.Name, events[0].Details["list_name"])
+
+		require.NoError(t, lists.ShareWithTribe(&models.ListShare{ListID: list.ID, TribeID: otherTribe.ID, UserID: owner.ID}))
+		require.NoError(t, lists.UnshareWithTribe(list.ID, otherTribe.ID, owner.ID))
+
+		events, err = repo.ListByTribe(otherTribe.ID, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []models.TribeEventType{models.EventListUnshared, models.EventListShared}, types(events))
+		assert.Equal(t, owner.ID, *events[0].ActorID)
+		assert.Equal(t, list.ID, events[1].SubjectID)
+		assert.Equal(t, owner.ID, *events[1].ActorID)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		all, err := repo.ListByTribe(tribe.ID, nil, 100)
+		require.NoError(t, err)
+		require.Len(t, all, 4)
+
+		var paged []uuid.UUID
+		var cursor *models.TribeEventCursor
+		for {
+			page, pageErr := repo.ListByTribe(tribe.ID, cursor, 3)
+			require.NoError(t, pageErr)
+			for _, event := range page {
+				paged = append(paged, event.ID)
+			}
+			if len(page) < 3 {
+				break
+			}
+			cursor = page[len(page)-1].CursorAfter()
+		}
+
+		var ids []uuid.UUID
+		for _, event := range all {
+			ids = append(ids, event.ID)
+		}
+		assert.Equal(t, ids, paged)
+	})
+
+	t.Run("sync changes", func(t *testing.T) {
+		list := testutil.CreateTestList(t, db, otherTribe)
+		before, err := repo.ListByTribe(otherTribe.ID, nil, 100)
+		require.NoError(t, err)
+
+		for _, name := range []string{"Tacos", "Pho"} {
+			item := &models.ListItem{ListID: list.ID, Name: name, Weight: 1, ExternalID: "remote-" + name}
+			require.NoError(t, lists.AddItem(item, nil))
+		}
+		require.NoError(t, lists.RecordSync(list.ID, 2, 0, 1))
+
+		events, err := repo.ListByTribe(otherTribe.ID, nil, 100)
+		require.NoError(t, err)
+		require.Len(t, events, len(before)+1, "synced items are summarized rather than listed")
+		assert.Equal(t, models.EventListSynced, events[0].Type)
+		assert.Equal(t, list.ID, events[0].SubjectID)
+		assert.Nil(t, events[0].ActorID)
+		assert.EqualValues(t, 2, events[0].Details["created"])
+		assert.EqualValues(t, 1, events[0].Details["deleted"])
+	})
+
+	t.Run("events need a subject", func(t *testing.T) {
+		err := repo.Create(&models.TribeEvent{TribeID: tribe.ID, Type: models.EventMenuPicked})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,280 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// inviteColumns lists the tribe invite columns in scan order
+const inviteColumns = `id, tribe_id, created_by, membership_type, single_use, use_count, expires_at, created_at, revoked_at`
+
+// TribeInviteRepository implements models.TribeInviteRepository using
+// PostgreSQL
+type TribeInviteRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewTribeInviteRepository creates a new PostgreSQL tribe invite repository
+func NewTribeInviteRepository(db interface{}) models.TribeInviteRepository {
+	baseRepo := NewBaseRepository(db)
+	return &TribeInviteRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// scanInvite scans one tribe invite row
+func scanInvite(row interface{ Scan(...interface{}) error }) (*models.TribeInvite, error) {
+	invite := &models.TribeInvite{}
+	err := row.Scan(
+		&invite.ID,
+		&invite.TribeID,
+		&invite.CreatedBy,
+		&invite.MembershipType,
+		&invite.SingleUse,
+		&invite.UseCount,
+		&invite.ExpiresAt,
+		&invite.CreatedAt,
+		&invite.RevokedAt,
+	)
+	return invite, err
+}
+
+// Create stores a new tribe invite
+func (r *TribeInviteRepository) Create(invite *models.TribeInvite) error {
+	if err := invite.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if invite.ID == uuid.Nil {
+			invite.ID = uuid.New()
+		}
+		invite.CreatedAt = time.Now()
+		invite.UseCount = 0
+		invite.RevokedAt = nil
+
+		_, err := tx.Exec(`
+			INSERT INTO tribe_invites (
+				id, tribe_id, created_by, membership_type, single_use, expires_at, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			invite.ID,
+			invite.TribeID,
+			invite.CreatedBy,
+			invite.MembershipType,
+			invite.SingleUse,
+			invite.ExpiresAt,
+			invite.CreatedAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return fmt.Errorf("%w: tribe or user does not exist", models.ErrNotFound)
+			}
+			return fmt.Errorf("error creating tribe invite: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves a tribe invite, usable or not
+func (r *TribeInviteRepository) GetByID(id uuid.UUID) (*models.TribeInvite, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var invite *models.TribeInvite
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var err error
+		invite, err = scanInvite(tx.QueryRow(`
+			SELECT `+inviteColumns+`
+			FROM tribe_invites
+			WHERE id = $1`, id))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting tribe invite: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
+
+// ListByTribe retrieves a tribe's invites, newest first
+func (r *TribeInviteRepository) ListByTribe(tribeID uuid.UUID) ([]*models.TribeInvite, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var invites []*models.TribeInvite
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT `+inviteColumns+`
+			FROM tribe_invites
+			WHERE tribe_id = $1
+			ORDER BY created_at DESC`, tribeID)
+		if err != nil {
+			return fmt.Errorf("error listing tribe invites: %w", err)
+		}
+		defer safeClose(rows)
+
+		invites = make([]*models.TribeInvite, 0)
+		for rows.Next() {
+			invite, scanErr := scanInvite(rows)
+			if scanErr != nil {
+				return fmt.Errorf("error scanning tribe invite: %w", scanErr)
+			}
+			invites = append(invites, invite)
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// Revoke disables one of a tribe's invites
+func (r *TribeInviteRepository) Revoke(tribeID, inviteID uuid.UUID) error {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE tribe_invites
+			SET revoked_at = COALESCE(revoked_at, $1)
+			WHERE id = $2 AND tribe_id = $3`,
+			time.Now(), inviteID, tribeID)
+		if err != nil {
+			return fmt.Errorf("error revoking tribe invite: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// Redeem adds the user to the invite's tribe, recording the invite's creator
+// as the inviter, and counts the use
+func (r *TribeInviteRepository) Redeem(inviteID, userID uuid.UUID) (*models.TribeInvite, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var invite *models.TribeInvite
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var err error
+		invite, err = scanInvite(tx.QueryRow(`
+			SELECT `+inviteColumns+`
+			FROM tribe_invites
+			WHERE id = $1
+			FOR UPDATE`, inviteID))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting tribe invite: %w", err)
+		}
+
+		now := time.Now()
+		if !invite.IsUsable(now) {
+			return models.ErrInviteUnavailable
+		}
+
+		var tribeExists bool
+		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM tribes WHERE id = $1 AND deleted_at IS NULL)", invite.TribeID).Scan(&tribeExists)
+		if err != nil {
+			return fmt.Errorf("error checking if tribe exists: %w", err)
+		}
+		if !tribeExists {
+			return models.ErrInviteUnavailable
+		}
+
+		// Invites stop working once their creator leaves the tribe or is no
+		// longer a full member
+		var creatorActive bool
+		err = tx.QueryRow(`
+			SELECT EXISTS(
+				SELECT 1 FROM tribe_members
+				WHERE tribe_id = $1 AND user_id = $2 AND membership_type = 'full'
+				AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > $3)
+			)`, invite.TribeID, invite.CreatedBy, now).Scan(&creatorActive)
+		if err != nil {
+			return fmt.Errorf("error checking invite creator: %w", err)
+		}
+		if !creatorActive {
+			return models.ErrInviteUnavailable
+		}
+
+		var deletedAt sql.NullTime
+		err = tx.QueryRow("SELECT deleted_at FROM tribe_members WHERE tribe_id = $1 AND user_id = $2", invite.TribeID, userID).Scan(&deletedAt)
+		switch {
+		case err == nil && deletedAt.Valid:
+			return fmt.Errorf("%w: former members must be reinvited directly", models.ErrForbidden)
+		case err == nil:
+			return fmt.Errorf("%w: already a member of this tribe", models.ErrDuplicate)
+		case err != sql.ErrNoRows:
+			return fmt.Errorf("error checking tribe membership: %w", err)
+		}
+
+		result, err := tx.Exec(`
+			INSERT INTO tribe_members (
+				tribe_id, user_id, membership_type, invited_by, invited_at, created_at, updated_at
+			)
+			SELECT $1, id, $2, $3, $4, $4, $4
+			FROM users
+			WHERE id = $5`,
+			invite.TribeID, invite.MembershipType, invite.CreatedBy, now, userID)
+		if err != nil {
+			return fmt.Errorf("error adding tribe member: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("%w: user does not exist", models.ErrNotFound)
+		}
+
+		err = tx.QueryRow(`
+			UPDATE tribe_invites
+			SET use_count = use_count + 1
+			WHERE id = $1
+			RETURNING use_count`, invite.ID).Scan(&invite.UseCount)
+		if err != nil {
+			return fmt.Errorf("error counting invite use: %w", err)
+		}
+
+		return recordMemberJoined(tx, invite.TribeID, userID, invite.MembershipType, &userID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeInviteRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewTribeInviteRepository(db.UnwrapDB())
+	tribes := NewTribeRepository(db.UnwrapDB())
+	inviter := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{inviter})
+	now := time.Now()
+
+	create := func(membershipType models.MembershipType, singleUse bool, expiresAt time.Time) *models.TribeInvite {
+		invite := &models.TribeInvite{
+			TribeID:        tribe.ID,
+			CreatedBy:      inviter.ID,
+			MembershipType: membershipType,
+			SingleUse:      singleUse,
+			ExpiresAt:      expiresAt,
+		}
+		require.NoError(t, repo.Create(invite))
+		return invite
+	}
+	memberOf := func(userID uuid.UUID) *models.TribeMember {
+		members, err := tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, member := range members {
+			if member.UserID == userID {
+				return member
+			}
+		}
+		return nil
+	}
+
+	t.Run("create and list", func(t *testing.T) {
+		err := repo.Create(&models.TribeInvite{TribeID: uuid.New(), CreatedBy: inviter.ID, MembershipType: models.MembershipFull, ExpiresAt: now.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		first := create(models.MembershipFull, false, now.Add(time.Hour))
+		second := create(models.MembershipPending, true, now.Add(time.Hour))
+
+		invites, err := repo.ListByTribe(tribe.ID)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(invites), 2)
+		assert.Equal(t, second.ID, invites[0].ID)
+		assert.Equal(t, first.ID, invites[1].ID)
+		assert.True(t, invites[0].SingleUse)
+		assert.Equal(t, models.MembershipPending, invites[0].MembershipType)
+	})
+
+	t.Run("redeem records the inviter", func(t *testing.T) {
+		invite := create(models.MembershipLimited, false, now.Add(time.Hour))
+		user := testutil.CreateTestUser(t, db)
+
+		redeemed, err := repo.Redeem(invite.ID, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, redeemed.UseCount)
+
+		member := memberOf(user.ID)
+		require.NotNil(t, member)
+		assert.Equal(t, models.MembershipLimited, member.MembershipType)
+		require.NotNil(t, member.InvitedBy)
+		assert.Equal(t, inviter.ID, *member.InvitedBy)
+		assert.NotNil(t, member.InvitedAt)
+
+		_, err = repo.Redeem(invite.ID, user.ID)
+		assert.ErrorIs(t, err, models.ErrDuplicate)
+
+		require.NoError(t, tribes.RemoveMember(tribe.ID, user.ID))
+		_, err = repo.Redeem(invite.ID, user.ID)
+		assert.ErrorIs(t, err, models.ErrForbidden)
+
+		stored, err := repo.GetByID(invite.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.UseCount)
+	})
+
+	t.Run("single use", func(t *testing.T) {
+		invite := create(models.MembershipPending, true, now.Add(time.Hour))
+		first := testutil.CreateTestUser(t, db)
+		second := testutil.CreateTestUser(t, db)
+
+		_, err := repo.Redeem(invite.ID, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.MembershipPending, memberOf(first.ID).MembershipType)
+
+		_, err = repo.Redeem(invite.ID, second.ID)
+		assert.ErrorIs(t, err, models.ErrInviteUnavailable)
+		assert.Nil(t, memberOf(second.ID))
+	})
+
+	t.Run("expired and revoked", func(t *testing.T) {
+		user := testutil.CreateTestUser(t, db)
+
+		expired := create(models.MembershipFull, false, now.Add(-time.Minute))
+		_, err := repo.Redeem(expired.ID, user.ID)
+		assert.ErrorIs(t, err, models.ErrInviteUnavailable)
+
+		revoked := create(models.MembershipFull, false, now.Add(time.Hour))
+		assert.ErrorIs(t, repo.Revoke(uuid.New(), revoked.ID), models.ErrNotFound)
+		require.NoError(t, repo.Revoke(tribe.ID, revoked.ID))
+		_, err = repo.Redeem(revoked.ID, user.ID)
+		assert.ErrorIs(t, err, models.ErrInviteUnavailable)
+
+		_, err = repo.Redeem(uuid.New(), user.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, memberOf(user.ID))
+	})
+
+	t.Run("creator no longer a full member", func(t *testing.T) {
+		creator := testutil.CreateTestUser(t, db)
+		require.NoError(t, tribes.AddMember(tribe.ID, creator.ID, models.MembershipFull, nil, nil))
+		invite := &models.TribeInvite{
+			TribeID:        tribe.ID,
+			CreatedBy:      creator.ID,
+			MembershipType: models.MembershipFull,
+			ExpiresAt:      now.Add(time.Hour),
+		}
+		require.NoError(t, repo.Create(invite))
+
+		require.NoError(t, tribes.RemoveMember(tribe.ID, creator.ID))
+		user := testutil.CreateTestUser(t, db)
+		_, err := repo.Redeem(invite.ID, user.ID)
+		assert.ErrorIs(t, err, models.ErrInviteUnavailable)
+		assert.Nil(t, memberOf(user.ID))
+	})
+}
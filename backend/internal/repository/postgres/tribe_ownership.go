@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/lib/pq"
+)
+
+// ownershipTransferColumns lists the ownership transfer columns in scan order
+const ownershipTransferColumns = `id, tribe_id, from_user_id, to_user_id, status, created_at, responded_at`
+
+// TribeOwnershipTransferRepository implements
+// models.TribeOwnershipTransferRepository using PostgreSQL
+type TribeOwnershipTransferRepository struct {
+	BaseRepository
+	tm *TransactionManager
+}
+
+// NewTribeOwnershipTransferRepository creates a new PostgreSQL ownership
+// transfer repository
+func NewTribeOwnershipTransferRepository(db interface{}) models.TribeOwnershipTransferRepository {
+	baseRepo := NewBaseRepository(db)
+	return &TribeOwnershipTransferRepository{
+		BaseRepository: baseRepo,
+		tm:             NewTransactionManager(baseRepo.GetQueryDB()),
+	}
+}
+
+// scanOwnershipTransfer scans one ownership transfer row
+func scanOwnershipTransfer(row interface{ Scan(...interface{}) error }) (*models.TribeOwnershipTransfer, error) {
+	transfer := &models.TribeOwnershipTransfer{}
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.TribeID,
+		&transfer.FromUserID,
+		&transfer.ToUserID,
+		&transfer.Status,
+		&transfer.CreatedAt,
+		&transfer.RespondedAt,
+	)
+	return transfer, err
+}
+
+// Create stores a new pending ownership transfer
+func (r *TribeOwnershipTransferRepository) Create(transfer *models.TribeOwnershipTransfer) error {
+	transfer.Status = models.OwnershipTransferPending
+	if err := transfer.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		if transfer.ID == uuid.Nil {
+			transfer.ID = uuid.New()
+		}
+		transfer.CreatedAt = time.Now()
+		transfer.RespondedAt = nil
+
+		_, err := tx.Exec(`
+			INSERT INTO tribe_ownership_transfers (
+				id, tribe_id, from_user_id, to_user_id, status, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6)`,
+			transfer.ID,
+			transfer.TribeID,
+			transfer.FromUserID,
+			transfer.ToUserID,
+			transfer.Status,
+			transfer.CreatedAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case "23505":
+					return fmt.Errorf("%w: tribe already has a pending ownership transfer", models.ErrDuplicate)
+				case "23503":
+					return fmt.Errorf("%w: tribe or user does not exist", models.ErrNotFound)
+				}
+			}
+			return fmt.Errorf("error creating ownership transfer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetPending retrieves the tribe's pending ownership transfer
+func (r *TribeOwnershipTransferRepository) GetPending(tribeID uuid.UUID) (*models.TribeOwnershipTransfer, error) {
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+	var transfer *models.TribeOwnershipTransfer
+
+	err := r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		var err error
+		transfer, err = scanOwnershipTransfer(tx.QueryRow(`
+			SELECT `+ownershipTransferColumns+`
+			FROM tribe_ownership_transfers
+			WHERE tribe_id = $1 AND status = 'pending'`, tribeID))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting ownership transfer: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Resolve closes a pending ownership transfer, swapping the roles when it is
+// accepted
+func (r *TribeOwnershipTransferRepository) Resolve(transferID uuid.UUID, status models.OwnershipTransferStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
+	if status == models.OwnershipTransferPending {
+		return fmt.Errorf("%w: a transfer cannot be resolved as pending", models.ErrInvalidInput)
+	}
+
+	ctx := context.Background()
+	opts := DefaultTransactionOptions()
+
+	return r.tm.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		transfer, err := scanOwnershipTransfer(tx.QueryRow(`
+			SELECT `+ownershipTransferColumns+`
+			FROM tribe_ownership_transfers
+			WHERE id = $1 AND status = 'pending'
+			FOR UPDATE`, transferID))
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting ownership transfer: %w", err)
+		}
+
+		now := time.Now()
+		if status == models.OwnershipTransferAccepted {
+			if err = acceptOwnershipTransfer(tx, transfer, now); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`
+			UPDATE tribe_ownership_transfers
+			SET status = $1, responded_at = $2
+			WHERE id = $3`,
+			status, now, transferID)
+		if err != nil {
+			return fmt.Errorf("error resolving ownership transfer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// acceptOwnershipTransfer makes the recipient an owner and the sender an
+// admin. Both must still hold the standing they had when the transfer was
+// offered.
+func acceptOwnershipTransfer(tx *sql.Tx, transfer *models.TribeOwnershipTransfer, now time.Time) error {
+	result, err := tx.Exec(`
+		UPDATE tribe_members
+		SET role = 'owner', updated_at = $1
+		WHERE tribe_id = $2 AND user_id = $3 AND membership_type = 'full' AND deleted_at IS NULL`,
+		now, transfer.TribeID, transfer.ToUserID)
+	if err != nil {
+		return fmt.Errorf("error promoting new owner: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: recipient is no longer a full member of the tribe", models.ErrConflict)
+	}
+
+	result, err = tx.Exec(`
+		UPDATE tribe_members
+		SET role = 'admin', updated_at = $1
+		WHERE tribe_id = $2 AND user_id = $3 AND role = 'owner' AND deleted_at IS NULL`,
+		now, transfer.TribeID, transfer.FromUserID)
+	if err != nil {
+		return fmt.Errorf("error demoting previous owner: %w", err)
+	}
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: sender is no longer an owner of the tribe", models.ErrConflict)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/jenglund/rlship-tools/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeOwnershipTransferRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.TeardownTestDB(t, db)
+
+	repo := NewTribeOwnershipTransferRepository(db.UnwrapDB())
+	tribes := NewTribeRepository(db.UnwrapDB())
+	owner := testutil.CreateTestUser(t, db)
+	heir := testutil.CreateTestUser(t, db)
+	other := testutil.CreateTestUser(t, db)
+	tribe := testutil.CreateTestTribe(t, db, []testutil.TestUser{owner, heir, other})
+	require.NoError(t, tribes.SetMemberRole(tribe.ID, owner.ID, models.TribeRoleOwner))
+
+	roleOf := func(userID uuid.UUID) models.TribeRole {
+		members, err := tribes.GetMembers(tribe.ID)
+		require.NoError(t, err)
+		for _, member := range members {
+			if member.UserID == userID {
+				return member.Role
+			}
+		}
+		return ""
+	}
+	offer := func(to uuid.UUID) *models.TribeOwnershipTransfer {
+		transfer := &models.TribeOwnershipTransfer{TribeID: tribe.ID, FromUserID: owner.ID, ToUserID: to}
+		require.NoError(t, repo.Create(transfer))
+		return transfer
+	}
+
+	t.Run("create validation", func(t *testing.T) {
+		err := repo.Create(&models.TribeOwnershipTransfer{TribeID: tribe.ID, FromUserID: owner.ID, ToUserID: owner.ID})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		err = repo.Create(&models.TribeOwnershipTransfer{TribeID: uuid.New(), FromUserID: owner.ID, ToUserID: heir.ID})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		_, err = repo.GetPending(tribe.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("one pending transfer per tribe", func(t *testing.T) {
+		transfer := offer(heir.ID)
+		assert.Equal(t, models.OwnershipTransferPending, transfer.Status)
+
+		err := repo.Create(&models.TribeOwnershipTransfer{TribeID: tribe.ID, FromUserID: owner.ID, ToUserID: other.ID})
+		assert.ErrorIs(t, err, models.ErrDuplicate)
+
+		pending, err := repo.GetPending(tribe.ID)
+		require.NoError(t, err)
+		assert.Equal(t, transfer.ID, pending.ID)
+		assert.Equal(t, heir.ID, pending.ToUserID)
+		assert.Nil(t, pending.RespondedAt)
+
+		require.NoError(t, repo.Resolve(transfer.ID, models.OwnershipTransferDeclined))
+		assert.ErrorIs(t, repo.Resolve(transfer.ID, models.OwnershipTransferAccepted), models.ErrNotFound)
+		assert.Equal(t, models.TribeRoleOwner, roleOf(owner.ID))
+		assert.Equal(t, models.TribeRoleMember, roleOf(heir.ID))
+	})
+
+	t.Run("resolve validation", func(t *testing.T) {
+		transfer := offer(heir.ID)
+		assert.ErrorIs(t, repo.Resolve(transfer.ID, models.OwnershipTransferPending), models.ErrInvalidInput)
+		assert.ErrorIs(t, repo.Resolve(transfer.ID, "lost"), models.ErrInvalidInput)
+		require.NoError(t, repo.Resolve(transfer.ID, models.OwnershipTransferCancelled))
+	})
+
+	t.Run("accept fails once the recipient has left", func(t *testing.T) {
+		transfer := offer(other.ID)
+		require.NoError(t, tribes.RemoveMember(tribe.ID, other.ID))
+
+		err := repo.Resolve(transfer.ID, models.OwnershipTransferAccepted)
+		assert.ErrorIs(t, err, models.ErrConflict)
+		assert.Equal(t, models.TribeRoleOwner, roleOf(owner.ID))
+
+		pending, err := repo.GetPending(tribe.ID)
+		require.NoError(t, err)
+		assert.Equal(t, transfer.ID, pending.ID)
+		require.NoError(t, repo.Resolve(transfer.ID, models.OwnershipTransferCancelled))
+	})
+
+	t.Run("accept swaps roles", func(t *testing.T) {
+		transfer := offer(heir.ID)
+		require.NoError(t, repo.Resolve(transfer.ID, models.OwnershipTransferAccepted))
+
+		assert.Equal(t, models.TribeRoleOwner, roleOf(heir.ID))
+		assert.Equal(t, models.TribeRoleAdmin, roleOf(owner.ID))
+
+		_, err := repo.GetPending(tribe.ID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -397,6 +397,159 @@ func TestTribeRepository(t *testing.T) {
 		})
 	})
 
+	t.Run("SetMemberRole", func(t *testing.T) {
+		now := time.Now()
+		tribe := &models.Tribe{
+			BaseModel: models.BaseModel{
+				ID:        uuid.New(),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			Name:        "Test Tribe " + uuid.New().String()[:8],
+			Type:        models.TribeTypeFriends,
+			Visibility:  models.VisibilityPrivate,
+			Description: "A test tribe",
+			Metadata:    models.JSONMap{},
+		}
+		err := repo.Create(tribe)
+		require.NoError(t, err)
+		err = repo.AddMember(tribe.ID, user1.ID, models.MembershipFull, nil, nil)
+		require.NoError(t, err)
+
+		t.Run("defaults to member", func(t *testing.T) {
+			members, err := repo.GetMembers(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, members, 1)
+			assert.Equal(t, models.TribeRoleMember, members[0].Role)
+		})
+
+		t.Run("valid role change", func(t *testing.T) {
+			err := repo.SetMemberRole(tribe.ID, user1.ID, models.TribeRoleOwner)
+			require.NoError(t, err)
+
+			fetched, err := repo.GetByID(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, fetched.Members, 1)
+			assert.Equal(t, models.TribeRoleOwner, fetched.Members[0].Role)
+
+			tribes, err := repo.GetUserTribes(user1.ID)
+			require.NoError(t, err)
+			for _, userTribe := range tribes {
+				if userTribe.ID == tribe.ID {
+					assert.Equal(t, models.TribeRoleOwner, userTribe.Members[0].Role)
+				}
+			}
+		})
+
+		t.Run("invalid role", func(t *testing.T) {
+			err := repo.SetMemberRole(tribe.ID, user1.ID, models.TribeRole("captain"))
+			assert.ErrorIs(t, err, models.ErrInvalidInput)
+		})
+
+		t.Run("non-member", func(t *testing.T) {
+			err := repo.SetMemberRole(tribe.ID, user2.ID, models.TribeRoleAdmin)
+			assert.EqualError(t, err, "tribe member not found")
+		})
+
+		t.Run("last owner", func(t *testing.T) {
+			err := repo.SetMemberRole(tribe.ID, user1.ID, models.TribeRoleAdmin)
+			assert.ErrorIs(t, err, models.ErrLastOwner)
+			err = repo.RemoveMember(tribe.ID, user1.ID)
+			assert.ErrorIs(t, err, models.ErrLastOwner)
+		})
+
+		t.Run("removal resets role", func(t *testing.T) {
+			err := repo.AddMember(tribe.ID, user2.ID, models.MembershipFull, nil, nil)
+			require.NoError(t, err)
+			err = repo.SetMemberRole(tribe.ID, user2.ID, models.TribeRoleOwner)
+			require.NoError(t, err)
+
+			err = repo.RemoveMember(tribe.ID, user1.ID)
+			require.NoError(t, err)
+			err = repo.ReinviteMember(tribe.ID, user1.ID, models.MembershipPending, nil, nil)
+			require.NoError(t, err)
+
+			members, err := repo.GetMembers(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, members, 2)
+			for _, member := range members {
+				if member.UserID == user1.ID {
+					assert.Equal(t, models.TribeRoleMember, member.Role)
+				}
+			}
+		})
+	})
+
+	t.Run("UpdateMemberProfile", func(t *testing.T) {
+		now := time.Now()
+		tribe := &models.Tribe{
+			BaseModel: models.BaseModel{
+				ID:        uuid.New(),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			Name:        "Test Tribe " + uuid.New().String()[:8],
+			Type:        models.TribeTypeFriends,
+			Visibility:  models.VisibilityPrivate,
+			Description: "A test tribe",
+			Metadata:    models.JSONMap{},
+		}
+		err := repo.Create(tribe)
+		require.NoError(t, err)
+		err = repo.AddMember(tribe.ID, user1.ID, models.MembershipFull, nil, nil)
+		require.NoError(t, err)
+
+		str := func(s string) *string { return &s }
+		member := func() *models.TribeMember {
+			members, err := repo.GetMembers(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, members, 1)
+			return members[0]
+		}
+
+		t.Run("falls back to the account", func(t *testing.T) {
+			got := member()
+			assert.Equal(t, user1.Name, got.DisplayName)
+			assert.Empty(t, got.Pronouns)
+		})
+
+		t.Run("override", func(t *testing.T) {
+			err := repo.UpdateMemberProfile(tribe.ID, user1.ID, &models.TribeMemberProfile{
+				DisplayName: str("Sammy"),
+				AvatarURL:   str("https://example.com/sammy.png"),
+				Pronouns:    str("they/them"),
+			})
+			require.NoError(t, err)
+
+			got := member()
+			assert.Equal(t, "Sammy", got.DisplayName)
+			assert.Equal(t, "https://example.com/sammy.png", got.AvatarURL)
+			assert.Equal(t, "they/them", got.Pronouns)
+
+			tribes, err := repo.GetUserTribes(user1.ID)
+			require.NoError(t, err)
+			for _, userTribe := range tribes {
+				if userTribe.ID == tribe.ID {
+					assert.Equal(t, "Sammy", userTribe.Members[0].DisplayName)
+				}
+			}
+		})
+
+		t.Run("nil fields are kept and empty ones cleared", func(t *testing.T) {
+			err := repo.UpdateMemberProfile(tribe.ID, user1.ID, &models.TribeMemberProfile{DisplayName: str("")})
+			require.NoError(t, err)
+
+			got := member()
+			assert.Equal(t, user1.Name, got.DisplayName)
+			assert.Equal(t, "they/them", got.Pronouns)
+		})
+
+		t.Run("non-member", func(t *testing.T) {
+			err := repo.UpdateMemberProfile(tribe.ID, user2.ID, &models.TribeMemberProfile{Pronouns: str("he/him")})
+			assert.ErrorIs(t, err, models.ErrNotFound)
+		})
+	})
+
 	t.Run("RemoveMember", func(t *testing.T) {
 		t.Run("valid member removal", func(t *testing.T) {
 			now := time.Now()
@@ -604,6 +757,60 @@ func TestTribeRepository(t *testing.T) {
 		})
 	})
 
+	t.Run("ReinviteExpiredGuest", func(t *testing.T) {
+		newTribe := func(t *testing.T) *models.Tribe {
+			now := time.Now()
+			tribe := &models.Tribe{
+				BaseModel: models.BaseModel{
+					ID:        uuid.New(),
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				Name:        "Test Tribe " + uuid.New().String()[:8],
+				Type:        models.TribeTypeFriends,
+				Visibility:  models.VisibilityPrivate,
+				Description: "A test tribe",
+				Metadata:    models.JSONMap{},
+			}
+			require.NoError(t, repo.Create(tribe))
+			return tribe
+		}
+
+		t.Run("expired guest is invited again", func(t *testing.T) {
+			tribe := newTribe(t)
+			require.NoError(t, repo.AddMember(tribe.ID, user1.ID, models.MembershipFull, nil, nil))
+			expiredTime := time.Now().Add(-24 * time.Hour)
+			require.NoError(t, repo.AddMember(tribe.ID, user2.ID, models.MembershipGuest, &expiredTime, nil))
+
+			err := repo.ReinviteExpiredGuest(tribe.ID, user2.ID, &user1.ID)
+			require.NoError(t, err)
+
+			members, err := repo.GetMembers(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, members, 2)
+			for _, member := range members {
+				if member.UserID == user2.ID {
+					assert.Equal(t, models.MembershipPending, member.MembershipType)
+					assert.Nil(t, member.ExpiresAt)
+				}
+			}
+		})
+
+		t.Run("failed removal leaves the membership alone", func(t *testing.T) {
+			tribe := newTribe(t)
+			require.NoError(t, repo.AddMember(tribe.ID, user1.ID, models.MembershipFull, nil, nil))
+			require.NoError(t, repo.SetMemberRole(tribe.ID, user1.ID, models.TribeRoleOwner))
+
+			err := repo.ReinviteExpiredGuest(tribe.ID, user1.ID, nil)
+			assert.ErrorIs(t, err, models.ErrLastOwner)
+
+			members, err := repo.GetMembers(tribe.ID)
+			require.NoError(t, err)
+			require.Len(t, members, 1)
+			assert.Equal(t, models.MembershipFull, members[0].MembershipType)
+		})
+	})
+
 	t.Run("GetByType", func(t *testing.T) {
 		// Clean up any existing tribes
 		_, err := db.Exec("DELETE FROM tribe_members")
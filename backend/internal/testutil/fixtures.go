@@ -298,6 +298,18 @@ func CleanupTestData(t *testing.T, db interface{}) {
 	}
 
 	tables := []string{
+		"tribe_events",
+		"tribe_audit_entries",
+		"pending_invitations",
+		"tribe_invites",
+		"tribe_ownership_transfers",
+		"interest_indicators",
+		"busy_intervals",
+		"availability_calendars",
+		"calendar_feed_tokens",
+		"activity_occurrence_exceptions",
+		"activity_rsvps",
+		"activity_schedules",
 		"list_sharing",
 		"list_items",
 		"list_owners",
@@ -45,8 +45,8 @@ func (m *MockListRepository) List(offset, limit int) ([]*models.List, error) {
 }
 
 // List item operations
-func (m *MockListRepository) AddItem(item *models.ListItem) error {
-	args := m.Called(item)
+func (m *MockListRepository) AddItem(item *models.ListItem, actorID *uuid.UUID) error {
+	args := m.Called(item, actorID)
 	return args.Error(0)
 }
 
@@ -153,8 +153,8 @@ func (m *MockListRepository) ShareWithTribe(share *models.ListShare) error {
 	return args.Error(0)
 }
 
-func (m *MockListRepository) UnshareWithTribe(listID, tribeID uuid.UUID) error {
-	args := m.Called(listID, tribeID)
+func (m *MockListRepository) UnshareWithTribe(listID, tribeID, userID uuid.UUID) error {
+	args := m.Called(listID, tribeID, userID)
 	return args.Error(0)
 }
 
@@ -195,6 +195,12 @@ func (m *MockListRepository) UpdateSyncStatus(listID uuid.UUID, status models.Li
 	return args.Error(0)
 }
 
+// RecordSync records a summary of a sync
+func (m *MockListRepository) RecordSync(listID uuid.UUID, created, updated, deleted int) error {
+	args := m.Called(listID, created, updated, deleted)
+	return args.Error(0)
+}
+
 // GetConflicts returns all sync conflicts for a list
 func (m *MockListRepository) GetConflicts(listID uuid.UUID) ([]*models.SyncConflict, error) {
 	args := m.Called(listID)
@@ -210,9 +216,18 @@ func (m *MockListRepository) CreateConflict(conflict *models.SyncConflict) error
 	return args.Error(0)
 }
 
-// ResolveConflict marks a sync conflict as resolved
-func (m *MockListRepository) ResolveConflict(conflictID uuid.UUID) error {
+// GetConflict returns a single sync conflict
+func (m *MockListRepository) GetConflict(conflictID uuid.UUID) (*models.SyncConflict, error) {
 	args := m.Called(conflictID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SyncConflict), args.Error(1)
+}
+
+// ResolveConflict marks a sync conflict as resolved
+func (m *MockListRepository) ResolveConflict(conflictID uuid.UUID, resolution string, change *models.ResolvedItem) error {
+	args := m.Called(conflictID, resolution, change)
 	return args.Error(0)
 }
 
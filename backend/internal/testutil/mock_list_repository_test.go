@@ -69,8 +69,8 @@ func TestMockListRepository_Items(t *testing.T) {
 	}
 
 	t.Run("AddItem", func(t *testing.T) {
-		repo.On("AddItem", testItem).Return(nil)
-		err := repo.AddItem(testItem)
+		repo.On("AddItem", testItem, (*uuid.UUID)(nil)).Return(nil)
+		err := repo.AddItem(testItem, nil)
 		assert.NoError(t, err)
 		repo.AssertExpectations(t)
 	})
@@ -205,8 +205,8 @@ func TestMockListRepository_Sharing(t *testing.T) {
 	})
 
 	t.Run("UnshareWithTribe", func(t *testing.T) {
-		repo.On("UnshareWithTribe", listID, tribeID).Return(nil)
-		err := repo.UnshareWithTribe(listID, tribeID)
+		repo.On("UnshareWithTribe", listID, tribeID, userID).Return(nil)
+		err := repo.UnshareWithTribe(listID, tribeID, userID)
 		assert.NoError(t, err)
 		repo.AssertExpectations(t)
 	})
@@ -310,8 +310,8 @@ func TestMockListRepository_SyncOperations(t *testing.T) {
 
 	t.Run("ResolveConflict", func(t *testing.T) {
 		repo := &MockListRepository{}
-		repo.On("ResolveConflict", testConflict.ID).Return(nil)
-		err := repo.ResolveConflict(testConflict.ID)
+		repo.On("ResolveConflict", testConflict.ID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(nil)
+		err := repo.ResolveConflict(testConflict.ID, models.ResolutionKeepLocal, nil)
 		assert.NoError(t, err)
 		repo.AssertExpectations(t)
 	})
@@ -362,8 +362,8 @@ func TestMockListRepository_SyncOperations(t *testing.T) {
 	t.Run("ResolveConflict error", func(t *testing.T) {
 		repo := &MockListRepository{}
 		expectedErr := fmt.Errorf("failed to resolve conflict")
-		repo.On("ResolveConflict", testConflict.ID).Return(expectedErr)
-		err := repo.ResolveConflict(testConflict.ID)
+		repo.On("ResolveConflict", testConflict.ID, models.ResolutionKeepLocal, (*models.ResolvedItem)(nil)).Return(expectedErr)
+		err := repo.ResolveConflict(testConflict.ID, models.ResolutionKeepLocal, nil)
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		repo.AssertExpectations(t)
@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMenuSessionRepository implements models.MenuSessionRepository for testing
+type MockMenuSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockMenuSessionRepository) Create(session *models.MenuSession) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockMenuSessionRepository) GetByID(id uuid.UUID) (*models.MenuSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MenuSession), args.Error(1)
+}
+
+func (m *MockMenuSessionRepository) SetChosenItem(sessionID, itemID, userID uuid.UUID) error {
+	args := m.Called(sessionID, itemID, userID)
+	return args.Error(0)
+}
+
+func (m *MockMenuSessionRepository) GetUserSessions(userID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	args := m.Called(userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MenuSession), args.Error(1)
+}
+
+func (m *MockMenuSessionRepository) GetTribeSessions(tribeID uuid.UUID, offset, limit int) ([]*models.MenuSession, error) {
+	args := m.Called(tribeID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MenuSession), args.Error(1)
+}
+
+func (m *MockMenuSessionRepository) CastVotes(votes []*models.MenuVote) error {
+	args := m.Called(votes)
+	return args.Error(0)
+}
+
+func (m *MockMenuSessionRepository) GetVotes(sessionID uuid.UUID) ([]*models.MenuVote, error) {
+	args := m.Called(sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MenuVote), args.Error(1)
+}
@@ -112,6 +112,11 @@ type MockTribeRepository struct {
 	RemoveMemberFunc               func(tribeID, userID uuid.UUID) error
 	GetMembersFunc                 func(tribeID uuid.UUID) ([]*models.TribeMember, error)
 	GetUserTribesFunc              func(userID uuid.UUID) ([]*models.Tribe, error)
+	CheckFormerTribeMemberFunc     func(tribeID, userID uuid.UUID) (bool, error)
+	ReinviteMemberFunc             func(tribeID, userID uuid.UUID, memberType models.MembershipType, expiresAt *time.Time, invitedBy *uuid.UUID) error
+	ReinviteExpiredGuestFunc       func(tribeID, userID uuid.UUID, invitedBy *uuid.UUID) error
+	SetMemberRoleFunc              func(tribeID, userID uuid.UUID, role models.TribeRole) error
+	UpdateMemberProfileFunc        func(tribeID, userID uuid.UUID, profile *models.TribeMemberProfile) error
 	GetExpiredGuestMembershipsFunc func() ([]*models.TribeMember, error)
 	GetByTypeFunc                  func(tribeType models.TribeType, offset, limit int) ([]*models.Tribe, error)
 	SearchFunc                     func(query string, offset, limit int) ([]*models.Tribe, error)
@@ -192,6 +197,41 @@ func (m *MockTribeRepository) GetUserTribes(userID uuid.UUID) ([]*models.Tribe,
 	return nil, nil
 }
 
+func (m *MockTribeRepository) CheckFormerTribeMember(tribeID, userID uuid.UUID) (bool, error) {
+	if m.CheckFormerTribeMemberFunc != nil {
+		return m.CheckFormerTribeMemberFunc(tribeID, userID)
+	}
+	return false, nil
+}
+
+func (m *MockTribeRepository) ReinviteMember(tribeID, userID uuid.UUID, memberType models.MembershipType, expiresAt *time.Time, invitedBy *uuid.UUID) error {
+	if m.ReinviteMemberFunc != nil {
+		return m.ReinviteMemberFunc(tribeID, userID, memberType, expiresAt, invitedBy)
+	}
+	return nil
+}
+
+func (m *MockTribeRepository) ReinviteExpiredGuest(tribeID, userID uuid.UUID, invitedBy *uuid.UUID) error {
+	if m.ReinviteExpiredGuestFunc != nil {
+		return m.ReinviteExpiredGuestFunc(tribeID, userID, invitedBy)
+	}
+	return nil
+}
+
+func (m *MockTribeRepository) SetMemberRole(tribeID, userID uuid.UUID, role models.TribeRole) error {
+	if m.SetMemberRoleFunc != nil {
+		return m.SetMemberRoleFunc(tribeID, userID, role)
+	}
+	return nil
+}
+
+func (m *MockTribeRepository) UpdateMemberProfile(tribeID, userID uuid.UUID, profile *models.TribeMemberProfile) error {
+	if m.UpdateMemberProfileFunc != nil {
+		return m.UpdateMemberProfileFunc(tribeID, userID, profile)
+	}
+	return nil
+}
+
 func (m *MockTribeRepository) GetExpiredGuestMemberships() ([]*models.TribeMember, error) {
 	if m.GetExpiredGuestMembershipsFunc != nil {
 		return m.GetExpiredGuestMembershipsFunc()
@@ -220,7 +260,8 @@ type MockActivityRepository struct {
 	UpdateFunc                    func(activity *models.Activity) error
 	DeleteFunc                    func(id uuid.UUID) error
 	ListFunc                      func(offset, limit int) ([]*models.Activity, error)
-	AddOwnerFunc                  func(activityID, ownerID uuid.UUID, ownerType string) error
+	ListForUserFunc               func(userID uuid.UUID, offset, limit int) ([]*models.Activity, error)
+	AddOwnerFunc                  func(activityID, ownerID uuid.UUID, ownerType models.OwnerType) error
 	RemoveOwnerFunc               func(activityID, ownerID uuid.UUID) error
 	GetOwnersFunc                 func(activityID uuid.UUID) ([]*models.ActivityOwner, error)
 	GetUserActivitiesFunc         func(userID uuid.UUID) ([]*models.Activity, error)
@@ -228,6 +269,17 @@ type MockActivityRepository struct {
 	ShareWithTribeFunc            func(activityID, tribeID, userID uuid.UUID, expiresAt *time.Time) error
 	UnshareWithTribeFunc          func(activityID, tribeID uuid.UUID) error
 	GetSharedActivitiesFunc       func(tribeID uuid.UUID) ([]*models.Activity, error)
+	GetCreatorNameFunc            func(activityID, viewerID uuid.UUID) (string, error)
+	SetScheduleFunc               func(schedule *models.ActivitySchedule) error
+	GetScheduleFunc               func(activityID uuid.UUID) (*models.ActivitySchedule, error)
+	DeleteScheduleFunc            func(activityID uuid.UUID) error
+	SetRSVPFunc                   func(rsvp *models.ActivityRSVP) error
+	DeleteRSVPFunc                func(activityID, userID uuid.UUID) error
+	GetRSVPsFunc                  func(activityID uuid.UUID) ([]*models.ActivityRSVP, error)
+	SetRecurrenceFunc             func(activityID uuid.UUID, rule string, start *time.Time) error
+	SetOccurrenceExceptionFunc    func(exception *models.OccurrenceException) error
+	DeleteOccurrenceExceptionFunc func(activityID uuid.UUID, originalStart time.Time) error
+	GetOccurrenceExceptionsFunc   func(activityID uuid.UUID) ([]*models.OccurrenceException, error)
 	MarkForDeletionFunc           func(activityID uuid.UUID) error
 	CleanupOrphanedActivitiesFunc func() error
 }
@@ -272,7 +324,14 @@ func (m *MockActivityRepository) List(offset, limit int) ([]*models.Activity, er
 	return nil, nil
 }
 
-func (m *MockActivityRepository) AddOwner(activityID, ownerID uuid.UUID, ownerType string) error {
+func (m *MockActivityRepository) ListForUser(userID uuid.UUID, offset, limit int) ([]*models.Activity, error) {
+	if m.ListForUserFunc != nil {
+		return m.ListForUserFunc(userID, offset, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockActivityRepository) AddOwner(activityID, ownerID uuid.UUID, ownerType models.OwnerType) error {
 	if m.AddOwnerFunc != nil {
 		return m.AddOwnerFunc(activityID, ownerID, ownerType)
 	}
@@ -328,6 +387,83 @@ func (m *MockActivityRepository) GetSharedActivities(tribeID uuid.UUID) ([]*mode
 	return nil, nil
 }
 
+func (m *MockActivityRepository) GetCreatorName(activityID, viewerID uuid.UUID) (string, error) {
+	if m.GetCreatorNameFunc != nil {
+		return m.GetCreatorNameFunc(activityID, viewerID)
+	}
+	return "", nil
+}
+
+func (m *MockActivityRepository) SetSchedule(schedule *models.ActivitySchedule) error {
+	if m.SetScheduleFunc != nil {
+		return m.SetScheduleFunc(schedule)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) GetSchedule(activityID uuid.UUID) (*models.ActivitySchedule, error) {
+	if m.GetScheduleFunc != nil {
+		return m.GetScheduleFunc(activityID)
+	}
+	return nil, nil
+}
+
+func (m *MockActivityRepository) DeleteSchedule(activityID uuid.UUID) error {
+	if m.DeleteScheduleFunc != nil {
+		return m.DeleteScheduleFunc(activityID)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) SetRSVP(rsvp *models.ActivityRSVP) error {
+	if m.SetRSVPFunc != nil {
+		return m.SetRSVPFunc(rsvp)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) DeleteRSVP(activityID, userID uuid.UUID) error {
+	if m.DeleteRSVPFunc != nil {
+		return m.DeleteRSVPFunc(activityID, userID)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) GetRSVPs(activityID uuid.UUID) ([]*models.ActivityRSVP, error) {
+	if m.GetRSVPsFunc != nil {
+		return m.GetRSVPsFunc(activityID)
+	}
+	return nil, nil
+}
+
+func (m *MockActivityRepository) SetRecurrence(activityID uuid.UUID, rule string, start *time.Time) error {
+	if m.SetRecurrenceFunc != nil {
+		return m.SetRecurrenceFunc(activityID, rule, start)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) SetOccurrenceException(exception *models.OccurrenceException) error {
+	if m.SetOccurrenceExceptionFunc != nil {
+		return m.SetOccurrenceExceptionFunc(exception)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) DeleteOccurrenceException(activityID uuid.UUID, originalStart time.Time) error {
+	if m.DeleteOccurrenceExceptionFunc != nil {
+		return m.DeleteOccurrenceExceptionFunc(activityID, originalStart)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) GetOccurrenceExceptions(activityID uuid.UUID) ([]*models.OccurrenceException, error) {
+	if m.GetOccurrenceExceptionsFunc != nil {
+		return m.GetOccurrenceExceptionsFunc(activityID)
+	}
+	return nil, nil
+}
+
 func (m *MockActivityRepository) MarkForDeletion(activityID uuid.UUID) error {
 	if m.MarkForDeletionFunc != nil {
 		return m.MarkForDeletionFunc(activityID)
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// GuestExpiryService defines the interface needed for the worker
+type GuestExpiryService interface {
+	// ProcessGuestExpiry warns guests about upcoming expiry and removes or
+	// downgrades expired ones
+	ProcessGuestExpiry() error
+}
+
+// GuestExpiryWorker periodically acts on guest memberships. Expired guests
+// already lose access when they run out; this takes them off the tribe and
+// any lists they co-own through it.
+type GuestExpiryWorker struct {
+	service    GuestExpiryService
+	interval   time.Duration
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+}
+
+// NewGuestExpiryWorker creates a new worker for processing guest expiry
+func NewGuestExpiryWorker(service GuestExpiryService, interval time.Duration) *GuestExpiryWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GuestExpiryWorker{
+		service:    service,
+		interval:   interval,
+		ctx:        ctx,
+		cancelFunc: cancel,
+	}
+}
+
+// Start begins the worker process
+func (w *GuestExpiryWorker) Start() {
+	log.Println("Starting guest expiry worker with interval:", w.interval)
+
+	if err := w.service.ProcessGuestExpiry(); err != nil {
+		log.Printf("Error during initial guest expiry run: %v\n", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.service.ProcessGuestExpiry(); err != nil {
+					log.Printf("Error during scheduled guest expiry run: %v\n", err)
+				}
+			case <-w.ctx.Done():
+				ticker.Stop()
+				log.Println("Guest expiry worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the worker process
+func (w *GuestExpiryWorker) Stop() {
+	log.Println("Stopping guest expiry worker")
+	w.cancelFunc()
+}
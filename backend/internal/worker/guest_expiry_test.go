@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGuestExpiryService mocks the guest expiry service used by the worker
+type MockGuestExpiryService struct {
+	mock.Mock
+}
+
+func (m *MockGuestExpiryService) ProcessGuestExpiry() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestGuestExpiryWorker(t *testing.T) {
+	interval := 50 * time.Millisecond
+
+	t.Run("Initial run happens immediately", func(t *testing.T) {
+		mockService := new(MockGuestExpiryService)
+		mockService.On("ProcessGuestExpiry").Return(nil).Once()
+
+		worker := NewGuestExpiryWorker(mockService, interval)
+		worker.Start()
+		time.Sleep(10 * time.Millisecond)
+		worker.Stop()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Periodic runs continues after errors", func(t *testing.T) {
+		mockService := new(MockGuestExpiryService)
+		mockService.On("ProcessGuestExpiry").Return(assert.AnError).Once()
+		mockService.On("ProcessGuestExpiry").Return(nil).Twice()
+
+		worker := NewGuestExpiryWorker(mockService, interval)
+		worker.Start()
+		time.Sleep(interval*2 + 20*time.Millisecond)
+		worker.Stop()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Stop ends runs", func(t *testing.T) {
+		mockService := new(MockGuestExpiryService)
+		mockService.On("ProcessGuestExpiry").Return(nil).Once()
+
+		worker := NewGuestExpiryWorker(mockService, interval)
+		worker.Start()
+		worker.Stop()
+		time.Sleep(interval + 20*time.Millisecond)
+
+		mockService.AssertNumberOfCalls(t, "ProcessGuestExpiry", 1)
+	})
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// InterestCleanupService defines the interface needed for the worker
+type InterestCleanupService interface {
+	// CleanupExpiredInterests removes expired interest indicators
+	CleanupExpiredInterests() error
+}
+
+// InterestCleanupWorker periodically purges expired interest indicators.
+// Expired indicators already stop matching when they run out; this only
+// keeps the table from growing.
+type InterestCleanupWorker struct {
+	service         InterestCleanupService
+	cleanupInterval time.Duration
+	ctx             context.Context
+	cancelFunc      context.CancelFunc
+}
+
+// NewInterestCleanupWorker creates a new worker for cleaning up expired
+// interest indicators
+func NewInterestCleanupWorker(service InterestCleanupService, cleanupInterval time.Duration) *InterestCleanupWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InterestCleanupWorker{
+		service:         service,
+		cleanupInterval: cleanupInterval,
+		ctx:             ctx,
+		cancelFunc:      cancel,
+	}
+}
+
+// Start begins the worker process
+func (w *InterestCleanupWorker) Start() {
+	log.Println("Starting interest cleanup worker with interval:", w.cleanupInterval)
+
+	if err := w.service.CleanupExpiredInterests(); err != nil {
+		log.Printf("Error during initial interest cleanup: %v\n", err)
+	}
+
+	ticker := time.NewTicker(w.cleanupInterval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.service.CleanupExpiredInterests(); err != nil {
+					log.Printf("Error during scheduled interest cleanup: %v\n", err)
+				}
+			case <-w.ctx.Done():
+				ticker.Stop()
+				log.Println("Interest cleanup worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the worker process
+func (w *InterestCleanupWorker) Stop() {
+	log.Println("Stopping interest cleanup worker")
+	w.cancelFunc()
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockInterestService mocks the interest cleanup used by the worker
+type MockInterestService struct {
+	mock.Mock
+}
+
+func (m *MockInterestService) CleanupExpiredInterests() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestInterestCleanupWorker(t *testing.T) {
+	interval := 50 * time.Millisecond
+
+	t.Run("Initial cleanup happens immediately", func(t *testing.T) {
+		mockService := new(MockInterestService)
+		mockService.On("CleanupExpiredInterests").Return(nil).Once()
+
+		worker := NewInterestCleanupWorker(mockService, interval)
+		worker.Start()
+		time.Sleep(10 * time.Millisecond)
+		worker.Stop()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Periodic cleanup continues after errors", func(t *testing.T) {
+		mockService := new(MockInterestService)
+		mockService.On("CleanupExpiredInterests").Return(assert.AnError).Once()
+		mockService.On("CleanupExpiredInterests").Return(nil).Twice()
+
+		worker := NewInterestCleanupWorker(mockService, interval)
+		worker.Start()
+		time.Sleep(interval*2 + 20*time.Millisecond)
+		worker.Stop()
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Stop ends cleanup", func(t *testing.T) {
+		mockService := new(MockInterestService)
+		mockService.On("CleanupExpiredInterests").Return(nil).Once()
+
+		worker := NewInterestCleanupWorker(mockService, interval)
+		worker.Start()
+		worker.Stop()
+		time.Sleep(interval + 20*time.Millisecond)
+
+		mockService.AssertNumberOfCalls(t, "CleanupExpiredInterests", 1)
+	})
+}
@@ -0,0 +1,244 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+// maxSyncBackoff caps how long a failing list is skipped for
+const maxSyncBackoff = 24 * time.Hour
+
+// syncSources are the sources the worker syncs lists from
+var syncSources = []models.SyncSource{
+	models.SyncSourceGoogleMaps,
+	models.SyncSourceManual,
+	models.SyncSourceImported,
+}
+
+// SyncListFinder finds the lists synced from a source
+type SyncListFinder interface {
+	GetListsBySource(source string) ([]*models.List, error)
+}
+
+// ListSyncService defines the interface needed for the worker
+type ListSyncService interface {
+	// SyncList synchronizes a list with its external source
+	SyncList(listID uuid.UUID) error
+	// CanSync reports whether lists from the source can be synced
+	CanSync(source models.SyncSource) bool
+}
+
+// ListSyncWorker periodically syncs every list that has an external source.
+// Lists are synced a few at a time, each after a random delay so they do not
+// hit the source all at once. A list whose source returns an error is backed
+// off exponentially before it is tried again.
+type ListSyncWorker struct {
+	finder      SyncListFinder
+	service     ListSyncService
+	interval    time.Duration
+	concurrency int
+	jitter      time.Duration
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+	wg          sync.WaitGroup
+
+	mu      sync.Mutex
+	backoff map[uuid.UUID]*syncBackoff
+}
+
+// syncBackoff tracks consecutive source errors for a list
+type syncBackoff struct {
+	failures  int
+	nextAfter time.Time
+}
+
+// NewListSyncWorker creates a new worker for syncing lists. Concurrency below
+// one syncs a single list at a time; jitter is the most each list's sync is
+// delayed within a run.
+func NewListSyncWorker(finder SyncListFinder, service ListSyncService, interval time.Duration, concurrency int, jitter time.Duration) *ListSyncWorker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ListSyncWorker{
+		finder:      finder,
+		service:     service,
+		interval:    interval,
+		concurrency: concurrency,
+		jitter:      jitter,
+		ctx:         ctx,
+		cancelFunc:  cancel,
+		backoff:     make(map[uuid.UUID]*syncBackoff),
+	}
+}
+
+// Start begins the worker process
+func (w *ListSyncWorker) Start() {
+	log.Println("Starting list sync worker with interval:", w.interval)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		// Run a sync immediately on start
+		w.RunOnce()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				log.Println("Running scheduled list sync...")
+				w.RunOnce()
+			case <-w.ctx.Done():
+				log.Println("List sync worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the worker process and waits for running syncs to finish
+func (w *ListSyncWorker) Stop() {
+	log.Println("Stopping list sync worker")
+	w.cancelFunc()
+	w.wg.Wait()
+}
+
+// RunOnce syncs every due list once, returning when they have all finished
+func (w *ListSyncWorker) RunOnce() {
+	lists := w.dueLists()
+	if len(lists) == 0 {
+		return
+	}
+
+	sem := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	for _, list := range lists {
+		select {
+		case sem <- struct{}{}:
+		case <-w.ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(listID uuid.UUID) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if !w.sleep(w.randomJitter()) {
+				return
+			}
+			w.syncList(listID)
+		}(list.ID)
+	}
+	wg.Wait()
+}
+
+// dueLists returns the synced lists that are not backing off. Sources
+// without a provider are skipped, since there is nothing to pull from them.
+func (w *ListSyncWorker) dueLists() []*models.List {
+	now := time.Now()
+	var due []*models.List
+	for _, source := range syncSources {
+		if !w.service.CanSync(source) {
+			continue
+		}
+		lists, err := w.finder.GetListsBySource(string(source))
+		if err != nil {
+			log.Printf("Error finding %s lists to sync: %v\n", source, err)
+			continue
+		}
+		for _, list := range lists {
+			if w.backingOff(list.ID, now) {
+				continue
+			}
+			due = append(due, list)
+		}
+	}
+	return due
+}
+
+func (w *ListSyncWorker) syncList(listID uuid.UUID) {
+	err := w.service.SyncList(listID)
+	switch {
+	case err == nil:
+		w.clearBackoff(listID)
+	case errors.Is(err, models.ErrExternalSourceError),
+		errors.Is(err, models.ErrExternalSourceUnavailable),
+		errors.Is(err, models.ErrExternalSourceTimeout):
+		// The source is failing, rate limiting or slow; give it room
+		delay := w.recordFailure(listID)
+		log.Printf("Error syncing list %s, retrying in %v: %v\n", listID, delay, err)
+	default:
+		log.Printf("Error syncing list %s: %v\n", listID, err)
+	}
+}
+
+func (w *ListSyncWorker) backingOff(listID uuid.UUID, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state, ok := w.backoff[listID]
+	return ok && now.Before(state.nextAfter)
+}
+
+func (w *ListSyncWorker) clearBackoff(listID uuid.UUID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.backoff, listID)
+}
+
+// recordFailure doubles a list's backoff, starting from one interval, and
+// returns the delay before its next attempt
+func (w *ListSyncWorker) recordFailure(listID uuid.UUID) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.backoff[listID]
+	if !ok {
+		state = &syncBackoff{}
+		w.backoff[listID] = state
+	}
+	state.failures++
+
+	delay := w.interval
+	for i := 1; i < state.failures && delay < maxSyncBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxSyncBackoff {
+		delay = maxSyncBackoff
+	}
+	delay += w.randomJitter()
+	state.nextAfter = time.Now().Add(delay)
+	return delay
+}
+
+func (w *ListSyncWorker) randomJitter() time.Duration {
+	if w.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(w.jitter)))
+}
+
+// sleep waits for the given duration, returning false if the worker stops
+func (w *ListSyncWorker) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return w.ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
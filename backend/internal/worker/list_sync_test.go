@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSyncListFinder mocks the list lookup used by the sync worker
+type MockSyncListFinder struct {
+	mock.Mock
+}
+
+func (m *MockSyncListFinder) GetListsBySource(source string) ([]*models.List, error) {
+	args := m.Called(source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.List), args.Error(1)
+}
+
+// fakeListSyncer records syncs and how many ran at once
+type fakeListSyncer struct {
+	mu         sync.Mutex
+	calls      map[uuid.UUID]int
+	errs       map[uuid.UUID]error
+	unsyncable map[models.SyncSource]bool
+	running    int32
+	peak       int32
+}
+
+func newFakeListSyncer() *fakeListSyncer {
+	return &fakeListSyncer{
+		calls:      make(map[uuid.UUID]int),
+		errs:       make(map[uuid.UUID]error),
+		unsyncable: make(map[models.SyncSource]bool),
+	}
+}
+
+func (f *fakeListSyncer) CanSync(source models.SyncSource) bool {
+	return !f.unsyncable[source]
+}
+
+func (f *fakeListSyncer) SyncList(listID uuid.UUID) error {
+	running := atomic.AddInt32(&f.running, 1)
+	defer atomic.AddInt32(&f.running, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if running <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, running) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[listID]++
+	return f.errs[listID]
+}
+
+func (f *fakeListSyncer) callCount(listID uuid.UUID) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[listID]
+}
+
+func syncedLists(n int) []*models.List {
+	lists := make([]*models.List, n)
+	for i := range lists {
+		lists[i] = &models.List{ID: uuid.New(), SyncSource: models.SyncSourceGoogleMaps}
+	}
+	return lists
+}
+
+func TestListSyncWorker(t *testing.T) {
+	t.Run("Syncs every source with bounded concurrency", func(t *testing.T) {
+		finder := new(MockSyncListFinder)
+		google := syncedLists(6)
+		imported := syncedLists(2)
+		finder.On("GetListsBySource", string(models.SyncSourceGoogleMaps)).Return(google, nil).Once()
+		finder.On("GetListsBySource", string(models.SyncSourceManual)).Return(nil, fmt.Errorf("db error")).Once()
+		finder.On("GetListsBySource", string(models.SyncSourceImported)).Return(imported, nil).Once()
+
+		syncer := newFakeListSyncer()
+		worker := NewListSyncWorker(finder, syncer, time.Hour, 2, time.Millisecond)
+		worker.RunOnce()
+
+		for _, list := range append(google, imported...) {
+			assert.Equal(t, 1, syncer.callCount(list.ID))
+		}
+		assert.LessOrEqual(t, atomic.LoadInt32(&syncer.peak), int32(2))
+		finder.AssertExpectations(t)
+	})
+
+	t.Run("Source errors back off the list", func(t *testing.T) {
+		finder := new(MockSyncListFinder)
+		lists := syncedLists(5)
+		failing, unavailable, timedOut, disabled, healthy := lists[0], lists[1], lists[2], lists[3], lists[4]
+		finder.On("GetListsBySource", string(models.SyncSourceGoogleMaps)).Return(lists, nil)
+		finder.On("GetListsBySource", mock.Anything).Return([]*models.List{}, nil)
+
+		syncer := newFakeListSyncer()
+		syncer.errs[failing.ID] = fmt.Errorf("sync failed: %w", models.ErrExternalSourceError)
+		syncer.errs[unavailable.ID] = fmt.Errorf("Google Maps returned 429: %w", models.ErrExternalSourceUnavailable)
+		syncer.errs[timedOut.ID] = models.ErrExternalSourceTimeout
+		syncer.errs[disabled.ID] = models.ErrSyncDisabled
+
+		worker := NewListSyncWorker(finder, syncer, time.Hour, 1, 0)
+		worker.RunOnce()
+		worker.RunOnce()
+
+		assert.Equal(t, 1, syncer.callCount(failing.ID))
+		assert.Equal(t, 1, syncer.callCount(unavailable.ID))
+		assert.Equal(t, 1, syncer.callCount(timedOut.ID))
+		assert.Equal(t, 2, syncer.callCount(disabled.ID))
+		assert.Equal(t, 2, syncer.callCount(healthy.ID))
+
+		// Each further failure doubles the wait
+		assert.GreaterOrEqual(t, worker.recordFailure(failing.ID), 2*time.Hour)
+		assert.Equal(t, maxSyncBackoff, NewListSyncWorker(finder, syncer, 48*time.Hour, 1, 0).recordFailure(failing.ID))
+	})
+
+	t.Run("Sources without a provider are skipped", func(t *testing.T) {
+		finder := new(MockSyncListFinder)
+		imported := syncedLists(1)
+		finder.On("GetListsBySource", string(models.SyncSourceImported)).Return(imported, nil).Once()
+
+		syncer := newFakeListSyncer()
+		syncer.unsyncable[models.SyncSourceGoogleMaps] = true
+		syncer.unsyncable[models.SyncSourceManual] = true
+
+		NewListSyncWorker(finder, syncer, time.Hour, 1, 0).RunOnce()
+
+		assert.Equal(t, 1, syncer.callCount(imported[0].ID))
+		finder.AssertExpectations(t)
+		finder.AssertNotCalled(t, "GetListsBySource", string(models.SyncSourceGoogleMaps))
+		finder.AssertNotCalled(t, "GetListsBySource", string(models.SyncSourceManual))
+	})
+
+	t.Run("Start syncs immediately and Stop waits", func(t *testing.T) {
+		finder := new(MockSyncListFinder)
+		lists := syncedLists(1)
+		finder.On("GetListsBySource", string(models.SyncSourceGoogleMaps)).Return(lists, nil)
+		finder.On("GetListsBySource", mock.Anything).Return([]*models.List{}, nil)
+
+		syncer := newFakeListSyncer()
+		worker := NewListSyncWorker(finder, syncer, time.Hour, 1, 0)
+		worker.Start()
+
+		assert.Eventually(t, func() bool { return syncer.callCount(lists[0].ID) == 1 }, time.Second, 5*time.Millisecond)
+		worker.Stop()
+	})
+}
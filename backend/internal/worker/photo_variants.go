@@ -0,0 +1,207 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+)
+
+const (
+	// variantQueueSize is how many new photos can wait for the worker;
+	// photos dropped from a full queue are picked up by the next sweep
+	variantQueueSize = 100
+	// variantSweepLimit caps how many pending photos one sweep handles
+	variantSweepLimit = 50
+)
+
+// PendingPhotoFinder finds photos whose variants still need generating
+type PendingPhotoFinder interface {
+	ListMissingVariants(maxAttempts, limit int) ([]*models.ActivityPhoto, error)
+}
+
+// PhotoVariantService defines the interface needed for the worker
+type PhotoVariantService interface {
+	// GenerateVariants creates and records the resized variants of a photo
+	GenerateVariants(ctx context.Context, photoID uuid.UUID) error
+}
+
+// PhotoVariantWorker generates photo variants in the background. New photos
+// are queued as they are created, and a periodic sweep picks up photos that
+// were missed or failed. A failed photo is retried after a delay that
+// doubles with each failure, up to maxAttempts attempts in total.
+type PhotoVariantWorker struct {
+	finder      PendingPhotoFinder
+	service     PhotoVariantService
+	interval    time.Duration
+	maxAttempts int
+	retryDelay  time.Duration
+	queue       chan uuid.UUID
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+	wg          sync.WaitGroup
+
+	mu      sync.Mutex
+	retries map[uuid.UUID]*variantRetry
+}
+
+// variantRetry tracks failed attempts at a photo during this run
+type variantRetry struct {
+	failures  int
+	nextAfter time.Time
+}
+
+// NewPhotoVariantWorker creates a new worker for generating photo variants
+func NewPhotoVariantWorker(finder PendingPhotoFinder, service PhotoVariantService, interval time.Duration, maxAttempts int, retryDelay time.Duration) *PhotoVariantWorker {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PhotoVariantWorker{
+		finder:      finder,
+		service:     service,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		queue:       make(chan uuid.UUID, variantQueueSize),
+		ctx:         ctx,
+		cancelFunc:  cancel,
+		retries:     make(map[uuid.UUID]*variantRetry),
+	}
+}
+
+// Start begins the worker process
+func (w *PhotoVariantWorker) Start() {
+	log.Println("Starting photo variant worker with interval:", w.interval)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		// Catch up on photos left over from before a restart
+		w.RunOnce()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case photoID := <-w.queue:
+				w.process(photoID)
+			case <-ticker.C:
+				w.RunOnce()
+			case <-w.ctx.Done():
+				log.Println("Photo variant worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the worker process and waits for the current job to finish
+func (w *PhotoVariantWorker) Stop() {
+	log.Println("Stopping photo variant worker")
+	w.cancelFunc()
+	w.wg.Wait()
+}
+
+// Enqueue schedules variant generation for a photo without blocking
+func (w *PhotoVariantWorker) Enqueue(photoID uuid.UUID) {
+	select {
+	case w.queue <- photoID:
+	default:
+		log.Printf("Photo variant queue full; photo %s will be picked up by the next sweep\n", photoID)
+	}
+}
+
+// RunOnce generates variants for every pending photo that is not waiting
+// to be retried
+func (w *PhotoVariantWorker) RunOnce() {
+	photos, err := w.finder.ListMissingVariants(w.maxAttempts, variantSweepLimit)
+	if err != nil {
+		log.Printf("Error finding photos needing variants: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, photo := range photos {
+		if w.ctx.Err() != nil {
+			return
+		}
+		if w.waiting(photo.ID, now) {
+			continue
+		}
+		w.process(photo.ID)
+	}
+}
+
+func (w *PhotoVariantWorker) process(photoID uuid.UUID) {
+	if err := w.service.GenerateVariants(w.ctx, photoID); err != nil {
+		delay, retry := w.recordFailure(photoID)
+		if retry {
+			log.Printf("Error generating variants for photo %s, retrying in %v: %v\n", photoID, delay, err)
+		} else {
+			log.Printf("Error generating variants for photo %s, giving up: %v\n", photoID, err)
+		}
+		return
+	}
+
+	w.mu.Lock()
+	delete(w.retries, photoID)
+	w.mu.Unlock()
+}
+
+func (w *PhotoVariantWorker) waiting(photoID uuid.UUID, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state, ok := w.retries[photoID]
+	return ok && now.Before(state.nextAfter)
+}
+
+// recordFailure doubles a photo's retry delay, starting from retryDelay. It
+// returns the delay and whether another attempt will be made.
+func (w *PhotoVariantWorker) recordFailure(photoID uuid.UUID) (time.Duration, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.retries[photoID]
+	if !ok {
+		state = &variantRetry{}
+		w.retries[photoID] = state
+	}
+	state.failures++
+	if state.failures >= w.maxAttempts {
+		delete(w.retries, photoID)
+		return 0, false
+	}
+
+	delay := w.retryDelay
+	for i := 1; i < state.failures; i++ {
+		delay *= 2
+	}
+	state.nextAfter = time.Now().Add(delay)
+	return delay, true
+}
+
+// QueueOnCreate wraps a photo repository so every photo it creates is queued
+// for variant generation
+func (w *PhotoVariantWorker) QueueOnCreate(repo models.ActivityPhotosRepository) models.ActivityPhotosRepository {
+	return &variantQueueingRepository{ActivityPhotosRepository: repo, worker: w}
+}
+
+// variantQueueingRepository queues new photos on the variant worker
+type variantQueueingRepository struct {
+	models.ActivityPhotosRepository
+	worker *PhotoVariantWorker
+}
+
+// Create stores the photo and queues it for variant generation
+func (r *variantQueueingRepository) Create(photo *models.ActivityPhoto) error {
+	if err := r.ActivityPhotosRepository.Create(photo); err != nil {
+		return err
+	}
+	r.worker.Enqueue(photo.ID)
+	return nil
+}
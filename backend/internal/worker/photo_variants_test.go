@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jenglund/rlship-tools/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPendingPhotoFinder mocks the pending photo lookup
+type MockPendingPhotoFinder struct {
+	mock.Mock
+}
+
+func (m *MockPendingPhotoFinder) ListMissingVariants(maxAttempts, limit int) ([]*models.ActivityPhoto, error) {
+	args := m.Called(maxAttempts, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ActivityPhoto), args.Error(1)
+}
+
+// fakeVariantService records variant jobs
+type fakeVariantService struct {
+	mu    sync.Mutex
+	calls map[uuid.UUID]int
+	errs  map[uuid.UUID]error
+}
+
+func newFakeVariantService() *fakeVariantService {
+	return &fakeVariantService{calls: make(map[uuid.UUID]int), errs: make(map[uuid.UUID]error)}
+}
+
+func (f *fakeVariantService) GenerateVariants(ctx context.Context, photoID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[photoID]++
+	return f.errs[photoID]
+}
+
+func (f *fakeVariantService) callCount(photoID uuid.UUID) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[photoID]
+}
+
+// fakePhotoCreator is the repository wrapped by QueueOnCreate
+type fakePhotoCreator struct {
+	models.ActivityPhotosRepository
+	err error
+}
+
+func (f *fakePhotoCreator) Create(photo *models.ActivityPhoto) error {
+	return f.err
+}
+
+func TestPhotoVariantWorker(t *testing.T) {
+	t.Run("Failed photos wait before retrying", func(t *testing.T) {
+		failing := &models.ActivityPhoto{BaseModel: models.BaseModel{ID: uuid.New()}}
+		healthy := &models.ActivityPhoto{BaseModel: models.BaseModel{ID: uuid.New()}}
+		finder := new(MockPendingPhotoFinder)
+		finder.On("ListMissingVariants", 3, variantSweepLimit).Return([]*models.ActivityPhoto{failing, healthy}, nil)
+
+		service := newFakeVariantService()
+		service.errs[failing.ID] = fmt.Errorf("decode failed")
+
+		worker := NewPhotoVariantWorker(finder, service, time.Hour, 3, time.Hour)
+		worker.RunOnce()
+		worker.RunOnce()
+
+		assert.Equal(t, 1, service.callCount(failing.ID))
+		assert.Equal(t, 2, service.callCount(healthy.ID))
+
+		// The delay doubles, and the last attempt gives up
+		delay, retry := worker.recordFailure(failing.ID)
+		assert.True(t, retry)
+		assert.Equal(t, 2*time.Hour, delay)
+		_, retry = worker.recordFailure(failing.ID)
+		assert.False(t, retry)
+		assert.False(t, worker.waiting(failing.ID, time.Now()))
+	})
+
+	t.Run("Sweep errors are logged", func(t *testing.T) {
+		finder := new(MockPendingPhotoFinder)
+		finder.On("ListMissingVariants", 5, variantSweepLimit).Return(nil, fmt.Errorf("db error"))
+
+		service := newFakeVariantService()
+		NewPhotoVariantWorker(finder, service, time.Hour, 5, time.Minute).RunOnce()
+		finder.AssertExpectations(t)
+	})
+
+	t.Run("Created photos are queued", func(t *testing.T) {
+		finder := new(MockPendingPhotoFinder)
+		finder.On("ListMissingVariants", mock.Anything, mock.Anything).Return([]*models.ActivityPhoto{}, nil)
+
+		service := newFakeVariantService()
+		worker := NewPhotoVariantWorker(finder, service, time.Hour, 5, time.Minute)
+		worker.Start()
+		defer worker.Stop()
+
+		photo := &models.ActivityPhoto{BaseModel: models.BaseModel{ID: uuid.New()}}
+		assert.NoError(t, worker.QueueOnCreate(&fakePhotoCreator{}).Create(photo))
+
+		failed := &models.ActivityPhoto{BaseModel: models.BaseModel{ID: uuid.New()}}
+		assert.Error(t, worker.QueueOnCreate(&fakePhotoCreator{err: fmt.Errorf("db error")}).Create(failed))
+
+		assert.Eventually(t, func() bool { return service.callCount(photo.ID) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, 0, service.callCount(failed.ID))
+	})
+}
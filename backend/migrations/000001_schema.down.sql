@@ -1,4 +1,8 @@
 -- Drop triggers
+DROP TRIGGER IF EXISTS update_interest_indicators_updated_at ON interest_indicators;
+DROP TRIGGER IF EXISTS update_availability_calendars_updated_at ON availability_calendars;
+DROP TRIGGER IF EXISTS increment_menu_sessions_version ON menu_sessions;
+DROP TRIGGER IF EXISTS update_menu_sessions_updated_at ON menu_sessions;
 DROP TRIGGER IF EXISTS update_activity_owners_updated_at ON activity_owners;
 DROP TRIGGER IF EXISTS validate_activity_owner_trigger ON activity_owners;
 DROP TRIGGER IF EXISTS validate_list_owner_trigger ON list_owners;
@@ -8,6 +12,9 @@ DROP TRIGGER IF EXISTS increment_list_items_version ON list_items;
 DROP TRIGGER IF EXISTS update_list_items_updated_at ON list_items;
 DROP TRIGGER IF EXISTS increment_lists_version ON lists;
 DROP TRIGGER IF EXISTS update_lists_updated_at ON lists;
+DROP TRIGGER IF EXISTS update_activity_occurrence_exceptions_updated_at ON activity_occurrence_exceptions;
+DROP TRIGGER IF EXISTS update_activity_rsvps_updated_at ON activity_rsvps;
+DROP TRIGGER IF EXISTS update_activity_schedules_updated_at ON activity_schedules;
 DROP TRIGGER IF EXISTS update_activity_shares_updated_at ON activity_shares;
 DROP TRIGGER IF EXISTS increment_activity_photos_version ON activity_photos;
 DROP TRIGGER IF EXISTS update_activity_photos_updated_at ON activity_photos;
@@ -22,8 +29,22 @@ DROP TRIGGER IF EXISTS update_users_updated_at ON users;
 DROP TRIGGER IF EXISTS update_list_conflicts_updated_at ON list_conflicts;
 
 -- Drop tables
+DROP TABLE IF EXISTS tribe_events CASCADE;
+DROP TABLE IF EXISTS tribe_audit_entries CASCADE;
+DROP TABLE IF EXISTS pending_invitations CASCADE;
+DROP TABLE IF EXISTS tribe_invites CASCADE;
+DROP TABLE IF EXISTS tribe_ownership_transfers CASCADE;
+DROP TABLE IF EXISTS interest_indicators CASCADE;
+DROP TABLE IF EXISTS busy_intervals CASCADE;
+DROP TABLE IF EXISTS availability_calendars CASCADE;
+DROP TABLE IF EXISTS calendar_feed_tokens CASCADE;
+DROP TABLE IF EXISTS menu_votes CASCADE;
+DROP TABLE IF EXISTS menu_sessions CASCADE;
 DROP TABLE IF EXISTS activity_owners CASCADE;
 DROP TABLE IF EXISTS activity_photos CASCADE;
+DROP TABLE IF EXISTS activity_occurrence_exceptions CASCADE;
+DROP TABLE IF EXISTS activity_rsvps CASCADE;
+DROP TABLE IF EXISTS activity_schedules CASCADE;
 DROP TABLE IF EXISTS activity_shares CASCADE;
 DROP TABLE IF EXISTS list_sharing CASCADE;
 DROP TABLE IF EXISTS list_items CASCADE;
@@ -50,6 +71,8 @@ DROP TYPE IF EXISTS sync_source CASCADE;
 DROP TYPE IF EXISTS activity_type CASCADE;
 DROP TYPE IF EXISTS owner_type CASCADE;
 DROP TYPE IF EXISTS membership_type CASCADE;
+DROP TYPE IF EXISTS tribe_role CASCADE;
+DROP TYPE IF EXISTS ownership_transfer_status CASCADE;
 
 -- Drop test database role
 DROP ROLE IF EXISTS "user"; 
\ No newline at end of file
@@ -23,6 +23,8 @@ CREATE TYPE sync_source AS ENUM ('none', 'google_maps', 'manual', 'imported', ''
 CREATE TYPE activity_type AS ENUM ('location', 'interest', 'list', 'custom', 'activity', 'event');
 CREATE TYPE owner_type AS ENUM ('user', 'tribe');
 CREATE TYPE membership_type AS ENUM ('full', 'limited', 'guest', 'pending');
+CREATE TYPE tribe_role AS ENUM ('owner', 'admin', 'member');
+CREATE TYPE ownership_transfer_status AS ENUM ('pending', 'accepted', 'declined', 'cancelled');
 
 -- Create users table
 CREATE TABLE users (
@@ -62,10 +64,14 @@ CREATE TABLE tribe_members (
     tribe_id UUID NOT NULL REFERENCES tribes(id),
     user_id UUID NOT NULL REFERENCES users(id),
     membership_type membership_type NOT NULL DEFAULT 'full',
-    display_name TEXT NOT NULL DEFAULT 'Member',
+    role tribe_role NOT NULL DEFAULT 'member',
+    display_name TEXT,
+    avatar_url TEXT,
+    pronouns TEXT,
     expires_at TIMESTAMP WITH TIME ZONE,
     invited_by UUID REFERENCES users(id),
     invited_at TIMESTAMP WITH TIME ZONE,
+    expiry_warned_for TIMESTAMP WITH TIME ZONE,
     metadata JSONB NOT NULL DEFAULT '{}' CHECK (metadata IS NOT NULL AND metadata != 'null'::jsonb),
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -119,13 +125,15 @@ CREATE TABLE list_items (
     latitude FLOAT,
     longitude FLOAT,
     address TEXT,
+    available BOOLEAN NOT NULL DEFAULT true,
     seasonal BOOLEAN NOT NULL DEFAULT false,
-    season_begin TEXT,
-    season_end TEXT,
-    cooldown INTEGER,
+    start_date DATE,
+    end_date DATE,
+    cooldown INTEGER CHECK (cooldown IS NULL OR cooldown >= 0),
     last_chosen TIMESTAMP WITH TIME ZONE,
     chosen_count INTEGER NOT NULL DEFAULT 0,
     metadata JSONB NOT NULL DEFAULT '{}' CHECK (metadata IS NOT NULL AND metadata != 'null'::jsonb),
+    sync_snapshot JSONB,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     version INTEGER NOT NULL DEFAULT 1,
@@ -141,10 +149,13 @@ CREATE TABLE activities (
     description TEXT NOT NULL DEFAULT '',
     visibility visibility_type NOT NULL DEFAULT 'private',
     metadata JSONB NOT NULL DEFAULT '{}' CHECK (metadata IS NOT NULL AND metadata != 'null'::jsonb),
+    recurrence_rule TEXT,
+    recurrence_start TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     version INTEGER NOT NULL DEFAULT 1,
-    deleted_at TIMESTAMP WITH TIME ZONE
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((recurrence_rule IS NULL) = (recurrence_start IS NULL))
 );
 
 -- Create activity_owners table
@@ -167,6 +178,9 @@ CREATE TABLE activity_photos (
     caption TEXT NOT NULL DEFAULT '',
     description TEXT NOT NULL DEFAULT '',
     metadata JSONB NOT NULL DEFAULT '{}' CHECK (metadata IS NOT NULL AND metadata != 'null'::jsonb),
+    storage_key TEXT NOT NULL DEFAULT '',
+    content_type TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE,
@@ -186,6 +200,43 @@ CREATE TABLE activity_shares (
     PRIMARY KEY (activity_id, tribe_id)
 );
 
+-- Create activity_schedules table
+CREATE TABLE activity_schedules (
+    activity_id UUID PRIMARY KEY REFERENCES activities(id),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    time_zone TEXT NOT NULL,
+    list_item_id UUID REFERENCES list_items(id),
+    location JSONB,
+    sequence INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time > start_time)
+);
+
+-- Create activity_rsvps table
+CREATE TABLE activity_rsvps (
+    activity_id UUID NOT NULL REFERENCES activities(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    status TEXT NOT NULL CHECK (status IN ('yes', 'no', 'maybe')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (activity_id, user_id)
+);
+
+-- Create activity_occurrence_exceptions table
+CREATE TABLE activity_occurrence_exceptions (
+    activity_id UUID NOT NULL REFERENCES activities(id),
+    original_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    start_time TIMESTAMP WITH TIME ZONE,
+    end_time TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (activity_id, original_start),
+    CHECK (cancelled OR (start_time IS NOT NULL AND end_time > start_time))
+);
+
 -- Create list_sharing table
 CREATE TABLE list_sharing (
     list_id UUID NOT NULL REFERENCES lists(id),
@@ -216,15 +267,151 @@ CREATE TABLE sync_conflicts (
 CREATE TABLE list_conflicts (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     list_id UUID NOT NULL REFERENCES lists(id),
+    item_id UUID REFERENCES list_items(id),
     type TEXT NOT NULL,
     local_data JSONB NOT NULL,
     remote_data JSONB NOT NULL,
+    resolution TEXT CHECK (resolution IN ('keep_local', 'take_remote', 'merge_fields', 'custom')),
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
     resolved_at TIMESTAMP WITH TIME ZONE,
     deleted_at TIMESTAMP WITH TIME ZONE
 );
 
+-- Create menu_sessions table
+CREATE TABLE menu_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    tribe_id UUID REFERENCES tribes(id),
+    params JSONB NOT NULL DEFAULT '{}',
+    items JSONB NOT NULL DEFAULT '[]',
+    chosen_item_id UUID REFERENCES list_items(id),
+    chosen_by UUID REFERENCES users(id),
+    chosen_at TIMESTAMP WITH TIME ZONE,
+    voting_rule TEXT CHECK (voting_rule IN ('majority', 'unanimous', 'first_veto')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+-- Create menu_votes table
+CREATE TABLE menu_votes (
+    session_id UUID NOT NULL REFERENCES menu_sessions(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    item_id UUID NOT NULL REFERENCES list_items(id),
+    kind TEXT NOT NULL CHECK (kind IN ('approve', 'veto', 'rank')),
+    rank INTEGER CHECK (rank IS NULL OR rank >= 1),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, user_id, item_id)
+);
+
+-- Create calendar_feed_tokens table
+CREATE TABLE calendar_feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create availability_calendars table
+CREATE TABLE availability_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    url TEXT,
+    last_imported_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create busy_intervals table
+CREATE TABLE busy_intervals (
+    calendar_id UUID NOT NULL REFERENCES availability_calendars(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (end_time > start_time)
+);
+
+-- Create interest_indicators table
+CREATE TABLE interest_indicators (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    tribe_id UUID NOT NULL REFERENCES tribes(id),
+    list_id UUID REFERENCES lists(id) ON DELETE CASCADE,
+    activity_type activity_type,
+    mood VARCHAR(280) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (expires_at > starts_at),
+    CHECK (list_id IS NULL OR activity_type IS NULL)
+);
+
+-- Create tribe_ownership_transfers table
+CREATE TABLE tribe_ownership_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tribe_id UUID NOT NULL REFERENCES tribes(id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    status ownership_transfer_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    CHECK (from_user_id <> to_user_id)
+);
+
+-- Create tribe_invites table
+CREATE TABLE tribe_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tribe_id UUID NOT NULL REFERENCES tribes(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    membership_type membership_type NOT NULL,
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CHECK (membership_type <> 'guest'),
+    CHECK (use_count >= 0)
+);
+
+-- Create pending_invitations table
+CREATE TABLE pending_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email TEXT NOT NULL,
+    tribe_id UUID NOT NULL REFERENCES tribes(id) ON DELETE CASCADE,
+    invited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    converted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create tribe_audit_entries table
+CREATE TABLE tribe_audit_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tribe_id UUID NOT NULL REFERENCES tribes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    actor_id UUID REFERENCES users(id),
+    action TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create tribe_events table
+CREATE TABLE tribe_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tribe_id UUID NOT NULL REFERENCES tribes(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    actor_id UUID REFERENCES users(id),
+    subject_id UUID NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create indexes
 CREATE INDEX idx_users_firebase_uid ON users(firebase_uid);
 CREATE INDEX idx_users_email ON users(email);
@@ -238,9 +425,27 @@ CREATE INDEX idx_list_items_list_id ON list_items(list_id);
 CREATE INDEX idx_activities_user_id ON activities(user_id);
 CREATE INDEX idx_activity_photos_activity_id ON activity_photos(activity_id);
 CREATE INDEX idx_activity_shares_activity_id ON activity_shares(activity_id);
+CREATE INDEX idx_activity_schedules_start_time ON activity_schedules(start_time);
 CREATE INDEX idx_list_sharing_list_id ON list_sharing(list_id);
 CREATE INDEX idx_sync_conflicts_list_id ON sync_conflicts(list_id);
 CREATE INDEX idx_list_conflicts_list_id ON list_conflicts(list_id);
+CREATE INDEX idx_menu_sessions_user_id ON menu_sessions(user_id);
+CREATE INDEX idx_menu_sessions_tribe_id ON menu_sessions(tribe_id);
+CREATE INDEX idx_menu_votes_session_id ON menu_votes(session_id);
+CREATE INDEX idx_calendar_feed_tokens_user_id ON calendar_feed_tokens(user_id);
+CREATE INDEX idx_availability_calendars_user_id ON availability_calendars(user_id);
+CREATE INDEX idx_busy_intervals_calendar_id ON busy_intervals(calendar_id);
+CREATE INDEX idx_busy_intervals_user_id_start_time ON busy_intervals(user_id, start_time);
+CREATE INDEX idx_interest_indicators_tribe_id_expires_at ON interest_indicators(tribe_id, expires_at);
+CREATE INDEX idx_interest_indicators_user_id ON interest_indicators(user_id);
+CREATE INDEX idx_interest_indicators_expires_at ON interest_indicators(expires_at);
+CREATE UNIQUE INDEX idx_tribe_ownership_transfers_pending ON tribe_ownership_transfers(tribe_id) WHERE status = 'pending';
+CREATE INDEX idx_tribe_invites_tribe_id ON tribe_invites(tribe_id);
+CREATE UNIQUE INDEX idx_pending_invitations_open ON pending_invitations(tribe_id, email) WHERE converted_at IS NULL;
+CREATE INDEX idx_pending_invitations_email ON pending_invitations(email) WHERE converted_at IS NULL;
+CREATE INDEX idx_tribe_audit_entries_tribe_id_created_at ON tribe_audit_entries(tribe_id, created_at DESC);
+CREATE INDEX idx_tribe_members_guest_expires_at ON tribe_members(expires_at) WHERE membership_type = 'guest' AND deleted_at IS NULL;
+CREATE INDEX idx_tribe_events_tribe_id_created_at ON tribe_events(tribe_id, created_at DESC, id DESC);
 
 -- Create test database role if it doesn't exist
 DO $$
@@ -302,6 +507,21 @@ CREATE TRIGGER increment_activity_photos_version
     FOR EACH ROW
     EXECUTE FUNCTION increment_version();
 
+CREATE TRIGGER update_activity_schedules_updated_at
+    BEFORE UPDATE ON activity_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_activity_rsvps_updated_at
+    BEFORE UPDATE ON activity_rsvps
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_activity_occurrence_exceptions_updated_at
+    BEFORE UPDATE ON activity_occurrence_exceptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
 CREATE TRIGGER update_activity_shares_updated_at
     BEFORE UPDATE ON activity_shares
     FOR EACH ROW
@@ -342,6 +562,16 @@ CREATE TRIGGER update_list_conflicts_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_menu_sessions_updated_at
+    BEFORE UPDATE ON menu_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER increment_menu_sessions_version
+    BEFORE UPDATE ON menu_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_version();
+
 CREATE OR REPLACE FUNCTION validate_list_owner()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -391,4 +621,14 @@ CREATE TRIGGER validate_activity_owner_trigger
 CREATE TRIGGER update_activity_owners_updated_at
     BEFORE UPDATE ON activity_owners
     FOR EACH ROW
-    EXECUTE FUNCTION update_updated_at_column(); 
\ No newline at end of file
+    EXECUTE FUNCTION update_updated_at_column(); 
+
+CREATE TRIGGER update_availability_calendars_updated_at
+    BEFORE UPDATE ON availability_calendars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_interest_indicators_updated_at
+    BEFORE UPDATE ON interest_indicators
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();